@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/logger"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/processor"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands are the subcommands, in the order of the usage text
+var commands = []command{
+	{"generate", "Generate clients from the discovered specs (the default)", runGenerate},
+	{"validate", "Validate the discovered specs without generating", runValidate},
+	{"diff", "Print a changelog between two spec files", runDiff},
+	{"list", "List the discovered specs and their clients", runList},
+	{"cache", "Inspect and manage the generation cache", runCache},
+	{"repair", "Fix common spec issues", runRepair},
+	{"config", "Print the resolved configuration", runConfig},
+	{"version", "Print the version", runVersion},
+}
+
+// newFlagSet returns the flag set of a subcommand, whose usage shows argsUsage and summary
+func newFlagSet(name, argsUsage, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet("openapi-go "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n\n%s\n\nFlags:\n", strings.TrimSpace("openapi-go "+name+" [flags] "+argsUsage), summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the arguments of a subcommand. It returns the exit code and false when the
+// command should not run: 0 for -h, 2 for invalid flags or arguments.
+func parseFlags(fs *flag.FlagSet, args []string, wantArgs int) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, false
+		}
+		return 2, false
+	}
+	if fs.NArg() != wantArgs {
+		fmt.Fprintf(fs.Output(), "Expected %d arguments, got %d\n", wantArgs, fs.NArg())
+		fs.Usage()
+		return 2, false
+	}
+	return 0, true
+}
+
+// configFlags are the flags of the subcommands that load the configuration. Each one overrides
+// its application.yml value (and environment variable) for the run.
+type configFlags struct {
+	overrides map[string]string
+	jsonLogs  *bool
+	textLogs  *bool
+}
+
+// addConfigFlags adds the configuration flags to fs
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	f := &configFlags{overrides: make(map[string]string)}
+	f.override(fs, "specs-dir", "specs_dir", "Directory of the specs")
+	f.override(fs, "output-dir", "output_dir", "Directory of the generated clients")
+	f.override(fs, "cache-dir", "cache_dir", "Directory of the generation cache")
+	f.override(fs, "services", "target_services", "Regex of the services to process")
+	f.override(fs, "workers", "worker_count", "Number of specs generated concurrently")
+	f.override(fs, "log-level", "log_level", "Log level (debug, info, warn or error)")
+	f.overrideBool(fs, "offline", "offline", "Never access the network; remote and git specs are used as last fetched")
+	fs.BoolFunc("no-cache", "Regenerate every client, ignoring the cache (overrides enable_cache)", func(value string) error {
+		disabled, err := strconv.ParseBool(value)
+		f.overrides["enable_cache"] = strconv.FormatBool(!disabled)
+		return err
+	})
+	fs.Func("set", "Override any configuration value as key=value, keyed like application.yml; lists are comma-separated (repeatable)", func(value string) error {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("expected key=value, got %q", value)
+		}
+		f.overrides[key] = val
+		return nil
+	})
+	f.jsonLogs = fs.Bool("json-logs", false, "Force JSON log output (overrides log_format)")
+	f.textLogs = fs.Bool("text-logs", false, "Force text log output (overrides log_format)")
+	return f
+}
+
+// override adds a flag setting the configuration value of key
+func (f *configFlags) override(fs *flag.FlagSet, name, key, usage string) {
+	fs.Func(name, fmt.Sprintf("%s (overrides %s)", usage, key), func(value string) error {
+		f.overrides[key] = value
+		return nil
+	})
+}
+
+// overrideBool adds a boolean flag setting the configuration value of key
+func (f *configFlags) overrideBool(fs *flag.FlagSet, name, key, usage string) {
+	fs.BoolFunc(name, fmt.Sprintf("%s (overrides %s)", usage, key), func(value string) error {
+		enabled, err := strconv.ParseBool(value)
+		f.overrides[key] = strconv.FormatBool(enabled)
+		return err
+	})
+}
+
+// load loads the configuration with the flag overrides. It returns the exit code and false
+// when the configuration cannot be loaded.
+func (f *configFlags) load() (config.Config, int, bool) {
+	cfg, err := config.LoadConfigWithOverrides(f.overrides)
+	if err != nil {
+		return cfg, fail(1, "Failed to load configuration", "error", err), false
+	}
+	cfg.LogFormat, err = config.ResolveLogFormat(cfg.LogFormat, *f.jsonLogs, *f.textLogs)
+	if err != nil {
+		return cfg, fail(2, "Invalid command line flags", "error", err), false
+	}
+	return cfg, 0, true
+}
+
+func runGenerate(args []string) int {
+	fs := newFlagSet("generate", "", "Generate clients from the discovered specs.")
+	cfgFlags := addConfigFlags(fs)
+	cfgFlags.overrideBool(fs, "exclude-deprecated", "exclude_deprecated", "Remove operations marked deprecated before generation")
+	cfgFlags.overrideBool(fs, "update-lock", "update_lock", "Record current spec checksums in the lockfile instead of verifying them")
+	cfgFlags.overrideBool(fs, "update-baseline", "update_baseline", "Rewrite baseline_fingerprints with the current specs instead of comparing against it")
+	cfgFlags.overrideBool(fs, "trace-cache", "trace_cache", "Log the inputs and outcome of each spec's cache decision as cache-trace lines")
+	cfgFlags.override(fs, "ogen-config", "ogen_config_path", "Path to an ogen configuration file")
+	cfgFlags.override(fs, "max-parallel-io", "io_concurrency", "Limit concurrent spec file reads, independently of worker_count")
+	cfgFlags.override(fs, "junit-report", "junit_report", "Write a JUnit XML report of the run to this path")
+	watch := fs.Bool("watch", false, "Keep running and regenerate clients when specs change (debounced by watch_debounce)")
+	dryRun := fs.Bool("dry-run", false, "Print which clients would be regenerated and why, without generating")
+	jsonOutput := fs.Bool("json", false, "Print the result of --dry-run as JSON")
+	githubAnnotations := fs.Bool("github-annotations", false, "Print failed specs as GitHub Actions annotations (automatic when GITHUB_ACTIONS=true)")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+
+	if *dryRun {
+		return planSpecs(cfg, *jsonOutput)
+	}
+	return generate(cfg, *watch, *githubAnnotations, *jsonOutput)
+}
+
+func runValidate(args []string) int {
+	fs := newFlagSet("validate", "", "Validate the discovered specs against validation_rules without generating.\nExits 1 if any spec is invalid.")
+	cfgFlags := addConfigFlags(fs)
+	cfgFlags.override(fs, "junit-report", "junit_report", "Write a JUnit XML report of the validation to this path")
+	strict := fs.Bool("strict", false, "Treat validation warnings as failures for this run")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	githubAnnotations := fs.Bool("github-annotations", false, "Print validation issues as GitHub Actions annotations (automatic when GITHUB_ACTIONS=true)")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+
+	if *strict {
+		// Scoped to this validation run; generation keeps the configured fail_on_warnings
+		cfg.FailOnWarnings = true
+	}
+	return validateSpecs(cfg, *jsonOutput, *githubAnnotations)
+}
+
+func runDiff(args []string) int {
+	fs := newFlagSet("diff", "<previous-spec> <current-spec>", "Print a Markdown changelog (added, modified, deleted and breaking operations) between two spec files.")
+	service := fs.String("service", "", "Service name for the heading (default: derived from the current spec's directory)")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	if code, ok := parseFlags(fs, args, 2); !ok {
+		return code
+	}
+	return diffSpecs(*service, fs.Arg(0), fs.Arg(1), *jsonOutput)
+}
+
+func runList(args []string) int {
+	fs := newFlagSet("list", "", "List the discovered specs with their version, operations and clients.\nRemote and git specs are listed as last fetched.")
+	cfgFlags := addConfigFlags(fs)
+	stats := fs.Bool("stats", false, "Print a summary of the specs (operations, methods, security, versions) instead")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+
+	if *stats {
+		return printStats(cfg, *jsonOutput)
+	}
+	list, err := processor.ListOpenAPISpecs(cfg)
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, list, *jsonOutput)
+	}
+	if err != nil {
+		return fail(1, "Failed to list specs", "error", err)
+	}
+	return 0
+}
+
+// cacheActions are the actions of the cache subcommand, with their arguments and summary
+var cacheActions = []struct {
+	name, args, summary string
+}{
+	{"status", "", "Print which clients are cached and which would be regenerated, and why"},
+	{"stats", "", "Print the number, size and age of the entries, and the hit rate of the last run"},
+	{"inspect", "<service>", "Print the cache entries of a service"},
+	{"prune", "", "Remove the entries not used within --older-than"},
+	{"clear", "", "Remove every entry, so the next run regenerates all clients"},
+}
+
+// runCache dispatches to the action given as the first argument; the flags follow the action
+func runCache(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		w := os.Stderr
+		code := 2
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "-help") {
+			w, code = os.Stdout, 0
+		}
+		fmt.Fprintln(w, "Usage: openapi-go cache <action> [flags] [args]")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Manage the generation cache of the configured generator version. Actions:")
+		for _, action := range cacheActions {
+			fmt.Fprintf(w, "  %-8s %s\n", action.name, action.summary)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, `Run "openapi-go cache <action> -h" for the flags of an action.`)
+		return code
+	}
+
+	action := args[0]
+	for _, a := range cacheActions {
+		if a.name == action {
+			return runCacheAction(a.name, a.args, a.summary, args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Unknown cache action %q\n", action)
+	return runCache(nil)
+}
+
+// runCacheAction parses the flags of a cache action and runs it
+func runCacheAction(action, argsUsage, summary string, args []string) int {
+	fs := newFlagSet("cache "+action, argsUsage, summary+".")
+	cfgFlags := addConfigFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	var olderThan *time.Duration
+	if action == "prune" {
+		olderThan = fs.Duration("older-than", 0, "Remove the entries not written or served from the cache within this duration, e.g. 720h (required)")
+	}
+	wantArgs := 0
+	if argsUsage != "" {
+		wantArgs = 1
+	}
+	if code, ok := parseFlags(fs, args, wantArgs); !ok {
+		return code
+	}
+	if olderThan != nil && *olderThan <= 0 {
+		fmt.Fprintln(fs.Output(), "--older-than must be a positive duration")
+		fs.Usage()
+		return 2
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+
+	if action == "status" {
+		return planSpecs(cfg, *jsonOutput)
+	}
+	// The cache file of the selected generator's version is the one managed
+	if err := processor.ConfigureGenerator(cfg); err != nil {
+		return fail(1, "Error configuring generator", "error", err)
+	}
+	var out processor.Output
+	var err error
+	switch action {
+	case "stats":
+		out, err = processor.CollectCacheStats(cfg)
+	case "inspect":
+		out, err = processor.InspectCache(cfg, fs.Arg(0))
+	case "prune":
+		out, err = processor.PruneCache(cfg, *olderThan)
+	case "clear":
+		out, err = processor.ClearCache(cfg)
+	}
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, out, *jsonOutput)
+	}
+	if err != nil {
+		return fail(1, "Cache "+action+" failed", "error", err)
+	}
+	return 0
+}
+
+func runRepair(args []string) int {
+	fs := newFlagSet("repair", "", "Fix common spec issues: add missing operationIds and info.version, and complete short\nopenapi versions. Repaired specs are written as <name>.repaired.<ext> copies.")
+	cfgFlags := addConfigFlags(fs)
+	write := fs.Bool("write", false, "Fix the specs in place instead of writing copies")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+	return repairSpecs(cfg, *write, *jsonOutput)
+}
+
+func runConfig(args []string) int {
+	fs := newFlagSet("config", "", "Print the resolved configuration (after environment and flag overrides, and defaults).\nSensitive values are redacted.")
+	cfgFlags := addConfigFlags(fs)
+	format := fs.String("format", "yaml", "Output format (yaml or json)")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	cfg, code, ok := cfgFlags.load()
+	if !ok {
+		return code
+	}
+	return printConfig(cfg, *format)
+}
+
+func runVersion(args []string) int {
+	fs := newFlagSet("version", "", "Print the version.")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+	fmt.Printf("openapi-go %s (%s)\n", network.Version, runtime.Version())
+	return 0
+}
+
+// generate generates the clients, or keeps regenerating them as specs change with watch
+func generate(cfg config.Config, watch, githubAnnotations, asJSON bool) int {
+	// Initialize structured logger with config
+	structuredLog := logger.New(logger.Config{
+		Level:  cfg.LogLevel,
+		Format: cfg.LogFormat,
+		Output: os.Stdout,
+		Redact: cfg.LogRedact,
+	})
+	if cfg.LogRedact {
+		// Most of the pipeline still logs through the standard log package
+		log.SetOutput(logger.NewRedactingWriter(log.Writer()))
+	}
+
+	structuredLog.Info("Starting OpenAPI client generator")
+	config.LogConfiguration(cfg, structuredLog)
+
+	// Select ogen or the configured generator plugin
+	if err := processor.ConfigureGenerator(cfg); err != nil {
+		structuredLog.Error("Error configuring generator", "error", err)
+		return 1
+	}
+
+	// Set up context with cancellation on interrupt
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle graceful shutdown on SIGINT/SIGTERM
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		structuredLog.Warn("Received interrupt signal, cancelling operations...")
+		cancel()
+	}()
+
+	// Process OpenAPI specs to generate clients
+	if watch {
+		if err := processor.WatchOpenAPISpecs(ctx, cfg, structuredLog); err != nil {
+			structuredLog.Error("Error watching OpenAPI specs", "error", err)
+			return 1
+		}
+		return 0
+	}
+	report, err := processor.ProcessOpenAPISpecsWithResult(ctx, cfg, structuredLog)
+	if annotate(githubAnnotations) {
+		if err := processor.WriteGitHubAnnotations(annotationOutput(asJSON), report.GitHubAnnotations()); err != nil {
+			structuredLog.Warn("Failed to write GitHub annotations", "error", err)
+		}
+	}
+	if err != nil {
+		structuredLog.Error("Error processing OpenAPI specs", "error", err)
+		return 1
+	}
+
+	structuredLog.Info("Client generation completed successfully")
+	return 0
+}
+
+// planSpecs reports which clients a run would regenerate without generating anything
+func planSpecs(cfg config.Config, asJSON bool) int {
+	err := processor.ConfigureGenerator(cfg)
+	var summary *processor.DryRunSummary
+	if err == nil {
+		summary, err = processor.PlanOpenAPISpecs(context.Background(), cfg)
+	}
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, summary, asJSON)
+	}
+	if err != nil {
+		return fail(1, "Dry run failed", "error", err)
+	}
+	return 0
+}
+
+// validateSpecs validates the spec inventory without generating anything
+func validateSpecs(cfg config.Config, asJSON, githubAnnotations bool) int {
+	summary, err := processor.CheckOpenAPISpecs(context.Background(), cfg)
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, summary, asJSON)
+	}
+	if err == nil && annotate(githubAnnotations) {
+		err = processor.WriteGitHubAnnotations(annotationOutput(asJSON), summary.GitHubAnnotations())
+	}
+	if err == nil {
+		err = summary.Err()
+	}
+	if err != nil {
+		return fail(1, "Spec validation failed", "error", err)
+	}
+	return 0
+}
+
+// diffSpecs compares two spec versions; it needs no configuration
+func diffSpecs(service, previous, current string, asJSON bool) int {
+	changes, err := processor.CompareSpecs(service, previous, current)
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, changes, asJSON)
+	}
+	if err != nil {
+		return fail(1, "Failed to build changelog", "error", err)
+	}
+	return 0
+}
+
+// printStats summarizes the spec inventory without generating anything
+func printStats(cfg config.Config, asJSON bool) int {
+	specStats, err := processor.CollectSpecStats(cfg)
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, specStats, asJSON)
+	}
+	if err != nil {
+		return fail(1, "Failed to collect spec stats", "error", err)
+	}
+	return 0
+}
+
+// repairSpecs applies safe automated fixes to the specs without generating anything
+func repairSpecs(cfg config.Config, write, asJSON bool) int {
+	summary, err := processor.RepairOpenAPISpecs(cfg, write)
+	if err == nil {
+		err = processor.WriteOutput(os.Stdout, summary, asJSON)
+	}
+	if err == nil {
+		err = summary.Err()
+	}
+	if err != nil {
+		return fail(1, "Spec repair failed", "error", err)
+	}
+	return 0
+}
+
+// printConfig prints the effective configuration (after env overrides and defaults)
+func printConfig(cfg config.Config, format string) int {
+	data, err := config.FormatConfig(cfg, format)
+	if err != nil {
+		return fail(2, "Failed to print configuration", "error", err)
+	}
+	os.Stdout.Write(data)
+	return 0
+}
+
+// annotate reports whether to print GitHub Actions annotations: when asked, or automatically
+// in GitHub Actions
+func annotate(githubAnnotations bool) bool {
+	return githubAnnotations || os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// annotationOutput is where annotations go; with --json they go to stderr to keep stdout parseable
+func annotationOutput(asJSON bool) io.Writer {
+	if asJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
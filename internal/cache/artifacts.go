@@ -0,0 +1,233 @@
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// artifactsSubdir is the directory of client archives, under the cache directory
+const artifactsSubdir = "artifacts"
+
+// artifactSubdir returns the directory of the client archives of the generator version,
+// relative to the cache directory. It is namespaced like the cache file, so each version keeps
+// its own archives.
+func (c *Cache) artifactSubdir() string {
+	version := sanitizeVersion(c.generatorVersion)
+	if version == "" {
+		version = "default"
+	}
+	return filepath.Join(artifactsSubdir, version)
+}
+
+// artifactName returns the archive file name of a spec's client, relative to the cache directory
+func (c *Cache) artifactName(specPath string) string {
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(specPath)))[:16]
+	return filepath.Join(c.artifactSubdir(), key+".tar.gz")
+}
+
+// storeArtifact archives the client directory of a spec and returns the archive name. The
+// archive is written to a temporary file first, so a failed write keeps the previous one.
+func (c *Cache) storeArtifact(specPath, outputPath string) (string, error) {
+	name := c.artifactName(specPath)
+	path := filepath.Join(c.cacheDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchive(tmp, outputPath); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to archive %s: %w", outputPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return name, nil
+}
+
+// writeArchive writes the directories and regular files under dir to w as a gzipped tarball
+func writeArchive(w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// hasArtifact reports whether the archive of an entry exists
+func (c *Cache) hasArtifact(entry *Entry) bool {
+	if entry.Artifact == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(c.cacheDir, entry.Artifact))
+	return err == nil
+}
+
+// RestoreArtifact extracts the archived client of a spec into its output directory, for a
+// decision with ReasonHitArtifact. A failed extraction removes the partial output.
+func (c *Cache) RestoreArtifact(specPath string) error {
+	c.mu.Lock()
+	entry, exists := c.entries[specPath]
+	c.mu.Unlock()
+	if !exists || entry.Artifact == "" {
+		return fmt.Errorf("no archived client for %s", specPath)
+	}
+
+	file, err := os.Open(filepath.Join(c.cacheDir, entry.Artifact))
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	if err := extractArchive(file, entry.OutputPath); err != nil {
+		os.RemoveAll(entry.OutputPath)
+		return fmt.Errorf("failed to restore %s: %w", entry.OutputPath, err)
+	}
+	return nil
+}
+
+// extractArchive extracts a gzipped tarball written by writeArchive into dir
+func extractArchive(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archive entry %q is outside the client directory", header.Name)
+		}
+		path := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, header.FileInfo().Mode().Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := extractFile(tr, path, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractFile writes the current archive entry to path
+func extractFile(r io.Reader, path string, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// orphanGracePeriod keeps recent archives that no entry refers to, since another run sharing
+// the cache directory may have written one without having saved its entry yet
+const orphanGracePeriod = 10 * time.Minute
+
+// removeOrphanedArtifacts deletes the archives of the generator version that no entry refers
+// to, such as those of invalidated or evicted entries. Archives written by other processes in
+// the last orphanGracePeriod are kept. The caller must hold c.mu.
+func (c *Cache) removeOrphanedArtifacts() {
+	dir := filepath.Join(c.cacheDir, c.artifactSubdir())
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	unlock, err := c.lock()
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	referenced := make(map[string]bool, len(c.entries))
+	for _, entries := range []map[string]*Entry{c.entries, c.savedEntries()} {
+		for _, entry := range entries {
+			if entry.Artifact != "" {
+				referenced[filepath.Base(entry.Artifact)] = true
+			}
+		}
+	}
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if referenced[name] {
+			continue
+		}
+		if info, err := dirEntry.Info(); err == nil && !c.stored[name] && time.Since(info.ModTime()) < orphanGracePeriod {
+			continue
+		}
+		os.Remove(filepath.Join(dir, name))
+	}
+}
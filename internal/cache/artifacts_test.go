@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheArtifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+	outputPath := filepath.Join(tmpDir, "clients", "testsdk")
+	files := map[string]string{
+		"oas_client_gen.go":   "package testsdk\n",
+		"internal/helpers.go": "package internal\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(outputPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create client dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0640); err != nil {
+			t.Fatalf("Failed to write client file: %v", err)
+		}
+	}
+
+	cache, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.0.0", Artifacts: true})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	if err := cache.Set(specPath, outputPath, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	entry, _ := cache.Get(specPath)
+	if entry.Artifact == "" {
+		t.Fatal("Set() should archive the client")
+	}
+
+	// On a clean checkout the client is missing, but its archive restores it
+	if err := os.RemoveAll(filepath.Join(tmpDir, "clients")); err != nil {
+		t.Fatalf("Failed to remove client: %v", err)
+	}
+	decision, err := cache.DecideFor(specPath, "v1.0.0", cache.describeFile(specPath))
+	if err != nil || !decision.Hit || decision.Reason != ReasonHitArtifact {
+		t.Fatalf("DecideFor() = %+v, %v; want a hit restoring the archive", decision, err)
+	}
+	if err := cache.RestoreArtifact(specPath); err != nil {
+		t.Fatalf("RestoreArtifact() failed: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(outputPath, filepath.FromSlash(name))
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("restored %s = %q, %v; want %q", name, data, err, content)
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0640 {
+			t.Errorf("restored %s mode = %v, want 0640", name, info.Mode().Perm())
+		}
+	}
+
+	// Without the archive, a missing client is regenerated
+	if err := os.RemoveAll(outputPath); err != nil {
+		t.Fatalf("Failed to remove client: %v", err)
+	}
+	if err := os.Remove(filepath.Join(cacheDir, entry.Artifact)); err != nil {
+		t.Fatalf("Failed to remove archive: %v", err)
+	}
+	decision, _ = cache.DecideFor(specPath, "v1.0.0", cache.describeFile(specPath))
+	if decision.Hit || decision.Reason != ReasonOutputMissing {
+		t.Errorf("DecideFor() without archive = %+v, want output_missing", decision)
+	}
+}
+
+func TestPruneInvalidRemovesOrphanedArtifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	cache, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.0.0", Artifacts: true})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+
+	var archives []string
+	for _, name := range []string{"kept", "removed"} {
+		specPath := filepath.Join(tmpDir, name, "openapi.json")
+		if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+			t.Fatalf("Failed to create spec dir: %v", err)
+		}
+		if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+			t.Fatalf("Failed to create spec file: %v", err)
+		}
+		if err := cache.Set(specPath, filepath.Dir(specPath), name, "v1.0.0"); err != nil {
+			t.Fatalf("Set() failed: %v", err)
+		}
+		entry, _ := cache.Get(specPath)
+		archives = append(archives, filepath.Join(cacheDir, entry.Artifact))
+	}
+
+	if err := os.RemoveAll(filepath.Join(tmpDir, "removed")); err != nil {
+		t.Fatalf("Failed to remove spec: %v", err)
+	}
+	if pruned, err := cache.PruneInvalid(); err != nil || pruned != 1 {
+		t.Fatalf("PruneInvalid() = %d, %v; want 1", pruned, err)
+	}
+	if _, err := os.Stat(archives[0]); err != nil {
+		t.Errorf("archive of the remaining entry was removed: %v", err)
+	}
+	if _, err := os.Stat(archives[1]); !os.IsNotExist(err) {
+		t.Errorf("archive of the pruned entry still exists, stat error = %v", err)
+	}
+}
@@ -7,7 +7,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
 )
 
 // Entry represents a cache entry for a generated client
@@ -22,18 +27,115 @@ type Entry struct {
 	ServiceName string `json:"service_name"`
 	// GeneratorVersion is the version of the generator used
 	GeneratorVersion string `json:"generator_version"`
+	// Fingerprint summarizes the code-affecting sections of the spec (nil if the spec could not be parsed)
+	Fingerprint *spec.Fingerprint `json:"fingerprint,omitempty"`
+	// ExcludeDeprecated records whether deprecated operations were excluded from generation
+	ExcludeDeprecated bool `json:"exclude_deprecated,omitempty"`
+	// DowngradeOpenAPI31 records whether OpenAPI 3.1 specs were converted to 3.0 for generation
+	DowngradeOpenAPI31 bool `json:"downgrade_openapi31,omitempty"`
+	// ConvertSwagger2 records whether Swagger 2.0 specs were converted to OpenAPI 3.0 for generation
+	ConvertSwagger2 bool `json:"convert_swagger2,omitempty"`
+	// GeneratorConfigHash is the hash of the generator configuration and templates the client
+	// was generated with (see HashInputs)
+	GeneratorConfigHash string `json:"generator_config_hash,omitempty"`
+	// LastUsed is when the entry was last written or served as a cache hit (drives LRU eviction)
+	LastUsed time.Time `json:"last_used,omitempty"`
+	// SpecVersion is the spec's info.version when the client was generated (empty if unknown)
+	SpecVersion string `json:"spec_version,omitempty"`
+	// OperationCount is the number of operations in the spec when the client was generated
+	// (0 if unknown)
+	OperationCount int `json:"operation_count,omitempty"`
+	// Dependencies are the hashes of the files the spec $refs, keyed by path, when the client
+	// was generated
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	// Artifact is the archive of the generated client, relative to the cache directory (empty
+	// if the client was not archived)
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// lastUsed returns when the entry was last used, falling back to its generation time
+// for entries written before LastUsed was tracked
+func (e *Entry) lastUsed() time.Time {
+	if e.LastUsed.IsZero() {
+		return e.GeneratedAt
+	}
+	return e.LastUsed
+}
+
+// settings returns the cache-affecting settings the entry was generated with
+func (e *Entry) settings() generationSettings {
+	return generationSettings{
+		excludeDeprecated:  e.ExcludeDeprecated,
+		downgradeOpenAPI31: e.DowngradeOpenAPI31,
+		convertSwagger2:    e.ConvertSwagger2,
+		generatorConfig:    e.GeneratorConfigHash,
+	}
 }
 
 // Cache manages a hash-based cache for OpenAPI client generation
 type Cache struct {
-	entries  map[string]*Entry // key: spec path
-	cacheDir string
+	mu                     sync.Mutex
+	entries                map[string]*Entry // key: spec path
+	saved                  map[string]Entry  // entries as last read from or written to the cache file
+	cacheDir               string
+	regenerateOnDocChanges bool
+	excludeDeprecated      bool
+	downgradeOpenAPI31     bool
+	convertSwagger2        bool
+	ignoreResponseHeaders  bool
+	generatorVersion       string
+	generatorConfigHash    string
+	sharedHashes           map[string]string // key: shared component file path
+	maxEntries             int
+	readOnly               bool
+	artifacts              bool
+	stored                 map[string]bool // archive names written by this process
+}
+
+// settings returns the cache-affecting settings of the current run
+func (c *Cache) settings() generationSettings {
+	return generationSettings{
+		excludeDeprecated:  c.excludeDeprecated,
+		downgradeOpenAPI31: c.downgradeOpenAPI31,
+		convertSwagger2:    c.convertSwagger2,
+		generatorConfig:    c.generatorConfigHash,
+	}
 }
 
 // Config contains configuration for the cache
 type Config struct {
 	// CacheDir is the directory where cache metadata is stored
 	CacheDir string
+	// RegenerateOnDocChanges invalidates entries on any spec byte change, even when
+	// only non-code-affecting metadata (info, servers, docs, formatting) changed
+	RegenerateOnDocChanges bool
+	// ExcludeDeprecated matches the generation setting: deprecated operations are left out of
+	// fingerprints, and entries generated with a different setting are invalid
+	ExcludeDeprecated bool
+	// DowngradeOpenAPI31 matches the generation setting: entries generated with a different
+	// setting are invalid
+	DowngradeOpenAPI31 bool
+	// ConvertSwagger2 matches the generation setting: entries generated with a different
+	// setting are invalid
+	ConvertSwagger2 bool
+	// IgnoreResponseHeaders leaves response header definitions out of fingerprints, for
+	// generators whose output does not depend on them
+	IgnoreResponseHeaders bool
+	// GeneratorVersion namespaces the cache file (cache-<version>.json), so switching
+	// between generator versions keeps a separate cache per version. Empty uses cache.json.
+	GeneratorVersion string
+	// GeneratorConfigHash is the hash of the generator configuration and templates (see
+	// HashInputs): entries generated with a different hash are invalid
+	GeneratorConfigHash string
+	// MaxEntries caps the number of entries; when exceeded, the least-recently-used
+	// entries are evicted. Zero means unlimited.
+	MaxEntries int
+	// ReadOnly never writes the cache file, for dry runs: invalidations, evictions and new
+	// entries only apply in memory
+	ReadOnly bool
+	// Artifacts archives each generated client with its entry, so a hit whose client
+	// directory is missing (e.g. on a clean checkout) can restore it
+	Artifacts bool
 }
 
 // NewCache creates a new cache instance
@@ -43,26 +145,44 @@ func NewCache(cfg Config) (*Cache, error) {
 	}
 
 	// Ensure cache directory exists
-	if err := os.MkdirAll(cfg.CacheDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	if !cfg.ReadOnly {
+		if err := os.MkdirAll(cfg.CacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
 	}
 
 	cache := &Cache{
-		entries:  make(map[string]*Entry),
-		cacheDir: cfg.CacheDir,
+		entries:                make(map[string]*Entry),
+		cacheDir:               cfg.CacheDir,
+		regenerateOnDocChanges: cfg.RegenerateOnDocChanges,
+		excludeDeprecated:      cfg.ExcludeDeprecated,
+		downgradeOpenAPI31:     cfg.DowngradeOpenAPI31,
+		convertSwagger2:        cfg.ConvertSwagger2,
+		ignoreResponseHeaders:  cfg.IgnoreResponseHeaders,
+		generatorVersion:       cfg.GeneratorVersion,
+		generatorConfigHash:    cfg.GeneratorConfigHash,
+		maxEntries:             cfg.MaxEntries,
+		readOnly:               cfg.ReadOnly,
+		artifacts:              cfg.Artifacts,
+		stored:                 make(map[string]bool),
 	}
 
 	// Load existing cache entries
 	if err := cache.load(); err != nil {
 		// Log warning but don't fail - we'll start with empty cache
-		fmt.Printf("Warning: Failed to load cache: %v\n", err)
+		fmt.Printf("Warning: Failed to load cache, discarding it: %v\n", err)
+		cache.entries = make(map[string]*Entry)
 	}
 
 	return cache, nil
 }
 
-// computeFileHash computes SHA256 hash of a file
-func computeFileHash(path string) (string, error) {
+// ComputeFileHash computes the SHA256 hash of a file as a hex string, within the spec I/O
+// concurrency limit (see spec.SetIOConcurrency)
+func ComputeFileHash(path string) (string, error) {
+	release := spec.AcquireIO()
+	defer release()
+
 	file, err := os.Open(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to open file: %w", err)
@@ -77,56 +197,256 @@ func computeFileHash(path string) (string, error) {
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
+// SpecInfo is what the cache records about a spec's content, derived from its decoded
+// document. Callers that already parsed the spec compute it once with Describe and share it
+// between IsValidFor and SetFor instead of each reading the spec again.
+type SpecInfo struct {
+	// Fingerprint is nil if the spec could not be parsed
+	Fingerprint *spec.Fingerprint
+	// Version is the spec's info.version
+	Version string
+	// OperationCount is the number of operations in the spec
+	OperationCount int
+	// Dependencies are the hashes of the files the spec $refs, keyed by path (see HashFiles)
+	Dependencies map[string]string
+}
+
+// Describe returns the SpecInfo of a decoded spec document (nil if it could not be parsed),
+// applying the cache's fingerprint settings. The document is not modified.
+func (c *Cache) Describe(doc map[string]interface{}) SpecInfo {
+	if doc == nil {
+		return SpecInfo{}
+	}
+	return SpecInfo{
+		Fingerprint:    c.fingerprintDocument(doc),
+		Version:        spec.InfoVersion(doc),
+		OperationCount: spec.CountOperations(doc),
+	}
+}
+
+// describeFile reads a spec and returns its SpecInfo, including the hashes of the files it
+// $refs; specs that cannot be parsed get an empty SpecInfo and fall back to hash-only checks
+func (c *Cache) describeFile(specPath string) SpecInfo {
+	doc, err := spec.LoadDocument(specPath)
+	if err != nil {
+		return SpecInfo{}
+	}
+	info := c.Describe(doc)
+	info.Dependencies = HashFiles(spec.ExternalRefFiles(specPath, doc))
+	return info
+}
+
+// HashFiles returns the hashes of files keyed by path, or nil for no files. A file that
+// cannot be read has an empty hash, so its appearance or removal counts as a change.
+func HashFiles(paths []string) map[string]string {
+	if len(paths) == 0 {
+		return nil
+	}
+	hashes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		hash, _ := ComputeFileHash(path)
+		hashes[path] = hash
+	}
+	return hashes
+}
+
+// HashInputs returns a combined hash of the content of files and directories that generation
+// depends on besides the spec, such as the generator configuration and templates. Directories
+// are hashed by the relative names and content of their files, and the input paths themselves
+// are left out, so moving the checkout keeps the hash. A missing input hashes as empty. The
+// hash is shortened like the plugin version hash, e.g. "sha256:3f9a0c1d2e4b".
+func HashInputs(paths []string) string {
+	hash := sha256.New()
+	for i, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			fileHash, err := ComputeFileHash(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "%d:%s:%s\n", i, filepath.ToSlash(rel), fileHash)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(hash, "%d:missing\n", i)
+		}
+	}
+	return "sha256:" + fmt.Sprintf("%x", hash.Sum(nil))[:12]
+}
+
 // IsValid checks if a cache entry is valid for the given spec file
 func (c *Cache) IsValid(specPath, generatorVersion string) (bool, error) {
+	// The spec is always parsed, to find the files it $refs
+	return c.IsValidFor(specPath, generatorVersion, c.describeFile(specPath))
+}
+
+// IsValidFor is IsValid for a spec the caller already described
+func (c *Cache) IsValidFor(specPath, generatorVersion string, info SpecInfo) (bool, error) {
+	decision, err := c.DecideFor(specPath, generatorVersion, info)
+	return decision.Hit, err
+}
+
+// DecideFor is IsValidFor returning the inputs and outcome of the check, for tracing
+func (c *Cache) DecideFor(specPath, generatorVersion string, info SpecInfo) (Decision, error) {
+	return c.decide(specPath, generatorVersion, info)
+}
+
+// decide checks a cache entry and records why it is or isn't valid
+func (c *Cache) decide(specPath, generatorVersion string, info SpecInfo) (Decision, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decision := Decision{
+		SpecPath:                specPath,
+		CurrentGeneratorVersion: generatorVersion,
+		CurrentConfig:           configSummary(c.settings()),
+		Fingerprint:             fingerprintNotCompared,
+	}
+
 	// Get cached entry
 	entry, exists := c.entries[specPath]
 	if !exists {
-		return false, nil
+		decision.Reason = ReasonNoEntry
+		return decision, nil
 	}
+	decision.StoredHash = entry.SpecHash
+	decision.StoredGeneratorVersion = entry.GeneratorVersion
+	decision.StoredConfig = configSummary(entry.settings())
+	decision.OutputPath = entry.OutputPath
 
 	// Compute current hash
-	currentHash, err := computeFileHash(specPath)
+	currentHash, err := ComputeFileHash(specPath)
 	if err != nil {
-		return false, fmt.Errorf("failed to compute current hash: %w", err)
+		decision.Reason = ReasonCheckFailed
+		return decision, fmt.Errorf("failed to compute current hash: %w", err)
 	}
+	decision.CurrentHash = currentHash
 
-	// Check if hash matches and generator version matches
-	if entry.SpecHash != currentHash {
-		return false, nil
+	if entry.GeneratorVersion != generatorVersion {
+		decision.Reason = ReasonGeneratorChanged
+		return decision, nil
+	}
+	if entry.settings() != c.settings() {
+		decision.Reason = ReasonConfigChanged
+		return decision, nil
 	}
 
-	if entry.GeneratorVersion != generatorVersion {
-		return false, nil
+	// A change to a file the spec $refs affects the client even if the spec itself is unchanged
+	if path := changedDependency(entry.Dependencies, info.Dependencies); path != "" {
+		decision.Reason = ReasonDependencyChanged
+		decision.Dependency = path
+		return decision, nil
 	}
 
-	// Verify output directory still exists
+	// A changed hash is still a hit if only non-code-affecting metadata changed
+	decision.Reason = ReasonHit
+	if entry.SpecHash != currentHash {
+		if c.regenerateOnDocChanges {
+			decision.Fingerprint = fingerprintDocChangeMode
+		} else {
+			decision.Fingerprint = compareFingerprints(entry.Fingerprint, info.Fingerprint)
+		}
+		if decision.Fingerprint != fingerprintEqual {
+			decision.Reason = ReasonSpecChanged
+			return decision, nil
+		}
+		decision.Reason = ReasonHitFingerprint
+	}
+
+	// Verify output directory still exists, or can be restored from its archive
 	if _, err := os.Stat(entry.OutputPath); os.IsNotExist(err) {
-		return false, nil
+		if !c.hasArtifact(entry) {
+			decision.Reason = ReasonOutputMissing
+			return decision, nil
+		}
+		decision.Reason = ReasonHitArtifact
 	}
 
-	return true, nil
+	// Record the hit so frequently used entries survive LRU eviction; it only needs
+	// persisting right away when a size limit is set
+	entry.LastUsed = time.Now()
+	if c.maxEntries > 0 {
+		if err := c.save(); err != nil {
+			fmt.Printf("Warning: Failed to record cache hit: %v\n", err)
+		}
+	}
+
+	decision.Hit = true
+	return decision, nil
+}
+
+// fingerprintDocument computes the spec fingerprint, leaving out deprecated operations and
+// response headers if they are excluded
+func (c *Cache) fingerprintDocument(doc map[string]interface{}) *spec.Fingerprint {
+	if !c.excludeDeprecated && !c.ignoreResponseHeaders {
+		return spec.FingerprintDocument(doc)
+	}
+
+	// Filtering modifies the document, which the caller may still use
+	doc = spec.CopyDocument(doc)
+	if c.excludeDeprecated {
+		spec.RemoveDeprecatedOperations(doc)
+	}
+	if c.ignoreResponseHeaders {
+		spec.StripResponseHeaders(doc)
+	}
+	return spec.FingerprintDocument(doc)
 }
 
 // Set adds or updates a cache entry
 func (c *Cache) Set(specPath, outputPath, serviceName, generatorVersion string) error {
+	return c.SetFor(specPath, outputPath, serviceName, generatorVersion, c.describeFile(specPath))
+}
+
+// SetFor is Set for a spec the caller already described
+func (c *Cache) SetFor(specPath, outputPath, serviceName, generatorVersion string, info SpecInfo) error {
 	// Compute spec hash
-	hash, err := computeFileHash(specPath)
+	hash, err := ComputeFileHash(specPath)
 	if err != nil {
 		return fmt.Errorf("failed to compute spec hash: %w", err)
 	}
 
 	// Create entry
 	entry := &Entry{
-		SpecHash:         hash,
-		GeneratedAt:      time.Now(),
-		OutputPath:       outputPath,
-		ServiceName:      serviceName,
-		GeneratorVersion: generatorVersion,
+		SpecHash:            hash,
+		GeneratedAt:         time.Now(),
+		OutputPath:          outputPath,
+		ServiceName:         serviceName,
+		GeneratorVersion:    generatorVersion,
+		Fingerprint:         info.Fingerprint,
+		ExcludeDeprecated:   c.excludeDeprecated,
+		DowngradeOpenAPI31:  c.downgradeOpenAPI31,
+		ConvertSwagger2:     c.convertSwagger2,
+		GeneratorConfigHash: c.generatorConfigHash,
+		SpecVersion:         info.Version,
+		OperationCount:      info.OperationCount,
+		Dependencies:        info.Dependencies,
 	}
+	entry.LastUsed = entry.GeneratedAt
 
-	// Store in memory
+	if c.artifacts && !c.readOnly {
+		// The entry is still worth keeping without its archive, as long as the output exists
+		if entry.Artifact, err = c.storeArtifact(specPath, outputPath); err != nil {
+			fmt.Printf("Warning: Failed to archive client of %s: %v\n", serviceName, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.Artifact != "" {
+		c.stored[filepath.Base(entry.Artifact)] = true
+	}
+
+	// Store in memory, making room by evicting the least-recently-used entries
 	c.entries[specPath] = entry
+	c.evictLRU()
 
 	// Persist to disk
 	if err := c.save(); err != nil {
@@ -136,14 +456,68 @@ func (c *Cache) Set(specPath, outputPath, serviceName, generatorVersion string)
 	return nil
 }
 
+// CheckSpecVersion compares a spec's current info.version with the version recorded when its
+// client was last generated. It returns an error describing a lower version (a possible
+// accidental downgrade) or a changed version that cannot be compared because it is not semver,
+// and nil otherwise, including when no version was recorded. The error is meant as a warning.
+func (c *Cache) CheckSpecVersion(specPath, current string) error {
+	c.mu.Lock()
+	entry, exists := c.entries[specPath]
+	c.mu.Unlock()
+	if !exists || entry.SpecVersion == "" {
+		return nil
+	}
+
+	if current == entry.SpecVersion {
+		return nil
+	}
+
+	previousVersion, err := spec.ParseVersion(entry.SpecVersion)
+	if err != nil {
+		return fmt.Errorf("cannot compare spec version %q of %s with cached version: %w", current, specPath, err)
+	}
+	currentVersion, err := spec.ParseVersion(current)
+	if err != nil {
+		return fmt.Errorf("cannot compare spec version of %s with cached version %q: %w", specPath, entry.SpecVersion, err)
+	}
+	if currentVersion.Compare(previousVersion) < 0 {
+		return fmt.Errorf("spec version of %s decreased from %s to %s (possible accidental downgrade)", specPath, entry.SpecVersion, current)
+	}
+	return nil
+}
+
+// CheckOperationCount compares a spec's current operation count with the count recorded when
+// its client was last generated. It returns an error if more than maxDrop (a fraction between
+// 0 and 1) of the operations disappeared, and nil otherwise, including when no count was
+// recorded. A maxDrop of 0 or less disables the check.
+func (c *Cache) CheckOperationCount(specPath string, current int, maxDrop float64) error {
+	c.mu.Lock()
+	entry, exists := c.entries[specPath]
+	c.mu.Unlock()
+	if maxDrop <= 0 || !exists || entry.OperationCount == 0 || current >= entry.OperationCount {
+		return nil
+	}
+
+	drop := float64(entry.OperationCount-current) / float64(entry.OperationCount)
+	if drop > maxDrop {
+		return fmt.Errorf("%s has %d operations, down from %d when its client was last generated (%.0f%% dropped, limit %.0f%%)",
+			specPath, current, entry.OperationCount, drop*100, maxDrop*100)
+	}
+	return nil
+}
+
 // Get retrieves a cache entry
 func (c *Cache) Get(specPath string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	entry, exists := c.entries[specPath]
 	return entry, exists
 }
 
 // Invalidate removes a cache entry
 func (c *Cache) Invalidate(specPath string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	delete(c.entries, specPath)
 
 	// Persist changes
@@ -154,51 +528,92 @@ func (c *Cache) Invalidate(specPath string) error {
 	return nil
 }
 
-// Clear removes all cache entries
+// Clear removes all cache entries and their client archives
 func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.entries = make(map[string]*Entry)
 
 	// Persist changes
 	if err := c.save(); err != nil {
 		return fmt.Errorf("failed to save cache after clear: %w", err)
 	}
+	if !c.readOnly {
+		if err := os.RemoveAll(filepath.Join(c.cacheDir, c.artifactSubdir())); err != nil {
+			return fmt.Errorf("failed to remove client archives: %w", err)
+		}
+	}
 
 	return nil
 }
 
 // Size returns the number of cache entries
 func (c *Cache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.entries)
 }
 
-// cacheFilePath returns the path to the cache metadata file
+// legacyCacheFileName is the cache file shared by all generator versions
+const legacyCacheFileName = "cache.json"
+
+// cacheFilePath returns the path to the cache metadata file for the generator version
 func (c *Cache) cacheFilePath() string {
-	return filepath.Join(c.cacheDir, "cache.json")
+	if c.generatorVersion == "" {
+		return filepath.Join(c.cacheDir, legacyCacheFileName)
+	}
+	return filepath.Join(c.cacheDir, "cache-"+sanitizeVersion(c.generatorVersion)+".json")
+}
+
+// sanitizeVersion makes a generator version safe for use in a file name
+func sanitizeVersion(version string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, version)
 }
 
-// save persists cache entries to disk
+// save persists cache entries to disk, merged with the entries other processes saved since, unless
+// the cache is read-only. The caller must hold c.mu (or own c exclusively).
 func (c *Cache) save() error {
-	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if c.readOnly {
+		return nil
+	}
+
+	// Runs sharing the cache directory take turns, each keeping the others' entries
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	c.mergeSaved()
+
+	file := cacheFile{Version: FormatVersion, Entries: c.entries, SharedHashes: c.sharedHashes}
+	data, err := json.MarshalIndent(file, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
-	cachePath := c.cacheFilePath()
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+	if err := writeFileAtomic(c.cacheFilePath(), data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
+	c.saved = snapshotEntries(c.entries)
 
 	return nil
 }
 
-// load reads cache entries from disk
+// load reads cache entries from disk, upgrading files written in an older format
 func (c *Cache) load() error {
 	cachePath := c.cacheFilePath()
 
 	// Check if cache file exists
 	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-		// No cache file yet, start with empty cache
-		return nil
+		// No cache file yet for this version; reuse matching entries from the shared legacy file
+		return c.loadLegacyEntries()
 	}
 
 	// Read cache file
@@ -207,16 +622,65 @@ func (c *Cache) load() error {
 		return fmt.Errorf("failed to read cache file: %w", err)
 	}
 
-	// Unmarshal cache entries
-	if err := json.Unmarshal(data, &c.entries); err != nil {
-		return fmt.Errorf("failed to unmarshal cache: %w", err)
+	file, version, err := decodeCacheFile(data)
+	if err != nil {
+		return err
+	}
+	c.entries = file.Entries
+	c.sharedHashes = file.SharedHashes
+	c.saved = snapshotEntries(c.entries)
+
+	// Apply a lowered limit to a cache written with a higher one
+	if c.evictLRU() > 0 && version == FormatVersion {
+		if err := c.save(); err != nil {
+			fmt.Printf("Warning: Failed to save cache after eviction: %v\n", err)
+		}
+	}
+
+	// Persist the upgraded format; the migrated entries stay usable even if that fails
+	if version < FormatVersion {
+		fmt.Printf("Migrated cache file from version %d to %d\n", version, FormatVersion)
+		if err := c.save(); err != nil {
+			fmt.Printf("Warning: Failed to save migrated cache: %v\n", err)
+		}
 	}
 
 	return nil
 }
 
-// PruneInvalid removes cache entries for specs that no longer exist
+// loadLegacyEntries seeds a version-specific cache with the entries for that version
+// from the shared cache.json written before caches were namespaced by version
+func (c *Cache) loadLegacyEntries() error {
+	if c.generatorVersion == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.cacheDir, legacyCacheFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy cache file: %w", err)
+	}
+
+	file, _, err := decodeCacheFile(data)
+	if err != nil {
+		return fmt.Errorf("failed to read legacy cache file: %w", err)
+	}
+	for specPath, entry := range file.Entries {
+		if entry.GeneratorVersion == c.generatorVersion {
+			c.entries[specPath] = entry
+		}
+	}
+	return nil
+}
+
+// PruneInvalid removes cache entries for specs that no longer exist, and the archived clients
+// of entries that were removed
 func (c *Cache) PruneInvalid() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	pruned := 0
 
 	for specPath := range c.entries {
@@ -231,6 +695,35 @@ func (c *Cache) PruneInvalid() (int, error) {
 			return pruned, fmt.Errorf("failed to save cache after pruning: %w", err)
 		}
 	}
+	if !c.readOnly {
+		c.removeOrphanedArtifacts()
+	}
 
 	return pruned, nil
 }
+
+// evictLRU removes the least-recently-used entries until at most maxEntries remain.
+// Returns the number of evicted entries. The caller must hold c.mu (or own c exclusively).
+func (c *Cache) evictLRU() int {
+	excess := len(c.entries) - c.maxEntries
+	if c.maxEntries <= 0 || excess <= 0 {
+		return 0
+	}
+
+	specPaths := make([]string, 0, len(c.entries))
+	for specPath := range c.entries {
+		specPaths = append(specPaths, specPath)
+	}
+	sort.Slice(specPaths, func(i, j int) bool {
+		ti, tj := c.entries[specPaths[i]].lastUsed(), c.entries[specPaths[j]].lastUsed()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return specPaths[i] < specPaths[j]
+	})
+
+	for _, specPath := range specPaths[:excess] {
+		delete(c.entries, specPath)
+	}
+	return excess
+}
@@ -1,10 +1,14 @@
 package cache
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
 )
 
 func TestNewCache(t *testing.T) {
@@ -86,21 +90,21 @@ func TestComputeFileHash(t *testing.T) {
 				t.Fatalf("Failed to create test file: %v", err)
 			}
 
-			hash1, err := computeFileHash(filePath)
+			hash1, err := ComputeFileHash(filePath)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("computeFileHash() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ComputeFileHash() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
 			if err == nil && hash1 == "" {
-				t.Error("computeFileHash() returned empty hash")
+				t.Error("ComputeFileHash() returned empty hash")
 			}
 
 			// Verify consistency
 			if tt.consistent {
-				hash2, err := computeFileHash(filePath)
+				hash2, err := ComputeFileHash(filePath)
 				if err != nil {
-					t.Errorf("Second computeFileHash() failed: %v", err)
+					t.Errorf("Second ComputeFileHash() failed: %v", err)
 				}
 				if hash1 != hash2 {
 					t.Errorf("Hash inconsistent: %s != %s", hash1, hash2)
@@ -111,9 +115,9 @@ func TestComputeFileHash(t *testing.T) {
 }
 
 func TestComputeFileHashNonexistent(t *testing.T) {
-	_, err := computeFileHash("/nonexistent/file.txt")
+	_, err := ComputeFileHash("/nonexistent/file.txt")
 	if err == nil {
-		t.Error("computeFileHash() should fail for nonexistent file")
+		t.Error("ComputeFileHash() should fail for nonexistent file")
 	}
 }
 
@@ -267,6 +271,124 @@ func TestCacheIsValid(t *testing.T) {
 	}
 }
 
+func TestCacheIsValidMetadataOnlyChange(t *testing.T) {
+	original := `{"openapi":"3.0.0","info":{"title":"Test","description":"Original"},"paths":{"/items":{"get":{"operationId":"listItems"}}}}`
+
+	tests := []struct {
+		name                   string
+		updated                string
+		regenerateOnDocChanges bool
+		wantValid              bool
+	}{
+		{
+			name:      "only info.description changed",
+			updated:   `{"openapi":"3.0.0","info":{"title":"Test","description":"Updated"},"paths":{"/items":{"get":{"operationId":"listItems"}}}}`,
+			wantValid: true,
+		},
+		{
+			name: "only formatting changed",
+			updated: `{
+				"paths": {"/items": {"get": {"operationId": "listItems"}}},
+				"info": {"description": "Original", "title": "Test"},
+				"openapi": "3.0.0"
+			}`,
+			wantValid: true,
+		},
+		{
+			name:                   "info.description changed with regenerate on doc changes",
+			updated:                `{"openapi":"3.0.0","info":{"title":"Test","description":"Updated"},"paths":{"/items":{"get":{"operationId":"listItems"}}}}`,
+			regenerateOnDocChanges: true,
+			wantValid:              false,
+		},
+		{
+			name:      "operation changed",
+			updated:   `{"openapi":"3.0.0","info":{"title":"Test","description":"Original"},"paths":{"/items":{"post":{"operationId":"createItem"}}}}`,
+			wantValid: false,
+		},
+		{
+			name:      "only servers changed",
+			updated:   `{"openapi":"3.0.0","info":{"title":"Test","description":"Original"},"servers":[{"url":"https://api.example.com"}],"paths":{"/items":{"get":{"operationId":"listItems"}}}}`,
+			wantValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			outputDir := filepath.Join(tmpDir, "output")
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				t.Fatalf("Failed to create output dir: %v", err)
+			}
+
+			cache, err := NewCache(Config{
+				CacheDir:               filepath.Join(tmpDir, "cache"),
+				RegenerateOnDocChanges: tt.regenerateOnDocChanges,
+			})
+			if err != nil {
+				t.Fatalf("NewCache() failed: %v", err)
+			}
+
+			specPath := filepath.Join(tmpDir, "openapi.json")
+			if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+				t.Fatalf("Failed to create spec file: %v", err)
+			}
+			if err := cache.Set(specPath, outputDir, "testservice", "v1.0.0"); err != nil {
+				t.Fatalf("Set() failed: %v", err)
+			}
+
+			if err := os.WriteFile(specPath, []byte(tt.updated), 0644); err != nil {
+				t.Fatalf("Failed to update spec file: %v", err)
+			}
+
+			valid, err := cache.IsValid(specPath, "v1.0.0")
+			if err != nil {
+				t.Fatalf("IsValid() error = %v", err)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("IsValid() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestCacheIsValidLatin1Spec(t *testing.T) {
+	spec.SetSpecEncoding("latin1")
+	t.Cleanup(func() { spec.SetSpecEncoding("") })
+
+	// The edit only touches a non-ASCII character in a code-affecting section (an enum value)
+	original := []byte("{\"openapi\":\"3.0.0\",\"paths\":{},\"components\":{\"schemas\":{\"Drink\":{\"type\":\"string\",\"enum\":[\"caf\xe9\"]}}}}")
+	updated := []byte("{\"openapi\":\"3.0.0\",\"paths\":{},\"components\":{\"schemas\":{\"Drink\":{\"type\":\"string\",\"enum\":[\"caf\xe8\"]}}}}")
+
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+	cache, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, original, 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+	if err := cache.Set(specPath, outputDir, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := os.WriteFile(specPath, updated, 0644); err != nil {
+		t.Fatalf("Failed to update spec file: %v", err)
+	}
+
+	valid, err := cache.IsValid(specPath, "v1.0.0")
+	if err != nil {
+		t.Fatalf("IsValid() error = %v", err)
+	}
+	if valid {
+		t.Error("IsValid() = true after a non-ASCII edit of a latin1 spec, want a miss")
+	}
+}
+
 func TestCacheInvalidate(t *testing.T) {
 	tmpDir := t.TempDir()
 	cacheDir := filepath.Join(tmpDir, "cache")
@@ -494,3 +616,308 @@ func TestCacheSize(t *testing.T) {
 		}
 	}
 }
+
+func TestCacheExcludeDeprecated(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+	cacheDir := filepath.Join(tmpDir, "cache")
+
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	original := `{"openapi":"3.0.0","paths":{"/items":{"get":{"operationId":"listItems"},"delete":{"operationId":"purge","deprecated":true}}}}`
+	if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+
+	cache, err := NewCache(Config{CacheDir: cacheDir, ExcludeDeprecated: true})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	if err := cache.Set(specPath, outputDir, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	// Changing only a deprecated (excluded) operation keeps the entry valid
+	updated := `{"openapi":"3.0.0","paths":{"/items":{"get":{"operationId":"listItems"},"delete":{"operationId":"purgeAll","deprecated":true}}}}`
+	if err := os.WriteFile(specPath, []byte(updated), 0644); err != nil {
+		t.Fatalf("Failed to update spec file: %v", err)
+	}
+	valid, err := cache.IsValid(specPath, "v1.0.0")
+	if err != nil || !valid {
+		t.Errorf("IsValid() = %v, %v; want valid when only excluded operations changed", valid, err)
+	}
+
+	// Entries generated with a different setting are invalid
+	including, err := NewCache(Config{CacheDir: cacheDir, ExcludeDeprecated: false})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	valid, err = including.IsValid(specPath, "v1.0.0")
+	if err != nil || valid {
+		t.Errorf("IsValid() = %v, %v; want invalid after toggling exclude_deprecated", valid, err)
+	}
+}
+
+func TestCacheDowngradeOpenAPI31(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.1.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+
+	cache, err := NewCache(Config{CacheDir: cacheDir, DowngradeOpenAPI31: true})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	if err := cache.Set(specPath, tmpDir, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if valid, err := cache.IsValid(specPath, "v1.0.0"); err != nil || !valid {
+		t.Errorf("IsValid() = %v, %v; want valid with the same setting", valid, err)
+	}
+
+	// Clients generated from converted specs are regenerated when the conversion is turned off
+	native, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	decision, err := native.DecideFor(specPath, "v1.0.0", native.describeFile(specPath))
+	if err != nil || decision.Hit || decision.Reason != ReasonConfigChanged {
+		t.Errorf("DecideFor() = %+v, %v; want a config_changed miss", decision, err)
+	}
+	if decision.StoredConfig != "exclude_deprecated:false,downgrade_openapi31:true" {
+		t.Errorf("StoredConfig = %q", decision.StoredConfig)
+	}
+}
+
+func TestCacheGeneratorConfigHash(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+	configPath := filepath.Join(tmpDir, "ogen.yml")
+	templatesDir := filepath.Join(tmpDir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatalf("Failed to create templates dir: %v", err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	write(configPath, "generator:\n  features: {}\n")
+	write(filepath.Join(templatesDir, "client.tmpl"), "package {{.Package}}\n")
+	inputs := []string{templatesDir, configPath}
+
+	hash := HashInputs(inputs)
+	if hash != HashInputs(inputs) || !strings.HasPrefix(hash, "sha256:") {
+		t.Fatalf("HashInputs() = %q, want a stable sha256 hash", hash)
+	}
+	cache, err := NewCache(Config{CacheDir: cacheDir, GeneratorConfigHash: hash})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	if err := cache.Set(specPath, tmpDir, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	// Editing the configuration, a template, or adding a template invalidates the entry
+	for name, edit := range map[string]func(){
+		"config":       func() { write(configPath, "generator:\n  features:\n    enable: [paths/client]\n") },
+		"template":     func() { write(filepath.Join(templatesDir, "client.tmpl"), "package {{.Name}}\n") },
+		"new template": func() { write(filepath.Join(templatesDir, "extra.tmpl"), "") },
+	} {
+		edit()
+		current, err := NewCache(Config{CacheDir: cacheDir, GeneratorConfigHash: HashInputs(inputs)})
+		if err != nil {
+			t.Fatalf("NewCache() failed: %v", err)
+		}
+		decision, err := current.DecideFor(specPath, "v1.0.0", current.describeFile(specPath))
+		if err != nil || decision.Hit || decision.Reason != ReasonConfigChanged {
+			t.Errorf("after changing the %s, DecideFor() = %+v, %v; want a config_changed miss", name, decision, err)
+		}
+	}
+	if decision, _ := cache.DecideFor(specPath, "v1.0.0", cache.describeFile(specPath)); !decision.Hit {
+		t.Errorf("DecideFor() with the stored hash = %+v, want a hit", decision)
+	}
+	if HashInputs([]string{filepath.Join(tmpDir, "missing.yml")}) == HashInputs(nil) {
+		t.Error("a missing input should change the hash")
+	}
+}
+
+func TestCacheIgnoreResponseHeaders(t *testing.T) {
+	original := `{"openapi":"3.0.0","paths":{"/items":{"get":{"responses":{"200":{"description":"ok","headers":{"X-Rate-Limit":{"schema":{"type":"integer"}}}}}}}}}`
+	updated := `{"openapi":"3.0.0","paths":{"/items":{"get":{"responses":{"200":{"description":"ok","headers":{"X-Rate-Limit":{"schema":{"type":"string"}}}}}}}}}`
+
+	tests := []struct {
+		name                  string
+		ignoreResponseHeaders bool
+		wantValid             bool
+	}{
+		{name: "header change regenerates", ignoreResponseHeaders: false, wantValid: false},
+		{name: "header change ignored", ignoreResponseHeaders: true, wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			outputDir := filepath.Join(tmpDir, "output")
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				t.Fatalf("Failed to create output dir: %v", err)
+			}
+
+			specPath := filepath.Join(tmpDir, "openapi.json")
+			if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+				t.Fatalf("Failed to create spec file: %v", err)
+			}
+
+			cache, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache"), IgnoreResponseHeaders: tt.ignoreResponseHeaders})
+			if err != nil {
+				t.Fatalf("NewCache() failed: %v", err)
+			}
+			if err := cache.Set(specPath, outputDir, "testservice", "v1.0.0"); err != nil {
+				t.Fatalf("Set() failed: %v", err)
+			}
+
+			if err := os.WriteFile(specPath, []byte(updated), 0644); err != nil {
+				t.Fatalf("Failed to update spec file: %v", err)
+			}
+			valid, err := cache.IsValid(specPath, "v1.0.0")
+			if err != nil || valid != tt.wantValid {
+				t.Errorf("IsValid() = %v, %v; want %v", valid, err, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestCheckSpecVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		cached   string
+		current  string
+		wantWarn string
+	}{
+		{name: "increase", cached: "1.2.3", current: "1.3.0"},
+		{name: "equal", cached: "1.2.3", current: "v1.2.3"},
+		{name: "decrease", cached: "1.2.3", current: "1.2.0", wantWarn: "decreased from 1.2.3 to 1.2.0"},
+		{name: "release to pre-release", cached: "2.0.0", current: "2.0.0-rc.1", wantWarn: "possible accidental downgrade"},
+		{name: "non-semver current", cached: "1.2.3", current: "2024-01-15", wantWarn: "cannot compare"},
+		{name: "unchanged non-semver", cached: "latest", current: "latest"},
+		{name: "no cached version", cached: "", current: "0.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			specPath := filepath.Join(tmpDir, "openapi.json")
+			writeVersion := func(version string) {
+				content := `{"openapi":"3.0.0","info":{"title":"svc","version":"` + version + `"},"paths":{}}`
+				if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write spec: %v", err)
+				}
+			}
+
+			c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+			if err != nil {
+				t.Fatalf("NewCache() error = %v", err)
+			}
+			writeVersion(tt.cached)
+			if err := c.Set(specPath, tmpDir, "svc", "v1"); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if entry, _ := c.Get(specPath); entry.SpecVersion != tt.cached {
+				t.Errorf("SpecVersion = %q, want %q", entry.SpecVersion, tt.cached)
+			}
+
+			err = c.CheckSpecVersion(specPath, tt.current)
+			if tt.wantWarn == "" {
+				if err != nil {
+					t.Errorf("CheckSpecVersion() = %v, want no warning", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantWarn) {
+				t.Errorf("CheckSpecVersion() = %v, want warning containing %q", err, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestCheckOperationCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "openapi.yaml")
+	var paths strings.Builder
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&paths, "  /op%d:\n    get: {operationId: op%d}\n", i, i)
+	}
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.0\npaths:\n"+paths.String()), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if err := c.Set(specPath, tmpDir, "svc", "v1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if entry, _ := c.Get(specPath); entry.OperationCount != 40 {
+		t.Fatalf("OperationCount = %d, want 40", entry.OperationCount)
+	}
+
+	tests := []struct {
+		name    string
+		current int
+		maxDrop float64
+		wantErr bool
+	}{
+		{name: "drop over the limit", current: 5, maxDrop: 0.5, wantErr: true},
+		{name: "drop at the limit", current: 20, maxDrop: 0.5},
+		{name: "drop under the limit", current: 25, maxDrop: 0.5},
+		{name: "lower limit", current: 25, maxDrop: 0.25, wantErr: true},
+		{name: "all operations removed", current: 0, maxDrop: 0.9, wantErr: true},
+		{name: "operations added", current: 60, maxDrop: 0.1},
+		{name: "check disabled", current: 1, maxDrop: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CheckOperationCount(specPath, tt.current, tt.maxDrop)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckOperationCount(%d, %v) = %v, wantErr %v", tt.current, tt.maxDrop, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := c.CheckOperationCount(filepath.Join(tmpDir, "other.json"), 0, 0.5); err != nil {
+		t.Errorf("CheckOperationCount() for a spec without an entry = %v, want nil", err)
+	}
+}
+
+func TestCacheReadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to create spec file: %v", err)
+	}
+
+	readOnly, err := NewCache(Config{CacheDir: cacheDir, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("NewCache() failed: %v", err)
+	}
+	if err := readOnly.Set(specPath, tmpDir, "testservice", "v1.0.0"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if valid, _ := readOnly.IsValid(specPath, "v1.0.0"); !valid {
+		t.Error("a read-only cache should still apply changes in memory")
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("a read-only cache should not write anything, stat error = %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// Reasons for a cache decision
+const (
+	ReasonHit               = "hit"
+	ReasonHitFingerprint    = "hit_fingerprint_match"
+	ReasonHitArtifact       = "hit_artifact"
+	ReasonNoEntry           = "no_entry"
+	ReasonGeneratorChanged  = "generator_version_changed"
+	ReasonConfigChanged     = "config_changed"
+	ReasonSpecChanged       = "spec_changed"
+	ReasonDependencyChanged = "dependency_changed"
+	ReasonOutputMissing     = "output_missing"
+	ReasonOutputPathChanged = "output_path_changed"
+	ReasonCheckFailed       = "check_failed"
+)
+
+// Fingerprint comparison summaries that are not a list of differing sections
+const (
+	fingerprintNotCompared   = "not_compared"
+	fingerprintEqual         = "equal"
+	fingerprintUnavailable   = "unavailable"
+	fingerprintDocChangeMode = "disabled"
+)
+
+// fingerprintDiffersPrefix starts the summary of differing fingerprint sections
+const fingerprintDiffersPrefix = "differs:"
+
+// Decision records the inputs and outcome of a cache validity check, so that unexpected
+// regenerations (or unexpected cache hits) can be explained
+type Decision struct {
+	// SpecPath is the spec that was checked
+	SpecPath string
+
+	// StoredHash and CurrentHash are the SHA256 of the spec when cached and now
+	StoredHash  string
+	CurrentHash string
+
+	// StoredGeneratorVersion and CurrentGeneratorVersion are the generator versions when
+	// cached and now
+	StoredGeneratorVersion  string
+	CurrentGeneratorVersion string
+
+	// StoredConfig and CurrentConfig summarize the cache-affecting settings when cached and now
+	StoredConfig  string
+	CurrentConfig string
+
+	// Fingerprint summarizes the fingerprint comparison: "not_compared" when the hash is
+	// unchanged, "equal", "differs:<sections>", "unavailable" when either side could not be
+	// computed, or "disabled" with regenerate_on_doc_changes
+	Fingerprint string
+
+	// Dependency is the $ref'd file that changed, with ReasonDependencyChanged
+	Dependency string
+
+	// OutputPath is the cached client directory
+	OutputPath string
+
+	// Hit reports whether the cached client is used
+	Hit bool
+
+	// Reason is the deciding factor, one of the Reason constants
+	Reason string
+}
+
+// String renders the decision as a single line of key=value pairs, e.g.
+// "spec=... decision=miss reason=spec_changed stored_hash=ab12... current_hash=cd34... ..."
+func (d Decision) String() string {
+	decision := "miss"
+	if d.Hit {
+		decision = "hit"
+	}
+	pairs := []struct{ key, value string }{
+		{"spec", d.SpecPath},
+		{"decision", decision},
+		{"reason", d.Reason},
+		{"stored_hash", d.StoredHash},
+		{"current_hash", d.CurrentHash},
+		{"stored_generator", d.StoredGeneratorVersion},
+		{"current_generator", d.CurrentGeneratorVersion},
+		{"stored_config", d.StoredConfig},
+		{"current_config", d.CurrentConfig},
+		{"fingerprint", d.Fingerprint},
+		{"dependency", d.Dependency},
+		{"output", d.OutputPath},
+	}
+
+	var b strings.Builder
+	for i, pair := range pairs {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		value := pair.value
+		if value == "" {
+			value = "-"
+		} else if strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&b, "%s=%s", pair.key, value)
+	}
+	return b.String()
+}
+
+// generationSettings are the settings that change generated code, recorded in entries;
+// an entry generated with other settings is invalid
+type generationSettings struct {
+	excludeDeprecated  bool
+	downgradeOpenAPI31 bool
+	convertSwagger2    bool
+	generatorConfig    string
+}
+
+// ChangedSections returns the fingerprint sections that differ from the cached spec, e.g.
+// ["operations", "schemas"], or nil if the fingerprints were not compared or are equal
+func (d Decision) ChangedSections() []string {
+	sections, ok := strings.CutPrefix(d.Fingerprint, fingerprintDiffersPrefix)
+	if !ok {
+		return nil
+	}
+	return strings.Split(sections, ",")
+}
+
+// configSummary describes the cache-affecting settings recorded in entries. Spec conversions
+// are only listed when enabled, so summaries of other setups stay as they were.
+func configSummary(settings generationSettings) string {
+	summary := fmt.Sprintf("exclude_deprecated:%v", settings.excludeDeprecated)
+	if settings.convertSwagger2 {
+		summary += ",convert_swagger2:true"
+	}
+	if settings.downgradeOpenAPI31 {
+		summary += ",downgrade_openapi31:true"
+	}
+	if settings.generatorConfig != "" {
+		summary += ",generator_config:" + settings.generatorConfig
+	}
+	return summary
+}
+
+// compareFingerprints summarizes how a cached fingerprint compares with the current one
+func compareFingerprints(stored, current *spec.Fingerprint) string {
+	if stored == nil || current == nil {
+		return fingerprintUnavailable
+	}
+
+	var differs []string
+	sections := []struct {
+		name            string
+		stored, current string
+	}{
+		{"version", stored.Version, current.Version},
+		{"operations", stored.Operations, current.Operations},
+		{"schemas", stored.Schemas, current.Schemas},
+		{"parameters", stored.Parameters, current.Parameters},
+		{"request_bodies", stored.RequestBodies, current.RequestBodies},
+		{"responses", stored.Responses, current.Responses},
+		{"headers", stored.Headers, current.Headers},
+		{"webhooks", stored.Webhooks, current.Webhooks},
+		{"servers", stored.Servers, current.Servers},
+		{"components", stored.ComponentsHash, current.ComponentsHash},
+		{"security", stored.Security, current.Security},
+	}
+	for _, section := range sections {
+		if section.stored != section.current {
+			differs = append(differs, section.name)
+		}
+	}
+	if len(differs) == 0 {
+		return fingerprintEqual
+	}
+	return fingerprintDiffersPrefix + strings.Join(differs, ",")
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+func TestDecideFor(t *testing.T) {
+	const original = `{"openapi":"3.0.0","info":{"title":"Funding","version":"1.0.0"},"paths":{"/a":{"get":{}}}}`
+
+	tests := []struct {
+		name             string
+		current          string // spec content at check time
+		generator        string
+		excludeDep       bool
+		removeOutput     bool
+		wantHit          bool
+		wantReason       string
+		wantFingerprint  string
+		wantCurrentMatch bool // current hash equals stored hash
+	}{
+		{name: "unchanged", current: original, generator: "v1", wantHit: true, wantReason: ReasonHit, wantFingerprint: fingerprintNotCompared, wantCurrentMatch: true},
+		{
+			name:            "metadata only",
+			current:         `{"openapi":"3.0.0","info":{"title":"Funding API","version":"1.0.1"},"paths":{"/a":{"get":{}}}}`,
+			generator:       "v1",
+			wantHit:         true,
+			wantReason:      ReasonHitFingerprint,
+			wantFingerprint: fingerprintEqual,
+		},
+		{
+			name:            "operations changed",
+			current:         `{"openapi":"3.0.0","info":{"title":"Funding","version":"1.0.0"},"paths":{"/b":{"get":{}}}}`,
+			generator:       "v1",
+			wantReason:      ReasonSpecChanged,
+			wantFingerprint: "differs:operations",
+		},
+		{name: "generator changed", current: original, generator: "v2", wantReason: ReasonGeneratorChanged, wantFingerprint: fingerprintNotCompared, wantCurrentMatch: true},
+		{name: "config changed", current: original, generator: "v1", excludeDep: true, wantReason: ReasonConfigChanged, wantFingerprint: fingerprintNotCompared, wantCurrentMatch: true},
+		{name: "output missing", current: original, generator: "v1", removeOutput: true, wantReason: ReasonOutputMissing, wantFingerprint: fingerprintNotCompared, wantCurrentMatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			outputDir := filepath.Join(tmpDir, "output")
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				t.Fatalf("Failed to create output dir: %v", err)
+			}
+			specPath := filepath.Join(tmpDir, "openapi.json")
+			if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+			if err != nil {
+				t.Fatalf("NewCache() error = %v", err)
+			}
+			if err := c.Set(specPath, outputDir, "funding", "v1"); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			if err := os.WriteFile(specPath, []byte(tt.current), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+			if tt.removeOutput {
+				os.RemoveAll(outputDir)
+			}
+			c.excludeDeprecated = tt.excludeDep
+
+			doc, err := spec.LoadDocument(specPath)
+			if err != nil {
+				t.Fatalf("LoadDocument() error = %v", err)
+			}
+			decision, err := c.DecideFor(specPath, tt.generator, c.Describe(doc))
+			if err != nil {
+				t.Fatalf("DecideFor() error = %v", err)
+			}
+
+			if decision.Hit != tt.wantHit || decision.Reason != tt.wantReason || decision.Fingerprint != tt.wantFingerprint {
+				t.Errorf("decision = hit %v, reason %q, fingerprint %q; want %v, %q, %q",
+					decision.Hit, decision.Reason, decision.Fingerprint, tt.wantHit, tt.wantReason, tt.wantFingerprint)
+			}
+			if decision.StoredHash == "" || decision.CurrentHash == "" || (decision.StoredHash == decision.CurrentHash) != tt.wantCurrentMatch {
+				t.Errorf("hashes stored %q, current %q, want equal = %v", decision.StoredHash, decision.CurrentHash, tt.wantCurrentMatch)
+			}
+			if decision.StoredGeneratorVersion != "v1" || decision.CurrentGeneratorVersion != tt.generator {
+				t.Errorf("generator versions = %q, %q", decision.StoredGeneratorVersion, decision.CurrentGeneratorVersion)
+			}
+			if decision.StoredConfig != "exclude_deprecated:false" || decision.CurrentConfig != configSummary(generationSettings{excludeDeprecated: tt.excludeDep}) {
+				t.Errorf("config = %q, %q", decision.StoredConfig, decision.CurrentConfig)
+			}
+		})
+	}
+}
+
+func TestDecideForWithoutEntry(t *testing.T) {
+	c, err := NewCache(Config{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	decision, err := c.DecideFor("/specs/openapi.json", "v1", SpecInfo{})
+	if err != nil {
+		t.Fatalf("DecideFor() error = %v", err)
+	}
+	if decision.Hit || decision.Reason != ReasonNoEntry {
+		t.Errorf("decision = %+v, want a no_entry miss", decision)
+	}
+}
+
+func TestDecisionString(t *testing.T) {
+	decision := Decision{
+		SpecPath:                "specs/my service/openapi.json",
+		StoredHash:              "abc",
+		CurrentHash:             "def",
+		StoredGeneratorVersion:  "v1",
+		CurrentGeneratorVersion: "v1",
+		StoredConfig:            "exclude_deprecated:false",
+		CurrentConfig:           "exclude_deprecated:false",
+		Fingerprint:             "differs:operations,schemas",
+		Reason:                  ReasonSpecChanged,
+	}
+	want := `spec="specs/my service/openapi.json" decision=miss reason=spec_changed stored_hash=abc current_hash=def ` +
+		`stored_generator=v1 current_generator=v1 stored_config=exclude_deprecated:false current_config=exclude_deprecated:false ` +
+		`fingerprint=differs:operations,schemas dependency=- output=-`
+	if got := decision.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if strings.Contains(decision.String(), "\n") {
+		t.Error("String() should be a single line")
+	}
+}
+
+func TestDecisionChangedSections(t *testing.T) {
+	tests := []struct {
+		fingerprint string
+		want        []string
+	}{
+		{"differs:operations,schemas", []string{"operations", "schemas"}},
+		{fingerprintEqual, nil},
+		{fingerprintNotCompared, nil},
+		{fingerprintDocChangeMode, nil},
+	}
+	for _, tt := range tests {
+		got := Decision{Fingerprint: tt.fingerprint}.ChangedSections()
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || (got == nil) != (tt.want == nil) {
+			t.Errorf("ChangedSections() for %q = %v, want %v", tt.fingerprint, got, tt.want)
+		}
+	}
+}
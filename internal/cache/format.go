@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FormatVersion is the current version of the cache file format.
+// Version 1 was a bare JSON object of entries keyed by spec path.
+const FormatVersion = 2
+
+// cacheFile is the on-disk cache format
+type cacheFile struct {
+	// Version is the cache file format version
+	Version int `json:"version"`
+	// Entries are the cache entries keyed by spec path
+	Entries map[string]*Entry `json:"entries"`
+	// SharedHashes are the hashes of shared component files keyed by path
+	SharedHashes map[string]string `json:"shared_hashes,omitempty"`
+}
+
+// decodeCacheFile decodes a cache file, migrating older formats to the current one.
+// Returns the version the data was written with, so callers can persist migrated files.
+// Files written by a newer version cannot be read and return an error.
+func decodeCacheFile(data []byte) (*cacheFile, int, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal cache: %w", err)
+	}
+
+	// Version 1 files have no version field; their keys are spec paths
+	version := 1
+	if raw, ok := probe["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, 0, fmt.Errorf("invalid cache file version: %w", err)
+		}
+	}
+
+	file := &cacheFile{Version: FormatVersion}
+	switch {
+	case version == 1:
+		if err := json.Unmarshal(data, &file.Entries); err != nil {
+			return nil, version, fmt.Errorf("failed to migrate version 1 cache: %w", err)
+		}
+	case version == FormatVersion:
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, version, fmt.Errorf("failed to unmarshal cache: %w", err)
+		}
+	default:
+		return nil, version, fmt.Errorf("unsupported cache file version %d (supported: 1-%d)", version, FormatVersion)
+	}
+
+	if file.Entries == nil {
+		file.Entries = make(map[string]*Entry)
+	}
+	return file, version, nil
+}
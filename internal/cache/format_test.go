@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCacheMigratesVersion1File(t *testing.T) {
+	cacheDir := t.TempDir()
+	specPath := filepath.Join(cacheDir, "specs", "funding", "openapi.json")
+
+	// Version 1 stored entries as a bare object keyed by spec path
+	legacy := `{
+  "` + specPath + `": {
+    "spec_hash": "abc123",
+    "output_path": "/generated/clients/fundingsdk",
+    "service_name": "funding",
+    "generator_version": "v1.14.0"
+  }
+}`
+	cachePath := filepath.Join(cacheDir, "cache.json")
+	if err := os.WriteFile(cachePath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy cache: %v", err)
+	}
+
+	c, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	entry, ok := c.Get(specPath)
+	if !ok {
+		t.Fatal("legacy entry should survive migration")
+	}
+	if entry.ServiceName != "funding" || entry.SpecHash != "abc123" {
+		t.Errorf("migrated entry = %+v", entry)
+	}
+
+	// The file is rewritten in the current format
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Failed to decode migrated cache: %v", err)
+	}
+	if file.Version != FormatVersion {
+		t.Errorf("migrated file version = %d, want %d", file.Version, FormatVersion)
+	}
+	if _, ok := file.Entries[specPath]; !ok {
+		t.Errorf("migrated file entries = %v, want entry for %s", file.Entries, specPath)
+	}
+}
+
+func TestNewCacheDiscardsUnreadableFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "newer version", content: `{"version": 99, "entries": {"/specs/a/openapi.json": {"spec_hash": "x"}}}`},
+		{name: "invalid version", content: `{"version": "two", "entries": {}}`},
+		{name: "corrupt file", content: `{"not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(cacheDir, "cache.json"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write cache file: %v", err)
+			}
+
+			c, err := NewCache(Config{CacheDir: cacheDir})
+			if err != nil {
+				t.Fatalf("NewCache() error = %v, want unreadable cache discarded", err)
+			}
+			if c.Size() != 0 {
+				t.Errorf("Size() = %d, want 0", c.Size())
+			}
+		})
+	}
+}
+
+func TestCacheFileRoundTrip(t *testing.T) {
+	cacheDir := t.TempDir()
+	c, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	specPath := filepath.Join(cacheDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := c.Set(specPath, cacheDir, "svc", "v1.0.0"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, "cache.json"))
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	file, version, err := decodeCacheFile(data)
+	if err != nil {
+		t.Fatalf("decodeCacheFile() error = %v", err)
+	}
+	entries := file.Entries
+	if version != FormatVersion {
+		t.Errorf("version = %d, want %d", version, FormatVersion)
+	}
+	if entries[specPath] == nil || entries[specPath].ServiceName != "svc" {
+		t.Errorf("entries = %v, want entry for svc", entries)
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// lock takes the advisory lock of the cache file, waiting while another process sharing the
+// cache directory holds it, and returns the function that releases it
+func (c *Cache) lock() (func(), error) {
+	file, err := os.OpenFile(c.cacheFilePath()+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache lock: %w", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock cache: %w", err)
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
+
+// snapshotEntries copies the entries as last read from or written to the cache file
+func snapshotEntries(entries map[string]*Entry) map[string]Entry {
+	snapshot := make(map[string]Entry, len(entries))
+	for specPath, entry := range entries {
+		snapshot[specPath] = *entry
+	}
+	return snapshot
+}
+
+// savedEntries returns the entries of the cache file, or nil if it is missing or unreadable
+func (c *Cache) savedEntries() map[string]*Entry {
+	data, err := os.ReadFile(c.cacheFilePath())
+	if err != nil {
+		return nil
+	}
+	file, _, err := decodeCacheFile(data)
+	if err != nil {
+		return nil
+	}
+	return file.Entries
+}
+
+// mergeSaved applies the entries this process added, changed or removed since it last read or
+// wrote the cache file to the current entries of the file, so runs sharing the cache directory
+// keep each other's entries. The caller must hold c.mu and the file lock.
+func (c *Cache) mergeSaved() {
+	merged := c.savedEntries()
+	if merged == nil {
+		return
+	}
+	for specPath, entry := range c.entries {
+		if saved, ok := c.saved[specPath]; !ok || !reflect.DeepEqual(saved, *entry) {
+			merged[specPath] = entry
+		}
+	}
+	for specPath := range c.saved {
+		if _, ok := c.entries[specPath]; !ok {
+			delete(merged, specPath)
+		}
+	}
+	c.entries = merged
+	c.evictLRU()
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over path, so
+// readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package cache
+
+import "os"
+
+// lockFile is a no-op without flock; the cache file is still replaced atomically, but
+// concurrent runs may drop each other's entries
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op without flock
+func unlockFile(file *os.File) error {
+	return nil
+}
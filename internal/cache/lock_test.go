@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCacheSharedBetweenRuns(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specs := writeSpecs(t, tmpDir, 8)
+
+	// Two runs open the same cache directory before either saves, as parallel CI jobs would
+	first, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	second, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i, specPath := range specs {
+		run := first
+		if i%2 == 1 {
+			run = second
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := run.Set(specPath, tmpDir, "svc", "v1"); err != nil {
+				t.Errorf("Set() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reloaded, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if reloaded.Size() != len(specs) {
+		t.Errorf("reloaded Size() = %d, want the entries of both runs (%d)", reloaded.Size(), len(specs))
+	}
+
+	// A removal is kept too, while the entries the run did not touch follow the file
+	if err := first.Invalidate(specs[0]); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if err := second.Invalidate(specs[1]); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if reloaded, _ = NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1"}); reloaded.Size() != len(specs)-2 {
+		t.Errorf("reloaded Size() = %d, want both removals (%d)", reloaded.Size(), len(specs)-2)
+	}
+	if _, ok := second.Get(specs[0]); ok {
+		t.Error("an entry removed by the other run should be dropped on save")
+	}
+
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatalf("Failed to read cache dir: %v", err)
+	}
+	for _, file := range files {
+		if strings.Contains(file.Name(), ".tmp-") {
+			t.Errorf("temporary file %s left in the cache directory", file.Name())
+		}
+	}
+}
+
+func TestOrphanedArtifactsOfOtherRunsKept(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specs := writeSpecs(t, tmpDir, 2)
+	config := Config{CacheDir: cacheDir, GeneratorVersion: "v1", Artifacts: true}
+
+	first, err := NewCache(config)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	second, err := NewCache(config)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	// The second run archived a client but has not saved its entry yet
+	name, err := second.storeArtifact(specs[1], tmpDir)
+	if err != nil {
+		t.Fatalf("storeArtifact() error = %v", err)
+	}
+	if err := first.Set(specs[0], tmpDir, "svc", "v1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := first.PruneInvalid(); err != nil {
+		t.Fatalf("PruneInvalid() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, name)); err != nil {
+		t.Errorf("a recent archive of another run was removed: %v", err)
+	}
+}
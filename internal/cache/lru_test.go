@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSpecs creates n spec files in dir and returns their paths
+func writeSpecs(t *testing.T, dir string, n int) []string {
+	t.Helper()
+	specs := make([]string, n)
+	for i := range specs {
+		specs[i] = filepath.Join(dir, fmt.Sprintf("spec%d.json", i))
+		content := fmt.Sprintf(`{"openapi":"3.0.0","info":{"title":"svc%d"}}`, i)
+		if err := os.WriteFile(specs[i], []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+	return specs
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+	specs := writeSpecs(t, tmpDir, 4)
+
+	c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache"), MaxEntries: 3})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	for _, specPath := range specs[:3] {
+		if err := c.Set(specPath, outputDir, "svc", "v1"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	// Age the entries so spec0 is the most recently used after its hit below
+	base := time.Now().Add(-time.Hour)
+	for i, specPath := range specs[:3] {
+		c.entries[specPath].LastUsed = base.Add(time.Duration(i) * time.Minute)
+	}
+	if valid, err := c.IsValid(specs[0], "v1"); err != nil || !valid {
+		t.Fatalf("IsValid(spec0) = %v, %v; want hit", valid, err)
+	}
+
+	// Adding a fourth entry evicts spec1, the least recently used
+	if err := c.Set(specs[3], outputDir, "svc", "v1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if c.Size() != 3 {
+		t.Errorf("Size() = %d, want 3", c.Size())
+	}
+	if _, ok := c.Get(specs[1]); ok {
+		t.Error("spec1 should have been evicted as least recently used")
+	}
+	for _, i := range []int{0, 2, 3} {
+		if _, ok := c.Get(specs[i]); !ok {
+			t.Errorf("spec%d should still be cached", i)
+		}
+	}
+
+	// LastUsed is persisted and the eviction survives a reload
+	reloaded, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache"), MaxEntries: 3})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if reloaded.Size() != 3 {
+		t.Errorf("reloaded Size() = %d, want 3", reloaded.Size())
+	}
+	entry, ok := reloaded.Get(specs[0])
+	if !ok || entry.LastUsed.Before(base.Add(time.Hour-time.Minute)) {
+		t.Errorf("spec0 LastUsed was not persisted after its cache hit: %+v", entry)
+	}
+}
+
+func TestCacheMaxEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		add        int
+		wantSize   int
+	}{
+		{name: "unlimited", maxEntries: 0, add: 5, wantSize: 5},
+		{name: "under limit", maxEntries: 10, add: 5, wantSize: 5},
+		{name: "over limit", maxEntries: 2, add: 5, wantSize: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			specs := writeSpecs(t, tmpDir, tt.add)
+
+			c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache"), MaxEntries: tt.maxEntries})
+			if err != nil {
+				t.Fatalf("NewCache() error = %v", err)
+			}
+			for _, specPath := range specs {
+				if err := c.Set(specPath, tmpDir, "svc", "v1"); err != nil {
+					t.Fatalf("Set() error = %v", err)
+				}
+			}
+
+			if c.Size() != tt.wantSize {
+				t.Errorf("Size() = %d, want %d", c.Size(), tt.wantSize)
+			}
+			// The most recently added entry is never the one evicted
+			if _, ok := c.Get(specs[len(specs)-1]); !ok {
+				t.Error("most recently added entry should be cached")
+			}
+		})
+	}
+}
+
+func TestCacheLoweredLimitTrimsOnLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specs := writeSpecs(t, tmpDir, 4)
+
+	c, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	for _, specPath := range specs {
+		if err := c.Set(specPath, tmpDir, "svc", "v1"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	limited, err := NewCache(Config{CacheDir: cacheDir, MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if limited.Size() != 2 {
+		t.Errorf("Size() = %d, want 2 after loading with a lower limit", limited.Size())
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entries returns a copy of the cache entries, keyed by spec path
+func (c *Cache) Entries() map[string]Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make(map[string]Entry, len(c.entries))
+	for specPath, entry := range c.entries {
+		entries[specPath] = *entry
+	}
+	return entries
+}
+
+// LastUsedAt returns when the entry was last written or served as a cache hit
+func (e *Entry) LastUsedAt() time.Time {
+	return e.lastUsed()
+}
+
+// FilePath returns the cache file of the generator version
+func (c *Cache) FilePath() string {
+	return c.cacheFilePath()
+}
+
+// DiskUsage returns the size in bytes of the cache file and of the client archives of the
+// generator version; missing files count as empty
+func (c *Cache) DiskUsage() (fileBytes, artifactBytes int64) {
+	if info, err := os.Stat(c.cacheFilePath()); err == nil {
+		fileBytes = info.Size()
+	}
+	filepath.WalkDir(filepath.Join(c.cacheDir, c.artifactSubdir()), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			artifactBytes += info.Size()
+		}
+		return nil
+	})
+	return fileBytes, artifactBytes
+}
+
+// PruneOlderThan removes the entries that were not written or served as a cache hit within
+// age, and their client archives. Returns the number of removed entries.
+func (c *Cache) PruneOlderThan(age time.Duration) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := time.Now().Add(-age)
+	pruned := 0
+	for specPath, entry := range c.entries {
+		if entry.lastUsed().Before(cutoff) {
+			delete(c.entries, specPath)
+			pruned++
+		}
+	}
+
+	if pruned > 0 {
+		if err := c.save(); err != nil {
+			return pruned, fmt.Errorf("failed to save cache after pruning: %w", err)
+		}
+	}
+	if !c.readOnly {
+		c.removeOrphanedArtifacts()
+	}
+	return pruned, nil
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCachePruneOlderThan(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+	specs := writeSpecs(t, tmpDir, 3)
+	cacheDir := filepath.Join(tmpDir, "cache")
+
+	c, err := NewCache(Config{CacheDir: cacheDir, Artifacts: true})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	for _, specPath := range specs {
+		if err := c.Set(specPath, outputDir, "svc", "v1"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	// The first spec was generated long ago, the second was generated long ago but used since
+	c.entries[specs[0]].LastUsed = time.Now().Add(-48 * time.Hour)
+	c.entries[specs[1]].GeneratedAt = time.Now().Add(-48 * time.Hour)
+	prunedArtifact := filepath.Join(cacheDir, c.entries[specs[0]].Artifact)
+
+	fileBytes, artifactBytes := c.DiskUsage()
+	if fileBytes == 0 || artifactBytes == 0 {
+		t.Errorf("DiskUsage() = %d, %d; want the cache file and archives counted", fileBytes, artifactBytes)
+	}
+
+	pruned, err := c.PruneOlderThan(24 * time.Hour)
+	if err != nil || pruned != 1 {
+		t.Fatalf("PruneOlderThan() = %d, %v; want 1 pruned", pruned, err)
+	}
+	entries := c.Entries()
+	if _, ok := entries[specs[0]]; ok || len(entries) != 2 {
+		t.Errorf("Entries() after pruning = %v, want the unused entry removed", entries)
+	}
+	if _, err := os.Stat(prunedArtifact); !os.IsNotExist(err) {
+		t.Errorf("archive of the pruned entry should be removed, stat error = %v", err)
+	}
+
+	reloaded, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if reloaded.Size() != 2 {
+		t.Errorf("reloaded Size() = %d, want the pruning persisted", reloaded.Size())
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, artifactBytes := c.DiskUsage(); artifactBytes != 0 {
+		t.Errorf("archives left after Clear() = %d bytes, want none", artifactBytes)
+	}
+}
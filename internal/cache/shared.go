@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// InvalidateOnSharedChanges compares the hashes of shared component files (e.g., a components
+// file that many specs $ref) with those recorded in the cache. If any file changed, appeared,
+// disappeared or was never recorded, all entries are invalidated so every client is regenerated.
+// The current hashes are recorded for the next run. Returns whether the cache was invalidated.
+func (c *Cache) InvalidateOnSharedChanges(sharedFiles []string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := make(map[string]string, len(sharedFiles))
+	for _, path := range sharedFiles {
+		hash, err := ComputeFileHash(path)
+		if err != nil {
+			if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+				return false, fmt.Errorf("failed to hash shared component file %s: %w", path, err)
+			}
+			// A missing file is recorded as such, so deleting or restoring it counts as a change
+			hash = ""
+		}
+		current[path] = hash
+	}
+
+	changed := !sameHashes(c.sharedHashes, current)
+	if !changed {
+		return false, nil
+	}
+
+	if len(current) == 0 {
+		c.sharedHashes = nil
+	} else {
+		c.sharedHashes = current
+		c.entries = make(map[string]*Entry)
+	}
+	if err := c.save(); err != nil {
+		return false, fmt.Errorf("failed to save cache after shared component change: %w", err)
+	}
+	return len(current) > 0, nil
+}
+
+// sameHashes reports whether two path-to-hash maps are identical
+func sameHashes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, hash := range a {
+		if other, ok := b[path]; !ok || other != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// changedDependency returns the first path, in sorted order, whose hash differs between the
+// dependencies recorded in an entry and the current ones, including files only one side
+// lists. Returns "" if they match.
+func changedDependency(stored, current map[string]string) string {
+	var changed []string
+	for path, hash := range current {
+		if other, ok := stored[path]; !ok || other != hash {
+			changed = append(changed, path)
+		}
+	}
+	for path := range stored {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+	sort.Strings(changed)
+	return changed[0]
+}
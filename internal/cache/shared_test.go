@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInvalidateOnSharedChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	sharedPath := filepath.Join(tmpDir, "components.yaml")
+	if err := os.WriteFile(sharedPath, []byte("components: {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+
+	var specs []string
+	for _, name := range []string{"funding", "holidays"} {
+		specPath := filepath.Join(tmpDir, name+".json")
+		if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		specs = append(specs, specPath)
+	}
+
+	// First run records the shared hashes and caches both specs
+	c, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if _, err := c.InvalidateOnSharedChanges([]string{sharedPath}); err != nil {
+		t.Fatalf("InvalidateOnSharedChanges() error = %v", err)
+	}
+	for _, specPath := range specs {
+		if err := c.Set(specPath, tmpDir, filepath.Base(specPath), "v1.0.0"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	// Unchanged shared file keeps the cache
+	c, err = NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	invalidated, err := c.InvalidateOnSharedChanges([]string{sharedPath})
+	if err != nil {
+		t.Fatalf("InvalidateOnSharedChanges() error = %v", err)
+	}
+	if invalidated {
+		t.Error("unchanged shared file should not invalidate the cache")
+	}
+	for _, specPath := range specs {
+		if valid, _ := c.IsValid(specPath, "v1.0.0"); !valid {
+			t.Errorf("%s should be a cache hit", specPath)
+		}
+	}
+
+	// Changing the shared file marks every spec for regeneration
+	if err := os.WriteFile(sharedPath, []byte("components: {schemas: {}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to update shared file: %v", err)
+	}
+	c, err = NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	invalidated, err = c.InvalidateOnSharedChanges([]string{sharedPath})
+	if err != nil {
+		t.Fatalf("InvalidateOnSharedChanges() error = %v", err)
+	}
+	if !invalidated {
+		t.Error("changed shared file should invalidate the cache")
+	}
+	for _, specPath := range specs {
+		if valid, _ := c.IsValid(specPath, "v1.0.0"); valid {
+			t.Errorf("%s should be regenerated after a shared component change", specPath)
+		}
+	}
+
+	// The new hashes are persisted, so the next run is stable again
+	c, err = NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if invalidated, _ := c.InvalidateOnSharedChanges([]string{sharedPath}); invalidated {
+		t.Error("shared hashes should be persisted after invalidation")
+	}
+}
+
+func TestInvalidateOnSharedChangesMissingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	sharedPath := filepath.Join(tmpDir, "components.yaml")
+	if err := os.WriteFile(sharedPath, []byte("a: 1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+	if _, err := c.InvalidateOnSharedChanges([]string{sharedPath}); err != nil {
+		t.Fatalf("InvalidateOnSharedChanges() error = %v", err)
+	}
+
+	// Deleting a shared file counts as a change rather than an error
+	if err := os.Remove(sharedPath); err != nil {
+		t.Fatalf("Failed to remove shared file: %v", err)
+	}
+	invalidated, err := c.InvalidateOnSharedChanges([]string{sharedPath})
+	if err != nil {
+		t.Fatalf("InvalidateOnSharedChanges() error = %v", err)
+	}
+	if !invalidated {
+		t.Error("deleted shared file should invalidate the cache")
+	}
+}
+
+func TestInvalidateOnSharedChangesNoFiles(t *testing.T) {
+	c, err := NewCache(Config{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	invalidated, err := c.InvalidateOnSharedChanges(nil)
+	if err != nil || invalidated {
+		t.Errorf("InvalidateOnSharedChanges(nil) = %v, %v, want false, nil", invalidated, err)
+	}
+}
+
+func TestDependencyChangeInvalidatesEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	sharedPath := filepath.Join(tmpDir, "shared", "components.yaml")
+	if err := os.MkdirAll(filepath.Dir(sharedPath), 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	if err := os.WriteFile(sharedPath, []byte("Pet:\n  type: object\n"), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	content := `{"openapi":"3.0.0","components":{"schemas":{"Pet":{"$ref":"shared/components.yaml#/Pet"}}}}`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	c, err := NewCache(Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if err := c.Set(specPath, tmpDir, "pets", "v1.0.0"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	entry, _ := c.Get(specPath)
+	if _, ok := entry.Dependencies[sharedPath]; !ok || len(entry.Dependencies) != 1 {
+		t.Fatalf("entry dependencies = %v, want %s", entry.Dependencies, sharedPath)
+	}
+	if valid, _ := c.IsValid(specPath, "v1.0.0"); !valid {
+		t.Error("unchanged dependency should be a cache hit")
+	}
+
+	if err := os.WriteFile(sharedPath, []byte("Pet:\n  type: string\n"), 0644); err != nil {
+		t.Fatalf("Failed to update shared file: %v", err)
+	}
+	info := c.describeFile(specPath)
+	decision, err := c.DecideFor(specPath, "v1.0.0", info)
+	if err != nil {
+		t.Fatalf("DecideFor() error = %v", err)
+	}
+	if decision.Hit || decision.Reason != ReasonDependencyChanged || decision.Dependency != sharedPath {
+		t.Errorf("decision = %+v, want a dependency_changed miss naming %s", decision, sharedPath)
+	}
+}
+
+func TestChangedDependency(t *testing.T) {
+	tests := []struct {
+		name            string
+		stored, current map[string]string
+		want            string
+	}{
+		{name: "none", want: ""},
+		{name: "equal", stored: map[string]string{"a": "1"}, current: map[string]string{"a": "1"}, want: ""},
+		{name: "changed hash", stored: map[string]string{"a": "1", "b": "1"}, current: map[string]string{"a": "1", "b": "2"}, want: "b"},
+		{name: "new dependency", stored: nil, current: map[string]string{"a": "1"}, want: "a"},
+		{name: "dropped dependency", stored: map[string]string{"a": "1"}, current: nil, want: "a"},
+		{name: "first in sorted order", stored: map[string]string{"b": "1"}, current: map[string]string{"a": "1"}, want: "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changedDependency(tt.stored, tt.current); got != tt.want {
+				t.Errorf("changedDependency() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachePerGeneratorVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	// Generate with v1.14.0, then v1.15.0
+	for _, version := range []string{"v1.14.0", "v1.15.0"} {
+		c, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: version})
+		if err != nil {
+			t.Fatalf("NewCache(%s) error = %v", version, err)
+		}
+		if valid, _ := c.IsValid(specPath, version); valid {
+			t.Errorf("%s: unexpected cache hit before first generation", version)
+		}
+		if err := c.Set(specPath, outputDir, "svc", version); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	for _, name := range []string{"cache-v1.14.0.json", "cache-v1.15.0.json"} {
+		if _, err := os.Stat(filepath.Join(cacheDir, name)); err != nil {
+			t.Errorf("expected cache file %s: %v", name, err)
+		}
+	}
+
+	// Switching back to v1.14.0 reuses its own cache
+	c, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.14.0"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	valid, err := c.IsValid(specPath, "v1.14.0")
+	if err != nil {
+		t.Fatalf("IsValid() error = %v", err)
+	}
+	if !valid {
+		t.Error("switching back to v1.14.0 should be a cache hit")
+	}
+
+	// Invalidating one version leaves the other untouched
+	if err := c.Invalidate(specPath); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	other, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.15.0"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if valid, _ := other.IsValid(specPath, "v1.15.0"); !valid {
+		t.Error("v1.15.0 cache should be independent of v1.14.0")
+	}
+}
+
+func TestCachePerGeneratorVersionSeedsFromLegacyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(tmpDir, "cache")
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	// A shared cache.json from before caches were namespaced
+	legacy, err := NewCache(Config{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if err := legacy.Set(specPath, tmpDir, "svc", "v1.14.0"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	matching, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.14.0"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if matching.Size() != 1 {
+		t.Errorf("Size() = %d, want legacy entry for the same version", matching.Size())
+	}
+
+	different, err := NewCache(Config{CacheDir: cacheDir, GeneratorVersion: "v1.15.0"})
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	if different.Size() != 0 {
+		t.Errorf("Size() = %d, want no legacy entries from another version", different.Size())
+	}
+}
+
+func TestSanitizeVersion(t *testing.T) {
+	if got := sanitizeVersion("v1.15.0+dirty/local"); got != "v1.15.0_dirty_local" {
+		t.Errorf("sanitizeVersion() = %q", got)
+	}
+}
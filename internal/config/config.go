@@ -3,13 +3,44 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// DefaultClientsSubdir is the default directory under output_dir holding the clients
+const DefaultClientsSubdir = "clients"
+
+// DefaultFacadePackagePath is the default directory under output_dir of the facade package
+const DefaultFacadePackagePath = "api"
+
+// DefaultLockFile is the default spec checksum lockfile, relative to the repository root
+const DefaultLockFile = "openapi.lock"
+
+// facadePackageNamePattern matches the last element of facade_package_path, which is used
+// as the package name
+var facadePackageNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Output modes for generated clients
+const (
+	// OutputModeCentral writes every client to <output_dir>/<clients_subdir>/<service>sdk
+	OutputModeCentral = "central"
+
+	// OutputModeAlongsideSpec writes each client to a "client" directory next to its spec
+	OutputModeAlongsideSpec = "alongside-spec"
+
+	// OutputModeModulePerService uses the central layout and makes each client its own Go module
+	OutputModeModulePerService = "module-per-service"
 )
 
 // Config holds all configuration parameters for the application
@@ -20,6 +51,29 @@ type Config struct {
 	// OutputDir is the base directory where generated clients will be stored
 	OutputDir string `mapstructure:"output_dir"`
 
+	// OutputMode selects where generated clients are written:
+	// "central" (<output_dir>/clients/<service>sdk), "alongside-spec" (<spec dir>/client)
+	// or "module-per-service" (central layout with a go.mod in each client)
+	// Default: central
+	OutputMode string `mapstructure:"output_mode"`
+
+	// ClientsSubdir is the directory under OutputDir holding the clients in the central layouts.
+	// An explicitly empty value places clients directly under OutputDir.
+	// Default: clients
+	ClientsSubdir string `mapstructure:"clients_subdir"`
+
+	// ModulePathPrefix is the module path prefix for module-per-service output;
+	// each client's module path is <prefix>/<service>sdk (e.g., "github.com/acme/sdks")
+	ModulePathPrefix string `mapstructure:"module_path_prefix"`
+
+	// ModuleGoVersion is the go directive written to each client's go.mod in module-per-service output
+	// Default: 1.24
+	ModuleGoVersion string `mapstructure:"module_go_version"`
+
+	// ModuleTidy runs `go mod tidy` in each client module after writing its go.mod
+	// Default: false
+	ModuleTidy bool `mapstructure:"module_tidy"`
+
 	// TargetServices is a regular expression pattern to filter services
 	// Empty string matches all services
 	TargetServices string `mapstructure:"target_services"`
@@ -32,6 +86,59 @@ type Config struct {
 	// Default: 4
 	WorkerCount int `mapstructure:"worker_count"`
 
+	// PostProcessConcurrency limits how many services are post-processed at once,
+	// regardless of the worker count (e.g., to avoid running many compile checks together)
+	// Default: 0 (no limit beyond the worker count)
+	PostProcessConcurrency int `mapstructure:"post_process_concurrency"`
+
+	// IOConcurrency limits how many spec files are read or hashed at once (discovery checks,
+	// validation, fingerprinting), independently of the CPU-bound generation workers
+	// Default: 0 (no limit)
+	IOConcurrency int `mapstructure:"io_concurrency"`
+
+	// MaxOutputBytes caps the total size of each generated client directory; a larger client
+	// fails with POST_PROCESS_FAILED and its partial output is removed
+	// Default: 0 (no limit)
+	MaxOutputBytes int64 `mapstructure:"max_output_bytes"`
+
+	// ConsecutiveFailureLimit aborts the batch, even with continue_on_error, after this many
+	// consecutive generation failures with the same error code (e.g. a missing generator binary)
+	// Default: 0 (disabled)
+	ConsecutiveFailureLimit int `mapstructure:"consecutive_failure_limit"`
+
+	// FailOnOperationDrop fails a spec whose operation count dropped by more than this fraction
+	// since its client was last generated (e.g. 0.5 fails when over half the operations
+	// disappeared), keeping the previous client. Requires enable_cache, which records the counts.
+	// Default: 0 (disabled; drops of over half the operations are only logged)
+	FailOnOperationDrop float64 `mapstructure:"fail_on_operation_drop"`
+
+	// FSRetryAttempts is how many times cleaning and writing output files is attempted when
+	// the filesystem reports a transient error (EBUSY, ETXTBSY), e.g. on networked filesystems.
+	// Other errors such as permission denied fail immediately.
+	// Default: 3 (1 disables retries)
+	FSRetryAttempts int `mapstructure:"fs_retry_attempts"`
+
+	// GeneratorRetryAttempts is how many times generating a client is attempted when the
+	// generator fails in a retryable way (timeout, transient error such as a file lock, missing
+	// binary). Rejected specs fail immediately.
+	// Default: 2 (1 disables retries)
+	GeneratorRetryAttempts int `mapstructure:"generator_retry_attempts"`
+
+	// GeneratorRetryBackoff is the wait before the second attempt; later attempts wait this
+	// multiplied by the attempt number
+	// Default: 1s
+	GeneratorRetryBackoff time.Duration `mapstructure:"generator_retry_backoff"`
+
+	// ParseCacheSize is how many parsed specs are kept in memory, keyed by content hash, so
+	// fingerprinting, validation and generation (and watch mode reruns) parse each spec once
+	// Default: 64 (0 disables the cache)
+	ParseCacheSize int `mapstructure:"parse_cache_size"`
+
+	// SubprocessGracePeriod is how long a generator subprocess gets to exit after SIGTERM when
+	// the run is cancelled (e.g. on SIGINT/SIGTERM) before it is killed with SIGKILL
+	// Default: 10s
+	SubprocessGracePeriod time.Duration `mapstructure:"subprocess_grace_period"`
+
 	// EnableCache enables caching of generated clients to skip regeneration
 	// Default: true
 	EnableCache bool `mapstructure:"enable_cache"`
@@ -40,10 +147,59 @@ type Config struct {
 	// Default: .openapi-cache
 	CacheDir string `mapstructure:"cache_dir"`
 
+	// RegenerateOnDocChanges regenerates clients on any spec change, even when only
+	// metadata (info, servers, docs, formatting) changed
+	// Default: false (serve from cache when operations and schemas are unchanged)
+	RegenerateOnDocChanges bool `mapstructure:"regenerate_on_doc_changes"`
+
+	// IgnoreResponseHeaderChanges serves cached clients when only response header definitions
+	// changed, for generator setups whose output does not depend on response headers
+	// Default: false (response header changes regenerate the client)
+	IgnoreResponseHeaderChanges bool `mapstructure:"ignore_response_header_changes"`
+
+	// CacheMaxEntries caps the number of cache entries; the least-recently-used entries
+	// are evicted when it is exceeded
+	// Default: 0 (unlimited)
+	CacheMaxEntries int `mapstructure:"cache_max_entries"`
+
+	// CacheArtifacts stores a compressed archive of each generated client with its cache entry,
+	// so a cache hit restores a client directory that is missing, e.g. on a clean checkout
+	// Default: false
+	CacheArtifacts bool `mapstructure:"cache_artifacts"`
+
+	// TraceCache logs, for each spec, the inputs of its cache decision (stored and current spec
+	// hash, generator version, cache settings, fingerprint comparison) and the outcome, as one
+	// greppable "cache-trace" line. Also enabled by --trace-cache.
+	// Default: false
+	TraceCache bool `mapstructure:"trace_cache"`
+
+	// SharedComponentFiles are files referenced by many specs (e.g., a shared components file).
+	// When one that no spec $refs changes between runs, all cached clients are regenerated;
+	// files specs $ref only regenerate their dependents, through the dependency graph.
+	SharedComponentFiles []string `mapstructure:"shared_component_files"`
+
+	// RemoteSpecs are specs downloaded over HTTP(S) at the start of each run into
+	// <cache_dir>/remote-specs/<service>, then generated like the specs under specs_dir
+	RemoteSpecs []RemoteSpec `mapstructure:"remote_specs"`
+
+	// GitSpecs are specs read from other git repositories at a pinned ref. Each repository is
+	// shallow-fetched into <cache_dir>/git-specs at the start of a run and its spec generated
+	// like the specs under specs_dir
+	GitSpecs []GitSpec `mapstructure:"git_specs"`
+
 	// SpecFilePatterns are the filenames to look for when discovering OpenAPI specs
 	// Default: ["openapi.json", "openapi.yaml", "openapi.yml"]
 	SpecFilePatterns []string `mapstructure:"spec_file_patterns"`
 
+	// FollowSymlinks makes spec discovery traverse symlinked directories
+	// Default: false
+	FollowSymlinks bool `mapstructure:"follow_symlinks"`
+
+	// WatchDebounce is how long --watch waits after the last change to a spec before
+	// regenerating, so a burst of editor writes triggers a single regeneration
+	// Default: 500ms
+	WatchDebounce time.Duration `mapstructure:"watch_debounce"`
+
 	// LogLevel sets the logging level (debug, info, warn, error)
 	// Default: info
 	LogLevel string `mapstructure:"log_level"`
@@ -51,11 +207,369 @@ type Config struct {
 	// LogFormat sets the log output format (json, text)
 	// Default: json
 	LogFormat string `mapstructure:"log_format"`
+
+	// LogRedact masks secrets (bearer tokens, credentials in URLs, token= values) in log output
+	// Default: false
+	LogRedact bool `mapstructure:"log_redact"`
+
+	// ExcludeDeprecated removes operations marked `deprecated: true` before generation
+	// Usually set with --include-deprecated=false
+	// Default: false
+	ExcludeDeprecated bool `mapstructure:"exclude_deprecated"`
+
+	// ConvertSwagger2 converts Swagger 2.0 specs to OpenAPI 3.0 before generation, instead of
+	// passing them to generators that only read OpenAPI 3
+	// Default: false
+	ConvertSwagger2 bool `mapstructure:"convert_swagger2"`
+
+	// DowngradeOpenAPI31 converts OpenAPI 3.1 specs to 3.0 before generation, for generators
+	// that only read 3.0
+	// Default: false
+	DowngradeOpenAPI31 bool `mapstructure:"downgrade_openapi31"`
+
+	// SpecEncoding is the character encoding of spec files (e.g., "utf-16le", "latin1")
+	// Default: "" (UTF-8, with UTF-16 detected from a byte order mark)
+	SpecEncoding string `mapstructure:"spec_encoding"`
+
+	// SpecPreprocessCommand is an optional command run against each spec before generation
+	// The spec path is appended as the last argument; stdout replaces the spec for the rest of the pipeline
+	// Example: ["redocly", "bundle", "--ext", "json"]
+	SpecPreprocessCommand []string `mapstructure:"spec_preprocess_command"`
+
+	// ValidationRules lists optional validation rules to run against each spec before generation
+	// Available: validate-examples, unused-security-scheme, required-properties-exist, unique-operation-ids
+	ValidationRules []string `mapstructure:"validation_rules"`
+
+	// RuleSeverities overrides the severity of validation rules: "error", "warning" or "off"
+	// Example: {"validate-examples": "error", "unique-operation-ids": "warning"}
+	RuleSeverities map[string]string `mapstructure:"rule_severities"`
+
+	// SpectralRuleset is a Spectral ruleset file (.spectral.yaml) whose rules run alongside
+	// ValidationRules. Rules using the truthy, pattern and enumeration functions are supported.
+	// Default: "" (none)
+	SpectralRuleset string `mapstructure:"spectral_ruleset"`
+
+	// ValidatorSkipPatterns exempts specs from validation (filepath.Match syntax). A pattern matches
+	// the spec path relative to specs_dir or the name of its service directory. Matching specs
+	// are still generated.
+	// Example: ["stripe-*", "vendor-*/openapi.yaml"]
+	ValidatorSkipPatterns []string `mapstructure:"validator_skip_patterns"`
+
+	// FailOnWarnings makes validation issues with warning severity fail the spec like errors
+	// Default: false
+	FailOnWarnings bool `mapstructure:"fail_on_warnings"`
+
+	// EmitValidationReportAlways writes validation-report.json to the output directory on every run,
+	// including runs where generation fails, with the validation issues found per service
+	// Default: false
+	EmitValidationReportAlways bool `mapstructure:"emit_validation_report_always"`
+
+	// MetricsPath is where the JSON metrics file is written, e.g. a CI artifacts directory
+	// Default: "" (<output_dir>/.openapi-metrics.json)
+	MetricsPath string `mapstructure:"metrics_path"`
+
+	// MetricsLabels are custom labels (e.g., team, environment) attached to all exported metrics
+	MetricsLabels map[string]string `mapstructure:"metrics_labels"`
+
+	// MetricsAddr is an optional address (e.g., ":9090") for an HTTP server exposing
+	// /healthz and /metrics (JSON or Prometheus) while the generator runs
+	MetricsAddr string `mapstructure:"metrics_addr"`
+
+	// InfluxEndpoint is an optional InfluxDB write URL that receives metrics in line protocol
+	// Example: http://localhost:8086/api/v2/write?org=acme&bucket=ci
+	// May contain credentials (e.g., u/p query parameters), so it is redacted when printed
+	InfluxEndpoint string `mapstructure:"influx_endpoint" sensitive:"true"`
+
+	// InfluxFile is an optional file path where metrics are written in line protocol
+	InfluxFile string `mapstructure:"influx_file"`
+
+	// OTelEndpoint is an optional OTLP/HTTP collector endpoint (e.g., http://localhost:4318) that
+	// receives generation timing as an OpenTelemetry trace: a root span for the run and child
+	// spans per spec and stage. Tracing is disabled when empty.
+	// May contain credentials, so it is redacted when printed
+	OTelEndpoint string `mapstructure:"otel_endpoint" sensitive:"true"`
+
+	// LockFile is the spec checksum lockfile; when it exists, every spec must match its recorded sha256
+	// Default: openapi.lock in the repository root
+	LockFile string `mapstructure:"lock_file"`
+
+	// UpdateLock records the current spec checksums in the lockfile instead of verifying them
+	// Usually set with the --update-lock flag
+	UpdateLock bool `mapstructure:"update_lock"`
+
+	// BaselineFingerprints is a committed baseline of each spec's fingerprint and operations
+	// (e.g. "fingerprints.json"). Each run compares the specs against it and reports API changes.
+	// Default: "" (disabled)
+	BaselineFingerprints string `mapstructure:"baseline_fingerprints"`
+
+	// UpdateBaseline rewrites the baseline with the current specs instead of comparing them
+	// Usually set with the --update-baseline flag
+	UpdateBaseline bool `mapstructure:"update_baseline"`
+
+	// BaselineFailOnBreaking fails the run when a spec has breaking changes against the baseline
+	// Default: false
+	BaselineFailOnBreaking bool `mapstructure:"baseline_fail_on_breaking"`
+
+	// Offline disables all network operations (generator installation, metrics push, remote and git spec fetching).
+	// Operations that need the network fail fast with guidance instead.
+	// Default: false
+	Offline bool `mapstructure:"offline"`
+
+	// SpecFetchProxy routes outbound HTTP requests (remote specs, metrics push) through this proxy,
+	// overriding HTTP_PROXY/HTTPS_PROXY/NO_PROXY. May contain credentials, so it is redacted when printed
+	// Example: http://proxy.corp:3128
+	// Default: "" (use the environment)
+	SpecFetchProxy string `mapstructure:"spec_fetch_proxy" sensitive:"true"`
+
+	// EmitErrorReport writes errors.txt to the output directory when any spec fails,
+	// with failures grouped by category and remediation suggestions
+	// Default: false
+	EmitErrorReport bool `mapstructure:"emit_error_report"`
+
+	// JUnitReport is where a JUnit XML report is written after each generation or validation
+	// run, with a test case per spec (failed, passed, or skipped when served from the cache), so
+	// CI systems can render the results
+	// Default: "" (disabled)
+	JUnitReport string `mapstructure:"junit_report"`
+
+	// PruneFiles are file name patterns (filepath.Match syntax) deleted from each client after generation
+	// Example: ["oas_server_gen.go", "oas_unimplemented_gen.go"]
+	PruneFiles []string `mapstructure:"prune_files"`
+
+	// GenerateErrorTypes writes api_errors_gen.go with a typed error per documented 4xx/5xx
+	// response of each operation
+	// Default: false
+	GenerateErrorTypes bool `mapstructure:"generate_error_types"`
+
+	// GenerateFixtures writes fixtures_gen.go with the request and response examples documented
+	// on each operation, for consumer tests
+	// Default: false
+	GenerateFixtures bool `mapstructure:"generate_fixtures"`
+
+	// GenerateClientReadme writes README.md into each client with the service title and version,
+	// its base URLs and the operations grouped by tag
+	// Default: false
+	GenerateClientReadme bool `mapstructure:"generate_client_readme"`
+
+	// GeneratedFileMode is the octal permission of files written by post-processors
+	// (oas_internal_client_gen.go, api_errors_gen.go, fixtures_gen.go, README.md, go.mod)
+	// Default: "0644"
+	GeneratedFileMode string `mapstructure:"generated_file_mode"`
+
+	// BundleSpecFile is the file name, relative to each client directory, of a bundled copy of the
+	// spec with external $refs inlined. The extension (.json, .yaml or .yml) selects the format.
+	// Example: "bundled-openapi.json"
+	// Default: "" (disabled)
+	BundleSpecFile string `mapstructure:"bundle_spec_file"`
+
+	// CopySpecToOutput copies each source spec unchanged into its client directory, as
+	// openapi.json or openapi.yaml depending on its format. The copy survives cleaning.
+	// Default: false
+	CopySpecToOutput bool `mapstructure:"copy_spec_to_output"`
+
+	// GenerateFacade writes a package under OutputDir that imports every generated client and
+	// exposes them through a single API struct with a constructor
+	// Default: false
+	GenerateFacade bool `mapstructure:"generate_facade"`
+
+	// FacadePackagePath is the directory of the facade package, relative to OutputDir; its last
+	// element is the package name
+	// Default: api
+	FacadePackagePath string `mapstructure:"facade_package_path"`
+
+	// CompileCheck compiles each generated client with the active Go toolchain after post-processing
+	// Default: false
+	CompileCheck bool `mapstructure:"compile_check"`
+
+	// MinGoVersion is the minimum Go toolchain version required for generated code (e.g., "1.22")
+	// Checked during the compile check; empty disables the version check
+	MinGoVersion string `mapstructure:"min_go_version"`
+
+	// Generator selects the code generator by name: "ogen" or the name of one of GeneratorPlugins
+	// Default: ogen
+	Generator string `mapstructure:"generator"`
+
+	// GeneratorPlugins are external generators run through the plugin protocol: each receives
+	// the generation request as JSON on stdin and writes the client into its output_dir
+	GeneratorPlugins []GeneratorPlugin `mapstructure:"generator_plugins"`
+
+	// OgenConfigPath is the ogen configuration file passed to the generator, replacing the
+	// repository default (ogen.yml at the repository root). The --ogen-config flag overrides it.
+	// Default: "" (ogen.yml at the repository root)
+	OgenConfigPath string `mapstructure:"ogen_config_path"`
+}
+
+// RemoteSpec declares a spec downloaded over HTTP(S)
+type RemoteSpec struct {
+	// Service names the staged spec directory and so the client, like the directory of a
+	// local spec (e.g. "funding-server-sdk" generates fundingsdk)
+	Service string `mapstructure:"service" json:"service"`
+
+	// URL is the http or https URL of the spec. It may carry credentials (userinfo or a
+	// token query parameter), so it is redacted when the configuration is printed.
+	URL string `mapstructure:"url" json:"url" sensitive:"true"`
+
+	// Headers are sent with the request. Values expand $VAR and ${VAR} from the environment,
+	// so credentials stay out of the config file
+	// Example: {"X-Api-Key": "${FUNDING_API_KEY}"}
+	// Values are redacted when the configuration is printed.
+	Headers map[string]string `mapstructure:"headers" json:"headers,omitempty" sensitive:"true"`
+
+	// TokenEnv names an environment variable holding a bearer token for the Authorization header
+	TokenEnv string `mapstructure:"token_env" json:"token_env,omitempty"`
+}
+
+// GeneratorPlugin configures an external generator plugin
+type GeneratorPlugin struct {
+	// Name selects the plugin with the generator option; must not be "ogen"
+	Name string `mapstructure:"name" json:"name"`
+
+	// Command is the plugin executable followed by its arguments
+	// Example: ["./bin/internal-codegen", "--flavor", "grpc-gateway"]
+	Command []string `mapstructure:"command" json:"command"`
+
+	// Version is recorded in cache entries, so bumping it regenerates all clients
+	// Default: "" (a hash of the plugin executable)
+	Version string `mapstructure:"version" json:"version,omitempty"`
+
+	// ConfigPath is passed to the plugin as config_path
+	ConfigPath string `mapstructure:"config_path" json:"config_path,omitempty"`
+}
+
+// DefaultMetricsFileName is the metrics file written to the output directory unless
+// metrics_path is set
+const DefaultMetricsFileName = ".openapi-metrics.json"
+
+// ResolvedMetricsPath returns where the JSON metrics file is written: MetricsPath if set,
+// <OutputDir>/.openapi-metrics.json otherwise
+func (cfg *Config) ResolvedMetricsPath() string {
+	if cfg.MetricsPath != "" {
+		return cfg.MetricsPath
+	}
+	return filepath.Join(cfg.OutputDir, DefaultMetricsFileName)
+}
+
+// DefaultGeneratedFileMode is the permission of generated files unless generated_file_mode is set
+const DefaultGeneratedFileMode os.FileMode = 0644
+
+// ResolvedGeneratedFileMode returns the permission of files written by post-processors:
+// GeneratedFileMode if set and valid, DefaultGeneratedFileMode otherwise
+func (cfg *Config) ResolvedGeneratedFileMode() os.FileMode {
+	if cfg.GeneratedFileMode != "" {
+		if mode, err := ParseFileMode(cfg.GeneratedFileMode); err == nil {
+			return mode
+		}
+	}
+	return DefaultGeneratedFileMode
+}
+
+// ParseFileMode parses an octal permission such as "0644" or "644". Only permission bits
+// are accepted.
+func ParseFileMode(s string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode %q: must be an octal permission such as 0644", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// ResolvedOgenConfigPath returns the ogen configuration file used for generation:
+// OgenConfigPath if set, the repository default otherwise
+func (cfg *Config) ResolvedOgenConfigPath() string {
+	if cfg.OgenConfigPath != "" {
+		return cfg.OgenConfigPath
+	}
+	return paths.GetOgenConfigPath()
+}
+
+// ValidateOgenConfigPath checks that a custom ogen configuration file exists and is a file.
+// An empty path (the repository default) is valid.
+func ValidateOgenConfigPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("ogen config file does not exist: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access ogen config file %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("ogen config path is a directory, not a file: %s", path)
+	}
+	return nil
+}
+
+// validateRemoteSpecs checks that each remote spec has a unique service name usable as a
+// directory name and an http(s) URL
+func (cfg *Config) validateRemoteSpecs() error {
+	services := make(map[string]bool)
+	for i, remote := range cfg.RemoteSpecs {
+		if remote.Service == "" || remote.Service != filepath.Base(remote.Service) || !filepath.IsLocal(remote.Service) {
+			return fmt.Errorf("remote_specs[%d]: service must be a directory name, got %q", i, remote.Service)
+		}
+		if services[remote.Service] {
+			return fmt.Errorf("remote_specs[%d]: service %q is already declared", i, remote.Service)
+		}
+		services[remote.Service] = true
+
+		parsed, err := url.Parse(remote.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("remote_specs[%d]: url must be an http or https URL, got %q", i, remote.URL)
+		}
+	}
+	return nil
+}
+
+// validateGitSpecs checks that each git spec source parses and names a distinct service
+func (cfg *Config) validateGitSpecs() error {
+	services := make(map[string]string)
+	for i, gitSpec := range cfg.GitSpecs {
+		source, err := ParseGitSource(gitSpec.Source)
+		if err != nil {
+			return fmt.Errorf("git_specs[%d]: %w", i, err)
+		}
+		if other, ok := services[source.Service()]; ok {
+			return fmt.Errorf("git_specs[%d]: service %q is also the service of %s", i, source.Service(), other)
+		}
+		services[source.Service()] = gitSpec.Source
+	}
+	return nil
+}
+
+// validateGenerator checks the generator plugins and that the selected generator exists
+func (cfg *Config) validateGenerator() error {
+	names := map[string]bool{generator.OgenName: true}
+	for i, plugin := range cfg.GeneratorPlugins {
+		if plugin.Name == "" {
+			return fmt.Errorf("generator_plugins[%d]: name is required", i)
+		}
+		if names[plugin.Name] {
+			return fmt.Errorf("generator_plugins[%d]: name %q is already used", i, plugin.Name)
+		}
+		names[plugin.Name] = true
+		if len(plugin.Command) == 0 || plugin.Command[0] == "" {
+			return fmt.Errorf("generator_plugins[%d]: command is required for plugin %q", i, plugin.Name)
+		}
+	}
+
+	if cfg.Generator != "" && !names[cfg.Generator] {
+		return fmt.Errorf("generator %q is neither %q nor one of generator_plugins", cfg.Generator, generator.OgenName)
+	}
+	return nil
 }
 
 // LoadConfig initializes Viper and loads configuration from application.yml
 // with the ability to override via environment variables
 func LoadConfig() (Config, error) {
+	return LoadConfigWithOverrides(nil)
+}
+
+// LoadConfigWithOverrides loads the configuration like LoadConfig, with values keyed like
+// application.yml (e.g. "worker_count": "8") taking precedence over the config file and the
+// environment. Values are strings as given on the command line; lists are comma-separated.
+func LoadConfigWithOverrides(overrides map[string]string) (Config, error) {
 	v := viper.New()
 
 	// Set up config file support with absolute paths
@@ -81,6 +595,14 @@ func LoadConfig() (Config, error) {
 
 	log.Printf("Using config file: %s", v.ConfigFileUsed())
 
+	keys := configValues(Config{})
+	for key, value := range overrides {
+		if _, ok := keys[key]; !ok {
+			return Config{}, fmt.Errorf("unknown configuration key %q", key)
+		}
+		v.Set(key, value)
+	}
+
 	// Unmarshal config into struct
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
@@ -91,6 +613,21 @@ func LoadConfig() (Config, error) {
 	if cfg.WorkerCount <= 0 {
 		cfg.WorkerCount = 4
 	}
+	if cfg.FSRetryAttempts <= 0 {
+		cfg.FSRetryAttempts = 3
+	}
+	if cfg.GeneratorRetryAttempts <= 0 {
+		cfg.GeneratorRetryAttempts = 2
+	}
+	if cfg.GeneratorRetryBackoff <= 0 {
+		cfg.GeneratorRetryBackoff = time.Second
+	}
+	if cfg.SubprocessGracePeriod <= 0 {
+		cfg.SubprocessGracePeriod = generator.DefaultSubprocessGracePeriod
+	}
+	if cfg.WatchDebounce <= 0 {
+		cfg.WatchDebounce = 500 * time.Millisecond
+	}
 
 	// Set EnableCache default to true (caching enabled by default)
 	// Note: Viper unmarshals false as zero value, so we need explicit handling
@@ -98,6 +635,14 @@ func LoadConfig() (Config, error) {
 	v.SetDefault("enable_cache", true)
 	cfg.EnableCache = v.GetBool("enable_cache")
 
+	// Same for clients_subdir, where an explicitly empty value means no subdirectory
+	v.SetDefault("clients_subdir", DefaultClientsSubdir)
+	cfg.ClientsSubdir = v.GetString("clients_subdir")
+
+	// Same for parse_cache_size, where 0 disables the cache
+	v.SetDefault("parse_cache_size", spec.DefaultParseCacheSize)
+	cfg.ParseCacheSize = v.GetInt("parse_cache_size")
+
 	if cfg.CacheDir == "" {
 		cfg.CacheDir = ".openapi-cache"
 	}
@@ -114,11 +659,51 @@ func LoadConfig() (Config, error) {
 	if cfg.LogFormat == "" {
 		cfg.LogFormat = "json"
 	}
+	if cfg.OutputMode == "" {
+		cfg.OutputMode = OutputModeCentral
+	}
+	if cfg.ModuleGoVersion == "" {
+		cfg.ModuleGoVersion = "1.24"
+	}
+	if cfg.FacadePackagePath == "" {
+		cfg.FacadePackagePath = DefaultFacadePackagePath
+	}
+	if cfg.Generator == "" {
+		cfg.Generator = generator.OgenName
+	}
 
 	// Convert relative paths to absolute paths
 	cfg.SpecsDir = paths.MakeAbsolutePath(cfg.SpecsDir)
 	cfg.OutputDir = paths.MakeAbsolutePath(cfg.OutputDir)
 	cfg.CacheDir = paths.MakeAbsolutePath(cfg.CacheDir)
+	if cfg.LockFile == "" {
+		cfg.LockFile = DefaultLockFile
+	}
+	cfg.LockFile = paths.MakeAbsolutePath(cfg.LockFile)
+	if cfg.BaselineFingerprints != "" {
+		cfg.BaselineFingerprints = paths.MakeAbsolutePath(cfg.BaselineFingerprints)
+	}
+	for i, path := range cfg.SharedComponentFiles {
+		cfg.SharedComponentFiles[i] = paths.MakeAbsolutePath(path)
+	}
+	for i, plugin := range cfg.GeneratorPlugins {
+		// Only commands given as a path are resolved; bare names are looked up in PATH
+		if len(plugin.Command) > 0 && strings.ContainsRune(plugin.Command[0], filepath.Separator) {
+			cfg.GeneratorPlugins[i].Command[0] = paths.MakeAbsolutePath(plugin.Command[0])
+		}
+		if plugin.ConfigPath != "" {
+			cfg.GeneratorPlugins[i].ConfigPath = paths.MakeAbsolutePath(plugin.ConfigPath)
+		}
+	}
+	if cfg.InfluxFile != "" {
+		cfg.InfluxFile = paths.MakeAbsolutePath(cfg.InfluxFile)
+	}
+	if cfg.MetricsPath != "" {
+		cfg.MetricsPath = paths.MakeAbsolutePath(cfg.MetricsPath)
+	}
+	if cfg.OgenConfigPath != "" {
+		cfg.OgenConfigPath = paths.MakeAbsolutePath(cfg.OgenConfigPath)
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -148,6 +733,132 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("output_dir validation failed: %w", err)
 	}
 
+	switch cfg.OutputMode {
+	case "", OutputModeCentral, OutputModeAlongsideSpec:
+	case OutputModeModulePerService:
+		if cfg.ModulePathPrefix == "" {
+			return fmt.Errorf("module_path_prefix is required when output_mode is %q", OutputModeModulePerService)
+		}
+	default:
+		return fmt.Errorf("output_mode must be %q, %q or %q, got %q",
+			OutputModeCentral, OutputModeAlongsideSpec, OutputModeModulePerService, cfg.OutputMode)
+	}
+
+	if cfg.ClientsSubdir != "" && (filepath.IsAbs(cfg.ClientsSubdir) || !filepath.IsLocal(cfg.ClientsSubdir)) {
+		return fmt.Errorf("clients_subdir must be a relative path inside output_dir, got %q", cfg.ClientsSubdir)
+	}
+
+	if cfg.BundleSpecFile != "" {
+		if filepath.IsAbs(cfg.BundleSpecFile) || !filepath.IsLocal(cfg.BundleSpecFile) {
+			return fmt.Errorf("bundle_spec_file must be a relative path inside the client directory, got %q", cfg.BundleSpecFile)
+		}
+		switch strings.ToLower(filepath.Ext(cfg.BundleSpecFile)) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return fmt.Errorf("bundle_spec_file must end in .json, .yaml or .yml, got %q", cfg.BundleSpecFile)
+		}
+		if cfg.CopySpecToOutput && strings.TrimSuffix(filepath.Clean(cfg.BundleSpecFile), filepath.Ext(cfg.BundleSpecFile)) == "openapi" {
+			return fmt.Errorf("bundle_spec_file %q collides with the spec copy written by copy_spec_to_output", cfg.BundleSpecFile)
+		}
+	}
+
+	if cfg.UpdateBaseline && cfg.BaselineFingerprints == "" {
+		return fmt.Errorf("baseline_fingerprints must be set to update the baseline")
+	}
+
+	if cfg.GenerateFacade {
+		if cfg.OutputMode == OutputModeModulePerService {
+			return fmt.Errorf("generate_facade is not supported with output_mode %q", OutputModeModulePerService)
+		}
+		facadePath := cfg.FacadePackagePath
+		if facadePath == "" {
+			facadePath = DefaultFacadePackagePath
+		}
+		if filepath.IsAbs(facadePath) || !filepath.IsLocal(facadePath) {
+			return fmt.Errorf("facade_package_path must be a relative path inside output_dir, got %q", facadePath)
+		}
+		if !facadePackageNamePattern.MatchString(filepath.Base(facadePath)) {
+			return fmt.Errorf("facade_package_path must end in a valid package name (lowercase letters, digits and underscores), got %q", facadePath)
+		}
+	}
+
+	if cfg.SpecFetchProxy != "" {
+		if _, err := network.ParseProxyURL(cfg.SpecFetchProxy); err != nil {
+			return fmt.Errorf("spec_fetch_proxy: %w", err)
+		}
+	}
+
+	if cfg.PostProcessConcurrency < 0 {
+		return fmt.Errorf("post_process_concurrency must not be negative")
+	}
+
+	if cfg.IOConcurrency < 0 {
+		return fmt.Errorf("io_concurrency must not be negative")
+	}
+
+	if cfg.MaxOutputBytes < 0 {
+		return fmt.Errorf("max_output_bytes must not be negative")
+	}
+
+	if cfg.ConsecutiveFailureLimit < 0 {
+		return fmt.Errorf("consecutive_failure_limit must not be negative")
+	}
+
+	if cfg.FailOnOperationDrop < 0 || cfg.FailOnOperationDrop > 1 {
+		return fmt.Errorf("fail_on_operation_drop must be between 0 and 1, got %v", cfg.FailOnOperationDrop)
+	}
+
+	if cfg.MetricsPath != "" {
+		if info, err := os.Stat(cfg.MetricsPath); err == nil && info.IsDir() {
+			return fmt.Errorf("metrics_path must be a file, got directory %s", cfg.MetricsPath)
+		}
+		if err := paths.EnsureDirectoryWritable(filepath.Dir(cfg.MetricsPath)); err != nil {
+			return fmt.Errorf("metrics_path validation failed: %w", err)
+		}
+	}
+
+	if err := ValidateOgenConfigPath(cfg.OgenConfigPath); err != nil {
+		return fmt.Errorf("ogen_config_path validation failed: %w", err)
+	}
+
+	if err := cfg.validateRemoteSpecs(); err != nil {
+		return err
+	}
+
+	if err := cfg.validateGitSpecs(); err != nil {
+		return err
+	}
+
+	if err := cfg.validateGenerator(); err != nil {
+		return err
+	}
+
+	if cfg.GeneratedFileMode != "" {
+		if _, err := ParseFileMode(cfg.GeneratedFileMode); err != nil {
+			return fmt.Errorf("generated_file_mode: %w", err)
+		}
+	}
+
+	if cfg.ParseCacheSize < 0 {
+		return fmt.Errorf("parse_cache_size must not be negative")
+	}
+
+	if cfg.CacheMaxEntries < 0 {
+		return fmt.Errorf("cache_max_entries must not be negative")
+	}
+
+	for _, pattern := range cfg.ValidatorSkipPatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("validator_skip_patterns pattern %q is invalid: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range cfg.PruneFiles {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("prune_files pattern %q is invalid: %w", pattern, err)
+		}
+	}
+
 	// Validate TargetServices regex
 	if cfg.TargetServices != "" {
 		if _, err := regexp.Compile(cfg.TargetServices); err != nil {
@@ -158,4 +869,21 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// ResolveLogFormat determines the effective log format from CLI flags and configuration.
+// Flags take precedence over the configured format; setting both flags is an error.
+func ResolveLogFormat(configFormat string, jsonLogs, textLogs bool) (string, error) {
+	switch {
+	case jsonLogs && textLogs:
+		return "", fmt.Errorf("--json-logs and --text-logs are mutually exclusive")
+	case jsonLogs:
+		return "json", nil
+	case textLogs:
+		return "text", nil
+	case configFormat == "":
+		return "json", nil
+	default:
+		return configFormat, nil
+	}
+}
+
 // LogConfiguration is now in config_logging.go to support structured logging
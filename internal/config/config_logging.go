@@ -9,6 +9,8 @@ import (
 // LogConfiguration logs the current configuration parameters using structured logging
 // If logger is provided, uses structured logging; otherwise uses standard log package
 func LogConfiguration(cfg Config, optionalLogger ...interface{}) {
+	cfg = cfg.Redacted()
+
 	var logger interface{}
 	if len(optionalLogger) > 0 {
 		logger = optionalLogger[0]
@@ -25,15 +27,80 @@ func LogConfiguration(cfg Config, optionalLogger ...interface{}) {
 			"repository_root", paths.GetRepositoryRoot(),
 			"specs_directory", cfg.SpecsDir,
 			"output_directory", cfg.OutputDir,
+			"output_mode", cfg.OutputMode,
+			"clients_subdir", cfg.ClientsSubdir,
+			"module_path_prefix", cfg.ModulePathPrefix,
+			"module_go_version", cfg.ModuleGoVersion,
+			"module_tidy", cfg.ModuleTidy,
 			"target_services", cfg.TargetServices,
 			"continue_on_error", cfg.ContinueOnError,
 			"worker_count", cfg.WorkerCount,
+			"post_process_concurrency", cfg.PostProcessConcurrency,
+			"io_concurrency", cfg.IOConcurrency,
+			"max_output_bytes", cfg.MaxOutputBytes,
+			"consecutive_failure_limit", cfg.ConsecutiveFailureLimit,
+			"fail_on_operation_drop", cfg.FailOnOperationDrop,
+			"fs_retry_attempts", cfg.FSRetryAttempts,
+			"generator_retry_attempts", cfg.GeneratorRetryAttempts,
+			"generator_retry_backoff", cfg.GeneratorRetryBackoff.String(),
+			"parse_cache_size", cfg.ParseCacheSize,
+			"subprocess_grace_period", cfg.SubprocessGracePeriod.String(),
 			"enable_cache", cfg.EnableCache,
 			"cache_directory", cfg.CacheDir,
+			"regenerate_on_doc_changes", cfg.RegenerateOnDocChanges,
+			"ignore_response_header_changes", cfg.IgnoreResponseHeaderChanges,
+			"cache_max_entries", cfg.CacheMaxEntries,
+			"cache_artifacts", cfg.CacheArtifacts,
+			"trace_cache", cfg.TraceCache,
+			"shared_component_files", cfg.SharedComponentFiles,
+			"remote_specs", remoteServices(cfg.RemoteSpecs),
+			"git_specs", gitSources(cfg.GitSpecs),
 			"spec_file_patterns", cfg.SpecFilePatterns,
+			"follow_symlinks", cfg.FollowSymlinks,
+			"watch_debounce", cfg.WatchDebounce.String(),
 			"log_level", cfg.LogLevel,
 			"log_format", cfg.LogFormat,
-			"ogen_config", paths.GetOgenConfigPath(),
+			"log_redact", cfg.LogRedact,
+			"exclude_deprecated", cfg.ExcludeDeprecated,
+			"convert_swagger2", cfg.ConvertSwagger2,
+			"downgrade_openapi31", cfg.DowngradeOpenAPI31,
+			"spec_encoding", cfg.SpecEncoding,
+			"spec_preprocess_command", cfg.SpecPreprocessCommand,
+			"validation_rules", cfg.ValidationRules,
+			"rule_severities", cfg.RuleSeverities,
+			"spectral_ruleset", cfg.SpectralRuleset,
+			"validator_skip_patterns", cfg.ValidatorSkipPatterns,
+			"fail_on_warnings", cfg.FailOnWarnings,
+			"emit_validation_report_always", cfg.EmitValidationReportAlways,
+			"metrics_path", cfg.ResolvedMetricsPath(),
+			"metrics_labels", cfg.MetricsLabels,
+			"metrics_addr", cfg.MetricsAddr,
+			"influx_endpoint", cfg.InfluxEndpoint,
+			"influx_file", cfg.InfluxFile,
+			"otel_endpoint", cfg.OTelEndpoint,
+			"lock_file", cfg.LockFile,
+			"update_lock", cfg.UpdateLock,
+			"baseline_fingerprints", cfg.BaselineFingerprints,
+			"update_baseline", cfg.UpdateBaseline,
+			"baseline_fail_on_breaking", cfg.BaselineFailOnBreaking,
+			"offline", cfg.Offline,
+			"spec_fetch_proxy", cfg.SpecFetchProxy,
+			"emit_error_report", cfg.EmitErrorReport,
+			"junit_report", cfg.JUnitReport,
+			"prune_files", cfg.PruneFiles,
+			"generate_error_types", cfg.GenerateErrorTypes,
+			"generate_fixtures", cfg.GenerateFixtures,
+			"generate_client_readme", cfg.GenerateClientReadme,
+			"generated_file_mode", cfg.ResolvedGeneratedFileMode().String(),
+			"bundle_spec_file", cfg.BundleSpecFile,
+			"copy_spec_to_output", cfg.CopySpecToOutput,
+			"generate_facade", cfg.GenerateFacade,
+			"facade_package_path", cfg.FacadePackagePath,
+			"compile_check", cfg.CompileCheck,
+			"min_go_version", cfg.MinGoVersion,
+			"generator", cfg.Generator,
+			"generator_plugins", pluginNames(cfg.GeneratorPlugins),
+			"ogen_config", cfg.ResolvedOgenConfigPath(),
 		)
 	} else {
 		// Fallback to standard logging (backward compatibility)
@@ -41,14 +108,106 @@ func LogConfiguration(cfg Config, optionalLogger ...interface{}) {
 		log.Printf("  Repository root: %s", paths.GetRepositoryRoot())
 		log.Printf("  Specs directory: %s", cfg.SpecsDir)
 		log.Printf("  Output directory: %s", cfg.OutputDir)
+		log.Printf("  Output mode: %s", cfg.OutputMode)
+		log.Printf("  Clients subdirectory: %q", cfg.ClientsSubdir)
+		log.Printf("  Module path prefix: %s", cfg.ModulePathPrefix)
+		log.Printf("  Module Go version: %s", cfg.ModuleGoVersion)
+		log.Printf("  Module tidy: %v", cfg.ModuleTidy)
 		log.Printf("  Target services: %s", cfg.TargetServices)
 		log.Printf("  Continue on error: %v", cfg.ContinueOnError)
 		log.Printf("  Worker count: %d", cfg.WorkerCount)
+		log.Printf("  Post-process concurrency: %d", cfg.PostProcessConcurrency)
+		log.Printf("  I/O concurrency: %d", cfg.IOConcurrency)
+		log.Printf("  Max output bytes: %d", cfg.MaxOutputBytes)
+		log.Printf("  Consecutive failure limit: %d", cfg.ConsecutiveFailureLimit)
+		log.Printf("  Fail on operation drop: %v", cfg.FailOnOperationDrop)
+		log.Printf("  FS retry attempts: %d", cfg.FSRetryAttempts)
+		log.Printf("  Generator retry attempts: %d", cfg.GeneratorRetryAttempts)
+		log.Printf("  Generator retry backoff: %s", cfg.GeneratorRetryBackoff)
+		log.Printf("  Parse cache size: %d", cfg.ParseCacheSize)
+		log.Printf("  Subprocess grace period: %s", cfg.SubprocessGracePeriod)
 		log.Printf("  Enable cache: %v", cfg.EnableCache)
 		log.Printf("  Cache directory: %s", cfg.CacheDir)
+		log.Printf("  Regenerate on doc changes: %v", cfg.RegenerateOnDocChanges)
+		log.Printf("  Ignore response header changes: %v", cfg.IgnoreResponseHeaderChanges)
+		log.Printf("  Cache max entries: %d", cfg.CacheMaxEntries)
+		log.Printf("  Cache artifacts: %v", cfg.CacheArtifacts)
+		log.Printf("  Trace cache: %v", cfg.TraceCache)
+		log.Printf("  Shared component files: %v", cfg.SharedComponentFiles)
+		log.Printf("  Remote specs: %v", remoteServices(cfg.RemoteSpecs))
+		log.Printf("  Git specs: %v", gitSources(cfg.GitSpecs))
 		log.Printf("  Spec file patterns: %v", cfg.SpecFilePatterns)
+		log.Printf("  Follow symlinks: %v", cfg.FollowSymlinks)
+		log.Printf("  Watch debounce: %s", cfg.WatchDebounce)
 		log.Printf("  Log level: %s", cfg.LogLevel)
 		log.Printf("  Log format: %s", cfg.LogFormat)
-		log.Printf("  Ogen config: %s", paths.GetOgenConfigPath())
+		log.Printf("  Log redact: %v", cfg.LogRedact)
+		log.Printf("  Exclude deprecated: %v", cfg.ExcludeDeprecated)
+		log.Printf("  Convert Swagger 2.0: %v", cfg.ConvertSwagger2)
+		log.Printf("  Downgrade OpenAPI 3.1: %v", cfg.DowngradeOpenAPI31)
+		log.Printf("  Spec encoding: %s", cfg.SpecEncoding)
+		log.Printf("  Spec preprocess command: %v", cfg.SpecPreprocessCommand)
+		log.Printf("  Validation rules: %v", cfg.ValidationRules)
+		log.Printf("  Rule severities: %v", cfg.RuleSeverities)
+		log.Printf("  Spectral ruleset: %s", cfg.SpectralRuleset)
+		log.Printf("  Validator skip patterns: %v", cfg.ValidatorSkipPatterns)
+		log.Printf("  Fail on warnings: %v", cfg.FailOnWarnings)
+		log.Printf("  Emit validation report always: %v", cfg.EmitValidationReportAlways)
+		log.Printf("  Metrics path: %s", cfg.ResolvedMetricsPath())
+		log.Printf("  Metrics labels: %v", cfg.MetricsLabels)
+		log.Printf("  Metrics address: %s", cfg.MetricsAddr)
+		log.Printf("  Influx endpoint: %s", cfg.InfluxEndpoint)
+		log.Printf("  Influx file: %s", cfg.InfluxFile)
+		log.Printf("  OTel endpoint: %s", cfg.OTelEndpoint)
+		log.Printf("  Lock file: %s", cfg.LockFile)
+		log.Printf("  Update lock: %v", cfg.UpdateLock)
+		log.Printf("  Baseline fingerprints: %s", cfg.BaselineFingerprints)
+		log.Printf("  Update baseline: %v", cfg.UpdateBaseline)
+		log.Printf("  Baseline fail on breaking: %v", cfg.BaselineFailOnBreaking)
+		log.Printf("  Offline: %v", cfg.Offline)
+		log.Printf("  Spec fetch proxy: %s", cfg.SpecFetchProxy)
+		log.Printf("  Emit error report: %v", cfg.EmitErrorReport)
+		log.Printf("  JUnit report: %s", cfg.JUnitReport)
+		log.Printf("  Prune files: %v", cfg.PruneFiles)
+		log.Printf("  Generate error types: %v", cfg.GenerateErrorTypes)
+		log.Printf("  Generate fixtures: %v", cfg.GenerateFixtures)
+		log.Printf("  Generate client README: %v", cfg.GenerateClientReadme)
+		log.Printf("  Generated file mode: %s", cfg.ResolvedGeneratedFileMode())
+		log.Printf("  Bundle spec file: %s", cfg.BundleSpecFile)
+		log.Printf("  Copy spec to output: %v", cfg.CopySpecToOutput)
+		log.Printf("  Generate facade: %v", cfg.GenerateFacade)
+		log.Printf("  Facade package path: %s", cfg.FacadePackagePath)
+		log.Printf("  Compile check: %v", cfg.CompileCheck)
+		log.Printf("  Min Go version: %s", cfg.MinGoVersion)
+		log.Printf("  Generator: %s", cfg.Generator)
+		log.Printf("  Generator plugins: %v", pluginNames(cfg.GeneratorPlugins))
+		log.Printf("  Ogen config: %s", cfg.ResolvedOgenConfigPath())
+	}
+}
+
+// pluginNames returns the names of the generator plugins
+func pluginNames(plugins []GeneratorPlugin) []string {
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name)
+	}
+	return names
+}
+
+// remoteServices returns the services of the remote specs
+func remoteServices(remotes []RemoteSpec) []string {
+	services := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		services = append(services, remote.Service)
+	}
+	return services
+}
+
+// gitSources returns the sources of the git specs
+func gitSources(gitSpecs []GitSpec) []string {
+	sources := make([]string, 0, len(gitSpecs))
+	for _, gitSpec := range gitSpecs {
+		sources = append(sources, gitSpec.Source)
 	}
+	return sources
 }
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// GitSpec declares a spec read from another git repository at a pinned ref
+type GitSpec struct {
+	// Source is <repository>//<path in repository>@<ref>
+	// Example: "git@gitlab.corp:payments/funding.git//specs/funding-server-sdk/openapi.yaml@v1.4.0"
+	// An https repository may carry credentials, so the source is redacted when the
+	// configuration is printed.
+	Source string `mapstructure:"source" json:"source" sensitive:"true"`
+}
+
+// GitSource is a parsed GitSpec source
+type GitSource struct {
+	// Repository is the clone URL, e.g. "git@gitlab.corp:payments/funding.git"
+	Repository string
+
+	// Path is the slash-separated spec path inside the repository
+	Path string
+
+	// Ref is the branch, tag or commit the spec is read at
+	Ref string
+}
+
+// ParseGitSource parses a git spec source of the form <repository>//<path>@<ref>. The
+// repository may be an scp-like address (git@host:group/repo.git) or a URL
+// (https://host/group/repo.git, file:///srv/repo.git).
+func ParseGitSource(source string) (GitSource, error) {
+	// The path separator is the first "//" after the URL scheme, if any
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	sep := strings.Index(source[start:], "//")
+	if sep < 0 {
+		return GitSource{}, fmt.Errorf("git source %q must be <repository>//<path>@<ref>", source)
+	}
+	repository := source[:start+sep]
+	rest := source[start+sep+2:]
+
+	at := strings.LastIndex(rest, "@")
+	if at < 0 {
+		return GitSource{}, fmt.Errorf("git source %q must pin a ref with @<ref>", source)
+	}
+	specPath, ref := rest[:at], rest[at+1:]
+
+	if repository == "" || start > 0 && start == len(repository) {
+		return GitSource{}, fmt.Errorf("git source %q has no repository", source)
+	}
+	if ref == "" {
+		return GitSource{}, fmt.Errorf("git source %q has an empty ref", source)
+	}
+	// git would parse a leading "-" as an option (e.g. --upload-pack=<command>)
+	if strings.HasPrefix(repository, "-") || strings.HasPrefix(ref, "-") {
+		return GitSource{}, fmt.Errorf("git source %q must not start its repository or ref with \"-\"", source)
+	}
+	if specPath == "" || path.IsAbs(specPath) || !isLocalSlashPath(specPath) {
+		return GitSource{}, fmt.Errorf("git source %q must have a relative spec path inside the repository", source)
+	}
+	return GitSource{Repository: repository, Path: path.Clean(specPath), Ref: ref}, nil
+}
+
+// isLocalSlashPath reports whether a slash-separated path stays inside its root
+func isLocalSlashPath(p string) bool {
+	cleaned := path.Clean(p)
+	return cleaned != "." && cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
+
+// RepositoryName returns the last element of the repository address without ".git",
+// e.g. "funding" for git@gitlab.corp:payments/funding.git
+func (s GitSource) RepositoryName() string {
+	name := strings.TrimSuffix(strings.TrimRight(s.Repository, "/"), ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Service returns the service name of the spec: the name of its directory in the repository,
+// as for local specs, or the repository name for a spec at the repository root
+func (s GitSource) Service() string {
+	if dir := path.Dir(s.Path); dir != "." {
+		return path.Base(dir)
+	}
+	return s.RepositoryName()
+}
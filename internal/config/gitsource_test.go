@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		source      string
+		want        GitSource
+		wantService string
+		wantErr     bool
+	}{
+		{
+			source:      "git@gitlab.corp:payments/funding.git//specs/funding-server-sdk/openapi.yaml@v1.4.0",
+			want:        GitSource{Repository: "git@gitlab.corp:payments/funding.git", Path: "specs/funding-server-sdk/openapi.yaml", Ref: "v1.4.0"},
+			wantService: "funding-server-sdk",
+		},
+		{
+			source:      "https://gitlab.corp/payments/holidays.git//openapi.json@main",
+			want:        GitSource{Repository: "https://gitlab.corp/payments/holidays.git", Path: "openapi.json", Ref: "main"},
+			wantService: "holidays",
+		},
+		{
+			source:      "file:///srv/git/rates//api/./rates-sdk/openapi.yml@0123456789abcdef0123456789abcdef01234567",
+			want:        GitSource{Repository: "file:///srv/git/rates", Path: "api/rates-sdk/openapi.yml", Ref: "0123456789abcdef0123456789abcdef01234567"},
+			wantService: "rates-sdk",
+		},
+		{source: "git@gitlab.corp:payments/funding.git/openapi.yaml@v1", wantErr: true},
+		{source: "git@gitlab.corp:payments/funding.git//openapi.yaml", wantErr: true},
+		{source: "git@gitlab.corp:payments/funding.git//openapi.yaml@", wantErr: true},
+		{source: "git@gitlab.corp:payments/funding.git//../openapi.yaml@v1", wantErr: true},
+		{source: "//openapi.yaml@v1", wantErr: true},
+		{source: "--upload-pack=touch /tmp/pwned//openapi.yaml@v1", wantErr: true},
+		{source: "git@gitlab.corp:payments/funding.git//openapi.yaml@--upload-pack=touch /tmp/pwned", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGitSource(tt.source)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseGitSource(%q) = %+v, want an error", tt.source, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want || got.Service() != tt.wantService {
+			t.Errorf("ParseGitSource(%q) = %+v (service %q), %v; want %+v (service %q)",
+				tt.source, got, got.Service(), err, tt.want, tt.wantService)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// RedactedValue replaces sensitive values when printing the configuration
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with sensitive fields masked.
+// Fields are marked sensitive with the `sensitive:"true"` struct tag, also in nested
+// entries such as remote_specs.
+func (cfg Config) Redacted() Config {
+	return redactValue(reflect.ValueOf(cfg)).Interface().(Config)
+}
+
+// redactValue returns a copy of v with sensitive fields masked, descending into structs,
+// slices and maps. Slices and maps are copied so the original configuration is unchanged.
+func redactValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("sensitive") == "true" {
+				copied.Field(i).Set(maskValue(v.Field(i)))
+			} else {
+				copied.Field(i).Set(redactValue(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), redactValue(iter.Value()))
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// maskValue masks a sensitive value: a non-empty string, or each value of a map or slice
+// of strings (e.g. header values, keeping the header names)
+func maskValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return v
+		}
+		return reflect.ValueOf(RedactedValue).Convert(v.Type())
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(maskValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), maskValue(iter.Value()))
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// FormatConfig renders the configuration in the given format ("yaml" or "json")
+// using the same keys as application.yml. Sensitive fields are redacted.
+func FormatConfig(cfg Config, format string) ([]byte, error) {
+	values := configValues(cfg.Redacted())
+
+	switch format {
+	case "yaml", "yml", "":
+		data, err := yaml.Marshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config as YAML: %w", err)
+		}
+		return data, nil
+	case "json":
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config as JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported config format %q (use yaml or json)", format)
+	}
+}
+
+// configValues maps the configuration to its mapstructure keys
+func configValues(cfg Config) map[string]interface{} {
+	v := reflect.ValueOf(cfg)
+	t := v.Type()
+
+	values := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+		value := v.Field(i).Interface()
+		// Print durations as in the config file ("500ms") rather than as nanoseconds
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		values[key] = value
+	}
+	return values
+}
@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ErrorCode classifies generation failures
+type ErrorCode string
+
+const (
+	// ErrCodeGeneratorFailed indicates the generator rejected the spec (not retryable)
+	ErrCodeGeneratorFailed ErrorCode = "GENERATOR_FAILED"
+
+	// ErrCodeGeneratorTimeout indicates the generator did not finish before the deadline (retryable)
+	ErrCodeGeneratorTimeout ErrorCode = "GENERATOR_TIMEOUT"
+
+	// ErrCodeGeneratorTransient indicates a transient failure such as a file lock (retryable)
+	ErrCodeGeneratorTransient ErrorCode = "GENERATOR_TRANSIENT"
+
+	// ErrCodeGeneratorNotInstalled indicates the generator binary is missing or failed to install (retryable)
+	ErrCodeGeneratorNotInstalled ErrorCode = "GENERATOR_NOT_INSTALLED"
+
+	// ErrCodeGeneratorCancelled indicates generation was cancelled (not retryable)
+	ErrCodeGeneratorCancelled ErrorCode = "GENERATOR_CANCELLED"
+
+	// ErrCodePostProcessFailed indicates the generated client was rejected after generation,
+	// e.g. because it exceeded max_output_bytes (not retryable)
+	ErrCodePostProcessFailed ErrorCode = "POST_PROCESS_FAILED"
+
+	// ErrCodeOther indicates a failure outside the generator, such as validation or post-processing
+	ErrCodeOther ErrorCode = "OTHER"
+)
+
+// suggestions are remediation hints shown for each error category
+var suggestions = map[ErrorCode]string{
+	ErrCodeGeneratorFailed:       "Check the spec for constructs ogen does not support; the generator output points to the offending location.",
+	ErrCodeGeneratorTimeout:      "Retry the run; if it keeps timing out, lower worker_count or split the spec.",
+	ErrCodeGeneratorTransient:    "Retry the run; the failure looks transient (file locks, resource limits, killed process).",
+	ErrCodeGeneratorNotInstalled: "Install ogen with 'go install " + OgenPackage + "@" + OgenVersion + "' and make sure $GOPATH/bin is in PATH.",
+	ErrCodeGeneratorCancelled:    "The run was cancelled before generation finished; rerun to complete it.",
+	ErrCodePostProcessFailed:     "The generated client was rejected after generation; an oversized client usually comes from a recursive or deeply nested schema.",
+	ErrCodeOther:                 "Failures outside the generator usually come from spec validation, preprocessing or post-processing; see the message for details.",
+}
+
+// pluginSuggestions replace the ogen-specific suggestions for failures of generator plugins
+var pluginSuggestions = map[ErrorCode]string{
+	ErrCodeGeneratorFailed:       "Check the spec for constructs the generator plugin does not support; its output points to the offending location.",
+	ErrCodeGeneratorNotInstalled: "Install the generator plugin's command and make sure it is in PATH, or fix its command in generator_plugins.",
+}
+
+// transientOutputPatterns are generator output fragments that indicate a retryable failure
+var transientOutputPatterns = []string{
+	"resource temporarily unavailable",
+	"text file busy",
+	"device or resource busy",
+	"too many open files",
+	"file is locked",
+	"lock held",
+}
+
+// GenerationError describes a failed generator run
+type GenerationError struct {
+	// Code classifies the failure
+	Code ErrorCode
+
+	// PackageName is the package being generated
+	PackageName string
+
+	// Generator is the name of the generator that failed (empty means ogen)
+	Generator string
+
+	// ExitCode is the generator process exit code (-1 if it did not exit normally)
+	ExitCode int
+
+	// Output is the combined generator output
+	Output string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the error message including generator output
+func (e *GenerationError) Error() string {
+	stage := e.generatorName()
+	switch e.Code {
+	case ErrCodeOther:
+		stage = "generation"
+	case ErrCodePostProcessFailed:
+		stage = "post-processing"
+	}
+	msg := fmt.Sprintf("%s failed for %s [%s]: %v", stage, e.PackageName, e.Code, e.Err)
+	if e.Output != "" {
+		msg += "\nOutput: " + e.Output
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether retrying the generation may succeed
+func (e *GenerationError) Retryable() bool {
+	switch e.Code {
+	case ErrCodeGeneratorTimeout, ErrCodeGeneratorTransient, ErrCodeGeneratorNotInstalled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Suggestion returns a remediation hint for the error category
+func (e *GenerationError) Suggestion() string {
+	if e.generatorName() != OgenName {
+		if suggestion, ok := pluginSuggestions[e.Code]; ok {
+			return suggestion
+		}
+	}
+	return suggestions[e.Code]
+}
+
+// generatorName returns the name of the generator that failed
+func (e *GenerationError) generatorName() string {
+	if e.Generator == "" {
+		return OgenName
+	}
+	return e.Generator
+}
+
+// IsRetryable reports whether err is a GenerationError that may succeed on retry
+func IsRetryable(err error) bool {
+	var genErr *GenerationError
+	return errors.As(err, &genErr) && genErr.Retryable()
+}
+
+// classifyGeneratorError converts a failed run of the named generator into a GenerationError
+// based on the context state, exit status and output.
+func classifyGeneratorError(ctx context.Context, generatorName, packageName string, err error, output []byte) *GenerationError {
+	genErr := &GenerationError{
+		Code:        ErrCodeGeneratorFailed,
+		PackageName: packageName,
+		Generator:   generatorName,
+		ExitCode:    -1,
+		Output:      string(output),
+		Err:         err,
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		genErr.ExitCode = exitErr.ExitCode()
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		genErr.Code = ErrCodeGeneratorTimeout
+	case errors.Is(ctx.Err(), context.Canceled):
+		genErr.Code = ErrCodeGeneratorCancelled
+	case errors.Is(err, exec.ErrNotFound):
+		genErr.Code = ErrCodeGeneratorNotInstalled
+	case exitErr != nil && genErr.ExitCode == -1:
+		// Killed by a signal (e.g. OOM killer) rather than exiting with an error
+		genErr.Code = ErrCodeGeneratorTransient
+	case hasTransientOutput(output):
+		genErr.Code = ErrCodeGeneratorTransient
+	}
+
+	return genErr
+}
+
+// hasTransientOutput reports whether generator output mentions a transient condition
+func hasTransientOutput(output []byte) bool {
+	lower := strings.ToLower(string(output))
+	for _, pattern := range transientOutputPatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// AsGenerationError returns err as a *GenerationError. Errors that did not come from the
+// generator are wrapped with code ErrCodeOther and the given package name.
+func AsGenerationError(packageName string, err error) *GenerationError {
+	var genErr *GenerationError
+	if errors.As(err, &genErr) {
+		return genErr
+	}
+	return &GenerationError{
+		Code:        ErrCodeOther,
+		PackageName: packageName,
+		ExitCode:    -1,
+		Err:         err,
+	}
+}
+
+// GroupByCategory groups generation errors by their error code
+func GroupByCategory(errs []*GenerationError) map[ErrorCode][]*GenerationError {
+	groups := make(map[ErrorCode][]*GenerationError)
+	for _, err := range errs {
+		groups[err.Code] = append(groups[err.Code], err)
+	}
+	return groups
+}
+
+// FormatList renders generation errors grouped by category, sorted by code,
+// with the category suggestion followed by one entry per package
+func FormatList(errs []*GenerationError) string {
+	groups := GroupByCategory(errs)
+
+	codes := make([]string, 0, len(groups))
+	for code := range groups {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+
+	var b strings.Builder
+	for i, code := range codes {
+		group := groups[ErrorCode(code)]
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", code, len(group))
+		if suggestion := group[0].Suggestion(); suggestion != "" {
+			fmt.Fprintf(&b, "  Suggestion: %s\n", suggestion)
+		}
+		for _, err := range group {
+			// Indent multi-line generator output under its entry
+			message := strings.ReplaceAll(strings.TrimSpace(err.Error()), "\n", "\n      ")
+			fmt.Fprintf(&b, "  - %s: %s\n", err.PackageName, message)
+		}
+	}
+	return b.String()
+}
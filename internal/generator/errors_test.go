@@ -0,0 +1,208 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyGeneratorError(t *testing.T) {
+	tests := []struct {
+		name          string
+		command       []string
+		timeout       time.Duration
+		cancel        bool
+		wantCode      ErrorCode
+		wantRetryable bool
+	}{
+		{
+			name:          "schema error exit",
+			command:       []string{"sh", "-c", "echo 'parse spec: invalid schema' >&2; exit 1"},
+			wantCode:      ErrCodeGeneratorFailed,
+			wantRetryable: false,
+		},
+		{
+			name:          "transient file lock",
+			command:       []string{"sh", "-c", "echo 'open oas_json_gen.go: resource temporarily unavailable' >&2; exit 1"},
+			wantCode:      ErrCodeGeneratorTransient,
+			wantRetryable: true,
+		},
+		{
+			name:          "killed by signal",
+			command:       []string{"sh", "-c", "kill -9 $$"},
+			wantCode:      ErrCodeGeneratorTransient,
+			wantRetryable: true,
+		},
+		{
+			name:          "timeout",
+			command:       []string{"sleep", "5"},
+			timeout:       50 * time.Millisecond,
+			wantCode:      ErrCodeGeneratorTimeout,
+			wantRetryable: true,
+		},
+		{
+			name:          "cancelled",
+			command:       []string{"sleep", "5"},
+			cancel:        true,
+			wantCode:      ErrCodeGeneratorCancelled,
+			wantRetryable: false,
+		},
+		{
+			name:          "binary not installed",
+			command:       []string{"openapi-go-nonexistent-generator"},
+			wantCode:      ErrCodeGeneratorNotInstalled,
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.timeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tt.timeout)
+			}
+			defer cancel()
+			if tt.cancel {
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			cmd := exec.CommandContext(ctx, tt.command[0], tt.command[1:]...)
+			output, err := cmd.CombinedOutput()
+			if err == nil {
+				t.Fatal("fake command should fail")
+			}
+
+			genErr := classifyGeneratorError(ctx, OgenName, "testpkg", err, output)
+			if genErr.Code != tt.wantCode {
+				t.Errorf("Code = %s, want %s (err: %v)", genErr.Code, tt.wantCode, err)
+			}
+			if genErr.Retryable() != tt.wantRetryable {
+				t.Errorf("Retryable() = %v, want %v", genErr.Retryable(), tt.wantRetryable)
+			}
+			if IsRetryable(fmt.Errorf("wrapped: %w", genErr)) != tt.wantRetryable {
+				t.Errorf("IsRetryable() on wrapped error = %v, want %v", !tt.wantRetryable, tt.wantRetryable)
+			}
+			if !errors.Is(genErr, err) {
+				t.Error("GenerationError should unwrap to the underlying error")
+			}
+		})
+	}
+}
+
+func TestIsRetryableNonGenerationError(t *testing.T) {
+	if IsRetryable(errors.New("plain error")) {
+		t.Error("IsRetryable() = true for a plain error, want false")
+	}
+	if IsRetryable(nil) {
+		t.Error("IsRetryable() = true for nil, want false")
+	}
+}
+
+// flakyGenerator fails with the configured errors before succeeding
+type flakyGenerator struct {
+	errs  []error
+	calls int
+}
+
+func (g *flakyGenerator) Name() string                              { return "flaky" }
+func (g *flakyGenerator) Version() string                           { return "v0.0.0-test" }
+func (g *flakyGenerator) EnsureInstalled(ctx context.Context) error { return nil }
+func (g *flakyGenerator) IsInstalled() bool                         { return true }
+
+func (g *flakyGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
+	g.calls++
+	if g.calls <= len(g.errs) {
+		return g.errs[g.calls-1]
+	}
+	return nil
+}
+
+func TestRetryingGenerator(t *testing.T) {
+	transient := &GenerationError{Code: ErrCodeGeneratorTransient, PackageName: "pkg", Err: errors.New("locked")}
+	failed := &GenerationError{Code: ErrCodeGeneratorFailed, PackageName: "pkg", Err: errors.New("bad schema")}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		attempts  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "retryable then success", errs: []error{transient}, attempts: 3, wantErr: false, wantCalls: 2},
+		{name: "non-retryable fails immediately", errs: []error{failed}, attempts: 3, wantErr: true, wantCalls: 1},
+		{name: "retryable exhausts attempts", errs: []error{transient, transient, transient}, attempts: 2, wantErr: true, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &flakyGenerator{errs: tt.errs}
+			retrying := NewRetryingGenerator(gen, tt.attempts, time.Millisecond)
+
+			err := retrying.Generate(context.Background(), GenerateSpec{PackageName: "pkg"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gen.calls != tt.wantCalls {
+				t.Errorf("Generate() called %d times, want %d", gen.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	errs := []*GenerationError{
+		{Code: ErrCodeGeneratorFailed, PackageName: "fundingsdk", ExitCode: 1, Output: "unsupported schema\nat #/paths", Err: errors.New("exit status 1")},
+		{Code: ErrCodeGeneratorTimeout, PackageName: "holidayssdk", ExitCode: -1, Err: context.DeadlineExceeded},
+		{Code: ErrCodeGeneratorFailed, PackageName: "usersdk", ExitCode: 2, Err: errors.New("exit status 2")},
+		AsGenerationError("accountsdk", errors.New("spec validation failed for accountsdk")),
+	}
+
+	groups := GroupByCategory(errs)
+	if len(groups) != 3 {
+		t.Fatalf("GroupByCategory() returned %d groups, want 3", len(groups))
+	}
+	if len(groups[ErrCodeGeneratorFailed]) != 2 {
+		t.Errorf("GroupByCategory()[%s] has %d errors, want 2", ErrCodeGeneratorFailed, len(groups[ErrCodeGeneratorFailed]))
+	}
+
+	output := FormatList(errs)
+	for _, want := range []string{
+		"GENERATOR_FAILED (2)\n  Suggestion: " + suggestions[ErrCodeGeneratorFailed],
+		"GENERATOR_TIMEOUT (1)\n  Suggestion: " + suggestions[ErrCodeGeneratorTimeout],
+		"OTHER (1)\n  Suggestion: " + suggestions[ErrCodeOther],
+		"  - fundingsdk: ogen failed for fundingsdk",
+		"\n      at #/paths",
+		"  - accountsdk: generation failed for accountsdk [OTHER]: spec validation failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("FormatList() output missing %q:\n%s", want, output)
+		}
+	}
+
+	// Categories are sorted by code
+	if strings.Index(output, "GENERATOR_FAILED") > strings.Index(output, "GENERATOR_TIMEOUT") ||
+		strings.Index(output, "GENERATOR_TIMEOUT") > strings.Index(output, "OTHER") {
+		t.Errorf("FormatList() categories not sorted:\n%s", output)
+	}
+}
+
+func TestAsGenerationError(t *testing.T) {
+	original := &GenerationError{Code: ErrCodeGeneratorTransient, PackageName: "fundingsdk"}
+	wrapped := fmt.Errorf("failed for fundingsdk: %w", original)
+
+	if got := AsGenerationError("ignored", wrapped); got != original {
+		t.Errorf("AsGenerationError() = %v, want the wrapped GenerationError", got)
+	}
+
+	other := AsGenerationError("holidayssdk", errors.New("boom"))
+	if other.Code != ErrCodeOther || other.PackageName != "holidayssdk" {
+		t.Errorf("AsGenerationError() = %+v, want OTHER for holidayssdk", other)
+	}
+}
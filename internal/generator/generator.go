@@ -3,6 +3,7 @@ package generator
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // Generator defines the interface for OpenAPI client code generators.
@@ -29,22 +30,31 @@ type Generator interface {
 	IsInstalled() bool
 }
 
-// GenerateSpec contains all parameters needed for code generation
+// GenerateSpec contains all parameters needed for code generation. Plugins receive it as
+// JSON on stdin (see PluginGenerator).
 type GenerateSpec struct {
 	// SpecPath is the absolute path to the OpenAPI specification file
-	SpecPath string
+	SpecPath string `json:"spec_path"`
 
 	// OutputDir is the directory where generated code should be written
-	OutputDir string
+	OutputDir string `json:"output_dir"`
 
 	// PackageName is the Go package name for the generated code
-	PackageName string
+	PackageName string `json:"package_name"`
 
 	// ConfigPath is the optional path to generator-specific configuration
-	ConfigPath string
+	ConfigPath string `json:"config_path,omitempty"`
 
 	// Clean indicates whether to clean the output directory before generation
-	Clean bool
+	Clean bool `json:"clean"`
+
+	// WorkingDir is the working directory for the generator subprocess
+	// Defaults to the repository root when empty
+	WorkingDir string `json:"working_dir,omitempty"`
+
+	// GracePeriod is how long the generator subprocess gets to exit after SIGTERM when ctx is
+	// cancelled, before it is killed. Defaults to DefaultSubprocessGracePeriod when zero
+	GracePeriod time.Duration `json:"-"`
 }
 
 // Registry manages available generators and provides a way to select and use them
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
 )
 
@@ -71,6 +72,12 @@ func (g *OgenGenerator) EnsureInstalled(ctx context.Context) error {
 		return nil
 	}
 
+	// Installing requires downloading the module
+	if err := network.Check(fmt.Sprintf("installing ogen %s", g.version),
+		fmt.Sprintf("install it beforehand with 'go install %s@%s' or disable offline mode", g.pkg, g.version)); err != nil {
+		return err
+	}
+
 	log.Printf("Installing ogen CLI %s...", g.version)
 
 	// Install specific version (not @latest for deterministic builds)
@@ -93,7 +100,13 @@ func (g *OgenGenerator) EnsureInstalled(ctx context.Context) error {
 func (g *OgenGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
 	// Ensure ogen is installed
 	if err := g.EnsureInstalled(ctx); err != nil {
-		return fmt.Errorf("failed to ensure ogen is installed: %w", err)
+		return &GenerationError{
+			Code:        ErrCodeGeneratorNotInstalled,
+			PackageName: spec.PackageName,
+			Generator:   OgenName,
+			ExitCode:    -1,
+			Err:         fmt.Errorf("failed to ensure ogen is installed: %w", err),
+		}
 	}
 
 	// Validate spec path
@@ -110,7 +123,27 @@ func (g *OgenGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
 		return fmt.Errorf("ogen config not found: %w", err)
 	}
 
-	// Build command arguments
+	// Execute ogen
+	log.Printf("Generating client with ogen for package %s...", spec.PackageName)
+	cmd := g.buildCommand(ctx, spec, configPath)
+
+	// Capture output for better error messages
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// Classify so callers can tell retryable failures from spec errors
+		return classifyGeneratorError(ctx, OgenName, spec.PackageName, err, output)
+	}
+
+	// Log ogen output
+	if len(output) > 0 {
+		log.Printf("ogen output for %s:\n%s", spec.PackageName, string(output))
+	}
+
+	return nil
+}
+
+// buildCommand constructs the ogen command for a generation request
+func (g *OgenGenerator) buildCommand(ctx context.Context, spec GenerateSpec, configPath string) *exec.Cmd {
 	args := []string{
 		"--target", spec.OutputDir,
 		"--package", spec.PackageName,
@@ -123,23 +156,15 @@ func (g *OgenGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
 
 	args = append(args, spec.SpecPath)
 
-	// Execute ogen
-	log.Printf("Generating client with ogen for package %s...", spec.PackageName)
-	cmd := exec.CommandContext(ctx, "ogen", args...)
-
-	// Capture output for better error messages
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ogen failed for %s: %w\nOutput: %s",
-			spec.PackageName, err, string(output))
-	}
+	cmd := commandWithGracePeriod(ctx, spec.GracePeriod, "ogen", args...)
 
-	// Log ogen output
-	if len(output) > 0 {
-		log.Printf("ogen output for %s:\n%s", spec.PackageName, string(output))
+	// Run from a stable directory so relative paths resolve consistently
+	cmd.Dir = spec.WorkingDir
+	if cmd.Dir == "" {
+		cmd.Dir = paths.GetRepositoryRoot()
 	}
 
-	return nil
+	return cmd
 }
 
 // Validate checks if the generator configuration is valid
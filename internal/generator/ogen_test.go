@@ -2,10 +2,15 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
 )
 
 func TestNewOgenGenerator(t *testing.T) {
@@ -239,6 +244,51 @@ func TestOgenGeneratorContextCancellation(t *testing.T) {
 	}
 }
 
+func TestOgenGeneratorBuildCommandWorkingDir(t *testing.T) {
+	customDir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		workingDir  string
+		expectedDir string
+	}{
+		{
+			name:        "defaults to repository root",
+			workingDir:  "",
+			expectedDir: paths.GetRepositoryRoot(),
+		},
+		{
+			name:        "custom working directory",
+			workingDir:  customDir,
+			expectedDir: customDir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewOgenGenerator()
+			spec := GenerateSpec{
+				SpecPath:    "/tmp/openapi.json",
+				OutputDir:   "/tmp/output",
+				PackageName: "testpkg",
+				WorkingDir:  tt.workingDir,
+				Clean:       true,
+			}
+
+			cmd := gen.buildCommand(context.Background(), spec, "/tmp/ogen.yml")
+
+			if cmd.Dir != tt.expectedDir {
+				t.Errorf("cmd.Dir = %q, want %q", cmd.Dir, tt.expectedDir)
+			}
+
+			// Spec path must remain the last argument
+			if last := cmd.Args[len(cmd.Args)-1]; last != spec.SpecPath {
+				t.Errorf("last argument = %q, want %q", last, spec.SpecPath)
+			}
+		})
+	}
+}
+
 func TestOgenGeneratorInterfaceImplementation(t *testing.T) {
 	// Verify OgenGenerator implements Generator interface
 	var _ Generator = (*OgenGenerator)(nil)
@@ -257,3 +307,24 @@ func TestOgenConstants(t *testing.T) {
 		t.Errorf("OgenPackage = %q, want %q", OgenPackage, "github.com/ogen-go/ogen/cmd/ogen")
 	}
 }
+
+func TestOgenGeneratorEnsureInstalledOffline(t *testing.T) {
+	network.SetOffline(true)
+	defer network.SetOffline(false)
+
+	// A version that is never installed forces an install attempt
+	gen := &OgenGenerator{version: "v0.0.0-offline-test", pkg: OgenPackage}
+
+	err := gen.EnsureInstalled(context.Background())
+	if err == nil {
+		t.Fatal("EnsureInstalled() expected error in offline mode")
+	}
+
+	var offlineErr *network.OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("EnsureInstalled() error = %v, want *network.OfflineError", err)
+	}
+	if !strings.Contains(err.Error(), "go install "+OgenPackage+"@v0.0.0-offline-test") {
+		t.Errorf("EnsureInstalled() error = %q, should explain how to install manually", err.Error())
+	}
+}
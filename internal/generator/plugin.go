@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
+)
+
+const (
+	// PluginProtocolVersion is the version of the plugin protocol implemented by PluginGenerator
+	PluginProtocolVersion = "1"
+
+	// PluginProtocolEnv is the environment variable passing PluginProtocolVersion to plugins
+	PluginProtocolEnv = "OPENAPI_GO_PLUGIN_PROTOCOL"
+)
+
+// PluginConfig describes an external generator plugin
+type PluginConfig struct {
+	// Name identifies the generator in the registry
+	Name string
+
+	// Command is the plugin executable followed by its arguments
+	Command []string
+
+	// Version is recorded in cache entries, so changing it regenerates all clients.
+	// Defaults to a hash of the plugin executable when empty
+	Version string
+
+	// ConfigPath is passed to the plugin as config_path instead of the ogen configuration
+	ConfigPath string
+}
+
+// PluginGenerator implements the Generator interface by running an external binary, similar
+// to protoc plugins. The protocol:
+//   - the GenerateSpec is written as JSON to the plugin's stdin (spec_path, output_dir,
+//     package_name, config_path, clean, working_dir)
+//   - the plugin writes the generated files into output_dir and exits with status 0
+//   - a non-zero exit status fails the spec; stdout and stderr are reported as its output
+//
+// The plugin runs in working_dir with PluginProtocolEnv set to PluginProtocolVersion.
+type PluginGenerator struct {
+	name       string
+	command    []string
+	configPath string
+
+	version     string
+	versionOnce sync.Once
+}
+
+// NewPluginGenerator creates a generator running the plugin described by cfg
+func NewPluginGenerator(cfg PluginConfig) (*PluginGenerator, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("generator plugin name cannot be empty")
+	}
+	if len(cfg.Command) == 0 || cfg.Command[0] == "" {
+		return nil, fmt.Errorf("generator plugin %q has no command", cfg.Name)
+	}
+	return &PluginGenerator{
+		name:       cfg.Name,
+		command:    cfg.Command,
+		configPath: cfg.ConfigPath,
+		version:    cfg.Version,
+	}, nil
+}
+
+// Name returns the generator name
+func (g *PluginGenerator) Name() string {
+	return g.name
+}
+
+// Version returns the configured version, or a hash of the plugin executable so that a
+// rebuilt plugin invalidates cached clients
+func (g *PluginGenerator) Version() string {
+	g.versionOnce.Do(func() {
+		if g.version == "" {
+			g.version = executableVersion(g.command[0])
+		}
+	})
+	return g.version
+}
+
+// IsInstalled checks if the plugin executable can be found
+func (g *PluginGenerator) IsInstalled() bool {
+	_, err := exec.LookPath(g.command[0])
+	return err == nil
+}
+
+// EnsureInstalled checks that the plugin executable is available; plugins are never installed
+func (g *PluginGenerator) EnsureInstalled(ctx context.Context) error {
+	if _, err := exec.LookPath(g.command[0]); err != nil {
+		return fmt.Errorf("generator plugin %q is not available: %w", g.name, err)
+	}
+	return nil
+}
+
+// Generate runs the plugin with the spec as JSON on stdin
+func (g *PluginGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
+	if err := g.EnsureInstalled(ctx); err != nil {
+		return &GenerationError{
+			Code:        ErrCodeGeneratorNotInstalled,
+			PackageName: spec.PackageName,
+			Generator:   g.name,
+			ExitCode:    -1,
+			Err:         err,
+		}
+	}
+
+	if err := paths.EnsurePathExists(spec.SpecPath); err != nil {
+		return fmt.Errorf("spec file not found: %w", err)
+	}
+
+	// The ogen configuration means nothing to a plugin; it gets its own, if any
+	spec.ConfigPath = g.configPath
+	if spec.WorkingDir == "" {
+		spec.WorkingDir = paths.GetRepositoryRoot()
+	}
+	request, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	log.Printf("Generating client with plugin %s for package %s...", g.name, spec.PackageName)
+	cmd := commandWithGracePeriod(ctx, spec.GracePeriod, g.command[0], g.command[1:]...)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = append(os.Environ(), PluginProtocolEnv+"="+PluginProtocolVersion)
+	cmd.Stdin = bytes.NewReader(request)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return classifyGeneratorError(ctx, g.name, spec.PackageName, fmt.Errorf("generator plugin %q: %w", g.name, err), output)
+	}
+
+	if len(output) > 0 {
+		log.Printf("%s output for %s:\n%s", g.name, spec.PackageName, string(output))
+	}
+	return nil
+}
+
+// executableVersion identifies an executable by the hash of its content, or "unknown" if it
+// cannot be read
+func executableVersion(command string) string {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "unknown"
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "unknown"
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "unknown"
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))[:12]
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPluginGeneratorGenerate(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	outputDir := filepath.Join(dir, "client")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+
+	// Fake plugin: stores the request and protocol version next to a generated file
+	script := `cat > request.json; echo "$OPENAPI_GO_PLUGIN_PROTOCOL" > protocol; echo "package $1" > "$2/client_gen.go"; echo generated`
+	gen, err := NewPluginGenerator(PluginConfig{
+		Name:       "internal-codegen",
+		Command:    []string{"sh", "-c", script, "sh", "funding", outputDir},
+		Version:    "1.4.0",
+		ConfigPath: "/etc/internal-codegen.yml",
+	})
+	if err != nil {
+		t.Fatalf("NewPluginGenerator() error = %v", err)
+	}
+	if gen.Name() != "internal-codegen" || gen.Version() != "1.4.0" || !gen.IsInstalled() {
+		t.Errorf("plugin = %s %s (installed %v)", gen.Name(), gen.Version(), gen.IsInstalled())
+	}
+
+	err = gen.Generate(context.Background(), GenerateSpec{
+		SpecPath:    specPath,
+		OutputDir:   outputDir,
+		PackageName: "funding",
+		ConfigPath:  "resources/ogen.yml",
+		Clean:       true,
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(outputDir, "client_gen.go")); err != nil || string(data) != "package funding\n" {
+		t.Errorf("client_gen.go = %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "protocol")); strings.TrimSpace(string(data)) != PluginProtocolVersion {
+		t.Errorf("%s = %q, want %q", PluginProtocolEnv, data, PluginProtocolVersion)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "request.json"))
+	if err != nil {
+		t.Fatalf("Failed to read request: %v", err)
+	}
+	var request map[string]interface{}
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatalf("request is not JSON: %v\n%s", err, data)
+	}
+	want := map[string]interface{}{
+		"spec_path":    specPath,
+		"output_dir":   outputDir,
+		"package_name": "funding",
+		"config_path":  "/etc/internal-codegen.yml",
+		"clean":        true,
+		"working_dir":  dir,
+	}
+	if len(request) != len(want) {
+		t.Errorf("request = %v, want %v", request, want)
+	}
+	for key, value := range want {
+		if request[key] != value {
+			t.Errorf("request[%q] = %v, want %v", key, request[key], value)
+		}
+	}
+}
+
+func TestPluginGeneratorFailures(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		command      []string
+		wantCode     ErrorCode
+		wantExitCode int
+		wantOutput   string
+	}{
+		{
+			name:         "non-zero exit",
+			command:      []string{"sh", "-c", "echo 'unsupported oneOf' >&2; exit 3"},
+			wantCode:     ErrCodeGeneratorFailed,
+			wantExitCode: 3,
+			wantOutput:   "unsupported oneOf",
+		},
+		{
+			name:         "missing executable",
+			command:      []string{"openapi-go-missing-plugin"},
+			wantCode:     ErrCodeGeneratorNotInstalled,
+			wantExitCode: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewPluginGenerator(PluginConfig{Name: "plugin", Command: tt.command})
+			if err != nil {
+				t.Fatalf("NewPluginGenerator() error = %v", err)
+			}
+
+			err = gen.Generate(context.Background(), GenerateSpec{SpecPath: specPath, OutputDir: t.TempDir(), PackageName: "funding"})
+			var genErr *GenerationError
+			if !errors.As(err, &genErr) {
+				t.Fatalf("Generate() error = %v, want a GenerationError", err)
+			}
+			if genErr.Code != tt.wantCode || genErr.ExitCode != tt.wantExitCode || !strings.Contains(genErr.Output, tt.wantOutput) {
+				t.Errorf("error = %s exit %d output %q, want %s exit %d output %q",
+					genErr.Code, genErr.ExitCode, genErr.Output, tt.wantCode, tt.wantExitCode, tt.wantOutput)
+			}
+			// Messages name the plugin rather than ogen
+			if !strings.HasPrefix(genErr.Error(), "plugin failed for funding") {
+				t.Errorf("Error() = %q, want it to name the plugin", genErr.Error())
+			}
+			if strings.Contains(genErr.Suggestion(), "ogen") {
+				t.Errorf("Suggestion() = %q, want a hint for plugins", genErr.Suggestion())
+			}
+		})
+	}
+}
+
+func TestNewPluginGenerator(t *testing.T) {
+	if _, err := NewPluginGenerator(PluginConfig{Command: []string{"gen"}}); err == nil {
+		t.Error("a plugin without a name should be rejected")
+	}
+	if _, err := NewPluginGenerator(PluginConfig{Name: "gen"}); err == nil {
+		t.Error("a plugin without a command should be rejected")
+	}
+
+	// Without a configured version, the executable's content identifies the plugin
+	plugin := filepath.Join(t.TempDir(), "codegen")
+	if err := os.WriteFile(plugin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	gen, err := NewPluginGenerator(PluginConfig{Name: "gen", Command: []string{plugin}})
+	if err != nil {
+		t.Fatalf("NewPluginGenerator() error = %v", err)
+	}
+	if version := gen.Version(); !strings.HasPrefix(version, "sha256:") || len(version) != len("sha256:")+12 {
+		t.Errorf("Version() = %q, want a hash of the executable", version)
+	}
+
+	missing, _ := NewPluginGenerator(PluginConfig{Name: "gen", Command: []string{"openapi-go-missing-plugin"}})
+	if missing.IsInstalled() || missing.Version() != "unknown" {
+		t.Errorf("missing plugin: installed %v, version %q", missing.IsInstalled(), missing.Version())
+	}
+}
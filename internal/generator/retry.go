@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RetryingGenerator wraps a Generator and retries generation failures classified as retryable
+type RetryingGenerator struct {
+	Generator
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewRetryingGenerator creates a generator that retries retryable failures up to maxAttempts
+// times in total, waiting backoff multiplied by the attempt number between attempts
+func NewRetryingGenerator(gen Generator, maxAttempts int, backoff time.Duration) *RetryingGenerator {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &RetryingGenerator{
+		Generator:   gen,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// Generate runs the wrapped generator, retrying failures for which IsRetryable is true
+func (g *RetryingGenerator) Generate(ctx context.Context, spec GenerateSpec) error {
+	var err error
+	for attempt := 1; attempt <= g.maxAttempts; attempt++ {
+		err = g.Generator.Generate(ctx, spec)
+		if err == nil || !IsRetryable(err) || attempt == g.maxAttempts {
+			return err
+		}
+
+		log.Printf("Retryable generation failure for %s (attempt %d/%d): %v",
+			spec.PackageName, attempt, g.maxAttempts, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("generation retry cancelled: %w", ctx.Err())
+		case <-time.After(g.backoff * time.Duration(attempt)):
+		}
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// DefaultSubprocessGracePeriod is how long a generator subprocess gets to exit after SIGTERM
+// before it is killed, when no grace period is configured
+const DefaultSubprocessGracePeriod = 10 * time.Second
+
+// commandWithGracePeriod creates a command that is stopped gracefully when ctx is done:
+// the process receives SIGTERM and is killed with SIGKILL if it is still running after
+// gracePeriod. A zero gracePeriod uses DefaultSubprocessGracePeriod.
+func commandWithGracePeriod(ctx context.Context, gracePeriod time.Duration, name string, args ...string) *exec.Cmd {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultSubprocessGracePeriod
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	// Wait kills the process once the delay has passed after cancellation
+	cmd.WaitDelay = gracePeriod
+	return cmd
+}
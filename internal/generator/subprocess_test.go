@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommandWithGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires POSIX signals")
+	}
+
+	tests := []struct {
+		name        string
+		trap        string
+		gracePeriod time.Duration
+		wantKilled  bool
+	}{
+		{
+			name:        "exits on SIGTERM within the grace period",
+			trap:        `echo term > "$0"; exit 0`,
+			gracePeriod: 5 * time.Second,
+			wantKilled:  false,
+		},
+		{
+			name:        "killed after ignoring SIGTERM for the grace period",
+			trap:        `echo term > "$0"`,
+			gracePeriod: 300 * time.Millisecond,
+			wantKilled:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marker := filepath.Join(t.TempDir(), "signal")
+			// Fake long-running generator: records SIGTERM in the marker file and keeps running
+			// unless the trap exits
+			script := `trap '` + tt.trap + `' TERM; touch "$0.ready"; while :; do sleep 0.05; done`
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cmd := commandWithGracePeriod(ctx, tt.gracePeriod, "sh", "-c", script, marker)
+			if err := cmd.Start(); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			waitForFile(t, marker+".ready")
+
+			cancel()
+			cancelled := time.Now()
+			cmd.Wait()
+			elapsed := time.Since(cancelled)
+
+			if _, err := os.Stat(marker); err != nil {
+				t.Errorf("subprocess did not receive SIGTERM: %v", err)
+			}
+
+			status := cmd.ProcessState.Sys().(syscall.WaitStatus)
+			killed := status.Signaled() && status.Signal() == syscall.SIGKILL
+			if killed != tt.wantKilled {
+				t.Errorf("killed = %v, want %v (status %v)", killed, tt.wantKilled, cmd.ProcessState)
+			}
+			if tt.wantKilled && elapsed < tt.gracePeriod {
+				t.Errorf("killed after %s, before the %s grace period", elapsed, tt.gracePeriod)
+			}
+			if !tt.wantKilled && elapsed >= tt.gracePeriod {
+				t.Errorf("graceful exit took %s, not before the %s grace period", elapsed, tt.gracePeriod)
+			}
+		})
+	}
+}
+
+func TestCommandWithGracePeriodDefault(t *testing.T) {
+	cmd := commandWithGracePeriod(context.Background(), 0, "ogen")
+	if cmd.WaitDelay != DefaultSubprocessGracePeriod {
+		t.Errorf("WaitDelay = %s, want %s", cmd.WaitDelay, DefaultSubprocessGracePeriod)
+	}
+	if cmd.Cancel == nil {
+		t.Error("Cancel should send SIGTERM instead of killing the process")
+	}
+}
+
+func TestBuildCommandGracePeriod(t *testing.T) {
+	spec := GenerateSpec{SpecPath: "openapi.json", OutputDir: "out", PackageName: "pets", GracePeriod: 3 * time.Second}
+	cmd := NewOgenGenerator().buildCommand(context.Background(), spec, "ogen.yml")
+	if cmd.WaitDelay != 3*time.Second {
+		t.Errorf("WaitDelay = %s, want 3s", cmd.WaitDelay)
+	}
+}
+
+// waitForFile waits until path exists
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
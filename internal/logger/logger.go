@@ -17,6 +17,7 @@ type Config struct {
 	Level  string // debug, info, warn, error
 	Format string // json, text
 	Output io.Writer
+	Redact bool // mask secrets such as bearer tokens and URL credentials in the output
 }
 
 // New creates a new structured logger with the specified configuration
@@ -29,6 +30,9 @@ func New(cfg Config) *Logger {
 	if output == nil {
 		output = os.Stdout
 	}
+	if cfg.Redact {
+		output = NewRedactingWriter(output)
+	}
 
 	// Create handler based on format
 	var handler slog.Handler
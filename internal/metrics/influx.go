@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+// InfluxMeasurement is the measurement name used for line protocol export
+const InfluxMeasurement = "openapi_generation"
+
+// influxPushTimeout bounds how long pushing metrics to an InfluxDB endpoint may take
+const influxPushTimeout = 10 * time.Second
+
+var (
+	// tagEscaper escapes measurement tag keys and values per the line protocol spec
+	tagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+	// fieldStringEscaper escapes string field values per the line protocol spec. Line breaks
+	// end a point, so they are written as \n and \r (e.g. for multi-line generator errors).
+	fieldStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+)
+
+// unknownService is the service tag value for metrics without a service name, since line
+// protocol does not allow empty tag values
+const unknownService = "unknown"
+
+// FormatLineProtocol renders spec metrics as InfluxDB line protocol, one line per spec.
+// Custom labels are added as tags after the service tag, sorted by key.
+// Example: openapi_generation,service=funding success=true,cached=false,duration_ms=1234 1700000000000000000
+func FormatLineProtocol(specMetrics []SpecMetric, labels map[string]string) string {
+	labelKeys := make([]string, 0, len(labels))
+	for k := range labels {
+		if k != "service" {
+			labelKeys = append(labelKeys, k)
+		}
+	}
+	sort.Strings(labelKeys)
+
+	var b strings.Builder
+	for _, m := range specMetrics {
+		b.WriteString(InfluxMeasurement)
+		service := m.ServiceName
+		if service == "" {
+			service = unknownService
+		}
+		b.WriteString(",service=")
+		b.WriteString(tagEscaper.Replace(service))
+		for _, k := range labelKeys {
+			if labels[k] == "" {
+				// Line protocol does not allow empty tag values
+				continue
+			}
+			b.WriteString(",")
+			b.WriteString(tagEscaper.Replace(k))
+			b.WriteString("=")
+			b.WriteString(tagEscaper.Replace(labels[k]))
+		}
+
+		b.WriteString(" success=")
+		b.WriteString(strconv.FormatBool(m.Success))
+		b.WriteString(",cached=")
+		b.WriteString(strconv.FormatBool(m.Cached))
+		b.WriteString(",duration_ms=")
+		b.WriteString(strconv.FormatInt(m.DurationMs, 10))
+		if m.Error != "" {
+			b.WriteString(`,error="`)
+			b.WriteString(fieldStringEscaper.Replace(m.Error))
+			b.WriteString(`"`)
+		}
+
+		if !m.GeneratedAt.IsZero() {
+			b.WriteString(" ")
+			b.WriteString(strconv.FormatInt(m.GeneratedAt.UnixNano(), 10))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// LineProtocol returns the collected spec metrics in InfluxDB line protocol
+func (c *Collector) LineProtocol() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return FormatLineProtocol(c.metrics.SpecMetrics, c.metrics.Labels)
+}
+
+// ExportLineProtocol writes the collected spec metrics as InfluxDB line protocol to a file
+func (c *Collector) ExportLineProtocol(path string) error {
+	if err := os.WriteFile(path, []byte(c.LineProtocol()), 0644); err != nil {
+		return fmt.Errorf("failed to write line protocol file: %w", err)
+	}
+	return nil
+}
+
+// PushLineProtocol posts the collected spec metrics as InfluxDB line protocol to an endpoint
+// (e.g. http://localhost:8086/api/v2/write?org=acme&bucket=ci)
+func (c *Collector) PushLineProtocol(ctx context.Context, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, influxPushTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBufferString(c.LineProtocol()))
+	if err != nil {
+		return fmt.Errorf("failed to create line protocol request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := network.NewHTTPClient(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push line protocol: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("line protocol push failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
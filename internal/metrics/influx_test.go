@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+func TestFormatLineProtocol(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name     string
+		metrics  []SpecMetric
+		labels   map[string]string
+		expected string
+	}{
+		{
+			name: "successful spec",
+			metrics: []SpecMetric{
+				{ServiceName: "funding", Success: true, DurationMs: 1234, GeneratedAt: ts},
+			},
+			expected: "openapi_generation,service=funding success=true,cached=false,duration_ms=1234 1700000000000000000\n",
+		},
+		{
+			name: "failed spec with escaped error",
+			metrics: []SpecMetric{
+				{ServiceName: "holidays", Success: false, DurationMs: 10, Error: `ogen "failed" at C:\specs`, GeneratedAt: ts},
+			},
+			expected: `openapi_generation,service=holidays success=false,cached=false,duration_ms=10,error="ogen \"failed\" at C:\\specs" 1700000000000000000` + "\n",
+		},
+		{
+			name: "failed spec with multi-line error",
+			metrics: []SpecMetric{
+				{ServiceName: "funding", Success: false, DurationMs: 5, Error: "failed to preprocess spec: exit status 1\r\nStderr: invalid spec\n", GeneratedAt: ts},
+			},
+			expected: `openapi_generation,service=funding success=false,cached=false,duration_ms=5,error="failed to preprocess spec: exit status 1\r\nStderr: invalid spec\n" 1700000000000000000` + "\n",
+		},
+		{
+			name: "spec without service name",
+			metrics: []SpecMetric{
+				{Success: true, DurationMs: 3, GeneratedAt: ts},
+			},
+			expected: "openapi_generation,service=unknown success=true,cached=false,duration_ms=3 1700000000000000000\n",
+		},
+		{
+			name: "tag escaping",
+			metrics: []SpecMetric{
+				{ServiceName: "my service,a=b", Success: true, Cached: true, GeneratedAt: ts},
+			},
+			expected: `openapi_generation,service=my\ service\,a\=b success=true,cached=true,duration_ms=0 1700000000000000000` + "\n",
+		},
+		{
+			name: "multiple specs without timestamp",
+			metrics: []SpecMetric{
+				{ServiceName: "a", Success: true, DurationMs: 1},
+				{ServiceName: "b", Success: true, DurationMs: 2},
+			},
+			expected: "openapi_generation,service=a success=true,cached=false,duration_ms=1\n" +
+				"openapi_generation,service=b success=true,cached=false,duration_ms=2\n",
+		},
+		{
+			name: "custom labels as sorted tags",
+			metrics: []SpecMetric{
+				{ServiceName: "funding", Success: true, DurationMs: 1, GeneratedAt: ts},
+			},
+			labels:   map[string]string{"team": "payments", "environment": "ci", "empty": ""},
+			expected: "openapi_generation,service=funding,environment=ci,team=payments success=true,cached=false,duration_ms=1 1700000000000000000\n",
+		},
+		{
+			name:     "no metrics",
+			metrics:  nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatLineProtocol(tt.metrics, tt.labels)
+			if got != tt.expected {
+				t.Errorf("FormatLineProtocol() =\n%q\nwant\n%q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportLineProtocol(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 5})
+
+	path := filepath.Join(t.TempDir(), "metrics.lp")
+	if err := collector.ExportLineProtocol(path); err != nil {
+		t.Fatalf("ExportLineProtocol() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read line protocol file: %v", err)
+	}
+	if !contains(string(data), "openapi_generation,service=funding success=true") {
+		t.Errorf("unexpected line protocol output: %q", string(data))
+	}
+}
+
+func TestPushLineProtocol(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	collector := NewCollector()
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 5})
+
+	if err := collector.PushLineProtocol(context.Background(), server.URL); err != nil {
+		t.Fatalf("PushLineProtocol() error = %v", err)
+	}
+	if !contains(received, "service=funding") {
+		t.Errorf("server received %q, want line protocol for funding", received)
+	}
+}
+
+func TestPushLineProtocolErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	collector := NewCollector()
+	err := collector.PushLineProtocol(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("PushLineProtocol() expected error for non-2xx status")
+	}
+	if !contains(err.Error(), "bucket not found") {
+		t.Errorf("PushLineProtocol() error = %q, should include response body", err.Error())
+	}
+}
+
+func TestPushLineProtocolOffline(t *testing.T) {
+	network.SetOffline(true)
+	defer network.SetOffline(false)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	collector := NewCollector()
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 5})
+
+	if err := collector.PushLineProtocol(context.Background(), server.URL); err == nil {
+		t.Fatal("PushLineProtocol() expected error in offline mode")
+	}
+	if requests != 0 {
+		t.Errorf("server received %d requests in offline mode, want 0", requests)
+	}
+}
@@ -10,32 +10,76 @@ import (
 
 // Metrics holds aggregated generation metrics
 type Metrics struct {
-	mu                sync.RWMutex
-	TotalSpecs        int             `json:"total_specs"`
-	SuccessfulSpecs   int             `json:"successful_specs"`
-	FailedSpecs       int             `json:"failed_specs"`
-	CachedSpecs       int             `json:"cached_specs"`
-	TotalDurationMs   int64           `json:"total_duration_ms"`
-	AverageDurationMs int64           `json:"average_duration_ms"`
-	StartTime         time.Time       `json:"start_time"`
-	EndTime           time.Time       `json:"end_time"`
-	SpecMetrics       []SpecMetric    `json:"spec_metrics"`
+	TotalSpecs        int          `json:"total_specs"`
+	SuccessfulSpecs   int          `json:"successful_specs"`
+	FailedSpecs       int          `json:"failed_specs"`
+	CachedSpecs       int          `json:"cached_specs"`
+	TotalDurationMs   int64        `json:"total_duration_ms"`
+	AverageDurationMs int64        `json:"average_duration_ms"`
+	StartTime         time.Time    `json:"start_time"`
+	EndTime           time.Time    `json:"end_time"`
+	SpecMetrics       []SpecMetric `json:"spec_metrics"`
+	// Labels are custom labels (e.g., team, environment) attached to all metrics
+	Labels map[string]string `json:"labels,omitempty"`
+	// ParsesReused counts spec reads served from the parsed-spec cache
+	ParsesReused int `json:"parses_reused"`
+	// ParsesExecuted counts spec reads that parsed the file
+	ParsesExecuted int `json:"parses_executed"`
+	// Progress is the progress of the current generation cycle, once one has started
+	Progress *CycleProgress `json:"progress,omitempty"`
+}
+
+// CycleProgress is the progress of the current generation cycle and the outcome of the last
+// completed one. It is kept across Reset, so a watch daemon reports it during and between
+// cycles.
+type CycleProgress struct {
+	// Cycle is the number of cycles started, including the current one
+	Cycle int `json:"cycle"`
+	// InProgress reports whether a cycle is running
+	InProgress bool `json:"in_progress"`
+	// SpecsPlanned is the number of specs of the current (or last) cycle
+	SpecsPlanned int `json:"specs_planned"`
+	// SpecsProcessed is the number of specs of the current (or last) cycle recorded so far
+	SpecsProcessed int `json:"specs_processed"`
+	// CompletedCycles is the number of cycles that ended
+	CompletedCycles int `json:"completed_cycles"`
+	// LastCycleSuccessRate is the success rate, as a percentage, of the last completed cycle
+	LastCycleSuccessRate float64 `json:"last_cycle_success_rate"`
+	// LastCycleCompletedAt is when the last completed cycle ended
+	LastCycleCompletedAt *time.Time `json:"last_cycle_completed_at,omitempty"`
 }
 
 // SpecMetric holds metrics for a single spec generation
 type SpecMetric struct {
-	SpecPath      string    `json:"spec_path"`
-	ServiceName   string    `json:"service_name"`
-	Success       bool      `json:"success"`
-	Cached        bool      `json:"cached"`
-	DurationMs    int64     `json:"duration_ms"`
-	Error         string    `json:"error,omitempty"`
-	GeneratedAt   time.Time `json:"generated_at"`
+	SpecPath    string `json:"spec_path"`
+	ServiceName string `json:"service_name"`
+	Success     bool   `json:"success"`
+	Cached      bool   `json:"cached"`
+	DurationMs  int64  `json:"duration_ms"`
+	// OperationCount is the number of operations in the spec, used to compare
+	// generation cost relative to spec size
+	OperationCount int       `json:"operation_count"`
+	Error          string    `json:"error,omitempty"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// MsPerOperation returns the generation duration per spec operation,
+// or 0 when the operation count is unknown
+func (m SpecMetric) MsPerOperation() float64 {
+	if m.OperationCount <= 0 {
+		return 0
+	}
+	return float64(m.DurationMs) / float64(m.OperationCount)
 }
 
 // Collector collects metrics during generation
 type Collector struct {
+	mu      sync.RWMutex
 	metrics *Metrics
+
+	// progress and cycleSuccessful track the generation cycle, independently of Reset
+	progress        CycleProgress
+	cycleSuccessful int
 }
 
 // NewCollector creates a new metrics collector
@@ -48,10 +92,39 @@ func NewCollector() *Collector {
 	}
 }
 
+// SetLabels attaches custom labels (e.g., team, environment) to all exported metrics
+func (c *Collector) SetLabels(labels map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(labels) == 0 {
+		c.metrics.Labels = nil
+		return
+	}
+
+	c.metrics.Labels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		c.metrics.Labels[k] = v
+	}
+}
+
+// Reset clears all recorded metrics and restarts timing, so a collector reused across runs
+// (e.g. in watch mode) reports only the latest run. Labels are kept.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics = &Metrics{
+		SpecMetrics: make([]SpecMetric, 0),
+		StartTime:   time.Now(),
+		Labels:      c.metrics.Labels,
+	}
+}
+
 // RecordSpec records metrics for a single spec generation
 func (c *Collector) RecordSpec(metric SpecMetric) {
-	c.metrics.mu.Lock()
-	defer c.metrics.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	c.metrics.TotalSpecs++
 	if metric.Success {
@@ -65,12 +138,68 @@ func (c *Collector) RecordSpec(metric SpecMetric) {
 
 	c.metrics.TotalDurationMs += metric.DurationMs
 	c.metrics.SpecMetrics = append(c.metrics.SpecMetrics, metric)
+
+	if c.progress.InProgress {
+		c.progress.SpecsProcessed++
+		if metric.Success {
+			c.cycleSuccessful++
+		}
+	}
+}
+
+// StartCycle marks the start of a generation cycle over plannedSpecs specs. Specs recorded
+// until EndCycle count toward its progress.
+func (c *Collector) StartCycle(plannedSpecs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.progress.Cycle++
+	c.progress.InProgress = true
+	c.progress.SpecsPlanned = plannedSpecs
+	c.progress.SpecsProcessed = 0
+	c.cycleSuccessful = 0
+}
+
+// EndCycle marks the end of the current generation cycle and records its success rate.
+// It does nothing if no cycle is in progress.
+func (c *Collector) EndCycle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.progress.InProgress {
+		return
+	}
+	c.progress.InProgress = false
+	c.progress.CompletedCycles++
+	c.progress.LastCycleSuccessRate = 0
+	if c.progress.SpecsProcessed > 0 {
+		c.progress.LastCycleSuccessRate = float64(c.cycleSuccessful) / float64(c.progress.SpecsProcessed) * 100.0
+	}
+	completedAt := time.Now()
+	c.progress.LastCycleCompletedAt = &completedAt
+}
+
+// Progress returns the progress of the current generation cycle
+func (c *Collector) Progress() CycleProgress {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.progress
+}
+
+// RecordParses adds spec reads served from the parsed-spec cache and reads that decoded the file
+func (c *Collector) RecordParses(reused, executed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics.ParsesReused += reused
+	c.metrics.ParsesExecuted += executed
 }
 
 // Finalize calculates final metrics before export
 func (c *Collector) Finalize() {
-	c.metrics.mu.Lock()
-	defer c.metrics.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	c.metrics.EndTime = time.Now()
 	if c.metrics.TotalSpecs > 0 {
@@ -82,8 +211,8 @@ func (c *Collector) Finalize() {
 func (c *Collector) Export(path string) error {
 	c.Finalize()
 
-	c.metrics.mu.RLock()
-	defer c.metrics.mu.RUnlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	data, err := json.MarshalIndent(c.metrics, "", "  ")
 	if err != nil {
@@ -97,10 +226,24 @@ func (c *Collector) Export(path string) error {
 	return nil
 }
 
+// Load reads a metrics file written by Export
+func Load(path string) (*Metrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics file: %w", err)
+	}
+
+	var m Metrics
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics file: %w", err)
+	}
+	return &m, nil
+}
+
 // Summary returns a human-readable summary
 func (c *Collector) Summary() string {
-	c.metrics.mu.RLock()
-	defer c.metrics.mu.RUnlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	totalSecs := c.metrics.TotalDurationMs / 1000
 	avgSecs := c.metrics.AverageDurationMs / 1000
@@ -118,21 +261,31 @@ func (c *Collector) Summary() string {
 
 // GetMetrics returns a copy of the current metrics (safe for concurrent access)
 func (c *Collector) GetMetrics() Metrics {
-	c.metrics.mu.RLock()
-	defer c.metrics.mu.RUnlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	// Create a deep copy to avoid race conditions
 	metricsCopy := *c.metrics
 	metricsCopy.SpecMetrics = make([]SpecMetric, len(c.metrics.SpecMetrics))
 	copy(metricsCopy.SpecMetrics, c.metrics.SpecMetrics)
+	if c.metrics.Labels != nil {
+		metricsCopy.Labels = make(map[string]string, len(c.metrics.Labels))
+		for k, v := range c.metrics.Labels {
+			metricsCopy.Labels[k] = v
+		}
+	}
+	if c.progress.Cycle > 0 {
+		progress := c.progress
+		metricsCopy.Progress = &progress
+	}
 
 	return metricsCopy
 }
 
 // SuccessRate returns the success rate as a percentage
 func (c *Collector) SuccessRate() float64 {
-	c.metrics.mu.RLock()
-	defer c.metrics.mu.RUnlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	if c.metrics.TotalSpecs == 0 {
 		return 0.0
@@ -142,8 +295,8 @@ func (c *Collector) SuccessRate() float64 {
 
 // CacheHitRate returns the cache hit rate as a percentage
 func (c *Collector) CacheHitRate() float64 {
-	c.metrics.mu.RLock()
-	defer c.metrics.mu.RUnlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	if c.metrics.TotalSpecs == 0 {
 		return 0.0
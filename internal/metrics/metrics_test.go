@@ -3,6 +3,7 @@ package metrics
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -102,6 +103,120 @@ func TestFinalize(t *testing.T) {
 	}
 }
 
+func TestReset(t *testing.T) {
+	collector := NewCollector()
+	collector.SetLabels(map[string]string{"team": "platform"})
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 1000})
+	collector.RecordSpec(SpecMetric{ServiceName: "holidays", Success: false, DurationMs: 500})
+	collector.RecordParses(2, 3)
+	collector.Finalize()
+	startBefore := collector.GetMetrics().StartTime
+
+	time.Sleep(time.Millisecond)
+	collector.Reset()
+
+	metrics := collector.GetMetrics()
+	if metrics.TotalSpecs != 0 || metrics.SuccessfulSpecs != 0 || metrics.FailedSpecs != 0 || len(metrics.SpecMetrics) != 0 {
+		t.Errorf("Reset() left recorded specs: %+v", metrics)
+	}
+	if metrics.TotalDurationMs != 0 || metrics.AverageDurationMs != 0 || metrics.ParsesReused != 0 || metrics.ParsesExecuted != 0 {
+		t.Errorf("Reset() left totals: %+v", metrics)
+	}
+	if !metrics.EndTime.IsZero() || !metrics.StartTime.After(startBefore) {
+		t.Errorf("Reset() should restart timing: start %v (was %v), end %v", metrics.StartTime, startBefore, metrics.EndTime)
+	}
+	if metrics.Labels["team"] != "platform" {
+		t.Errorf("Reset() should keep labels, got %v", metrics.Labels)
+	}
+
+	// The collector stays usable
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, Cached: true, DurationMs: 200})
+	collector.Finalize()
+	metrics = collector.GetMetrics()
+	if metrics.TotalSpecs != 1 || metrics.CachedSpecs != 1 || metrics.AverageDurationMs != 200 {
+		t.Errorf("recording after Reset() = %+v, want a single cached spec", metrics)
+	}
+}
+
+func TestCycleProgress(t *testing.T) {
+	collector := NewCollector()
+	if collector.GetMetrics().Progress != nil {
+		t.Error("Progress should be omitted before the first cycle")
+	}
+
+	collector.StartCycle(3)
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true})
+	progress := collector.Progress()
+	if progress.Cycle != 1 || !progress.InProgress || progress.SpecsPlanned != 3 || progress.SpecsProcessed != 1 {
+		t.Errorf("mid-cycle progress = %+v, want cycle 1 in progress with 1/3 specs", progress)
+	}
+	if progress.CompletedCycles != 0 || progress.LastCycleCompletedAt != nil {
+		t.Errorf("mid-cycle progress = %+v, want no completed cycle", progress)
+	}
+
+	collector.RecordSpec(SpecMetric{ServiceName: "holidays", Success: false})
+	collector.RecordSpec(SpecMetric{ServiceName: "payments", Success: true})
+	collector.RecordSpec(SpecMetric{ServiceName: "users", Success: true})
+	collector.EndCycle()
+	collector.EndCycle() // no cycle in progress: ignored
+
+	progress = collector.Progress()
+	if progress.InProgress || progress.SpecsProcessed != 4 || progress.CompletedCycles != 1 || progress.LastCycleCompletedAt == nil {
+		t.Errorf("completed progress = %+v, want 4 specs in 1 completed cycle", progress)
+	}
+	if progress.LastCycleSuccessRate != 75.0 {
+		t.Errorf("LastCycleSuccessRate = %v, want 75", progress.LastCycleSuccessRate)
+	}
+
+	// Reset starts a new run's metrics but keeps the cycle history
+	collector.Reset()
+	collector.StartCycle(2)
+	exported := collector.GetMetrics().Progress
+	if exported == nil || exported.Cycle != 2 || exported.SpecsProcessed != 0 || exported.LastCycleSuccessRate != 75.0 {
+		t.Errorf("progress after Reset() = %+v, want cycle 2 keeping the last success rate", exported)
+	}
+
+	// Specs recorded outside a cycle do not count toward it
+	collector.EndCycle()
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true})
+	if progress := collector.Progress(); progress.SpecsProcessed != 0 || progress.LastCycleSuccessRate != 0 {
+		t.Errorf("progress = %+v, want an empty second cycle", progress)
+	}
+}
+
+func TestCycleProgressPrometheus(t *testing.T) {
+	collector := NewCollector()
+	if body := FormatPrometheus(collector.GetMetrics()); contains(body, "openapi_generation_cycle") {
+		t.Errorf("cycle gauges should be omitted before the first cycle:\n%s", body)
+	}
+
+	collector.StartCycle(2)
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true})
+	body := FormatPrometheus(collector.GetMetrics())
+	for _, want := range []string{
+		"openapi_generation_cycle 1\n",
+		"openapi_generation_cycle_in_progress 1\n",
+		"openapi_generation_cycle_specs_planned 2\n",
+		"openapi_generation_cycle_specs_processed 1\n",
+	} {
+		if !contains(body, want) {
+			t.Errorf("Prometheus output missing %q:\n%s", want, body)
+		}
+	}
+	if contains(body, "openapi_generation_last_cycle_success_rate") {
+		t.Errorf("success rate should be omitted before a cycle completes:\n%s", body)
+	}
+
+	collector.RecordSpec(SpecMetric{ServiceName: "holidays", Success: false})
+	collector.EndCycle()
+	body = FormatPrometheus(collector.GetMetrics())
+	for _, want := range []string{"openapi_generation_cycle_in_progress 0\n", "openapi_generation_last_cycle_success_rate 50\n"} {
+		if !contains(body, want) {
+			t.Errorf("Prometheus output missing %q:\n%s", want, body)
+		}
+	}
+}
+
 func TestExport(t *testing.T) {
 	collector := NewCollector()
 
@@ -141,6 +256,60 @@ func TestExport(t *testing.T) {
 	}
 }
 
+func TestExportWithLabels(t *testing.T) {
+	collector := NewCollector()
+	labels := map[string]string{"team": "platform", "environment": "ci"}
+	collector.SetLabels(labels)
+	collector.RecordSpec(SpecMetric{SpecPath: "/spec.json", ServiceName: "svc", Success: true})
+
+	// Mutating the input map must not affect the collector
+	labels["team"] = "changed"
+
+	tmpFile := t.TempDir() + "/metrics.json"
+	if err := collector.Export(tmpFile); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read exported file: %v", err)
+	}
+
+	var exported Metrics
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to unmarshal exported metrics: %v", err)
+	}
+
+	if exported.Labels["team"] != "platform" {
+		t.Errorf("Expected label team=platform, got %q", exported.Labels["team"])
+	}
+	if exported.Labels["environment"] != "ci" {
+		t.Errorf("Expected label environment=ci, got %q", exported.Labels["environment"])
+	}
+}
+
+func TestLoad(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordSpec(SpecMetric{SpecPath: "/a.json", ServiceName: "a", Success: true, Cached: true})
+	collector.RecordSpec(SpecMetric{SpecPath: "/b.json", ServiceName: "b", Success: true})
+
+	tmpFile := t.TempDir() + "/metrics.json"
+	if err := collector.Export(tmpFile); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	loaded, err := Load(tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.TotalSpecs != 2 || loaded.CachedSpecs != 1 || len(loaded.SpecMetrics) != 2 {
+		t.Errorf("Load() = %+v, want the exported metrics", loaded)
+	}
+
+	if _, err := Load(t.TempDir() + "/missing.json"); err == nil {
+		t.Error("Load() of a missing file should fail")
+	}
+}
+
 func TestSummary(t *testing.T) {
 	collector := NewCollector()
 
@@ -331,3 +500,48 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSpecMetricOperationCount(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 1200, OperationCount: 40})
+
+	metric := collector.GetMetrics().SpecMetrics[0]
+	if metric.OperationCount != 40 {
+		t.Errorf("OperationCount = %d, want 40", metric.OperationCount)
+	}
+
+	tests := []struct {
+		name   string
+		metric SpecMetric
+		want   float64
+	}{
+		{"per operation", metric, 30},
+		{"fractional", SpecMetric{DurationMs: 10, OperationCount: 4}, 2.5},
+		{"unknown operation count", SpecMetric{DurationMs: 500}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.MsPerOperation(); got != tt.want {
+				t.Errorf("MsPerOperation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordParses(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordParses(3, 1)
+	collector.RecordParses(2, 0)
+
+	m := collector.GetMetrics()
+	if m.ParsesReused != 5 || m.ParsesExecuted != 1 {
+		t.Errorf("parses = %d reused, %d executed, want 5, 1", m.ParsesReused, m.ParsesExecuted)
+	}
+
+	output := FormatPrometheus(m)
+	for _, want := range []string{"openapi_generation_spec_parses_reused 5", "openapi_generation_spec_parses_executed 1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Prometheus output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// prometheusLabelEscaper escapes label values per the Prometheus text format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// FormatPrometheus renders metrics in the Prometheus text exposition format.
+// Custom labels are added to every series.
+func FormatPrometheus(m Metrics) string {
+	var b strings.Builder
+
+	gauge := func(name, help string, value int64, labels string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s%s %d\n", name, help, name, name, labels, value)
+	}
+
+	common := prometheusLabels(m.Labels, nil)
+	gauge("openapi_generation_specs_total", "Specs processed in the current run.", int64(m.TotalSpecs), common)
+	gauge("openapi_generation_specs_successful", "Specs generated successfully in the current run.", int64(m.SuccessfulSpecs), common)
+	gauge("openapi_generation_specs_failed", "Specs that failed in the current run.", int64(m.FailedSpecs), common)
+	gauge("openapi_generation_specs_cached", "Specs served from cache in the current run.", int64(m.CachedSpecs), common)
+	gauge("openapi_generation_spec_parses_reused", "Spec reads served from the parsed-spec cache in the current run.", int64(m.ParsesReused), common)
+	gauge("openapi_generation_spec_parses_executed", "Spec reads that parsed the file in the current run.", int64(m.ParsesExecuted), common)
+	gauge("openapi_generation_duration_ms_total", "Total generation time in milliseconds in the current run.", m.TotalDurationMs, common)
+
+	if p := m.Progress; p != nil {
+		inProgress := int64(0)
+		if p.InProgress {
+			inProgress = 1
+		}
+		gauge("openapi_generation_cycle", "Generation cycles started.", int64(p.Cycle), common)
+		gauge("openapi_generation_cycle_in_progress", "Whether a generation cycle is running (1) or not (0).", inProgress, common)
+		gauge("openapi_generation_cycle_specs_planned", "Specs of the current generation cycle.", int64(p.SpecsPlanned), common)
+		gauge("openapi_generation_cycle_specs_processed", "Specs of the current generation cycle processed so far.", int64(p.SpecsProcessed), common)
+		if p.CompletedCycles > 0 {
+			name := "openapi_generation_last_cycle_success_rate"
+			fmt.Fprintf(&b, "# HELP %s Success rate in percent of the last completed generation cycle.\n# TYPE %s gauge\n%s%s %g\n",
+				name, name, name, common, p.LastCycleSuccessRate)
+		}
+	}
+
+	if len(m.SpecMetrics) > 0 {
+		b.WriteString("# HELP openapi_generation_spec_duration_ms Generation time per spec in milliseconds.\n")
+		b.WriteString("# TYPE openapi_generation_spec_duration_ms gauge\n")
+		for _, spec := range m.SpecMetrics {
+			labels := prometheusLabels(m.Labels, [][2]string{
+				{"service", spec.ServiceName},
+				{"success", fmt.Sprintf("%t", spec.Success)},
+				{"cached", fmt.Sprintf("%t", spec.Cached)},
+			})
+			fmt.Fprintf(&b, "openapi_generation_spec_duration_ms%s %d\n", labels, spec.DurationMs)
+		}
+	}
+
+	return b.String()
+}
+
+// prometheusLabels renders a label set: the given pairs first, then custom labels sorted by key.
+// Custom labels never override the given pairs.
+func prometheusLabels(custom map[string]string, pairs [][2]string) string {
+	reserved := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		reserved[pair[0]] = true
+	}
+
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		if !reserved[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(pairs)+len(keys))
+	for _, pair := range pairs {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pair[0], prometheusLabelEscaper.Replace(pair[1])))
+	}
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, k, prometheusLabelEscaper.Replace(custom[k])))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
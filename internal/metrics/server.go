@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// serverShutdownTimeout bounds how long in-flight requests may take once the server stops
+const serverShutdownTimeout = 5 * time.Second
+
+// Server exposes health and metrics endpoints over HTTP so orchestration can monitor
+// a long-running generator process:
+//   - /healthz returns {"status":"ok"}
+//   - /metrics returns the current metrics as JSON, or in Prometheus text format
+//     with ?format=prometheus or an Accept header asking for text/plain
+type Server struct {
+	addr      string
+	collector *Collector
+}
+
+// NewServer creates a metrics server listening on addr (e.g., ":9090") for the collector's metrics
+func NewServer(addr string, collector *Collector) *Server {
+	return &Server{
+		addr:      addr,
+		collector: collector,
+	}
+}
+
+// Handler returns the HTTP handler serving the health and metrics endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+// Run serves requests until ctx is cancelled, then shuts the server down gracefully
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+	log.Printf("Metrics server listening on %s", s.addr)
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("metrics server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down metrics server: %w", err)
+	}
+	if err := <-errChan; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+
+	log.Printf("Metrics server stopped")
+	return nil
+}
+
+// handleHealth reports that the process is alive
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}` + "\n"))
+}
+
+// handleMetrics serves the current metrics as JSON or Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	current := s.collector.GetMetrics()
+
+	if r.URL.Query().Get("format") == "prometheus" || strings.Contains(r.Header.Get("Accept"), "text/plain") {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(FormatPrometheus(current)))
+		return
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServer() *Server {
+	collector := NewCollector()
+	collector.SetLabels(map[string]string{"team": "platform"})
+	collector.RecordSpec(SpecMetric{ServiceName: "funding", Success: true, DurationMs: 120})
+	collector.RecordSpec(SpecMetric{ServiceName: "holidays", Success: false, DurationMs: 30, Error: "ogen failed"})
+	return NewServer(":0", collector)
+}
+
+func TestServerHealth(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", rec.Code)
+	}
+	if body := rec.Body.String(); body != "{\"status\":\"ok\"}\n" {
+		t.Errorf("/healthz body = %q", body)
+	}
+}
+
+func TestServerMetricsJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got Metrics
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	if got.TotalSpecs != 2 || got.SuccessfulSpecs != 1 || got.FailedSpecs != 1 {
+		t.Errorf("metrics = %+v, want 2 total, 1 successful, 1 failed", got)
+	}
+	if got.Labels["team"] != "platform" {
+		t.Errorf("labels = %v, want team=platform", got.Labels)
+	}
+}
+
+func TestServerMetricsPrometheus(t *testing.T) {
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/metrics?format=prometheus", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			r.Header.Set("Accept", "text/plain")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		newTestServer().Handler().ServeHTTP(rec, req)
+
+		body := rec.Body.String()
+		for _, want := range []string{
+			"# TYPE openapi_generation_specs_total gauge\nopenapi_generation_specs_total{team=\"platform\"} 2\n",
+			"openapi_generation_specs_failed{team=\"platform\"} 1\n",
+			"openapi_generation_spec_duration_ms{service=\"funding\",success=\"true\",cached=\"false\",team=\"platform\"} 120\n",
+		} {
+			if !contains(body, want) {
+				t.Errorf("%s: Prometheus output missing %q:\n%s", req.URL, want, body)
+			}
+		}
+	}
+}
+
+func TestServerRunStopsWithContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server := NewServer(addr, NewCollector())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Run(ctx) }()
+
+	// Wait for the server to accept requests
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get("http://" + addr + "/healthz")
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("server did not start: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !contains(string(body), "ok") {
+		t.Errorf("/healthz body = %q", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after context cancellation")
+	}
+}
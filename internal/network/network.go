@@ -0,0 +1,145 @@
+package network
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// Version is the openapi-go version reported in the User-Agent header and by the version command.
+// Set at build time with -ldflags "-X gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network.Version=v1.2.3".
+var Version = "dev"
+
+// offline disables all network operations when set
+var offline atomic.Bool
+
+// proxy overrides the proxy from the environment when set
+var proxy atomic.Pointer[url.URL]
+
+// sharedTransport is the base transport of all clients, so connections are reused across them.
+// HTTP/2 is negotiated with servers that support it, also through proxies.
+var sharedTransport = newBaseTransport()
+
+// UserAgent returns the User-Agent sent with all outbound requests (e.g., "openapi-go/v1.2.3")
+func UserAgent() string {
+	return "openapi-go/" + Version
+}
+
+// SetOffline enables or disables offline mode for all network operations
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// Offline reports whether offline mode is enabled
+func Offline() bool {
+	return offline.Load()
+}
+
+// ParseProxyURL parses a proxy URL such as "http://proxy.corp:3128".
+// Supported schemes are http, https and socks5.
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// SetProxy routes all outbound requests through the given proxy URL, overriding
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY. An empty URL restores the environment settings.
+func SetProxy(rawURL string) error {
+	if rawURL == "" {
+		proxy.Store(nil)
+		return nil
+	}
+	u, err := ParseProxyURL(rawURL)
+	if err != nil {
+		return err
+	}
+	proxy.Store(u)
+	return nil
+}
+
+// proxyForRequest returns the configured proxy, or the proxy from the environment
+func proxyForRequest(req *http.Request) (*url.URL, error) {
+	if u := proxy.Load(); u != nil {
+		return u, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// newBaseTransport returns a copy of the default transport using the configured proxy
+func newBaseTransport() *http.Transport {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = proxyForRequest
+	base.ForceAttemptHTTP2 = true
+	return base
+}
+
+// OfflineError is returned when a network operation is attempted in offline mode
+type OfflineError struct {
+	// Operation describes the blocked network operation
+	Operation string
+
+	// Guidance tells the user how to proceed without network access
+	Guidance string
+}
+
+// Error returns the error message including guidance
+func (e *OfflineError) Error() string {
+	msg := fmt.Sprintf("%s requires network access, but offline mode is enabled (offline: true)", e.Operation)
+	if e.Guidance != "" {
+		msg += "; " + e.Guidance
+	}
+	return msg
+}
+
+// Check returns an OfflineError if offline mode is enabled, so callers fail fast before any network access
+func Check(operation, guidance string) error {
+	if !Offline() {
+		return nil
+	}
+	return &OfflineError{Operation: operation, Guidance: guidance}
+}
+
+// NewHTTPClient returns an HTTP client that sets the openapi-go User-Agent,
+// uses the configured proxy (see SetProxy) and refuses all requests in offline mode
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &transport{base: sharedTransport},
+	}
+}
+
+// transport enforces offline mode and the User-Agent on outbound requests
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request unless offline mode is enabled
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := Check(fmt.Sprintf("%s %s", req.Method, req.URL.Redacted()), "disable offline mode to allow it"); err != nil {
+		// RoundTrip must always close the request body
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent())
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,135 @@
+package postprocessor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompileCheckProcessor verifies that the generated client compiles with the active Go toolchain.
+// If a minimum Go version is configured, the toolchain version is checked first.
+type CompileCheckProcessor struct {
+	minGoVersion string
+
+	// goVersion returns the active toolchain version (e.g., "go1.24.0"); overridable for testing
+	goVersion func(ctx context.Context) (string, error)
+
+	versionOnce sync.Once
+	versionErr  error
+}
+
+// NewCompileCheckProcessor creates a new compile check processor.
+// minGoVersion is optional (e.g., "1.22"); empty disables the toolchain version check.
+func NewCompileCheckProcessor(minGoVersion string) *CompileCheckProcessor {
+	return &CompileCheckProcessor{
+		minGoVersion: minGoVersion,
+		goVersion:    activeGoVersion,
+	}
+}
+
+// Name returns the processor name
+func (p *CompileCheckProcessor) Name() string {
+	return "CompileCheck"
+}
+
+// Process checks the toolchain version and compiles the generated client package
+func (p *CompileCheckProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	// The toolchain doesn't change during a run, so check it only once
+	p.versionOnce.Do(func() {
+		p.versionErr = p.checkGoVersion(ctx)
+	})
+	if p.versionErr != nil {
+		return p.versionErr
+	}
+
+	log.Printf("Compiling generated client in %s...", spec.ClientPath)
+	cmd := exec.CommandContext(ctx, "go", "build", ".")
+	cmd.Dir = spec.ClientPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("generated client does not compile: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// checkGoVersion verifies the active toolchain meets the configured minimum version
+func (p *CompileCheckProcessor) checkGoVersion(ctx context.Context) error {
+	if p.minGoVersion == "" {
+		return nil
+	}
+
+	have, err := p.goVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect Go toolchain version: %w", err)
+	}
+
+	if !goVersionSatisfies(have, p.minGoVersion) {
+		return fmt.Errorf("Go toolchain %s is older than min_go_version %s required for generated code", have, p.minGoVersion)
+	}
+
+	log.Printf("Go toolchain %s satisfies min_go_version %s", have, p.minGoVersion)
+	return nil
+}
+
+// activeGoVersion returns the version of the go command in PATH (e.g., "go1.24.0")
+func activeGoVersion(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// goVersionSatisfies reports whether Go version have is at least want.
+// Both accept forms like "go1.22.3", "1.22" or "v1.22"; missing components count as zero
+// and pre-release suffixes (e.g., "rc1") are ignored.
+func goVersionSatisfies(have, want string) bool {
+	haveParts := parseGoVersion(have)
+	wantParts := parseGoVersion(want)
+
+	for i := 0; i < len(haveParts) || i < len(wantParts); i++ {
+		var h, w int
+		if i < len(haveParts) {
+			h = haveParts[i]
+		}
+		if i < len(wantParts) {
+			w = wantParts[i]
+		}
+		if h != w {
+			return h > w
+		}
+	}
+	return true
+}
+
+// parseGoVersion splits a Go version string into numeric components
+func parseGoVersion(version string) []int {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(version, "go")
+	version = strings.TrimPrefix(version, "v")
+
+	// Drop toolchain suffixes such as " X:boringcrypto"
+	if i := strings.IndexAny(version, " -+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, component := range strings.Split(version, ".") {
+		// Keep only the leading digits (e.g., "22rc1" -> 22)
+		end := 0
+		for end < len(component) && component[end] >= '0' && component[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(component[:end])
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,71 @@
+package postprocessor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGoVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		have string
+		want string
+		ok   bool
+	}{
+		{"go1.22.0", "1.22", true},
+		{"go1.21.5", "1.22", false},
+		{"go1.22.3", "1.22.4", false},
+		{"go1.23", "1.22.9", true},
+		{"go1.24.0", "go1.24", true},
+		{"1.22", "v1.21", true},
+		{"go1.22rc1", "1.22", true},
+		{"go1.22.1 X:boringcrypto", "1.22.1", true},
+		{"go2.0", "1.99", true},
+		{"go1.9", "1.10", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.have+" vs "+tt.want, func(t *testing.T) {
+			if got := goVersionSatisfies(tt.have, tt.want); got != tt.ok {
+				t.Errorf("goVersionSatisfies(%q, %q) = %v, want %v", tt.have, tt.want, got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCompileCheckProcessorMinGoVersion(t *testing.T) {
+	processor := NewCompileCheckProcessor("1.22")
+	calls := 0
+	processor.goVersion = func(ctx context.Context) (string, error) {
+		calls++
+		return "go1.21.5", nil
+	}
+
+	spec := ProcessSpec{ClientPath: t.TempDir(), ServiceName: "test"}
+	for i := 0; i < 2; i++ {
+		err := processor.Process(context.Background(), spec)
+		if err == nil {
+			t.Fatal("Process() expected error for toolchain older than min_go_version")
+		}
+		if !strings.Contains(err.Error(), "go1.21.5") || !strings.Contains(err.Error(), "1.22") {
+			t.Errorf("Process() error = %q, should mention both versions", err.Error())
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("goVersion called %d times, want 1", calls)
+	}
+}
+
+func TestCompileCheckProcessorVersionDetectionError(t *testing.T) {
+	processor := NewCompileCheckProcessor("1.22")
+	processor.goVersion = func(ctx context.Context) (string, error) {
+		return "", errors.New("go not found")
+	}
+
+	err := processor.Process(context.Background(), ProcessSpec{ClientPath: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "go not found") {
+		t.Errorf("Process() error = %v, want version detection error", err)
+	}
+}
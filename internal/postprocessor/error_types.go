@@ -0,0 +1,268 @@
+package postprocessor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// ErrorTypesFileName is the name of the generated error types file
+const ErrorTypesFileName = "api_errors_gen.go"
+
+// ErrorTypesProcessor generates typed Go errors for the 4xx/5xx responses documented on each
+// operation, plus a constructor per operation mapping a status code to its error type.
+// Operations without documented error responses get no types.
+type ErrorTypesProcessor struct{}
+
+// NewErrorTypesProcessor creates a new error types processor
+func NewErrorTypesProcessor() *ErrorTypesProcessor {
+	return &ErrorTypesProcessor{}
+}
+
+// Name returns the processor name
+func (p *ErrorTypesProcessor) Name() string {
+	return "ErrorTypes"
+}
+
+// errorResponse is a documented error response of an operation
+type errorResponse struct {
+	status      string // "404" or a range such as "5XX"
+	typeName    string
+	description string
+}
+
+// errorOperation is an operation with documented error responses
+type errorOperation struct {
+	operationID string // operationId, or "METHOD /path" if there is none
+	goName      string
+	responses   []errorResponse
+}
+
+// Process writes api_errors_gen.go to the client directory
+func (p *ErrorTypesProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	doc, err := spec.LoadDocument()
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+	operations := collectErrorOperations(doc)
+	if len(operations) == 0 {
+		log.Printf("No documented error responses in %s, skipping %s", spec.ServiceName, ErrorTypesFileName)
+		return nil
+	}
+
+	source, err := renderErrorTypes(spec.PackageName, operations)
+	if err != nil {
+		return err
+	}
+
+	outputPath := filepath.Join(spec.ClientPath, ErrorTypesFileName)
+	if err := writeGeneratedFile(outputPath, source, spec.fileMode()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ErrorTypesFileName, err)
+	}
+
+	log.Printf("Generated error types for %d operation(s): %s", len(operations), outputPath)
+	return nil
+}
+
+// collectErrorOperations returns the operations of a spec that document 4xx/5xx responses,
+// sorted by path and method
+func collectErrorOperations(doc map[string]interface{}) []errorOperation {
+	paths, _ := doc["paths"].(map[string]interface{})
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	var operations []errorOperation
+	usedNames := make(map[string]bool)
+	for _, path := range pathKeys {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range spec.HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			responses, _ := operation["responses"].(map[string]interface{})
+			operationID, _ := operation["operationId"].(string)
+			nameSource := operationID
+			if operationID == "" {
+				operationID = strings.ToUpper(method) + " " + path
+				nameSource = method + " " + path
+			}
+			goName := uniqueGoName(goIdentifier(nameSource), usedNames)
+
+			op := errorOperation{operationID: operationID, goName: goName}
+			for _, status := range sortedStatuses(responses) {
+				response, _ := responses[status].(map[string]interface{})
+				description, _ := response["description"].(string)
+				op.responses = append(op.responses, errorResponse{
+					status:      status,
+					typeName:    goName + statusName(status) + "Error",
+					description: description,
+				})
+			}
+			if len(op.responses) > 0 {
+				operations = append(operations, op)
+			}
+		}
+	}
+	return operations
+}
+
+// sortedStatuses returns the 4xx/5xx status codes and ranges of a responses object, sorted
+func sortedStatuses(responses map[string]interface{}) []string {
+	var statuses []string
+	for status := range responses {
+		if isErrorStatus(status) {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// isErrorStatus reports whether a response key is a 4xx/5xx code or range ("4XX", "5XX")
+func isErrorStatus(status string) bool {
+	if len(status) != 3 || (status[0] != '4' && status[0] != '5') {
+		return false
+	}
+	if strings.EqualFold(status[1:], "XX") {
+		return true
+	}
+	_, err := strconv.Atoi(status)
+	return err == nil
+}
+
+// statusRange returns the status class of a range key ("4XX" -> 4), or 0 for a concrete code
+func statusRange(status string) int {
+	if strings.EqualFold(status[1:], "XX") {
+		return int(status[0] - '0')
+	}
+	return 0
+}
+
+// statusName returns the Go name part for a status, e.g. "NotFound" for 404 and "Status5XX" for 5XX
+func statusName(status string) string {
+	if statusRange(status) > 0 {
+		return "Status" + strings.ToUpper(status)
+	}
+	code, _ := strconv.Atoi(status)
+	if text := http.StatusText(code); text != "" {
+		return goIdentifier(text)
+	}
+	return "Status" + status
+}
+
+// goIdentifier converts an operationId or phrase to an exported Go identifier,
+// e.g. "get-pet_by id" -> "GetPetByID"
+func goIdentifier(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		if strings.EqualFold(part, "id") {
+			b.WriteString("ID")
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Op" + name
+	}
+	return name
+}
+
+// uniqueGoName returns name, adding a numeric suffix if it was already used
+func uniqueGoName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// renderErrorTypes renders and gofmts the error types file
+func renderErrorTypes(packageName string, operations []errorOperation) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by openapi-go postprocessor, DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	b.WriteString(`import "fmt"
+
+// DocumentedAPIError is an error response documented in the API spec.
+// Each documented response has its own type embedding it, so callers can match
+// specific failures with errors.As.
+type DocumentedAPIError struct {
+	// OperationID identifies the operation that failed
+	OperationID string
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Description is the response description from the spec
+	Description string
+}
+
+// Error returns the operation, status code and documented description
+func (e *DocumentedAPIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("%s: status %d", e.OperationID, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: status %d: %s", e.OperationID, e.StatusCode, e.Description)
+}
+`)
+
+	for _, op := range operations {
+		for _, resp := range op.responses {
+			fmt.Fprintf(&b, "\n// %s is the %s response of %s", resp.typeName, resp.status, op.operationID)
+			if resp.description != "" {
+				fmt.Fprintf(&b, ": %s", singleLine(resp.description))
+			}
+			fmt.Fprintf(&b, "\ntype %s struct{ DocumentedAPIError }\n", resp.typeName)
+		}
+
+		fmt.Fprintf(&b, "\n// New%sError returns the typed error for a %s error response status code.\n", op.goName, op.operationID)
+		b.WriteString("// Undocumented status codes return a plain *DocumentedAPIError.\n")
+		fmt.Fprintf(&b, "func New%sError(statusCode int) error {\n", op.goName)
+		b.WriteString("switch {\n")
+		// Concrete codes are sorted before ranges ("404" < "4XX"), so they take precedence
+		for _, resp := range op.responses {
+			if class := statusRange(resp.status); class > 0 {
+				fmt.Fprintf(&b, "case statusCode >= %d && statusCode < %d:\n", class*100, class*100+100)
+			} else {
+				fmt.Fprintf(&b, "case statusCode == %s:\n", resp.status)
+			}
+			fmt.Fprintf(&b, "return &%s{DocumentedAPIError{OperationID: %q, StatusCode: statusCode, Description: %q}}\n",
+				resp.typeName, op.operationID, resp.description)
+		}
+		b.WriteString("}\n")
+		fmt.Fprintf(&b, "return &DocumentedAPIError{OperationID: %q, StatusCode: statusCode}\n}\n", op.operationID)
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated error types: %w", err)
+	}
+	return source, nil
+}
+
+// singleLine collapses whitespace so a description fits in a line comment
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,160 @@
+package postprocessor
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const errorTypesSpec = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}},
+			"post": {
+				"operationId": "createPet",
+				"responses": {
+					"201": {"description": "Created"},
+					"400": {"description": "Invalid pet"},
+					"409": {"description": "Pet already exists"},
+					"5XX": {"description": "Server failure"}
+				}
+			}
+		},
+		"/pets/{id}": {
+			"delete": {"responses": {"404": {"description": "Pet\nnot found"}, "default": {"description": "Error"}}}
+		}
+	}
+}`
+
+func TestErrorTypesProcessor(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(errorTypesSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: dir, ServiceName: "pets", SpecPath: specPath, PackageName: "pets"}
+	if err := NewErrorTypesProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ErrorTypesFileName))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", ErrorTypesFileName, err)
+	}
+	source := string(data)
+
+	// One type per documented 4xx/5xx response, one constructor per operation with error responses
+	wantDecls := []string{
+		"DocumentedAPIError",
+		"CreatePetBadRequestError",
+		"CreatePetConflictError",
+		"CreatePetStatus5XXError",
+		"NewCreatePetError",
+		"DeletePetsIDNotFoundError",
+		"NewDeletePetsIDError",
+	}
+	decls := topLevelDecls(t, source)
+	for _, name := range wantDecls {
+		if !decls[name] {
+			t.Errorf("missing declaration %s in:\n%s", name, source)
+		}
+	}
+	if decls["NewListPetsError"] {
+		t.Error("operations without error responses should not get error types")
+	}
+	if strings.Contains(source, "Default") || strings.Contains(source, "Created") {
+		t.Errorf("only 4xx/5xx responses should get error types:\n%s", source)
+	}
+	if !strings.Contains(source, "// DeletePetsIDNotFoundError is the 404 response of DELETE /pets/{id}: Pet not found\n") {
+		t.Errorf("multi-line description should be collapsed into the doc comment:\n%s", source)
+	}
+
+	typeCheck(t, source)
+}
+
+func TestErrorTypesProcessorNoErrorResponses(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	content := "openapi: 3.0.3\npaths:\n  /health:\n    get:\n      responses:\n        '200':\n          description: OK\n"
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: dir, ServiceName: "health", SpecPath: specPath, PackageName: "health"}
+	if err := NewErrorTypesProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ErrorTypesFileName)); !os.IsNotExist(err) {
+		t.Errorf("%s should not be written without error responses", ErrorTypesFileName)
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"getPet":            "GetPet",
+		"list-pets_by name": "ListPetsByName",
+		"get /pets/{id}":    "GetPetsID",
+		"123abc":            "Op123abc",
+		"Not Found":         "NotFound",
+	}
+	for input, want := range tests {
+		if got := goIdentifier(input); got != want {
+			t.Errorf("goIdentifier(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// topLevelDecls parses Go source and returns the names of its top-level types, functions,
+// variables and constants
+func topLevelDecls(t *testing.T, source string) map[string]bool {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), ErrorTypesFileName, source, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, source)
+	}
+
+	names := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names[d.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch spec := s.(type) {
+				case *ast.TypeSpec:
+					names[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// typeCheck verifies that the generated source compiles
+func typeCheck(t *testing.T, source string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, ErrorTypesFileName, source, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("pets", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("generated source does not type-check: %v\n%s", err, source)
+	}
+}
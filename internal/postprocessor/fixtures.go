@@ -0,0 +1,275 @@
+package postprocessor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// FixturesFileName is the name of the generated fixtures file
+const FixturesFileName = "fixtures_gen.go"
+
+// maxFixtureRefDepth bounds chains of $refs followed while collecting examples
+const maxFixtureRefDepth = 16
+
+// FixturesProcessor generates example request and response payloads for consumer tests from
+// the `example` and `examples` fields of each operation's request body and responses.
+// Operations without examples are skipped.
+type FixturesProcessor struct{}
+
+// NewFixturesProcessor creates a new fixtures processor
+func NewFixturesProcessor() *FixturesProcessor {
+	return &FixturesProcessor{}
+}
+
+// Name returns the processor name
+func (p *FixturesProcessor) Name() string {
+	return "Fixtures"
+}
+
+// fixture is an example payload documented for a media type
+type fixture struct {
+	name        string // example name, or "default" for a single `example` value
+	contentType string
+	body        string
+}
+
+// fixtureOperation is an operation with documented examples
+type fixtureOperation struct {
+	operationID string // operationId, or "METHOD /path" if there is none
+	goName      string
+	requests    []fixture
+	responses   map[string][]fixture // key: status code or "default"
+}
+
+// Process writes fixtures_gen.go to the client directory
+func (p *FixturesProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	doc, err := spec.LoadDocument()
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+	operations := collectFixtureOperations(spec.SpecPath, doc)
+	if len(operations) == 0 {
+		log.Printf("No examples in %s, skipping %s", spec.ServiceName, FixturesFileName)
+		return nil
+	}
+
+	source, err := renderFixtures(spec.PackageName, operations)
+	if err != nil {
+		return err
+	}
+
+	outputPath := filepath.Join(spec.ClientPath, FixturesFileName)
+	if err := writeGeneratedFile(outputPath, source, spec.fileMode()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", FixturesFileName, err)
+	}
+
+	log.Printf("Generated fixtures for %d operation(s): %s", len(operations), outputPath)
+	return nil
+}
+
+// collectFixtureOperations returns the operations of a spec that document request or response
+// examples, sorted by path and method
+func collectFixtureOperations(specPath string, doc map[string]interface{}) []fixtureOperation {
+	root := spec.RefLocation{Path: specPath, Root: doc}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	var operations []fixtureOperation
+	usedNames := make(map[string]bool)
+	for _, path := range pathKeys {
+		item, itemLoc := resolveObject(paths[path], root)
+		for _, method := range spec.HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			op := fixtureOperation{responses: make(map[string][]fixture)}
+			requestBody, bodyLoc := resolveObject(operation["requestBody"], itemLoc)
+			op.requests = contentFixtures(requestBody, bodyLoc)
+
+			responses, _ := operation["responses"].(map[string]interface{})
+			for status, rawResponse := range responses {
+				response, responseLoc := resolveObject(rawResponse, itemLoc)
+				if fixtures := contentFixtures(response, responseLoc); len(fixtures) > 0 {
+					op.responses[status] = fixtures
+				}
+			}
+			if len(op.requests) == 0 && len(op.responses) == 0 {
+				continue
+			}
+
+			operationID, _ := operation["operationId"].(string)
+			nameSource := operationID
+			if operationID == "" {
+				operationID = strings.ToUpper(method) + " " + path
+				nameSource = method + " " + path
+			}
+			op.operationID = operationID
+			op.goName = uniqueGoName(goIdentifier(nameSource), usedNames)
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
+
+// contentFixtures returns the examples of a request body or response object, sorted by
+// content type and example name
+func contentFixtures(object map[string]interface{}, loc spec.RefLocation) []fixture {
+	content, _ := object["content"].(map[string]interface{})
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	var fixtures []fixture
+	for _, contentType := range contentTypes {
+		media, _ := content[contentType].(map[string]interface{})
+		if value, ok := media["example"]; ok {
+			if body, err := fixtureBody(contentType, value); err == nil {
+				fixtures = append(fixtures, fixture{name: "default", contentType: contentType, body: body})
+			}
+		}
+
+		examples, _ := media["examples"].(map[string]interface{})
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			// Examples with only an externalValue have no inline payload
+			example, _ := resolveObject(examples[name], loc)
+			value, ok := example["value"]
+			if !ok {
+				continue
+			}
+			if body, err := fixtureBody(contentType, value); err == nil {
+				fixtures = append(fixtures, fixture{name: name, contentType: contentType, body: body})
+			}
+		}
+	}
+	return fixtures
+}
+
+// fixtureBody encodes an example value as it would be sent: indented JSON, or a string
+// verbatim for non-JSON media types such as text/plain
+func fixtureBody(contentType string, value interface{}) (string, error) {
+	if s, ok := value.(string); ok && !strings.Contains(contentType, "json") {
+		return s, nil
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// resolveObject follows $refs until it reaches an object, returning it with the location
+// further refs inside it resolve against. Unresolvable refs return nil.
+func resolveObject(node interface{}, loc spec.RefLocation) (map[string]interface{}, spec.RefLocation) {
+	for depth := 0; depth < maxFixtureRefDepth; depth++ {
+		object, _ := node.(map[string]interface{})
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, loc
+		}
+		target, targetLoc, err := loc.Resolve(ref)
+		if err != nil {
+			return nil, loc
+		}
+		node, loc = target, targetLoc
+	}
+	return nil, loc
+}
+
+// renderFixtures renders and gofmts the fixtures file
+func renderFixtures(packageName string, operations []fixtureOperation) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by openapi-go postprocessor, DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	b.WriteString(`// SpecFixture is an example payload documented in the API spec
+type SpecFixture struct {
+	// Name is the example name, or "default" for a single example value
+	Name string
+	// ContentType is the media type the example is documented for
+	ContentType string
+	// Body is the payload: JSON for JSON media types, the example text otherwise
+	Body string
+}
+
+// SpecOperationFixtures holds the documented examples of an operation
+type SpecOperationFixtures struct {
+	// OperationID identifies the operation
+	OperationID string
+	// Requests are the request body examples
+	Requests []SpecFixture
+	// Responses are the response examples by status code ("200", "4XX", "default")
+	Responses map[string][]SpecFixture
+}
+`)
+
+	for _, op := range operations {
+		fmt.Fprintf(&b, "\n// %sFixtures holds the documented examples of %s\n", op.goName, op.operationID)
+		fmt.Fprintf(&b, "var %sFixtures = SpecOperationFixtures{\n", op.goName)
+		fmt.Fprintf(&b, "OperationID: %q,\n", op.operationID)
+		if len(op.requests) > 0 {
+			b.WriteString("Requests: []SpecFixture{\n")
+			writeFixtures(&b, op.requests)
+			b.WriteString("},\n")
+		}
+		if len(op.responses) > 0 {
+			statuses := make([]string, 0, len(op.responses))
+			for status := range op.responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+
+			b.WriteString("Responses: map[string][]SpecFixture{\n")
+			for _, status := range statuses {
+				fmt.Fprintf(&b, "%q: {\n", status)
+				writeFixtures(&b, op.responses[status])
+				b.WriteString("},\n")
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated fixtures: %w", err)
+	}
+	return source, nil
+}
+
+// writeFixtures writes fixture literals, one per line group
+func writeFixtures(b *bytes.Buffer, fixtures []fixture) {
+	for _, f := range fixtures {
+		fmt.Fprintf(b, "{Name: %q, ContentType: %q, Body: %s},\n", f.name, f.contentType, goStringLiteral(f.body))
+	}
+}
+
+// goStringLiteral returns a Go literal for s, as a raw string when that keeps it readable
+func goStringLiteral(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
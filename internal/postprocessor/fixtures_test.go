@@ -0,0 +1,117 @@
+package postprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fixturesSpec = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}},
+			"post": {
+				"operationId": "createPet",
+				"requestBody": {
+					"content": {
+						"application/json": {
+							"examples": {
+								"cat": {"value": {"name": "Tom", "kind": "cat"}},
+								"remote": {"externalValue": "https://example.com/dog.json"}
+							}
+						}
+					}
+				},
+				"responses": {
+					"201": {"description": "Created", "content": {"application/json": {"example": {"id": 1, "name": "Tom"}}}},
+					"409": {"$ref": "#/components/responses/Conflict"}
+				}
+			}
+		},
+		"/health": {
+			"get": {"responses": {"200": {"description": "OK", "content": {"text/plain": {"example": "ok"}}}}}
+		}
+	},
+	"components": {
+		"responses": {
+			"Conflict": {
+				"description": "Pet already exists",
+				"content": {"application/json": {"examples": {"duplicate": {"$ref": "#/components/examples/Duplicate"}}}}
+			}
+		},
+		"examples": {
+			"Duplicate": {"value": {"message": "pet ` + "`Tom`" + ` exists"}}
+		}
+	}
+}`
+
+func TestFixturesProcessor(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(fixturesSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: dir, ServiceName: "pets", SpecPath: specPath, PackageName: "pets"}
+	if err := NewFixturesProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FixturesFileName))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", FixturesFileName, err)
+	}
+	source := string(data)
+
+	decls := topLevelDecls(t, source)
+	for _, name := range []string{"SpecFixture", "SpecOperationFixtures", "CreatePetFixtures", "GetHealthFixtures"} {
+		if !decls[name] {
+			t.Errorf("missing declaration %s in:\n%s", name, source)
+		}
+	}
+	if decls["ListPetsFixtures"] {
+		t.Error("operations without examples should be skipped")
+	}
+
+	wantSnippets := []string{
+		// Named request example; externalValue-only examples are skipped
+		`{Name: "cat", ContentType: "application/json", Body: ` + "`{\n  \"kind\": \"cat\",\n  \"name\": \"Tom\"\n}`},",
+		// Single response example
+		`"201": {`,
+		`{Name: "default", ContentType: "application/json", Body: ` + "`{\n  \"id\": 1,\n  \"name\": \"Tom\"\n}`},",
+		// Referenced response and example; backticks force a quoted literal
+		`{Name: "duplicate", ContentType: "application/json", Body: "{\n  \"message\": \"pet ` + "`Tom`" + ` exists\"\n}"},`,
+		// Non-JSON payloads are kept verbatim
+		"{Name: \"default\", ContentType: \"text/plain\", Body: `ok`},",
+	}
+	for _, snippet := range wantSnippets {
+		if !strings.Contains(source, snippet) {
+			t.Errorf("missing %s in:\n%s", snippet, source)
+		}
+	}
+	if strings.Contains(source, "remote") {
+		t.Errorf("externalValue examples should be skipped:\n%s", source)
+	}
+
+	typeCheck(t, source)
+}
+
+func TestFixturesProcessorNoExamples(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	content := "openapi: 3.0.3\npaths:\n  /health:\n    get:\n      responses:\n        '200':\n          description: OK\n"
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: dir, ServiceName: "health", SpecPath: specPath, PackageName: "health"}
+	if err := NewFixturesProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FixturesFileName)); !os.IsNotExist(err) {
+		t.Errorf("%s should not be written without examples", FixturesFileName)
+	}
+}
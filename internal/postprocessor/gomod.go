@@ -0,0 +1,80 @@
+package postprocessor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+// GoModFileName is the module file written into each generated client
+const GoModFileName = "go.mod"
+
+// GoModProcessor writes a go.mod into the generated client so it can be published as its own module.
+// The module path is the configured prefix followed by the client package name.
+type GoModProcessor struct {
+	modulePathPrefix string
+	goVersion        string
+}
+
+// NewGoModProcessor creates a new go.mod writer.
+// modulePathPrefix is e.g. "github.com/acme/sdks"; goVersion is the go directive (e.g., "1.24").
+func NewGoModProcessor(modulePathPrefix, goVersion string) *GoModProcessor {
+	return &GoModProcessor{
+		modulePathPrefix: strings.TrimSuffix(modulePathPrefix, "/"),
+		goVersion:        goVersion,
+	}
+}
+
+// Name returns the processor name
+func (p *GoModProcessor) Name() string {
+	return "GoMod"
+}
+
+// ModulePath returns the module path for a client package
+func (p *GoModProcessor) ModulePath(packageName string) string {
+	return p.modulePathPrefix + "/" + packageName
+}
+
+// Process writes go.mod into the client directory
+func (p *GoModProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	content := fmt.Sprintf("module %s\n\ngo %s\n", p.ModulePath(spec.PackageName), p.goVersion)
+	if err := writeGeneratedFile(filepath.Join(spec.ClientPath, GoModFileName), []byte(content), spec.fileMode()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", GoModFileName, err)
+	}
+	return nil
+}
+
+// GoModTidyProcessor runs `go mod tidy` in the generated client module to record its dependencies.
+// In offline mode only the local module cache is used.
+type GoModTidyProcessor struct{}
+
+// NewGoModTidyProcessor creates a new go mod tidy processor
+func NewGoModTidyProcessor() *GoModTidyProcessor {
+	return &GoModTidyProcessor{}
+}
+
+// Name returns the processor name
+func (p *GoModTidyProcessor) Name() string {
+	return "GoModTidy"
+}
+
+// Process runs go mod tidy in the client directory
+func (p *GoModTidyProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	log.Printf("Running go mod tidy in %s...", spec.ClientPath)
+	cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
+	cmd.Dir = spec.ClientPath
+	if network.Offline() {
+		cmd.Env = append(os.Environ(), "GOPROXY=off")
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go mod tidy failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
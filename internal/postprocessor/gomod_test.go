@@ -0,0 +1,72 @@
+package postprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+func TestGoModProcessor(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefix     string
+		goVersion  string
+		wantModule string
+	}{
+		{"simple prefix", "github.com/acme/sdks", "1.24", "github.com/acme/sdks/fundingsdk"},
+		{"trailing slash", "github.com/acme/sdks/", "1.22", "github.com/acme/sdks/fundingsdk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPath := t.TempDir()
+			processor := NewGoModProcessor(tt.prefix, tt.goVersion)
+
+			err := processor.Process(context.Background(), ProcessSpec{ClientPath: clientPath, PackageName: "fundingsdk"})
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(clientPath, GoModFileName))
+			if err != nil {
+				t.Fatalf("Failed to read go.mod: %v", err)
+			}
+			want := "module " + tt.wantModule + "\n\ngo " + tt.goVersion + "\n"
+			if string(data) != want {
+				t.Errorf("go.mod = %q, want %q", string(data), want)
+			}
+		})
+	}
+}
+
+func TestGoModTidyProcessor(t *testing.T) {
+	// Stay offline so the test only uses the local module cache
+	network.SetOffline(true)
+	t.Cleanup(func() { network.SetOffline(false) })
+
+	clientPath := t.TempDir()
+	spec := ProcessSpec{ClientPath: clientPath, PackageName: "fundingsdk"}
+	if err := NewGoModProcessor("example.com/sdks", "1.21").Process(context.Background(), spec); err != nil {
+		t.Fatalf("GoModProcessor.Process() error = %v", err)
+	}
+	source := "package fundingsdk\n\nimport \"strings\"\n\nvar Upper = strings.ToUpper\n"
+	if err := os.WriteFile(filepath.Join(clientPath, "client.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	if err := NewGoModTidyProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("GoModTidyProcessor.Process() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(clientPath, GoModFileName))
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "module example.com/sdks/fundingsdk\n") {
+		t.Errorf("go.mod after tidy = %q", string(data))
+	}
+}
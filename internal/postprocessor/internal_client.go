@@ -1,17 +1,23 @@
 package postprocessor
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"go/format"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
 )
 
+// InternalClientFileName is the name of the generated internal client file
+const InternalClientFileName = "oas_internal_client_gen.go"
+
 // InternalClientProcessor generates an internal client file with convenience functions
 // for initializing clients with base security for internal endpoints.
 type InternalClientProcessor struct {
@@ -37,23 +43,25 @@ func (p *InternalClientProcessor) Process(ctx context.Context, spec ProcessSpec)
 		return fmt.Errorf("template not found: %w", err)
 	}
 
-	// Parse OpenAPI spec to detect security requirements
-	hasSecurity, err := p.detectSecurityFromSpec(spec.SpecPath)
-	if err != nil {
-		// Fall back to file-based detection if spec parsing fails
-		log.Printf("Warning: Failed to parse spec for security detection, falling back to file check: %v", err)
-		hasSecurity = p.detectSecurityFromGeneratedFiles(spec.ClientPath)
-	}
+	log.Printf("Security detection for %s: hasSecurity=%v", spec.ServiceName, spec.HasSecurity)
 
-	log.Printf("Security detection for %s: hasSecurity=%v", spec.ServiceName, hasSecurity)
+	// Server constants are optional: a spec that cannot be read just gets none
+	var servers []internalClientServer
+	if doc, err := spec.LoadDocument(); err != nil {
+		log.Printf("Warning: Failed to load spec servers for %s: %v", spec.ServiceName, err)
+	} else {
+		servers = collectInternalClientServers(doc)
+	}
 
 	// Create the template data
 	data := struct {
 		PackageName string
 		HasSecurity bool
+		Servers     []internalClientServer
 	}{
 		PackageName: spec.ServiceName,
-		HasSecurity: hasSecurity,
+		HasSecurity: spec.HasSecurity,
+		Servers:     servers,
 	}
 
 	// Parse the template from file
@@ -62,25 +70,107 @@ func (p *InternalClientProcessor) Process(ctx context.Context, spec ProcessSpec)
 		return fmt.Errorf("failed to parse template file %s: %w", p.templatePath, err)
 	}
 
-	// Create the output file
-	outputPath := filepath.Join(spec.ClientPath, "oas_internal_client_gen.go")
-	file, err := os.Create(outputPath)
+	// Execute the template
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, filepath.Base(p.templatePath), data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	// gofmt the output so the file is stable whatever the template's whitespace
+	source, err := format.Source(b.Bytes())
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return fmt.Errorf("failed to format internal client from %s: %w", p.templatePath, err)
 	}
-	defer file.Close()
 
-	// Execute the template
-	if err := tmpl.ExecuteTemplate(file, filepath.Base(p.templatePath), data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	outputPath := filepath.Join(spec.ClientPath, InternalClientFileName)
+	if err := writeGeneratedFile(outputPath, source, spec.fileMode()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", InternalClientFileName, err)
 	}
 
 	log.Printf("Generated internal client file: %s", outputPath)
 	return nil
 }
 
+// internalClientServer is a server declared in the spec, rendered as a base URL constant
+type internalClientServer struct {
+	Name        string // Go identifier suffix, from the description (e.g. "Production") or position
+	Description string
+	URL         string // with server variables set to their defaults
+}
+
+// collectInternalClientServers returns the servers of a spec in declaration order. Servers are
+// named after their description, or "Server<N>" by position if they have none.
+func collectInternalClientServers(doc map[string]interface{}) []internalClientServer {
+	rawServers, _ := doc["servers"].([]interface{})
+	used := make(map[string]bool)
+	var servers []internalClientServer
+	for i, raw := range rawServers {
+		server, _ := raw.(map[string]interface{})
+		url, _ := server["url"].(string)
+		if url == "" {
+			continue
+		}
+
+		// Substitute {variable} placeholders with their defaults
+		variables, _ := server["variables"].(map[string]interface{})
+		for name, rawVariable := range variables {
+			variable, _ := rawVariable.(map[string]interface{})
+			if value, ok := variable["default"].(string); ok {
+				url = strings.ReplaceAll(url, "{"+name+"}", value)
+			}
+		}
+
+		description, _ := server["description"].(string)
+		description = singleLine(description)
+		name := fmt.Sprintf("Server%d", i+1)
+		if description != "" {
+			name = goIdentifier(description)
+		}
+		servers = append(servers, internalClientServer{
+			Name:        uniqueGoName(name, used),
+			Description: description,
+			URL:         url,
+		})
+	}
+	return servers
+}
+
+// RegenerateInternalClient regenerates only the internal client file of an existing client
+// from its spec, without re-running ogen. Useful after changing the internal client template.
+func RegenerateInternalClient(clientPath, serviceName, specPath string) error {
+	info, err := os.Stat(clientPath)
+	if err != nil {
+		return fmt.Errorf("client directory not found: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("client path is not a directory: %s", clientPath)
+	}
+
+	spec := ProcessSpec{
+		ClientPath:  clientPath,
+		ServiceName: serviceName,
+		SpecPath:    specPath,
+		PackageName: serviceName,
+		HasSecurity: DetectSecurity(specPath, clientPath),
+	}
+
+	return NewInternalClientProcessor().Process(context.Background(), spec)
+}
+
+// DetectSecurity reports whether a spec defines security schemes or requirements.
+// If the spec cannot be parsed, it falls back to checking the generated client for
+// ogen's security file.
+func DetectSecurity(specPath, clientPath string) bool {
+	hasSecurity, err := detectSecurityFromSpec(specPath)
+	if err != nil {
+		log.Printf("Warning: Failed to parse spec for security detection, falling back to file check: %v", err)
+		return detectSecurityFromGeneratedFiles(clientPath)
+	}
+	return hasSecurity
+}
+
 // detectSecurityFromSpec parses the OpenAPI spec to check for security schemes
-func (p *InternalClientProcessor) detectSecurityFromSpec(specPath string) (bool, error) {
+func detectSecurityFromSpec(specPath string) (bool, error) {
 	openAPISpec, err := spec.ParseSpecFile(specPath)
 	if err != nil {
 		return false, err
@@ -90,7 +180,7 @@ func (p *InternalClientProcessor) detectSecurityFromSpec(specPath string) (bool,
 }
 
 // detectSecurityFromGeneratedFiles checks for security file (fallback method)
-func (p *InternalClientProcessor) detectSecurityFromGeneratedFiles(clientPath string) bool {
+func detectSecurityFromGeneratedFiles(clientPath string) bool {
 	securityFilePath := filepath.Join(clientPath, "oas_security_gen.go")
 	_, err := os.Stat(securityFilePath)
 	return err == nil
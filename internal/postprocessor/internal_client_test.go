@@ -2,8 +2,10 @@ package postprocessor
 
 import (
 	"context"
+	"go/format"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -62,6 +64,7 @@ func TestInternalClientProcessorProcess(t *testing.T) {
 					ServiceName: "testservice",
 					SpecPath:    specPath,
 					PackageName: "testpkg",
+					HasSecurity: true,
 				}
 			},
 			wantErr: false,
@@ -192,8 +195,7 @@ func TestInternalClientProcessorDetectSecurity(t *testing.T) {
 			tmpFile := filepath.Join(t.TempDir(), "spec.json")
 			os.WriteFile(tmpFile, []byte(tt.spec), 0644)
 
-			processor := NewInternalClientProcessor()
-			hasSecurity, err := processor.detectSecurityFromSpec(tmpFile)
+			hasSecurity, err := detectSecurityFromSpec(tmpFile)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("detectSecurityFromSpec() error = %v, wantErr %v", err, tt.wantErr)
@@ -245,8 +247,7 @@ func TestInternalClientProcessorDetectSecurityFromFiles(t *testing.T) {
 				}
 			}
 
-			processor := NewInternalClientProcessor()
-			result := processor.detectSecurityFromGeneratedFiles(tmpDir)
+			result := detectSecurityFromGeneratedFiles(tmpDir)
 
 			if result != tt.expected {
 				t.Errorf("detectSecurityFromGeneratedFiles() = %v, want %v", result, tt.expected)
@@ -255,7 +256,270 @@ func TestInternalClientProcessorDetectSecurityFromFiles(t *testing.T) {
 	}
 }
 
+func TestInternalClientProcessorUsesHasSecurity(t *testing.T) {
+	tests := []struct {
+		name        string
+		hasSecurity bool
+		want        string
+	}{
+		{name: "secured", hasSecurity: true, want: "NewClient(serverURL, nil, opts...)"},
+		{name: "unsecured", hasSecurity: false, want: "NewClient(serverURL, opts...)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPath := t.TempDir()
+
+			// Security comes from the ProcessSpec, not the (missing) spec
+			spec := ProcessSpec{
+				ClientPath:  clientPath,
+				ServiceName: "testservice",
+				SpecPath:    filepath.Join(clientPath, "missing.json"),
+				PackageName: "testservice",
+				HasSecurity: tt.hasSecurity,
+			}
+			if err := NewInternalClientProcessor().Process(context.Background(), spec); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(clientPath, InternalClientFileName))
+			if err != nil {
+				t.Fatalf("Failed to read internal client file: %v", err)
+			}
+			if !contains(string(content), tt.want) {
+				t.Errorf("Internal client should contain %q:\n%s", tt.want, content)
+			}
+		})
+	}
+}
+
+func TestInternalClientProcessorServers(t *testing.T) {
+	clientPath := t.TempDir()
+	specPath := filepath.Join(clientPath, "openapi.yaml")
+	content := `openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+servers:
+  - url: https://pets.example.com
+    description: Production
+  - url: https://{region}.staging.pets.example.com/v1
+    description: Staging (EU)
+    variables:
+      region: {default: eu-west-1}
+  - url: http://localhost:8080
+paths: {}
+`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: clientPath, ServiceName: "pets", SpecPath: specPath, PackageName: "pets"}
+	if err := NewInternalClientProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(clientPath, InternalClientFileName))
+	if err != nil {
+		t.Fatalf("Failed to read internal client file: %v", err)
+	}
+	source := string(data)
+
+	for _, want := range []string{
+		`ServerURLProduction = "https://pets.example.com"`,
+		`ServerURLStagingEU = "https://eu-west-1.staging.pets.example.com/v1"`,
+		`ServerURLServer3 = "http://localhost:8080"`,
+		`"Production": ServerURLProduction,`,
+		"func NewInternalClientForServer(name string, opts ...ClientOption) (*Client, error) {",
+	} {
+		if !contains(source, want) {
+			t.Errorf("internal client missing %q:\n%s", want, source)
+		}
+	}
+
+	// The generated code compiles against ogen's client API
+	typeCheck(t, source+`
+type Client struct{}
+type ClientOption func()
+func NewClient(serverURL string, opts ...ClientOption) (*Client, error) { return &Client{}, nil }
+`)
+}
+
+func TestInternalClientProcessorWithoutServers(t *testing.T) {
+	clientPath := t.TempDir()
+	specPath := filepath.Join(clientPath, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: clientPath, ServiceName: "pets", SpecPath: specPath, PackageName: "pets"}
+	if err := NewInternalClientProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(clientPath, InternalClientFileName))
+	if err != nil {
+		t.Fatalf("Failed to read internal client file: %v", err)
+	}
+	if contains(string(data), "ServerURL") || contains(string(data), `"fmt"`) {
+		t.Errorf("internal client without servers should not declare server constants:\n%s", data)
+	}
+}
+
+func TestInternalClientProcessorOutputIsStable(t *testing.T) {
+	// A sloppy template: misindented, no trailing newline
+	templatePath := filepath.Join(t.TempDir(), "internal_client.tmpl")
+	sloppy := "package {{ .PackageName }}\n\nfunc   NewInternalClient( ) int {\nreturn 1 }"
+	if err := os.WriteFile(templatePath, []byte(sloppy), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	processors := map[string]*InternalClientProcessor{
+		"default template": NewInternalClientProcessor(),
+		"sloppy template":  {templatePath: templatePath},
+	}
+	for name, processor := range processors {
+		t.Run(name, func(t *testing.T) {
+			clientPath := t.TempDir()
+			outputPath := filepath.Join(clientPath, InternalClientFileName)
+			spec := ProcessSpec{ClientPath: clientPath, ServiceName: "testservice", PackageName: "testservice", FileMode: 0640}
+			if err := processor.Process(context.Background(), spec); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			content, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("Failed to read internal client file: %v", err)
+			}
+			if !strings.HasSuffix(string(content), "}\n") {
+				t.Errorf("internal client should end with a single newline:\n%q", content)
+			}
+			formatted, err := format.Source(content)
+			if err != nil {
+				t.Fatalf("internal client does not parse: %v", err)
+			}
+			if string(formatted) != string(content) {
+				t.Errorf("internal client is not gofmt-clean:\n%s", content)
+			}
+			assertFileMode(t, outputPath, 0640)
+
+			// Overwriting an existing file applies the mode too
+			spec.FileMode = 0
+			if err := processor.Process(context.Background(), spec); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			assertFileMode(t, outputPath, DefaultFileMode)
+		})
+	}
+}
+
+// assertFileMode checks the permission bits of a file
+func assertFileMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %v, want %v", filepath.Base(path), got, want)
+	}
+}
+
+func TestDetectSecurity(t *testing.T) {
+	tmpDir := t.TempDir()
+	securedSpec := filepath.Join(tmpDir, "secured.json")
+	os.WriteFile(securedSpec, []byte(`{"openapi": "3.0.0", "security": [{"bearerAuth": []}]}`), 0644)
+	unsecuredSpec := filepath.Join(tmpDir, "unsecured.json")
+	os.WriteFile(unsecuredSpec, []byte(`{"openapi": "3.0.0", "paths": {}}`), 0644)
+
+	clientWithSecurity := filepath.Join(tmpDir, "client")
+	os.MkdirAll(clientWithSecurity, 0755)
+	os.WriteFile(filepath.Join(clientWithSecurity, "oas_security_gen.go"), []byte("package client\n"), 0644)
+
+	tests := []struct {
+		name       string
+		specPath   string
+		clientPath string
+		want       bool
+	}{
+		{name: "secured spec", specPath: securedSpec, clientPath: tmpDir, want: true},
+		{name: "unsecured spec ignores generated files", specPath: unsecuredSpec, clientPath: clientWithSecurity, want: false},
+		{name: "unreadable spec falls back to generated files", specPath: filepath.Join(tmpDir, "missing.json"), clientPath: clientWithSecurity, want: true},
+		{name: "unreadable spec without security file", specPath: filepath.Join(tmpDir, "missing.json"), clientPath: tmpDir, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectSecurity(tt.specPath, tt.clientPath); got != tt.want {
+				t.Errorf("DetectSecurity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestInternalClientProcessorImplementsInterface(t *testing.T) {
 	// Verify InternalClientProcessor implements PostProcessor interface
 	var _ PostProcessor = (*InternalClientProcessor)(nil)
 }
+
+func TestRegenerateInternalClient(t *testing.T) {
+	tmpDir := t.TempDir()
+	clientPath := filepath.Join(tmpDir, "client")
+	if err := os.MkdirAll(clientPath, 0755); err != nil {
+		t.Fatalf("Failed to create client directory: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.json")
+	outputPath := filepath.Join(clientPath, InternalClientFileName)
+
+	// Generated code from ogen must be left untouched
+	otherFile := filepath.Join(clientPath, "oas_client_gen.go")
+	if err := os.WriteFile(otherFile, []byte("package testservice\n"), 0644); err != nil {
+		t.Fatalf("Failed to write generated file: %v", err)
+	}
+
+	// Initially the spec has no security schemes
+	if err := os.WriteFile(specPath, []byte(`{"openapi": "3.0.0", "info": {"title": "Test", "version": "1.0"}, "paths": {}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := RegenerateInternalClient(clientPath, "testservice", specPath); err != nil {
+		t.Fatalf("RegenerateInternalClient() error = %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Expected internal client file to be created: %v", err)
+	}
+	if !contains(string(content), "package testservice") || !contains(string(content), "NewClient(serverURL, opts...)") {
+		t.Errorf("Unexpected internal client without security:\n%s", content)
+	}
+
+	// Adding a security scheme must be reflected when regenerating
+	specWithSecurity := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"components": {"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}}}
+	}`
+	if err := os.WriteFile(specPath, []byte(specWithSecurity), 0644); err != nil {
+		t.Fatalf("Failed to update spec: %v", err)
+	}
+	if err := RegenerateInternalClient(clientPath, "testservice", specPath); err != nil {
+		t.Fatalf("RegenerateInternalClient() error = %v", err)
+	}
+
+	content, err = os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read internal client file: %v", err)
+	}
+	if !contains(string(content), "NewClient(serverURL, nil, opts...)") {
+		t.Errorf("Internal client was not overwritten with security detection:\n%s", content)
+	}
+
+	other, err := os.ReadFile(otherFile)
+	if err != nil || string(other) != "package testservice\n" {
+		t.Errorf("Other generated files should be left untouched, got %q (err %v)", other, err)
+	}
+}
+
+func TestRegenerateInternalClientMissingClient(t *testing.T) {
+	err := RegenerateInternalClient(filepath.Join(t.TempDir(), "missing"), "testservice", "spec.json")
+	if err == nil {
+		t.Fatal("RegenerateInternalClient() expected error for missing client directory")
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
 )
 
 // PostProcessor defines the interface for post-processing generated client code.
@@ -33,6 +36,49 @@ type ProcessSpec struct {
 
 	// PackageName is the Go package name for the generated client
 	PackageName string
+
+	// HasSecurity reports whether the spec defines security schemes or requirements,
+	// detected once per client (see DetectSecurity) so processors can branch on it
+	HasSecurity bool
+
+	// FileMode is the permission of files written by processors (0 means DefaultFileMode)
+	FileMode os.FileMode
+
+	// Document is the decoded spec at SpecPath if the pipeline already parsed it, or nil.
+	// It is shared with other stages, so processors must not modify it.
+	Document map[string]interface{}
+}
+
+// LoadDocument returns the decoded spec, parsing SpecPath only if Document is not set
+func (s ProcessSpec) LoadDocument() (map[string]interface{}, error) {
+	if s.Document != nil {
+		return s.Document, nil
+	}
+	return spec.LoadDocument(s.SpecPath)
+}
+
+// DefaultFileMode is the permission of generated files unless ProcessSpec.FileMode is set
+const DefaultFileMode os.FileMode = 0644
+
+// fileMode returns the permission for generated files
+func (s ProcessSpec) fileMode() os.FileMode {
+	if s.FileMode == 0 {
+		return DefaultFileMode
+	}
+	return s.FileMode
+}
+
+// writeGeneratedFile writes a generated file ending in a newline with the given permission.
+// The permission is set explicitly, so it is the same for new and overwritten files
+// whatever the umask, which keeps diffs of generated clients reproducible.
+func writeGeneratedFile(path string, data []byte, mode os.FileMode) error {
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
 }
 
 // Chain manages an ordered list of post-processors and executes them sequentially
@@ -0,0 +1,66 @@
+package postprocessor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// PruneFilesProcessor deletes generated files the consumer doesn't want (e.g., oas_server_gen.go).
+// Patterns use filepath.Match syntax and are matched against file names in the client directory.
+type PruneFilesProcessor struct {
+	patterns []string
+}
+
+// NewPruneFilesProcessor creates a processor that removes files matching any of the patterns
+func NewPruneFilesProcessor(patterns []string) *PruneFilesProcessor {
+	return &PruneFilesProcessor{patterns: patterns}
+}
+
+// Name returns the processor name
+func (p *PruneFilesProcessor) Name() string {
+	return "PruneFiles"
+}
+
+// Process removes matching files from the top level of the client directory
+func (p *PruneFilesProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	entries, err := os.ReadDir(spec.ClientPath)
+	if err != nil {
+		return fmt.Errorf("failed to read client directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matched, err := p.matches(entry.Name())
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if err := os.Remove(filepath.Join(spec.ClientPath, entry.Name())); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", entry.Name(), err)
+		}
+		log.Printf("Pruned %s from %s", entry.Name(), spec.PackageName)
+	}
+
+	return nil
+}
+
+// matches reports whether a file name matches any prune pattern
+func (p *PruneFilesProcessor) matches(name string) (bool, error) {
+	for _, pattern := range p.patterns {
+		matched, err := filepath.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid prune pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,56 @@
+package postprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPruneFilesProcessor(t *testing.T) {
+	clientPath := t.TempDir()
+	files := []string{
+		"oas_client_gen.go",
+		"oas_schemas_gen.go",
+		"oas_server_gen.go",
+		"oas_unimplemented_gen.go",
+		"oas_router_gen.go",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(clientPath, name), []byte("package client\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	// Directories are never pruned, even when their name matches
+	if err := os.Mkdir(filepath.Join(clientPath, "oas_server_gen.go.d"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	processor := NewPruneFilesProcessor([]string{"oas_server_gen.go", "oas_unimplemented_gen.go", "oas_rout*", "oas_server_gen.go.*"})
+	if err := processor.Process(context.Background(), ProcessSpec{ClientPath: clientPath, PackageName: "fundingsdk"}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	for _, name := range []string{"oas_server_gen.go", "oas_unimplemented_gen.go", "oas_router_gen.go"} {
+		if _, err := os.Stat(filepath.Join(clientPath, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be pruned", name)
+		}
+	}
+	for _, name := range []string{"oas_client_gen.go", "oas_schemas_gen.go", "oas_server_gen.go.d"} {
+		if _, err := os.Stat(filepath.Join(clientPath, name)); err != nil {
+			t.Errorf("%s should be retained: %v", name, err)
+		}
+	}
+}
+
+func TestPruneFilesProcessorInvalidPattern(t *testing.T) {
+	clientPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(clientPath, "oas_client_gen.go"), []byte("package client\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	processor := NewPruneFilesProcessor([]string{"oas_[gen.go"})
+	if err := processor.Process(context.Background(), ProcessSpec{ClientPath: clientPath}); err == nil {
+		t.Error("Process() should fail for an invalid pattern")
+	}
+}
@@ -0,0 +1,197 @@
+package postprocessor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// ClientReadmeFileName is the name of the generated client README
+const ClientReadmeFileName = "README.md"
+
+// untaggedOperations is the README section for operations without tags
+const untaggedOperations = "Other"
+
+// ClientReadmeProcessor writes a README.md into each client directory summarizing the service:
+// title and version from the spec's info, base URLs from servers, and the operations grouped
+// by tag, so consumers can find what a client offers without reading generated code.
+type ClientReadmeProcessor struct{}
+
+// NewClientReadmeProcessor creates a new client README processor
+func NewClientReadmeProcessor() *ClientReadmeProcessor {
+	return &ClientReadmeProcessor{}
+}
+
+// Name returns the processor name
+func (p *ClientReadmeProcessor) Name() string {
+	return "ClientReadme"
+}
+
+// readmeOperation is an operation listed in the README
+type readmeOperation struct {
+	operationID string
+	method      string
+	path        string
+	summary     string
+	deprecated  bool
+}
+
+// Process writes README.md to the client directory
+func (p *ClientReadmeProcessor) Process(ctx context.Context, spec ProcessSpec) error {
+	doc, err := spec.LoadDocument()
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+
+	outputPath := filepath.Join(spec.ClientPath, ClientReadmeFileName)
+	if err := writeGeneratedFile(outputPath, []byte(renderClientReadme(spec.PackageName, doc)), spec.fileMode()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ClientReadmeFileName, err)
+	}
+
+	log.Printf("Generated client README: %s", outputPath)
+	return nil
+}
+
+// renderClientReadme renders the README of a client package from its spec
+func renderClientReadme(packageName string, doc map[string]interface{}) string {
+	info, _ := doc["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	if title == "" {
+		title = packageName
+	}
+	version, _ := info["version"].(string)
+	description, _ := info["description"].(string)
+
+	var b strings.Builder
+	b.WriteString("<!-- Code generated by openapi-go postprocessor, DO NOT EDIT. -->\n\n")
+	fmt.Fprintf(&b, "# %s\n\n", singleLine(title))
+	fmt.Fprintf(&b, "Go client package `%s`", packageName)
+	if version != "" {
+		fmt.Fprintf(&b, " for API version `%s`", version)
+	}
+	b.WriteString(".\n")
+	if description = strings.TrimSpace(description); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+
+	servers := serverURLs(doc)
+	if len(servers) > 0 {
+		b.WriteString("\n## Base URL\n\n")
+		for _, server := range servers {
+			fmt.Fprintf(&b, "- `%s`\n", server)
+		}
+	}
+
+	baseURL := "https://api.example.com"
+	if len(servers) > 0 {
+		baseURL = servers[0]
+	}
+	b.WriteString("\n## Usage\n\n```go\n")
+	fmt.Fprintf(&b, "client, err := %s.NewInternalClient(%q)\n", packageName, baseURL)
+	b.WriteString("```\n")
+
+	groups, tags := groupOperationsByTag(doc)
+	b.WriteString("\n## Operations\n")
+	if len(tags) == 0 {
+		b.WriteString("\nThe spec defines no operations.\n")
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(&b, "\n### %s\n\n", singleLine(tag))
+		b.WriteString("| Operation | Method | Path | Summary |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, op := range groups[tag] {
+			summary := escapeTableCell(op.summary)
+			if op.deprecated {
+				summary = strings.TrimSpace("**Deprecated.** " + summary)
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | `%s` | %s |\n", op.operationID, strings.ToUpper(op.method), op.path, summary)
+		}
+	}
+	return b.String()
+}
+
+// serverURLs returns the URLs of the spec's servers, in order
+func serverURLs(doc map[string]interface{}) []string {
+	servers, _ := doc["servers"].([]interface{})
+	var urls []string
+	for _, raw := range servers {
+		server, _ := raw.(map[string]interface{})
+		if url, _ := server["url"].(string); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// groupOperationsByTag returns the operations of a spec grouped by their first tag, and the tags
+// in README order: tags declared at the top level first, in declaration order, then the
+// remaining tags sorted, then untagged operations. Operations are sorted by path and method.
+func groupOperationsByTag(doc map[string]interface{}) (map[string][]readmeOperation, []string) {
+	paths, _ := doc["paths"].(map[string]interface{})
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	groups := make(map[string][]readmeOperation)
+	for _, path := range pathKeys {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range spec.HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			op := readmeOperation{method: method, path: path}
+			op.operationID, _ = operation["operationId"].(string)
+			if op.operationID == "" {
+				op.operationID = strings.ToUpper(method) + " " + path
+			}
+			op.summary, _ = operation["summary"].(string)
+			op.deprecated, _ = operation["deprecated"].(bool)
+
+			tag := untaggedOperations
+			if tags, _ := operation["tags"].([]interface{}); len(tags) > 0 {
+				if name, _ := tags[0].(string); name != "" {
+					tag = name
+				}
+			}
+			groups[tag] = append(groups[tag], op)
+		}
+	}
+
+	var order []string
+	seen := make(map[string]bool)
+	declared, _ := doc["tags"].([]interface{})
+	for _, raw := range declared {
+		tag, _ := raw.(map[string]interface{})
+		name, _ := tag["name"].(string)
+		if len(groups[name]) > 0 && !seen[name] && name != untaggedOperations {
+			order = append(order, name)
+			seen[name] = true
+		}
+	}
+	var rest []string
+	for name := range groups {
+		if !seen[name] && name != untaggedOperations {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+	if len(groups[untaggedOperations]) > 0 {
+		order = append(order, untaggedOperations)
+	}
+	return groups, order
+}
+
+// escapeTableCell makes text safe for a Markdown table cell
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(singleLine(s), "|", `\|`)
+}
@@ -0,0 +1,105 @@
+package postprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const readmeSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 2.1.0
+  description: Manages pets.
+servers:
+  - url: https://pets.example.com/v2
+  - url: https://staging.pets.example.com/v2
+tags:
+  - name: pets
+  - name: admin
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      summary: List pets | paginated
+    post:
+      operationId: createPet
+      tags: [pets, admin]
+      summary: Create a pet
+  /stores:
+    get:
+      operationId: listStores
+      tags: [stores]
+      deprecated: true
+  /health:
+    get:
+      summary: Health check
+`
+
+func TestClientReadmeProcessor(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(readmeSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec := ProcessSpec{ClientPath: dir, ServiceName: "pets", SpecPath: specPath, PackageName: "petssdk"}
+	if err := NewClientReadmeProcessor().Process(context.Background(), spec); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ClientReadmeFileName))
+	if err != nil {
+		t.Fatalf("expected %s: %v", ClientReadmeFileName, err)
+	}
+	readme := string(data)
+
+	for _, want := range []string{
+		"# Pet Store\n",
+		"Go client package `petssdk` for API version `2.1.0`.",
+		"Manages pets.",
+		"- `https://pets.example.com/v2`\n- `https://staging.pets.example.com/v2`\n",
+		`client, err := petssdk.NewInternalClient("https://pets.example.com/v2")`,
+		"| `listPets` | GET | `/pets` | List pets \\| paginated |",
+		"| `createPet` | POST | `/pets` | Create a pet |",
+		"| `listStores` | GET | `/stores` | **Deprecated.** |",
+		"| `GET /health` | GET | `/health` | Health check |",
+	} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README missing %q:\n%s", want, readme)
+		}
+	}
+
+	// Declared tags come first, then other tags, then untagged operations. admin has no
+	// operation of its own: createPet is listed under its first tag only.
+	if strings.Contains(readme, "### admin") {
+		t.Errorf("README should not have a section for a tag without operations:\n%s", readme)
+	}
+	pets, stores, other := strings.Index(readme, "### pets"), strings.Index(readme, "### stores"), strings.Index(readme, "### Other")
+	if pets < 0 || stores < pets || other < stores {
+		t.Errorf("README sections out of order (pets=%d, stores=%d, Other=%d):\n%s", pets, stores, other, readme)
+	}
+	if !strings.HasSuffix(readme, "\n") {
+		t.Error("README should end with a newline")
+	}
+}
+
+func TestRenderClientReadmeMinimalSpec(t *testing.T) {
+	readme := renderClientReadme("holidayssdk", map[string]interface{}{"openapi": "3.0.0"})
+
+	for _, want := range []string{
+		"# holidayssdk\n",
+		"Go client package `holidayssdk`.\n",
+		"The spec defines no operations.",
+	} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README missing %q:\n%s", want, readme)
+		}
+	}
+	if strings.Contains(readme, "## Base URL") {
+		t.Errorf("README should not have a base URL section without servers:\n%s", readme)
+	}
+}
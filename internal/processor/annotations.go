@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// GitHubAnnotations returns a GitHub Actions annotation per validation issue, in spec order.
+// Specs that failed without an error-severity issue (e.g. not an OpenAPI document, or
+// warnings with fail_on_warnings) also get a file-level error annotation.
+func (s *ValidationSummary) GitHubAnnotations() []string {
+	var annotations []string
+	for _, result := range s.Specs {
+		annotations = append(annotations, specAnnotations(result.SpecPath, result.Issues, result.Error)...)
+	}
+	return annotations
+}
+
+// GitHubAnnotations returns a GitHub Actions annotation per validation issue found during the
+// run, plus an error annotation for each spec that failed to generate, in spec order
+func (r *RunReport) GitHubAnnotations() []string {
+	failures := make(map[string]string)
+	if r.Result != nil {
+		for _, failure := range r.Result.FailedSpecs {
+			failures[failure.SpecPath] = failure.Error.Error()
+		}
+	}
+
+	var annotations []string
+	for _, specPath := range r.Specs {
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		annotations = append(annotations, specAnnotations(specPath, r.ValidationIssues[serviceName], failures[specPath])...)
+	}
+	return annotations
+}
+
+// WriteGitHubAnnotations writes annotations to w, one per line
+func WriteGitHubAnnotations(w io.Writer, annotations []string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w, strings.Join(annotations, "\n")+"\n")
+	return err
+}
+
+// specAnnotations returns the annotations of a spec's validation issues, located at the line of
+// their JSON pointer when it resolves in the spec file. A failure without an error-severity
+// issue explaining it is added as a file-level error.
+func specAnnotations(specPath string, issues []validation.Issue, failure string) []string {
+	file := annotationFile(specPath)
+
+	// Only read the spec if there is something to locate
+	var content []byte
+	for _, issue := range issues {
+		if issue.Path != "" {
+			content = readAnnotatedSpec(specPath)
+			break
+		}
+	}
+
+	var annotations []string
+	for _, issue := range issues {
+		loc := validation.Location{File: file}
+		if content != nil && issue.Path != "" {
+			if pos, ok := spec.PointerPosition(content, issue.Path); ok {
+				loc.Line, loc.Column = pos.Line, pos.Column
+			}
+		}
+		annotations = append(annotations, issue.GitHubAnnotation(loc))
+	}
+
+	if failure != "" && !validation.HasErrors(issues) {
+		annotations = append(annotations, validation.FormatGitHubAnnotation("error", validation.Location{File: file}, "openapi-go", failure))
+	}
+	return annotations
+}
+
+// readAnnotatedSpec returns a spec's content as UTF-8, or nil if it cannot be read
+func readAnnotatedSpec(specPath string) []byte {
+	data, err := spec.ReadFile(specPath)
+	if err != nil {
+		return nil
+	}
+	converted, err := spec.ToUTF8(data, "")
+	if err != nil {
+		return nil
+	}
+	return converted
+}
+
+// annotationFile returns a spec path relative to the repository root (GITHUB_WORKSPACE, or the
+// working directory outside Actions), since annotations only attach to repository files.
+// Paths outside the repository are returned unchanged.
+func annotationFile(specPath string) string {
+	root := os.Getenv("GITHUB_WORKSPACE")
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return specPath
+		}
+		root = wd
+	}
+
+	absPath, err := filepath.Abs(specPath)
+	if err != nil {
+		return specPath
+	}
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil || !filepath.IsLocal(rel) {
+		return specPath
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+func TestValidationSummaryGitHubAnnotations(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+
+	specPath := filepath.Join(workspace, "specs", "pets-server-sdk", "openapi.yaml")
+	if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	content := "openapi: 3.0.0\npaths:\n  /pets:\n    post:\n      responses: {}\n"
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	summary := &ValidationSummary{
+		Specs: []SpecValidation{
+			{
+				Service:  "pets",
+				SpecPath: specPath,
+				Valid:    false,
+				Error:    "spec validation failed for pets",
+				Issues: []validation.Issue{
+					{Rule: "require-request-body", Severity: validation.SeverityError, Path: "/paths/~1pets/post", Message: "POST has no request body"},
+					{Rule: "examples", Severity: validation.SeverityWarning, Path: "/paths/~1missing", Message: "unlocated"},
+				},
+			},
+			{
+				Service:  "notes",
+				SpecPath: filepath.Join(workspace, "specs", "notes-server-sdk", "openapi.json"),
+				Valid:    false,
+				Error:    "invalid spec for notes: not an OpenAPI document",
+			},
+		},
+	}
+
+	want := []string{
+		"::error file=specs/pets-server-sdk/openapi.yaml,line=4,col=5,title=require-request-body::POST has no request body (at /paths/~1pets/post)",
+		"::warning file=specs/pets-server-sdk/openapi.yaml,title=examples::unlocated (at /paths/~1missing)",
+		"::error file=specs/notes-server-sdk/openapi.json,title=openapi-go::invalid spec for notes: not an OpenAPI document",
+	}
+	if got := summary.GitHubAnnotations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitHubAnnotations() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestRunReportGitHubAnnotations(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+
+	fundingSpec := filepath.Join(workspace, "specs", "funding-server-sdk", "openapi.json")
+	holidaysSpec := filepath.Join(workspace, "specs", "holidays-server-sdk", "openapi.json")
+	report := &RunReport{
+		Specs: []string{fundingSpec, holidaysSpec},
+		Result: &ProcessingResult{
+			FailedSpecs: []SpecFailure{{SpecPath: holidaysSpec, ServiceName: "holidays", Error: errors.New("generator failed")}},
+		},
+		ValidationIssues: map[string][]validation.Issue{
+			"funding": {{Rule: "security", Severity: validation.SeverityWarning, Message: "unused scheme"}},
+		},
+	}
+
+	want := []string{
+		"::warning file=specs/funding-server-sdk/openapi.json,title=security::unused scheme",
+		"::error file=specs/holidays-server-sdk/openapi.json,title=openapi-go::generator failed",
+	}
+	if got := report.GitHubAnnotations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitHubAnnotations() =\n%v\nwant\n%v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubAnnotations(&buf, want); err != nil {
+		t.Fatalf("WriteGitHubAnnotations() error = %v", err)
+	}
+	if buf.String() != want[0]+"\n"+want[1]+"\n" {
+		t.Errorf("WriteGitHubAnnotations() wrote %q", buf.String())
+	}
+}
+
+func TestAnnotationFileOutsideWorkspace(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", t.TempDir())
+	outside := filepath.Join(t.TempDir(), "openapi.json")
+	if got := annotationFile(outside); got != outside {
+		t.Errorf("annotationFile() = %q, want the path unchanged", got)
+	}
+}
@@ -0,0 +1,214 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// baselineFile records the fingerprint and operation surface of each spec, keyed like the
+// lockfile by spec path relative to the specs directory. Committed to the repository, it lets
+// CI report API changes without a local cache.
+type baselineFile struct {
+	Specs map[string]baselineEntry `json:"specs"`
+}
+
+// baselineEntry is the baseline of a single spec
+type baselineEntry struct {
+	Fingerprint *spec.Fingerprint `json:"fingerprint"`
+	Surface     *spec.Surface     `json:"surface"`
+}
+
+// loadBaseline reads a baseline file. Returns nil without error if the file does not exist.
+func loadBaseline(path string) (*baselineFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline baselineFile
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if baseline.Specs == nil {
+		baseline.Specs = make(map[string]baselineEntry)
+	}
+	return &baseline, nil
+}
+
+// save writes the baseline with entries sorted by spec path
+func (b *baselineFile) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+	if err := writeFileWithRetry(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// newBaselineEntry computes the baseline of a spec
+func newBaselineEntry(specPath string) (baselineEntry, error) {
+	doc, err := spec.LoadDocument(specPath)
+	if err != nil {
+		return baselineEntry{}, err
+	}
+	return baselineEntry{
+		Fingerprint: spec.FingerprintDocument(doc),
+		Surface:     spec.SummarizeSurface(doc),
+	}, nil
+}
+
+// baselineServiceName returns the service name of a baseline key
+func baselineServiceName(key string) string {
+	return normalizeServiceName(filepath.Base(filepath.Dir(filepath.FromSlash(key))))
+}
+
+// compareWithBaseline compares the specs with the baseline and returns a changelog for each
+// spec whose operations or components changed, sorted by service. Specs missing from the
+// baseline are compared with an empty spec, and baseline entries whose spec file no longer
+// exists are reported as removed. With failOnBreaking, breaking changes fail the run.
+func compareWithBaseline(path, specsDir string, specs []string, failOnBreaking bool) ([]*Changelog, error) {
+	baseline, err := loadBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+	if baseline == nil {
+		return nil, fmt.Errorf("baseline %s does not exist (run with --update-baseline to create it)", path)
+	}
+
+	empty := &spec.Surface{}
+	var changelogs []*Changelog
+	record := func(key string, previous, current *spec.Surface) {
+		comparison := spec.CompareSurfaces(previous, current)
+		if !comparison.HasChanges() {
+			return
+		}
+		changelogs = append(changelogs, &Changelog{
+			Service:    baselineServiceName(key),
+			Breaking:   nonNil(comparison.Breaking()),
+			Comparison: withEmptyLists(comparison),
+		})
+	}
+
+	for _, specPath := range specs {
+		key := lockKey(specsDir, specPath)
+		current, err := newBaselineEntry(specPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint spec %s: %w", key, err)
+		}
+
+		entry, ok := baseline.Specs[key]
+		switch {
+		case !ok || entry.Surface == nil:
+			log.Printf("Baseline: %s is not in the baseline, comparing with an empty spec", key)
+			record(key, empty, current.Surface)
+		case entry.Fingerprint.Equal(current.Fingerprint):
+			// Unchanged since the baseline
+		default:
+			before := len(changelogs)
+			record(key, entry.Surface, current.Surface)
+			if len(changelogs) == before {
+				log.Printf("Baseline: %s changed outside its operations and components", key)
+			}
+		}
+	}
+
+	for _, key := range sortedBaselineKeys(baseline) {
+		if _, err := os.Stat(filepath.Join(specsDir, filepath.FromSlash(key))); os.IsNotExist(err) && baseline.Specs[key].Surface != nil {
+			log.Printf("Baseline: %s no longer exists", key)
+			record(key, baseline.Specs[key].Surface, empty)
+		}
+	}
+
+	sort.Slice(changelogs, func(i, j int) bool { return changelogs[i].Service < changelogs[j].Service })
+	logBaselineChanges(path, len(specs), changelogs)
+
+	if failOnBreaking {
+		var breaking []string
+		for _, changelog := range changelogs {
+			for _, reason := range changelog.Breaking {
+				breaking = append(breaking, changelog.Service+": "+reason)
+			}
+		}
+		if len(breaking) > 0 {
+			return changelogs, fmt.Errorf("breaking changes against baseline %s (run with --update-baseline to accept them):\n  %s",
+				path, strings.Join(breaking, "\n  "))
+		}
+	}
+	return changelogs, nil
+}
+
+// logBaselineChanges logs the changes of each service against the baseline
+func logBaselineChanges(path string, specCount int, changelogs []*Changelog) {
+	breakingServices := 0
+	for _, changelog := range changelogs {
+		log.Printf("Baseline: %s: +%d added, ~%d modified, -%d deleted operations, %d changed and %d deleted components",
+			changelog.Service, len(changelog.Added), len(changelog.Modified), len(changelog.Deleted),
+			len(changelog.ChangedComponents), len(changelog.DeletedComponents))
+		for _, reason := range changelog.Breaking {
+			log.Printf("  breaking: %s", reason)
+		}
+		if len(changelog.Breaking) > 0 {
+			breakingServices++
+		}
+	}
+	log.Printf("Compared %d spec(s) against baseline %s: %d changed, %d with breaking changes",
+		specCount, path, len(changelogs), breakingServices)
+}
+
+// sortedBaselineKeys returns the spec paths of the baseline in sorted order
+func sortedBaselineKeys(baseline *baselineFile) []string {
+	keys := make([]string, 0, len(baseline.Specs))
+	for key := range baseline.Specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// updateBaselineFile records the current fingerprints of the given specs in the baseline.
+// Entries for other specs are kept if their files still exist, so filtered runs don't drop them.
+func updateBaselineFile(path, specsDir string, specs []string) error {
+	baseline, err := loadBaseline(path)
+	if err != nil {
+		return err
+	}
+	if baseline == nil {
+		baseline = &baselineFile{Specs: make(map[string]baselineEntry)}
+	}
+
+	for key := range baseline.Specs {
+		if _, err := os.Stat(filepath.Join(specsDir, filepath.FromSlash(key))); os.IsNotExist(err) {
+			delete(baseline.Specs, key)
+		}
+	}
+
+	for _, specPath := range specs {
+		entry, err := newBaselineEntry(specPath)
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint spec %s: %w", specPath, err)
+		}
+		baseline.Specs[lockKey(specsDir, specPath)] = entry
+	}
+
+	if err := baseline.save(path); err != nil {
+		return err
+	}
+
+	log.Printf("Updated baseline %s with fingerprints of %d spec(s)", path, len(specs))
+	return nil
+}
@@ -0,0 +1,156 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+const baselineFundingSpec = `{"openapi":"3.0.0","paths":{
+	"/withdrawals":{"get":{"operationId":"listWithdrawals","responses":{"200":{}}},"post":{"operationId":"createWithdrawal","responses":{"201":{}}}}}}`
+
+func TestBaselineRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	baselinePath := filepath.Join(tmpDir, "ci", "fingerprints.json")
+	specs := []string{
+		writeLockTestSpec(t, specsDir, "funding-server-sdk", baselineFundingSpec),
+		writeLockTestSpec(t, specsDir, "holidays-server-sdk", `{"openapi":"3.0.0","paths":{"/holidays":{"get":{}}}}`),
+	}
+
+	if _, err := compareWithBaseline(baselinePath, specsDir, specs, false); err == nil || !strings.Contains(err.Error(), "--update-baseline") {
+		t.Errorf("compareWithBaseline() without a baseline error = %v, want a hint to create it", err)
+	}
+
+	if err := updateBaselineFile(baselinePath, specsDir, specs); err != nil {
+		t.Fatalf("updateBaselineFile() error = %v", err)
+	}
+	baseline, err := loadBaseline(baselinePath)
+	if err != nil {
+		t.Fatalf("loadBaseline() error = %v", err)
+	}
+	if keys := sortedBaselineKeys(baseline); !reflect.DeepEqual(keys, []string{"funding-server-sdk/openapi.json", "holidays-server-sdk/openapi.json"}) {
+		t.Errorf("baseline keys = %v", keys)
+	}
+	entry := baseline.Specs["funding-server-sdk/openapi.json"]
+	if entry.Fingerprint == nil || len(entry.Surface.Operations) != 2 {
+		t.Errorf("funding entry = %+v, want a fingerprint and 2 operations", entry)
+	}
+
+	changes, err := compareWithBaseline(baselinePath, specsDir, specs, true)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("compareWithBaseline() = %v, %v; want no changes against a fresh baseline", changes, err)
+	}
+}
+
+func TestBaselineDetectsBreakingChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	baselinePath := filepath.Join(tmpDir, "fingerprints.json")
+	fundingPath := writeLockTestSpec(t, specsDir, "funding-server-sdk", baselineFundingSpec)
+	holidaysPath := writeLockTestSpec(t, specsDir, "holidays-server-sdk", `{"openapi":"3.0.0","paths":{"/holidays":{"get":{}}}}`)
+	if err := updateBaselineFile(baselinePath, specsDir, []string{fundingPath, holidaysPath}); err != nil {
+		t.Fatalf("updateBaselineFile() error = %v", err)
+	}
+
+	// Remove an operation, drop holidays and add payments
+	writeLockTestSpec(t, specsDir, "funding-server-sdk", `{"openapi":"3.0.0","paths":{
+		"/withdrawals":{"get":{"operationId":"listWithdrawals","responses":{"200":{}}}}}}`)
+	if err := os.RemoveAll(filepath.Join(specsDir, "holidays-server-sdk")); err != nil {
+		t.Fatalf("Failed to remove holidays: %v", err)
+	}
+	paymentsPath := writeLockTestSpec(t, specsDir, "payments-server-sdk", `{"openapi":"3.0.0","paths":{"/payments":{"get":{}}}}`)
+	specs := []string{fundingPath, paymentsPath}
+
+	changes, err := compareWithBaseline(baselinePath, specsDir, specs, false)
+	if err != nil {
+		t.Fatalf("compareWithBaseline() error = %v", err)
+	}
+	var services []string
+	for _, change := range changes {
+		services = append(services, change.Service)
+	}
+	if !reflect.DeepEqual(services, []string{"funding", "holidays", "payments"}) {
+		t.Fatalf("changed services = %v, want funding, holidays and payments", services)
+	}
+	if want := []string{"`POST /withdrawals`: operation removed"}; !reflect.DeepEqual(changes[0].Breaking, want) {
+		t.Errorf("funding breaking = %v, want %v", changes[0].Breaking, want)
+	}
+	if want := []string{"GET /holidays"}; !reflect.DeepEqual(changes[1].Deleted, want) {
+		t.Errorf("holidays deleted = %v, want %v", changes[1].Deleted, want)
+	}
+	if want := []string{"GET /payments"}; !reflect.DeepEqual(changes[2].Added, want) || len(changes[2].Breaking) != 0 {
+		t.Errorf("payments = %+v, want one added operation and no breaking change", changes[2])
+	}
+
+	_, err = compareWithBaseline(baselinePath, specsDir, specs, true)
+	if err == nil {
+		t.Fatal("compareWithBaseline() should fail on breaking changes")
+	}
+	for _, want := range []string{"funding: `POST /withdrawals`: operation removed", "holidays: `GET /holidays`: operation removed", "--update-baseline"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err.Error(), want)
+		}
+	}
+
+	// Regenerating the baseline accepts the changes and drops the removed spec
+	if err := updateBaselineFile(baselinePath, specsDir, specs); err != nil {
+		t.Fatalf("updateBaselineFile() error = %v", err)
+	}
+	baseline, err := loadBaseline(baselinePath)
+	if err != nil {
+		t.Fatalf("loadBaseline() error = %v", err)
+	}
+	if keys := sortedBaselineKeys(baseline); !reflect.DeepEqual(keys, []string{"funding-server-sdk/openapi.json", "payments-server-sdk/openapi.json"}) {
+		t.Errorf("baseline keys after update = %v", keys)
+	}
+	if changes, err := compareWithBaseline(baselinePath, specsDir, specs, true); err != nil || len(changes) != 0 {
+		t.Errorf("compareWithBaseline() after update = %v, %v; want no changes", changes, err)
+	}
+}
+
+func TestBaselineFailsRunBeforeGeneration(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	fundingPath := writeLockTestSpec(t, specsDir, "funding-server-sdk", baselineFundingSpec)
+	baselinePath := filepath.Join(tmpDir, "fingerprints.json")
+	if err := updateBaselineFile(baselinePath, specsDir, []string{fundingPath}); err != nil {
+		t.Fatalf("updateBaselineFile() error = %v", err)
+	}
+	writeLockTestSpec(t, specsDir, "funding-server-sdk", `{"openapi":"3.0.0","paths":{}}`)
+
+	cfg := config.Config{
+		SpecsDir:               specsDir,
+		OutputDir:              filepath.Join(tmpDir, "output"),
+		WorkerCount:            1,
+		BaselineFingerprints:   baselinePath,
+		BaselineFailOnBreaking: true,
+	}
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "breaking changes against baseline") {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v, want breaking changes", err)
+	}
+	if len(report.BaselineChanges) != 1 || len(report.BaselineChanges[0].Deleted) != 2 {
+		t.Errorf("BaselineChanges = %+v, want funding with 2 deleted operations", report.BaselineChanges)
+	}
+	if gen.specPath != "" {
+		t.Error("the generator should not run when the baseline check fails")
+	}
+
+	// --update-baseline accepts the change, and the next run passes
+	cfg.UpdateBaseline = true
+	if _, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() with update_baseline error = %v", err)
+	}
+	cfg.UpdateBaseline = false
+	report, err = ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil || len(report.BaselineChanges) != 0 {
+		t.Errorf("ProcessOpenAPISpecsWithResult() = %+v, %v; want no changes after updating the baseline", report.BaselineChanges, err)
+	}
+}
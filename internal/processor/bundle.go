@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// bundleSpec writes the spec with its external $refs inlined to a temporary file and returns
+// its path with a cleanup removing it. Relative refs are resolved against the source spec's
+// directory, since transcoding may have moved the spec to a temporary file. Specs without
+// external refs are returned as is, and so are specs that cannot be parsed, which fail
+// validation with a clearer error. The snapshot's bundle is reused when specPath is the
+// snapshotted spec.
+func bundleSpec(sourcePath, specPath, serviceName string, snapshot *specSnapshot) (string, func(), error) {
+	noop := func() {}
+
+	fromSnapshot := snapshot != nil && snapshot.bundled && specPath == snapshot.path
+	var bundled map[string]interface{}
+	if fromSnapshot {
+		bundled = snapshot.doc
+	} else {
+		doc := snapshot.documentFor(specPath)
+		if doc == nil {
+			loaded, err := spec.LoadDocument(specPath)
+			if err != nil {
+				return specPath, noop, nil
+			}
+			doc = loaded
+		}
+		if !spec.HasExternalRefs(doc) {
+			return specPath, noop, nil
+		}
+
+		var err error
+		bundled, err = spec.BundleDocument(spec.RefLocation{Path: sourcePath, Root: doc})
+		if err != nil {
+			return "", noop, fmt.Errorf("failed to bundle external $refs for %s: %w", serviceName, err)
+		}
+	}
+
+	data, err := spec.EncodeDocument(bundled, filepath.Ext(specPath))
+	if err != nil {
+		return "", noop, err
+	}
+	bundledPath, cleanup, err := writeTempSpec("openapi-bundled-", specPath, data)
+	if err != nil {
+		return "", noop, err
+	}
+	if fromSnapshot {
+		snapshot.bundledPath = bundledPath
+	}
+
+	log.Printf("Bundled external $refs of %s into a single document", serviceName)
+	return bundledPath, cleanup, nil
+}
+
+// writeBundledSpec writes the prepared spec with its external $refs inlined to fileName in the
+// client directory. Relative refs are resolved against the source spec's directory, since
+// preprocessing may have moved the prepared spec to a temporary file. Returns the bundled document.
+func writeBundledSpec(sourcePath, preparedPath, clientPath, fileName string) (map[string]interface{}, error) {
+	root, err := spec.LoadDocument(preparedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec for bundling: %w", err)
+	}
+
+	bundled, err := spec.BundleDocument(spec.RefLocation{Path: sourcePath, Root: root})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle spec: %w", err)
+	}
+
+	data, err := spec.EncodeDocument(bundled, filepath.Ext(fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	bundlePath := filepath.Join(clientPath, fileName)
+	if err := os.MkdirAll(filepath.Dir(bundlePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for bundled spec: %w", err)
+	}
+	if err := writeFileWithRetry(bundlePath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write bundled spec: %w", err)
+	}
+
+	log.Printf("Bundled spec written to: %s", bundlePath)
+	return bundled, nil
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+func TestWriteBundledSpec(t *testing.T) {
+	specDir := t.TempDir()
+	sourcePath := filepath.Join(specDir, "openapi.yaml")
+	content := "openapi: 3.0.3\npaths:\n  /pets:\n    $ref: 'pets.yaml'\n"
+	if err := os.WriteFile(sourcePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "pets.yaml"), []byte("get:\n  operationId: listPets\n"), 0644); err != nil {
+		t.Fatalf("Failed to write pets.yaml: %v", err)
+	}
+
+	// Preprocessing moves the spec elsewhere; refs must still resolve against the source
+	preparedPath := filepath.Join(t.TempDir(), "openapi-prepared.yaml")
+	if err := os.WriteFile(preparedPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write prepared spec: %v", err)
+	}
+
+	clientPath := t.TempDir()
+	if _, err := writeBundledSpec(sourcePath, preparedPath, clientPath, "bundled-openapi.json"); err != nil {
+		t.Fatalf("writeBundledSpec() error = %v", err)
+	}
+
+	bundled, err := spec.LoadDocument(filepath.Join(clientPath, "bundled-openapi.json"))
+	if err != nil {
+		t.Fatalf("Failed to load bundled spec: %v", err)
+	}
+	operationID, err := spec.LookupPointer(bundled, "/paths/~1pets/get/operationId")
+	if err != nil || operationID != "listPets" {
+		t.Errorf("bundled operationId = %v, %v, want listPets", operationID, err)
+	}
+}
+
+func TestWriteBundledSpecUnresolvableRef(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi": "3.0.3", "paths": {"/x": {"$ref": "missing.json"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	clientPath := t.TempDir()
+	if _, err := writeBundledSpec(specPath, specPath, clientPath, "bundled-openapi.json"); err == nil {
+		t.Fatal("writeBundledSpec() expected error for missing $ref target")
+	}
+	if _, err := os.Stat(filepath.Join(clientPath, "bundled-openapi.json")); !os.IsNotExist(err) {
+		t.Error("no bundle should be written when bundling fails")
+	}
+}
+
+func TestRunBundlesExternalRefs(t *testing.T) {
+	gen := useRecordingGenerator(t)
+
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	specDir := filepath.Join(specsDir, "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	content := "openapi: 3.0.3\ninfo: {title: Funding, version: 1.0.0}\npaths:\n  /pets:\n    $ref: 'paths/pets.yaml'\n"
+	if err := os.WriteFile(filepath.Join(specDir, "openapi.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(specDir, "paths"), 0755); err != nil {
+		t.Fatalf("Failed to create paths dir: %v", err)
+	}
+	petsPath := filepath.Join(specDir, "paths", "pets.yaml")
+	if err := os.WriteFile(petsPath, []byte("get:\n  operationId: listPets\n"), 0644); err != nil {
+		t.Fatalf("Failed to write pets.yaml: %v", err)
+	}
+
+	cfg := config.Config{SpecsDir: specsDir, OutputDir: t.TempDir(), CacheDir: t.TempDir()}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	// The generator gets a self-contained spec in a temporary file
+	if gen.specPath == filepath.Join(specDir, "openapi.yaml") {
+		t.Error("generator should get the bundled spec, not the source")
+	}
+	bundled, err := spec.DecodeDocument([]byte(gen.specContent), filepath.Ext(gen.specPath))
+	if err != nil {
+		t.Fatalf("Failed to decode generated spec: %v", err)
+	}
+	if spec.HasExternalRefs(bundled) {
+		t.Errorf("generated spec still has external $refs: %s", gen.specContent)
+	}
+	if id, _ := spec.LookupPointer(bundled, "/paths/~1pets/get/operationId"); id != "listPets" {
+		t.Errorf("bundled operationId = %v, want listPets", id)
+	}
+	if _, err := os.Stat(gen.specPath); !os.IsNotExist(err) {
+		t.Error("the bundled temporary spec should be removed after the run")
+	}
+
+	// The snapshot counts the operations of referenced files
+	snapshot := takeSpecSnapshot(filepath.Join(specDir, "openapi.yaml"), nil, nil)
+	if !snapshot.bundled || snapshot.operationCount != 1 {
+		t.Errorf("snapshot bundled = %v, operationCount = %d; want a bundled spec with 1 operation",
+			snapshot.bundled, snapshot.operationCount)
+	}
+}
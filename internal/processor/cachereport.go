@@ -0,0 +1,272 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+)
+
+// CacheStats summarizes the generation cache of the selected generator version
+type CacheStats struct {
+	// CacheFile is the cache file of the generator version
+	CacheFile string `json:"cache_file"`
+
+	// GeneratorVersion is the version the cache file belongs to
+	GeneratorVersion string `json:"generator_version"`
+
+	// Entries is the number of cached clients, and Archived the number with a client archive
+	Entries  int `json:"entries"`
+	Archived int `json:"archived"`
+
+	// FileBytes and ArtifactBytes are the sizes of the cache file and of the client archives
+	FileBytes     int64 `json:"file_bytes"`
+	ArtifactBytes int64 `json:"artifact_bytes"`
+
+	// OldestGeneratedAt and NewestGeneratedAt bound when the cached clients were generated, and
+	// LeastRecentlyUsedAt is the oldest last use (nil without entries)
+	OldestGeneratedAt   *time.Time `json:"oldest_generated_at,omitempty"`
+	NewestGeneratedAt   *time.Time `json:"newest_generated_at,omitempty"`
+	LeastRecentlyUsedAt *time.Time `json:"least_recently_used_at,omitempty"`
+
+	// LastRun is the cache hit rate of the last run, from its metrics file (nil if there is none)
+	LastRun *CacheRunStats `json:"last_run,omitempty"`
+}
+
+// CacheRunStats is the cache hit rate of a run
+type CacheRunStats struct {
+	Specs   int       `json:"specs"`
+	Cached  int       `json:"cached"`
+	HitRate float64   `json:"hit_rate"`
+	EndTime time.Time `json:"end_time"`
+}
+
+// CacheInspection lists the cache entries of a service
+type CacheInspection struct {
+	Service string         `json:"service"`
+	Entries []CachedClient `json:"entries"`
+}
+
+// CachedClient is a cache entry and whether the last run served it from the cache
+type CachedClient struct {
+	SpecPath string `json:"spec_path"`
+	cache.Entry
+
+	// LastRunCached is nil if the last run did not process the spec
+	LastRunCached *bool `json:"last_run_cached,omitempty"`
+}
+
+// CachePruneResult reports the entries removed from the cache
+type CachePruneResult struct {
+	Removed   int `json:"removed"`
+	Remaining int `json:"remaining"`
+
+	// OlderThan is the prune age, empty when the cache was cleared
+	OlderThan string `json:"older_than,omitempty"`
+}
+
+// openRunCache opens the cache of the configured generator; readOnly leaves the cache file and
+// the archives unchanged
+func openRunCache(cfg config.Config, readOnly bool) (*cache.Cache, error) {
+	cacheConfig := runCacheConfig(cfg)
+	cacheConfig.ReadOnly = readOnly
+	specCache, err := cache.NewCache(cacheConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	return specCache, nil
+}
+
+// lastRunMetrics returns the metrics of the last run, or nil if it wrote none
+func lastRunMetrics(cfg config.Config) *metrics.Metrics {
+	m, err := metrics.Load(cfg.ResolvedMetricsPath())
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// CollectCacheStats summarizes the cache of the configured generator, with the hit rate of the
+// last run
+func CollectCacheStats(cfg config.Config) (*CacheStats, error) {
+	specCache, err := openRunCache(cfg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CacheStats{
+		CacheFile:        specCache.FilePath(),
+		GeneratorVersion: defaultGenerator.Version(),
+	}
+	stats.FileBytes, stats.ArtifactBytes = specCache.DiskUsage()
+	for _, entry := range specCache.Entries() {
+		stats.Entries++
+		if entry.Artifact != "" {
+			stats.Archived++
+		}
+		generatedAt, lastUsed := entry.GeneratedAt, entry.LastUsedAt()
+		if stats.OldestGeneratedAt == nil || generatedAt.Before(*stats.OldestGeneratedAt) {
+			stats.OldestGeneratedAt = &generatedAt
+		}
+		if stats.NewestGeneratedAt == nil || generatedAt.After(*stats.NewestGeneratedAt) {
+			stats.NewestGeneratedAt = &generatedAt
+		}
+		if stats.LeastRecentlyUsedAt == nil || lastUsed.Before(*stats.LeastRecentlyUsedAt) {
+			stats.LeastRecentlyUsedAt = &lastUsed
+		}
+	}
+
+	if m := lastRunMetrics(cfg); m != nil && m.TotalSpecs > 0 {
+		stats.LastRun = &CacheRunStats{
+			Specs:   m.TotalSpecs,
+			Cached:  m.CachedSpecs,
+			HitRate: float64(m.CachedSpecs) / float64(m.TotalSpecs) * 100,
+			EndTime: m.EndTime,
+		}
+	}
+	return stats, nil
+}
+
+// InspectCache returns the cache entries of a service, by its name as in "list"
+func InspectCache(cfg config.Config, service string) (*CacheInspection, error) {
+	specCache, err := openRunCache(cfg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedByPath := make(map[string]bool)
+	if m := lastRunMetrics(cfg); m != nil {
+		for _, spec := range m.SpecMetrics {
+			cachedByPath[spec.SpecPath] = spec.Cached
+		}
+	}
+
+	inspection := &CacheInspection{Service: service, Entries: []CachedClient{}}
+	for specPath, entry := range specCache.Entries() {
+		if entry.ServiceName != service {
+			continue
+		}
+		client := CachedClient{SpecPath: specPath, Entry: entry}
+		if cached, ok := cachedByPath[specPath]; ok {
+			client.LastRunCached = &cached
+		}
+		inspection.Entries = append(inspection.Entries, client)
+	}
+	if len(inspection.Entries) == 0 {
+		return nil, fmt.Errorf("no cache entries for service %q", service)
+	}
+	sort.Slice(inspection.Entries, func(i, j int) bool {
+		return inspection.Entries[i].SpecPath < inspection.Entries[j].SpecPath
+	})
+	return inspection, nil
+}
+
+// PruneCache removes the cache entries not written or served as a cache hit within olderThan
+func PruneCache(cfg config.Config, olderThan time.Duration) (*CachePruneResult, error) {
+	specCache, err := openRunCache(cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := specCache.PruneOlderThan(olderThan)
+	if err != nil {
+		return nil, err
+	}
+	return &CachePruneResult{Removed: removed, Remaining: specCache.Size(), OlderThan: olderThan.String()}, nil
+}
+
+// ClearCache removes every cache entry of the configured generator, so all clients are
+// regenerated by the next run
+func ClearCache(cfg config.Config) (*CachePruneResult, error) {
+	specCache, err := openRunCache(cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	removed := specCache.Size()
+	if err := specCache.Clear(); err != nil {
+		return nil, err
+	}
+	return &CachePruneResult{Removed: removed}, nil
+}
+
+// Format renders the cache summary, one line per figure
+func (s *CacheStats) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cache file:        %s (%d bytes)\n", s.CacheFile, s.FileBytes)
+	fmt.Fprintf(&b, "Generator version: %s\n", s.GeneratorVersion)
+	fmt.Fprintf(&b, "Entries:           %d\n", s.Entries)
+	if s.Archived > 0 || s.ArtifactBytes > 0 {
+		fmt.Fprintf(&b, "Archived clients:  %d (%d bytes)\n", s.Archived, s.ArtifactBytes)
+	}
+	if s.OldestGeneratedAt != nil {
+		fmt.Fprintf(&b, "Oldest entry:      generated %s\n", formatTimeAgo(*s.OldestGeneratedAt))
+		fmt.Fprintf(&b, "Newest entry:      generated %s\n", formatTimeAgo(*s.NewestGeneratedAt))
+		fmt.Fprintf(&b, "Least recent use:  %s\n", formatTimeAgo(*s.LeastRecentlyUsedAt))
+	}
+	if s.LastRun != nil {
+		fmt.Fprintf(&b, "Last run:          %d/%d clients from the cache (%.1f%% hit rate), %s\n",
+			s.LastRun.Cached, s.LastRun.Specs, s.LastRun.HitRate, formatTimeAgo(s.LastRun.EndTime))
+	}
+	return b.String()
+}
+
+// Format renders each entry of the service as a block of fields
+func (i *CacheInspection) Format() string {
+	var b strings.Builder
+	for n, client := range i.Entries {
+		if n > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n", client.SpecPath)
+		fmt.Fprintf(&b, "  Client:            %s\n", client.OutputPath)
+		fmt.Fprintf(&b, "  Generated:         %s with %s\n", formatTimeAgo(client.GeneratedAt), client.GeneratorVersion)
+		fmt.Fprintf(&b, "  Last used:         %s\n", formatTimeAgo(client.LastUsedAt()))
+		fmt.Fprintf(&b, "  Spec hash:         %s\n", client.SpecHash)
+		if client.SpecVersion != "" {
+			fmt.Fprintf(&b, "  Spec version:      %s, %d operations\n", client.SpecVersion, client.OperationCount)
+		}
+		if client.GeneratorConfigHash != "" {
+			fmt.Fprintf(&b, "  Generator config:  %s\n", client.GeneratorConfigHash)
+		}
+		if len(client.Dependencies) > 0 {
+			fmt.Fprintf(&b, "  Dependencies:      %d files\n", len(client.Dependencies))
+		}
+		if client.Artifact != "" {
+			fmt.Fprintf(&b, "  Archive:           %s\n", client.Artifact)
+		}
+		if client.LastRunCached != nil {
+			lastRun := "generated"
+			if *client.LastRunCached {
+				lastRun = "served from the cache"
+			}
+			fmt.Fprintf(&b, "  Last run:          %s\n", lastRun)
+		}
+	}
+	return b.String()
+}
+
+// Format renders the number of removed and remaining entries
+func (r *CachePruneResult) Format() string {
+	if r.OlderThan == "" {
+		return fmt.Sprintf("Removed %d cache entries\n", r.Removed)
+	}
+	return fmt.Sprintf("Removed %d cache entries not used in the last %s, %d remain\n", r.Removed, r.OlderThan, r.Remaining)
+}
+
+// formatTimeAgo renders a time with how long ago it was, e.g. "2026-01-02 15:04 (3d ago)"
+func formatTimeAgo(t time.Time) string {
+	age := time.Since(t)
+	var ago string
+	switch {
+	case age >= 24*time.Hour:
+		ago = fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	case age >= time.Hour:
+		ago = fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		ago = fmt.Sprintf("%dm ago", int(age/time.Minute))
+	}
+	return fmt.Sprintf("%s (%s)", t.Local().Format("2006-01-02 15:04"), ago)
+}
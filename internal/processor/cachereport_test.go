@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+func TestCacheManagement(t *testing.T) {
+	useRecordingGenerator(t)
+	tmpDir := t.TempDir()
+	cfg := config.Config{
+		SpecsDir:    writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk"),
+		OutputDir:   filepath.Join(tmpDir, "output"),
+		WorkerCount: 1,
+		EnableCache: true,
+		CacheDir:    filepath.Join(tmpDir, "cache"),
+	}
+	// The second run serves both clients from the cache
+	for range 2 {
+		if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+			t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+		}
+	}
+
+	stats, err := CollectCacheStats(cfg)
+	if err != nil {
+		t.Fatalf("CollectCacheStats() error = %v", err)
+	}
+	if stats.Entries != 2 || stats.FileBytes == 0 || stats.OldestGeneratedAt == nil {
+		t.Errorf("stats = %+v, want 2 entries", stats)
+	}
+	if stats.LastRun == nil || stats.LastRun.Cached != 2 || stats.LastRun.HitRate != 100 {
+		t.Errorf("LastRun = %+v, want both clients from the cache", stats.LastRun)
+	}
+	if output := stats.Format(); !strings.Contains(output, "2/2 clients from the cache (100.0% hit rate)") {
+		t.Errorf("Format() = %q, want the hit rate of the last run", output)
+	}
+
+	inspection, err := InspectCache(cfg, "funding")
+	if err != nil {
+		t.Fatalf("InspectCache() error = %v", err)
+	}
+	if len(inspection.Entries) != 1 {
+		t.Fatalf("InspectCache() = %+v, want the funding entry", inspection)
+	}
+	if client := inspection.Entries[0]; client.ServiceName != "funding" || client.LastRunCached == nil || !*client.LastRunCached {
+		t.Errorf("entry = %+v, want funding served from the cache by the last run", client)
+	}
+	if output := inspection.Format(); !strings.Contains(output, "Last run:          served from the cache") {
+		t.Errorf("Format() = %q", output)
+	}
+	if _, err := InspectCache(cfg, "payments"); err == nil {
+		t.Error("InspectCache() of a service without entries should fail")
+	}
+
+	pruned, err := PruneCache(cfg, time.Hour)
+	if err != nil || pruned.Removed != 0 || pruned.Remaining != 2 {
+		t.Errorf("PruneCache() = %+v, %v; want recently used entries kept", pruned, err)
+	}
+	cleared, err := ClearCache(cfg)
+	if err != nil || cleared.Removed != 2 {
+		t.Errorf("ClearCache() = %+v, %v; want 2 removed", cleared, err)
+	}
+	if stats, err := CollectCacheStats(cfg); err != nil || stats.Entries != 0 {
+		t.Errorf("CollectCacheStats() after clearing = %+v, %v; want no entries", stats, err)
+	}
+}
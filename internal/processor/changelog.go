@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// Changelog is the comparison of two versions of a service's spec
+type Changelog struct {
+	// Service is the name of the compared service
+	Service string `json:"service"`
+
+	// Breaking describes every breaking change, sorted
+	Breaking []string `json:"breaking"`
+
+	*spec.Comparison
+}
+
+// Format renders the changelog as Markdown
+func (c *Changelog) Format() string {
+	return FormatChangelog(c.Service, c.Comparison)
+}
+
+// CompareSpecs compares two versions of a service's spec.
+// An empty serviceName is derived from the directory of the current spec.
+func CompareSpecs(serviceName, previousPath, currentPath string) (*Changelog, error) {
+	comparison, err := spec.CompareFiles(previousPath, currentPath)
+	if err != nil {
+		return nil, err
+	}
+	if serviceName == "" {
+		serviceName = normalizeServiceName(filepath.Base(filepath.Dir(currentPath)))
+	}
+	return &Changelog{Service: serviceName, Breaking: nonNil(comparison.Breaking()), Comparison: withEmptyLists(comparison)}, nil
+}
+
+// withEmptyLists replaces nil lists of a comparison with empty ones, so every JSON field is an array
+func withEmptyLists(c *spec.Comparison) *spec.Comparison {
+	c.Added = nonNil(c.Added)
+	c.Deleted = nonNil(c.Deleted)
+	c.ChangedComponents = nonNil(c.ChangedComponents)
+	c.DeletedComponents = nonNil(c.DeletedComponents)
+	if c.Modified == nil {
+		c.Modified = []spec.OperationChange{}
+	}
+	for i := range c.Modified {
+		c.Modified[i].BreakingReasons = nonNil(c.Modified[i].BreakingReasons)
+	}
+	return c
+}
+
+// nonNil returns values, or an empty slice if it is nil
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// BuildChangelog compares two versions of a service's spec and renders a Markdown changelog.
+// An empty serviceName is derived from the directory of the current spec.
+func BuildChangelog(serviceName, previousPath, currentPath string) (string, error) {
+	changelog, err := CompareSpecs(serviceName, previousPath, currentPath)
+	if err != nil {
+		return "", err
+	}
+	return changelog.Format(), nil
+}
+
+// FormatChangelog renders a spec comparison as Markdown, grouping breaking changes and
+// added, modified and deleted operations under their own headings
+func FormatChangelog(serviceName string, comparison *spec.Comparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Changelog: %s\n", serviceName)
+
+	if !comparison.HasChanges() {
+		b.WriteString("\nNo API changes.\n")
+		return b.String()
+	}
+
+	writeChangelogSection(&b, "Breaking changes", comparison.Breaking())
+	writeChangelogSection(&b, "Added", quoteAll(comparison.Added))
+
+	modified := make([]string, 0, len(comparison.Modified))
+	for _, change := range comparison.Modified {
+		entry := "`" + change.Operation + "`"
+		if change.Breaking() {
+			entry += " (breaking)"
+		}
+		modified = append(modified, entry)
+	}
+	writeChangelogSection(&b, "Modified", modified)
+	writeChangelogSection(&b, "Deleted", quoteAll(comparison.Deleted))
+
+	var components []string
+	for _, name := range comparison.ChangedComponents {
+		components = append(components, "`"+name+"` added or changed")
+	}
+	for _, name := range comparison.DeletedComponents {
+		components = append(components, "`"+name+"` removed")
+	}
+	writeChangelogSection(&b, "Components", components)
+
+	return b.String()
+}
+
+// writeChangelogSection writes a heading followed by one bullet per entry; empty sections are skipped
+func writeChangelogSection(b *strings.Builder, heading string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", heading)
+	for _, entry := range entries {
+		fmt.Fprintf(b, "- %s\n", entry)
+	}
+}
+
+// quoteAll wraps each value in backticks
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "`" + value + "`"
+	}
+	return quoted
+}
@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildChangelog(t *testing.T) {
+	dir := t.TempDir()
+	previousPath := filepath.Join(dir, "previous.yaml")
+	currentPath := filepath.Join(dir, "funding-server-sdk", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(currentPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+
+	previous := `openapi: 3.0.3
+paths:
+  /withdrawals:
+    get:
+      responses: {"200": {}}
+    post:
+      responses: {"201": {}}
+  /withdrawals/{id}:
+    delete:
+      responses: {"204": {}}
+`
+	current := `{
+		"openapi": "3.0.3",
+		"paths": {
+			"/withdrawals": {
+				"get": {"parameters": [{"name": "status", "in": "query", "required": true}], "responses": {"200": {}}},
+				"post": {"summary": "Create a withdrawal", "responses": {"201": {}}}
+			},
+			"/deposits": {"get": {"responses": {"200": {}}}}
+		}
+	}`
+	if err := os.WriteFile(previousPath, []byte(previous), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.WriteFile(currentPath, []byte(current), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	changelog, err := BuildChangelog("", previousPath, currentPath)
+	if err != nil {
+		t.Fatalf("BuildChangelog() error = %v", err)
+	}
+
+	// Each operation is listed under the heading it belongs to
+	want := []struct {
+		heading string
+		entry   string
+	}{
+		{"## Breaking changes", "- `DELETE /withdrawals/{id}`: operation removed"},
+		{"## Breaking changes", "- `GET /withdrawals`: new required parameter `status` (query)"},
+		{"## Added", "- `GET /deposits`"},
+		{"## Modified", "- `GET /withdrawals` (breaking)"},
+		{"## Modified", "- `POST /withdrawals`"},
+		{"## Deleted", "- `DELETE /withdrawals/{id}`"},
+	}
+	sections := changelogSections(changelog)
+	for _, w := range want {
+		if !contains(sections[w.heading], w.entry) {
+			t.Errorf("section %q missing %q\n%s", w.heading, w.entry, changelog)
+		}
+	}
+	if !strings.HasPrefix(changelog, "# Changelog: funding\n") {
+		t.Errorf("changelog should be titled with the derived service name:\n%s", changelog)
+	}
+	if strings.Contains(sections["## Breaking changes"], "POST /withdrawals") {
+		t.Errorf("non-breaking modification listed as breaking:\n%s", changelog)
+	}
+}
+
+func TestBuildChangelogNoChanges(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi": "3.0.3", "paths": {"/a": {"get": {}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	changelog, err := BuildChangelog("holidays", specPath, specPath)
+	if err != nil {
+		t.Fatalf("BuildChangelog() error = %v", err)
+	}
+	if want := "# Changelog: holidays\n\nNo API changes.\n"; changelog != want {
+		t.Errorf("BuildChangelog() = %q, want %q", changelog, want)
+	}
+}
+
+func TestBuildChangelogMissingSpec(t *testing.T) {
+	if _, err := BuildChangelog("svc", "missing-previous.json", "missing-current.json"); err == nil {
+		t.Error("BuildChangelog() should fail for missing spec files")
+	}
+}
+
+// changelogSections splits a Markdown changelog into its "## " sections keyed by heading
+func changelogSections(markdown string) map[string]string {
+	sections := make(map[string]string)
+	heading := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			heading = line
+			continue
+		}
+		sections[heading] += line + "\n"
+	}
+	return sections
+}
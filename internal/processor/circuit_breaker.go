@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+)
+
+// errBatchAborted is returned for specs skipped after the failure breaker tripped
+var errBatchAborted = errors.New("skipped: batch aborted after repeated generator failures")
+
+// failureBreaker aborts a batch after a number of consecutive generation failures with the
+// same error code (e.g. a missing generator binary), even with continue_on_error, since the
+// remaining specs would fail the same way. Failures classified as OTHER (validation,
+// preprocessing) have unrelated causes and reset the streak like successes. A nil breaker
+// never trips.
+type failureBreaker struct {
+	mu      sync.Mutex
+	limit   int
+	code    generator.ErrorCode
+	streak  int
+	tripped error
+}
+
+// newFailureBreaker creates a breaker tripping after limit consecutive failures (nil if limit is 0)
+func newFailureBreaker(limit int) *failureBreaker {
+	if limit <= 0 {
+		return nil
+	}
+	return &failureBreaker{limit: limit}
+}
+
+// Record records the outcome of a spec. Once the limit is reached it trips and logs why.
+func (b *failureBreaker) Record(serviceName string, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tripped != nil {
+		return
+	}
+	if err == nil || errors.Is(err, errBatchAborted) {
+		b.streak = 0
+		return
+	}
+
+	code := generator.AsGenerationError(serviceName, err).Code
+	if code == generator.ErrCodeOther {
+		b.streak = 0
+		return
+	}
+	if code != b.code {
+		b.code = code
+		b.streak = 0
+	}
+	b.streak++
+
+	if b.streak >= b.limit {
+		b.tripped = fmt.Errorf("aborting batch after %d consecutive %s failures (consecutive_failure_limit), last for %s: %w",
+			b.streak, code, serviceName, err)
+		log.Printf("⛔ %v", b.tripped)
+	}
+}
+
+// Err returns the reason the breaker tripped, or nil
+func (b *failureBreaker) Err() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tripped
+}
@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+// failingGenerator is a fake generator failing every call with the same error code
+type failingGenerator struct {
+	code  generator.ErrorCode
+	calls atomic.Int32
+}
+
+func (g *failingGenerator) Name() string                              { return "failing" }
+func (g *failingGenerator) Version() string                           { return "v0.0.0-test" }
+func (g *failingGenerator) EnsureInstalled(ctx context.Context) error { return nil }
+func (g *failingGenerator) IsInstalled() bool                         { return true }
+
+func (g *failingGenerator) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	g.calls.Add(1)
+	return &generator.GenerationError{Code: g.code, PackageName: spec.PackageName, Err: errors.New("ogen: command not found")}
+}
+
+func TestFailureBreaker(t *testing.T) {
+	notInstalled := &generator.GenerationError{Code: generator.ErrCodeGeneratorNotInstalled, Err: errors.New("not found")}
+	timeout := &generator.GenerationError{Code: generator.ErrCodeGeneratorTimeout, Err: errors.New("deadline")}
+	other := errors.New("validation failed")
+
+	tests := []struct {
+		name        string
+		outcomes    []error
+		wantTripped bool
+	}{
+		{name: "same code reaches the limit", outcomes: []error{notInstalled, notInstalled, notInstalled}, wantTripped: true},
+		{name: "below the limit", outcomes: []error{notInstalled, notInstalled}, wantTripped: false},
+		{name: "success resets the streak", outcomes: []error{notInstalled, notInstalled, nil, notInstalled}, wantTripped: false},
+		{name: "different code resets the streak", outcomes: []error{notInstalled, notInstalled, timeout, notInstalled}, wantTripped: false},
+		{name: "other failures do not count", outcomes: []error{other, other, other}, wantTripped: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := newFailureBreaker(3)
+			for i, outcome := range tt.outcomes {
+				breaker.Record(fmt.Sprintf("svc%d", i), outcome)
+			}
+			if tripped := breaker.Err() != nil; tripped != tt.wantTripped {
+				t.Errorf("tripped = %v, want %v (err: %v)", tripped, tt.wantTripped, breaker.Err())
+			}
+		})
+	}
+
+	disabled := newFailureBreaker(0)
+	disabled.Record("svc", notInstalled)
+	if disabled.Err() != nil {
+		t.Error("a limit of 0 should disable the breaker")
+	}
+}
+
+func TestProcessOpenAPISpecsConsecutiveFailureLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		workerCount int
+		maxCalls    int32
+	}{
+		{name: "sequential", workerCount: 1, maxCalls: 2},
+		// Specs already started when the breaker trips still finish
+		{name: "parallel", workerCount: 2, maxCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &failingGenerator{code: generator.ErrCodeGeneratorNotInstalled}
+			previousGenerator := defaultGenerator
+			previousChain := defaultPostProcessorChain
+			SetGenerator(fake)
+			SetPostProcessorChain(postprocessor.NewChain())
+			t.Cleanup(func() {
+				defaultGenerator = previousGenerator
+				defaultPostProcessorChain = previousChain
+			})
+
+			tmpDir := t.TempDir()
+			for _, service := range []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"} {
+				specDir := filepath.Join(tmpDir, "specs", service+"-server-sdk")
+				if err := os.MkdirAll(specDir, 0755); err != nil {
+					t.Fatalf("Failed to create spec dir: %v", err)
+				}
+				if err := os.WriteFile(filepath.Join(specDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+					t.Fatalf("Failed to write spec: %v", err)
+				}
+			}
+
+			cfg := config.Config{
+				SpecsDir:                filepath.Join(tmpDir, "specs"),
+				OutputDir:               filepath.Join(tmpDir, "output"),
+				WorkerCount:             tt.workerCount,
+				ContinueOnError:         true,
+				ConsecutiveFailureLimit: 2,
+			}
+
+			report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+			if err == nil {
+				t.Fatal("ProcessOpenAPISpecsWithResult() should abort after consecutive failures")
+			}
+			if !strings.Contains(err.Error(), "2 consecutive GENERATOR_NOT_INSTALLED failures") {
+				t.Errorf("error should summarize the repeated failure: %v", err)
+			}
+			if !strings.Contains(err.Error(), "not attempted") {
+				t.Errorf("error should report skipped specs: %v", err)
+			}
+
+			calls := fake.calls.Load()
+			if calls < 2 || calls > tt.maxCalls {
+				t.Errorf("generator called %d times, want between 2 and %d", calls, tt.maxCalls)
+			}
+			if report != nil && len(report.Result.FailedSpecs) != int(calls) {
+				t.Errorf("FailedSpecs = %d, want one per attempted spec (%d)", len(report.Result.FailedSpecs), calls)
+			}
+		})
+	}
+}
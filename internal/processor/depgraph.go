@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// dependencyGraph links each spec to the files it $refs (specs → shared files) and each file
+// to the specs referencing it (shared files → dependents), built by scanning external $refs
+// during discovery. Cache entries record the hashes of their spec's dependencies, so a changed
+// shared file regenerates exactly its dependents. A nil graph has no dependencies.
+type dependencyGraph struct {
+	dependencies map[string][]string // key: spec path, sorted file paths
+	dependents   map[string][]string // key: file path, sorted spec paths
+	hashes       map[string]string   // key: file path, "" if the file cannot be read
+
+	// documents are the specs parsed while scanning, handed over to their task's snapshot so
+	// each spec is still parsed once per run
+	mu        sync.Mutex
+	documents map[string]map[string]interface{}
+}
+
+// buildDependencyGraph scans the external $refs of each spec, following refs inside
+// referenced files. Specs that cannot be parsed have no dependencies.
+func buildDependencyGraph(specs []string) *dependencyGraph {
+	graph := &dependencyGraph{
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+		documents:    make(map[string]map[string]interface{}),
+	}
+
+	var files []string
+	for _, specPath := range specs {
+		doc, err := spec.LoadDocument(specPath)
+		if err != nil {
+			continue
+		}
+		graph.documents[specPath] = doc
+
+		refs := spec.ExternalRefFiles(specPath, doc)
+		if len(refs) == 0 {
+			continue
+		}
+		graph.dependencies[specPath] = refs
+		for _, file := range refs {
+			if _, ok := graph.dependents[file]; !ok {
+				files = append(files, file)
+			}
+			graph.dependents[file] = append(graph.dependents[file], specPath)
+		}
+	}
+	for _, dependents := range graph.dependents {
+		sort.Strings(dependents)
+	}
+
+	// Each shared file is hashed once, however many specs reference it
+	graph.hashes = cache.HashFiles(files)
+	return graph
+}
+
+// takeDocument returns the document parsed for a spec while scanning, once, or nil
+func (g *dependencyGraph) takeDocument(specPath string) map[string]interface{} {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	doc := g.documents[specPath]
+	delete(g.documents, specPath)
+	return doc
+}
+
+// Dependencies returns the files a spec references, directly or transitively
+func (g *dependencyGraph) Dependencies(specPath string) []string {
+	if g == nil {
+		return nil
+	}
+	return g.dependencies[specPath]
+}
+
+// Dependents returns the specs referencing a file, directly or transitively
+func (g *dependencyGraph) Dependents(file string) []string {
+	if g == nil {
+		return nil
+	}
+	return g.dependents[filepath.Clean(file)]
+}
+
+// DependencyHashes returns the hashes of the files a spec references, keyed by path, as
+// recorded in its cache entry (nil without dependencies)
+func (g *dependencyGraph) DependencyHashes(specPath string) map[string]string {
+	files := g.Dependencies(specPath)
+	if len(files) == 0 {
+		return nil
+	}
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		hashes[file] = g.hashes[file]
+	}
+	return hashes
+}
+
+// Files returns the referenced files in sorted order
+func (g *dependencyGraph) Files() []string {
+	if g == nil {
+		return nil
+	}
+	files := make([]string, 0, len(g.dependents))
+	for file := range g.dependents {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// unreferenced returns the files no spec $refs. Changes to referenced files regenerate their
+// dependents through the cache entries; other shared_component_files still invalidate the
+// whole cache, since the specs depending on them are unknown.
+func (g *dependencyGraph) unreferenced(files []string) []string {
+	var result []string
+	for _, file := range files {
+		if len(g.Dependents(file)) == 0 {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+// logDependencyGraph logs each shared file with the number of specs depending on it
+func logDependencyGraph(g *dependencyGraph) {
+	files := g.Files()
+	if len(files) == 0 {
+		return
+	}
+	log.Printf("Dependency graph: %d spec(s) reference %d shared file(s)", len(g.dependencies), len(files))
+	for _, file := range files {
+		log.Printf("  %s: %d dependent spec(s)", file, len(g.dependents[file]))
+	}
+}
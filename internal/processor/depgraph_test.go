@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+// writeSharedSpecs writes three specs, funding and payments referencing shared/components.yaml
+// and holidays standalone. Returns the specs directory and the shared file path.
+func writeSharedSpecs(t *testing.T) (string, string) {
+	t.Helper()
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	sharedPath := filepath.Join(specsDir, "shared", "components.yaml")
+	if err := os.MkdirAll(filepath.Dir(sharedPath), 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	if err := os.WriteFile(sharedPath, []byte("Money:\n  type: object\n"), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+
+	referencing := `{"openapi":"3.0.0","paths":{},"components":{"schemas":{"Money":{"$ref":"../shared/components.yaml#/Money"}}}}`
+	writeLockTestSpec(t, specsDir, "funding-server-sdk", referencing)
+	writeLockTestSpec(t, specsDir, "payments-server-sdk", referencing)
+	writeLockTestSpec(t, specsDir, "holidays-server-sdk", `{"openapi":"3.0.0","paths":{}}`)
+	return specsDir, sharedPath
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	specsDir, sharedPath := writeSharedSpecs(t)
+	specs, err := findOpenAPISpecs(specsDir, "", nil, false)
+	if err != nil {
+		t.Fatalf("findOpenAPISpecs() error = %v", err)
+	}
+
+	graph := buildDependencyGraph(specs)
+	if files := graph.Files(); !reflect.DeepEqual(files, []string{sharedPath}) {
+		t.Errorf("Files() = %v, want %s", files, sharedPath)
+	}
+	wantDependents := []string{
+		filepath.Join(specsDir, "funding-server-sdk", "openapi.json"),
+		filepath.Join(specsDir, "payments-server-sdk", "openapi.json"),
+	}
+	if dependents := graph.Dependents(sharedPath); !reflect.DeepEqual(dependents, wantDependents) {
+		t.Errorf("Dependents() = %v, want %v", dependents, wantDependents)
+	}
+	if deps := graph.Dependencies(filepath.Join(specsDir, "holidays-server-sdk", "openapi.json")); len(deps) != 0 {
+		t.Errorf("holidays Dependencies() = %v, want none", deps)
+	}
+	if hashes := graph.DependencyHashes(wantDependents[0]); len(hashes) != 1 || hashes[sharedPath] == "" {
+		t.Errorf("DependencyHashes() = %v, want the hash of %s", hashes, sharedPath)
+	}
+
+	other := filepath.Join(specsDir, "other.yaml")
+	if got := graph.unreferenced([]string{sharedPath, other}); !reflect.DeepEqual(got, []string{other}) {
+		t.Errorf("unreferenced() = %v, want only %s", got, other)
+	}
+
+	var nilGraph *dependencyGraph
+	if nilGraph.Dependencies(wantDependents[0]) != nil || nilGraph.DependencyHashes(wantDependents[0]) != nil || nilGraph.Files() != nil {
+		t.Error("a nil graph should have no dependencies")
+	}
+}
+
+func TestSharedFileChangeRegeneratesOnlyDependents(t *testing.T) {
+	useRecordingGenerator(t)
+	specsDir, sharedPath := writeSharedSpecs(t)
+	cfg := config.Config{
+		SpecsDir:    specsDir,
+		OutputDir:   filepath.Join(t.TempDir(), "output"),
+		WorkerCount: 1,
+		EnableCache: true,
+		CacheDir:    t.TempDir(),
+		// Listing the file no longer invalidates every client, since its dependents are known
+		SharedComponentFiles: []string{sharedPath},
+	}
+
+	// regenerated runs the generator and returns the services that were not served from the cache
+	regenerated := func() []string {
+		t.Helper()
+		report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+		}
+		var services []string
+		for _, metric := range report.Metrics.SpecMetrics {
+			if !metric.Cached {
+				services = append(services, metric.ServiceName)
+			}
+		}
+		sort.Strings(services)
+		return services
+	}
+
+	if got := regenerated(); len(got) != 3 {
+		t.Fatalf("first run regenerated %v, want all 3 services", got)
+	}
+	if got := regenerated(); len(got) != 0 {
+		t.Fatalf("unchanged run regenerated %v, want none", got)
+	}
+
+	if err := os.WriteFile(sharedPath, []byte("Money:\n  type: string\n"), 0644); err != nil {
+		t.Fatalf("Failed to update shared file: %v", err)
+	}
+	if got := regenerated(); !reflect.DeepEqual(got, []string{"funding", "payments"}) {
+		t.Errorf("after the shared file changed, regenerated %v, want funding and payments", got)
+	}
+	if got := regenerated(); len(got) != 0 {
+		t.Errorf("run after regenerating dependents regenerated %v, want none", got)
+	}
+}
@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// excludeDeprecatedOperations writes a copy of the spec without operations marked deprecated
+// to a temporary JSON file, so generated SDKs don't expose retiring endpoints.
+// Specs without deprecated operations are returned unchanged with a no-op cleanup.
+// parsed is the already decoded spec, or nil to load it; it is copied, not modified.
+func excludeDeprecatedOperations(specPath, serviceName string, parsed map[string]interface{}) (string, func(), error) {
+	noop := func() {}
+
+	doc := spec.CopyDocument(parsed)
+	if doc == nil {
+		var err error
+		if doc, err = spec.LoadDocument(specPath); err != nil {
+			return "", noop, fmt.Errorf("failed to load spec for %s: %w", serviceName, err)
+		}
+	}
+
+	removed := spec.RemoveDeprecatedOperations(doc)
+	if len(removed) == 0 {
+		return specPath, noop, nil
+	}
+	log.Printf("Excluding %d deprecated operation(s) from %s: %v", len(removed), serviceName, removed)
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to encode spec for %s: %w", serviceName, err)
+	}
+
+	// The filtered spec is always JSON, whatever the original format
+	return writeTempSpec("openapi-nodeprecated-", "openapi.json", data)
+}
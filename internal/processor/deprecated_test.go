@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateClientForSpecExcludesDeprecated(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    get:
+      operationId: listUsers
+    delete:
+      operationId: deleteAllUsers
+      deprecated: true
+  /v1/users:
+    get:
+      operationId: listUsersV1
+      deprecated: true
+`
+
+	tests := []struct {
+		name              string
+		excludeDeprecated bool
+		wantDeprecated    bool
+	}{
+		{name: "deprecated operations excluded", excludeDeprecated: true, wantDeprecated: false},
+		{name: "deprecated operations kept by default", excludeDeprecated: false, wantDeprecated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := useRecordingGenerator(t)
+
+			tmpDir := t.TempDir()
+			specPath := filepath.Join(tmpDir, "users-sdk", "openapi.yaml")
+			if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+				t.Fatalf("Failed to create spec dir: %v", err)
+			}
+			if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			opts := pipelineOptions{excludeDeprecated: tt.excludeDeprecated}
+			if err := generateClientForSpec(ctx, specPath, "users", "userssdk", filepath.Join(tmpDir, "output"), nil, opts); err != nil {
+				t.Fatalf("generateClientForSpec() error = %v", err)
+			}
+
+			if !contains(gen.specContent, "listUsers") {
+				t.Errorf("generated spec should keep listUsers:\n%s", gen.specContent)
+			}
+			for _, operationID := range []string{"deleteAllUsers", "listUsersV1"} {
+				if got := contains(gen.specContent, operationID); got != tt.wantDeprecated {
+					t.Errorf("generated spec contains %s = %v, want %v:\n%s", operationID, got, tt.wantDeprecated, gen.specContent)
+				}
+			}
+			if tt.excludeDeprecated && contains(gen.specContent, "/v1/users") {
+				t.Errorf("path without remaining operations should be removed:\n%s", gen.specContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// discoveryMtimeGranularity is the coarsest directory mtime resolution trusted by the discovery
+// cache. A listing read within this long of the directory's mtime may have missed a change
+// made in the same tick, so it is not reused.
+const discoveryMtimeGranularity = 2 * time.Second
+
+// specDiscovery caches directory listings of the specs tree between runs (watch mode, repeated
+// runs in one process), so unchanged directories are not read again
+var specDiscovery = newDiscoveryCache()
+
+// discoveryCache holds directory listings keyed by path, each valid while the directory's
+// mtime is unchanged. Adding, removing or renaming an entry changes the mtime of its parent
+// directory only, so every directory of the tree is still stat'ed; an unchanged directory
+// reuses its listing instead of being read, and a changed one is read again.
+type discoveryCache struct {
+	mu       sync.Mutex
+	listings map[discoveryKey]*dirListing
+	counts   discoveryStats
+}
+
+// discoveryKey identifies a listing; symlinked entries are classified differently when
+// symlinks are followed
+type discoveryKey struct {
+	path           string
+	followSymlinks bool
+}
+
+// dirListing is the cached content of a directory
+type dirListing struct {
+	modTime  time.Time
+	listedAt time.Time
+	entries  []dirEntry // sorted by name
+}
+
+// dirEntry is a directory entry; dir reports whether it is walked into
+type dirEntry struct {
+	name string
+	dir  bool
+}
+
+// discoveryStats counts directories whose listing was reused or read
+type discoveryStats struct {
+	reused int
+	read   int
+}
+
+// newDiscoveryCache creates an empty discovery cache
+func newDiscoveryCache() *discoveryCache {
+	return &discoveryCache{listings: make(map[discoveryKey]*dirListing)}
+}
+
+// findFiles walks the tree rooted at root like walkSpecTree and returns the paths of files
+// accepted by match, in walk order. Unreadable directories are skipped.
+func (c *discoveryCache) findFiles(root string, followSymlinks bool, match func(name string) bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var info os.FileInfo
+	var err error
+	if followSymlinks {
+		info, err = os.Stat(root)
+	} else {
+		info, err = os.Lstat(root)
+	}
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		if match(filepath.Base(root)) {
+			return []string{root}
+		}
+		return nil
+	}
+
+	var files []string
+	c.walk(root, info, followSymlinks, make(map[string]bool), match, &files)
+	return files
+}
+
+// walk collects the matching files under dir. The caller must hold c.mu.
+func (c *discoveryCache) walk(dir string, info os.FileInfo, followSymlinks bool, visited map[string]bool, match func(string) bool, files *[]string) {
+	// Guard against symlink loops by tracking resolved directory paths
+	if followSymlinks {
+		realPath, err := filepath.EvalSymlinks(dir)
+		if err != nil || visited[realPath] {
+			return
+		}
+		visited[realPath] = true
+	}
+
+	listing, err := c.listing(dir, info, followSymlinks)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range listing.entries {
+		path := filepath.Join(dir, entry.name)
+		if !entry.dir {
+			if match(entry.name) {
+				*files = append(*files, path)
+			}
+			continue
+		}
+
+		var childInfo os.FileInfo
+		if followSymlinks {
+			childInfo, err = os.Stat(path)
+		} else {
+			childInfo, err = os.Lstat(path)
+		}
+		if err != nil || !childInfo.IsDir() {
+			continue
+		}
+		c.walk(path, childInfo, followSymlinks, visited, match, files)
+	}
+}
+
+// listing returns the entries of dir, reusing the cached listing if the directory's mtime is
+// unchanged. The caller must hold c.mu.
+func (c *discoveryCache) listing(dir string, info os.FileInfo, followSymlinks bool) (*dirListing, error) {
+	key := discoveryKey{path: dir, followSymlinks: followSymlinks}
+	if cached, ok := c.listings[key]; ok && cached.modTime.Equal(info.ModTime()) &&
+		cached.listedAt.Sub(cached.modTime) >= discoveryMtimeGranularity {
+		c.counts.reused++
+		return cached, nil
+	}
+
+	listedAt := time.Now()
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		delete(c.listings, key)
+		return nil, err
+	}
+	c.counts.read++
+
+	listing := &dirListing{modTime: info.ModTime(), listedAt: listedAt}
+	for _, entry := range dirEntries {
+		isDir := entry.IsDir()
+		// os.Stat follows symlinks, so symlinked directories are walked into
+		if followSymlinks && entry.Type()&os.ModeSymlink != 0 {
+			if target, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil {
+				isDir = target.IsDir()
+			}
+		}
+		listing.entries = append(listing.entries, dirEntry{name: entry.Name(), dir: isDir})
+	}
+	c.listings[key] = listing
+	return listing, nil
+}
+
+// stats returns the number of directory listings reused and read since the cache was created
+func (c *discoveryCache) stats() discoveryStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts
+}
@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// isTestSpecFile matches the default spec file names
+func isTestSpecFile(name string) bool {
+	return name == "openapi.json" || name == "openapi.yaml"
+}
+
+// writeDiscoveryTree creates specs for two services, one nested, and returns the root
+func writeDiscoveryTree(t *testing.T) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "specs")
+	for _, path := range []string{
+		filepath.Join(root, "funding-server-sdk", "openapi.json"),
+		filepath.Join(root, "team", "holidays-server-sdk", "openapi.yaml"),
+		filepath.Join(root, "team", "README.md"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	return root
+}
+
+// ageDirectories sets the mtime of every directory under root an hour back, so their
+// listings are old enough to be reused
+func ageDirectories(t *testing.T, root string) {
+	t.Helper()
+	past := time.Now().Add(-time.Hour)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, past, past)
+	})
+	if err != nil {
+		t.Fatalf("Failed to age directories: %v", err)
+	}
+}
+
+func TestDiscoveryCacheReusesUnchangedTree(t *testing.T) {
+	root := writeDiscoveryTree(t)
+	ageDirectories(t, root)
+	c := newDiscoveryCache()
+
+	want := []string{
+		filepath.Join(root, "funding-server-sdk", "openapi.json"),
+		filepath.Join(root, "team", "holidays-server-sdk", "openapi.yaml"),
+	}
+	first := c.findFiles(root, false, isTestSpecFile)
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("findFiles() = %v, want %v", first, want)
+	}
+	if got := c.stats(); got.read != 4 || got.reused != 0 {
+		t.Errorf("first walk stats = %+v, want 4 read, 0 reused", got)
+	}
+
+	second := c.findFiles(root, false, isTestSpecFile)
+	if !reflect.DeepEqual(second, want) {
+		t.Errorf("cached findFiles() = %v, want %v", second, want)
+	}
+	if got := c.stats(); got.read != 4 || got.reused != 4 {
+		t.Errorf("second walk stats = %+v, want no new reads and 4 reused", got)
+	}
+}
+
+func TestDiscoveryCacheRewalksChangedDirectories(t *testing.T) {
+	root := writeDiscoveryTree(t)
+	ageDirectories(t, root)
+	c := newDiscoveryCache()
+	c.findFiles(root, false, isTestSpecFile)
+
+	// Adding a service changes the root's mtime; removing a spec changes its directory's mtime
+	added := filepath.Join(root, "accounts-server-sdk", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(added), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(added, []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "team", "holidays-server-sdk", "openapi.yaml")); err != nil {
+		t.Fatalf("Failed to remove spec: %v", err)
+	}
+
+	before := c.stats()
+	got := c.findFiles(root, false, isTestSpecFile)
+	want := []string{added, filepath.Join(root, "funding-server-sdk", "openapi.json")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findFiles() after changes = %v, want %v", got, want)
+	}
+
+	// root, accounts-server-sdk and holidays-server-sdk are read; funding-server-sdk and team are reused
+	after := c.stats()
+	if read, reused := after.read-before.read, after.reused-before.reused; read != 3 || reused != 2 {
+		t.Errorf("walk after changes read %d and reused %d directories, want 3 and 2", read, reused)
+	}
+}
+
+func TestDiscoveryCacheRereadsRecentlyModifiedDirectories(t *testing.T) {
+	// Just-written directories may change again within their mtime resolution
+	root := writeDiscoveryTree(t)
+	c := newDiscoveryCache()
+	c.findFiles(root, false, isTestSpecFile)
+	c.findFiles(root, false, isTestSpecFile)
+
+	if got := c.stats(); got.reused != 0 || got.read != 8 {
+		t.Errorf("stats = %+v, want every directory read twice", got)
+	}
+}
+
+func TestDiscoveryCacheMissingRoot(t *testing.T) {
+	c := newDiscoveryCache()
+	if got := c.findFiles(filepath.Join(t.TempDir(), "missing"), false, isTestSpecFile); got != nil {
+		t.Errorf("findFiles() for missing root = %v, want nil", got)
+	}
+}
@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// Reasons of dry run decisions that are not cache decisions
+const (
+	// ReasonCacheDisabled means every client is regenerated since enable_cache is off
+	ReasonCacheDisabled = "cache_disabled"
+
+	// ReasonSharedFilesChanged means shared_component_files changed, which regenerates every client
+	ReasonSharedFilesChanged = "shared_component_files_changed"
+)
+
+// DryRunSummary is what a run would do with each discovered spec
+type DryRunSummary struct {
+	// TotalSpecs is the number of discovered specs
+	TotalSpecs int `json:"total_specs"`
+
+	// RegenerateSpecs is the number of specs whose client would be regenerated
+	RegenerateSpecs int `json:"regenerate_specs"`
+
+	// Specs holds the decision for each spec, in discovery order
+	Specs []SpecDecision `json:"specs"`
+}
+
+// SpecDecision is whether a run would use the cached client of a spec or regenerate it
+type SpecDecision struct {
+	// Service is the service name derived from the spec's directory
+	Service string `json:"service"`
+
+	// SpecPath is the path of the spec file
+	SpecPath string `json:"spec_path"`
+
+	// Cached reports that the cached client would be used
+	Cached bool `json:"cached"`
+
+	// Reason is the deciding factor: a cache decision reason (e.g. "spec_changed"), or
+	// ReasonCacheDisabled or ReasonSharedFilesChanged
+	Reason string `json:"reason"`
+
+	// Changed lists the fingerprint sections that changed, with reason "spec_changed"
+	Changed []string `json:"changed,omitempty"`
+
+	// Dependency is the $ref'd file that changed, with reason "dependency_changed"
+	Dependency string `json:"dependency,omitempty"`
+}
+
+// Explain describes the decision in a few words, e.g. "operations, schemas changed"
+func (d SpecDecision) Explain() string {
+	switch d.Reason {
+	case cache.ReasonHit:
+		return "spec unchanged"
+	case cache.ReasonHitFingerprint:
+		return "only documentation changed"
+	case cache.ReasonHitArtifact:
+		return "client directory restored from its archive"
+	case cache.ReasonNoEntry:
+		return "no cached client"
+	case cache.ReasonGeneratorChanged:
+		return "generator version changed"
+	case cache.ReasonConfigChanged:
+		return "generation settings changed"
+	case cache.ReasonSpecChanged:
+		if len(d.Changed) > 0 {
+			return strings.Join(d.Changed, ", ") + " changed"
+		}
+		return "spec changed"
+	case cache.ReasonDependencyChanged:
+		return d.Dependency + " changed"
+	case cache.ReasonOutputMissing:
+		return "client directory missing"
+	case cache.ReasonOutputPathChanged:
+		return "client directory moved"
+	case cache.ReasonCheckFailed:
+		return "cache check failed"
+	case ReasonCacheDisabled:
+		return "caching disabled"
+	case ReasonSharedFilesChanged:
+		return "shared component files changed"
+	default:
+		return d.Reason
+	}
+}
+
+// Format renders one line per spec followed by the overall result
+func (s *DryRunSummary) Format() string {
+	var b strings.Builder
+	for _, decision := range s.Specs {
+		if decision.Cached {
+			fmt.Fprintf(&b, "⚡ %s cached (%s)\n", decision.Service, decision.Explain())
+		} else {
+			fmt.Fprintf(&b, "🔄 %s would regenerate: %s\n", decision.Service, decision.Explain())
+		}
+	}
+	fmt.Fprintf(&b, "%d/%d clients would be regenerated\n", s.RegenerateSpecs, s.TotalSpecs)
+	return b.String()
+}
+
+// PlanOpenAPISpecs runs spec discovery, fingerprinting and cache validation like a run, and
+// returns which clients would be regenerated and why, without generating anything. Remote
+// and git specs are fetched. The cache is opened read-only, so a dry run leaves it as it was.
+func PlanOpenAPISpecs(ctx context.Context, cfg config.Config) (*DryRunSummary, error) {
+	spec.SetParseCacheSize(cfg.ParseCacheSize)
+	spec.SetIOConcurrency(cfg.IOConcurrency)
+	spec.SetSpecEncoding(cfg.SpecEncoding)
+
+	network.SetOffline(cfg.Offline)
+	if err := network.SetProxy(cfg.SpecFetchProxy); err != nil {
+		return nil, err
+	}
+	sourceSpecs, err := fetchSpecSources(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks, sourceSpecs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var specCache *cache.Cache
+	var dependencies *dependencyGraph
+	sharedChanged := false
+	if cfg.EnableCache {
+		dependencies = buildDependencyGraph(specs)
+
+		if specCache, err = openRunCache(cfg, true); err != nil {
+			return nil, err
+		}
+		if sharedChanged, err = specCache.InvalidateOnSharedChanges(dependencies.unreferenced(cfg.SharedComponentFiles)); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &DryRunSummary{TotalSpecs: len(specs), Specs: make([]SpecDecision, 0, len(specs))}
+	for _, specPath := range specs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		decision := SpecDecision{Service: serviceName, SpecPath: specPath}
+		switch {
+		case specCache == nil:
+			decision.Reason = ReasonCacheDisabled
+		case sharedChanged:
+			decision.Reason = ReasonSharedFilesChanged
+		default:
+			snapshot := takeSpecSnapshot(specPath, specCache, dependencies)
+			clientPath := clientOutputPath(cfg.OutputDir, cfg.ClientsSubdir, specPath, serviceName+"sdk", cfg.OutputMode)
+			cacheDecision, err := decideCachedClient(specCache, snapshot, clientPath, cfg.TraceCache)
+			if err != nil {
+				log.Printf("Warning: Cache check failed for %s: %v", serviceName, err)
+			}
+			decision.Cached = cacheDecision.Hit
+			decision.Reason = cacheDecision.Reason
+			decision.Changed = cacheDecision.ChangedSections()
+			decision.Dependency = cacheDecision.Dependency
+		}
+
+		if !decision.Cached {
+			summary.RegenerateSpecs++
+		}
+		summary.Specs = append(summary.Specs, decision)
+	}
+	return summary, nil
+}
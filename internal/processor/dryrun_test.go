@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+func TestPlanOpenAPISpecs(t *testing.T) {
+	useRecordingGenerator(t)
+	specsDir, sharedPath := writeSharedSpecs(t)
+	cfg := config.Config{
+		SpecsDir:    specsDir,
+		OutputDir:   filepath.Join(t.TempDir(), "output"),
+		WorkerCount: 1,
+		EnableCache: true,
+		CacheDir:    t.TempDir(),
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	// holidays gains an operation, and the file funding and payments reference changes
+	writeLockTestSpec(t, specsDir, "holidays-server-sdk",
+		`{"openapi":"3.0.0","paths":{"/holidays":{"get":{"operationId":"listHolidays","responses":{"200":{"description":"OK"}}}}}}`)
+	if err := os.WriteFile(sharedPath, []byte("Money:\n  type: string\n"), 0644); err != nil {
+		t.Fatalf("Failed to update shared file: %v", err)
+	}
+	cacheFiles := func() map[string]string {
+		t.Helper()
+		entries, err := os.ReadDir(cfg.CacheDir)
+		if err != nil {
+			t.Fatalf("Failed to read cache dir: %v", err)
+		}
+		files := map[string]string{}
+		for _, entry := range entries {
+			data, _ := os.ReadFile(filepath.Join(cfg.CacheDir, entry.Name()))
+			files[entry.Name()] = string(data)
+		}
+		return files
+	}
+	before := cacheFiles()
+
+	summary, err := PlanOpenAPISpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("PlanOpenAPISpecs() error = %v", err)
+	}
+	if summary.TotalSpecs != 3 || summary.RegenerateSpecs != 3 {
+		t.Errorf("summary = %d/%d regenerated, want 3/3", summary.RegenerateSpecs, summary.TotalSpecs)
+	}
+	decisions := map[string]SpecDecision{}
+	for _, decision := range summary.Specs {
+		decisions[decision.Service] = decision
+	}
+	if d := decisions["holidays"]; d.Cached || d.Reason != "spec_changed" || !reflect.DeepEqual(d.Changed, []string{"operations"}) {
+		t.Errorf("holidays decision = %+v, want regenerated with operations changed", d)
+	}
+	if d := decisions["payments"]; d.Cached || d.Reason != "dependency_changed" || d.Dependency != sharedPath {
+		t.Errorf("payments decision = %+v, want regenerated with %s changed", d, sharedPath)
+	}
+
+	output := summary.Format()
+	for _, want := range []string{
+		"🔄 holidays would regenerate: operations changed",
+		"🔄 funding would regenerate: " + sharedPath + " changed",
+		"3/3 clients would be regenerated",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Format() = %q, want it to contain %q", output, want)
+		}
+	}
+
+	if after := cacheFiles(); !reflect.DeepEqual(after, before) {
+		t.Error("a dry run should leave the cache unchanged")
+	}
+
+	// Once regenerated, every client is cached
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+	if summary, err = PlanOpenAPISpecs(context.Background(), cfg); err != nil || summary.RegenerateSpecs != 0 {
+		t.Errorf("PlanOpenAPISpecs() after regenerating = %+v, %v; want every client cached", summary, err)
+	}
+}
+
+func TestPlanOpenAPISpecsWithoutCache(t *testing.T) {
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk")
+	cfg := config.Config{SpecsDir: specsDir, OutputDir: t.TempDir()}
+
+	summary, err := PlanOpenAPISpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("PlanOpenAPISpecs() error = %v", err)
+	}
+	if summary.RegenerateSpecs != 2 {
+		t.Errorf("RegenerateSpecs = %d, want every spec", summary.RegenerateSpecs)
+	}
+	for _, decision := range summary.Specs {
+		if decision.Cached || decision.Reason != ReasonCacheDisabled {
+			t.Errorf("decision = %+v, want regenerated with caching disabled", decision)
+		}
+	}
+	if entries, _ := os.ReadDir(cfg.OutputDir); len(entries) != 0 {
+		t.Errorf("a dry run wrote %d output entries, want none", len(entries))
+	}
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// transcodeSpec converts a spec that is not UTF-8 into a temporary UTF-8 file, so the
+// generator and all later pipeline steps can read it. encodingName overrides BOM detection.
+// Specs that are already UTF-8 are returned unchanged with a no-op cleanup.
+func transcodeSpec(specPath, encodingName string) (string, func(), error) {
+	noop := func() {}
+
+	data, err := spec.ReadFile(specPath)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	converted, err := spec.ToUTF8(data, encodingName)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to transcode spec %s: %w", specPath, err)
+	}
+	if bytes.Equal(converted, data) {
+		return specPath, noop, nil
+	}
+
+	log.Printf("Transcoded spec %s to UTF-8", specPath)
+	return writeTempSpec("openapi-utf8-", specPath, converted)
+}
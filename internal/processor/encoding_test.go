@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestTranscodeSpec(t *testing.T) {
+	content := `{"openapi":"3.0.0","info":{"title":"Café"}}`
+	tmpDir := t.TempDir()
+
+	utf16Data, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to encode UTF-16: %v", err)
+	}
+	utf16Path := filepath.Join(tmpDir, "utf16.json")
+	if err := os.WriteFile(utf16Path, utf16Data, 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	transcoded, cleanup, err := transcodeSpec(utf16Path, "")
+	if err != nil {
+		t.Fatalf("transcodeSpec() error = %v", err)
+	}
+	if transcoded == utf16Path {
+		t.Fatal("transcodeSpec() should return a new UTF-8 file for UTF-16 input")
+	}
+	if filepath.Ext(transcoded) != ".json" {
+		t.Errorf("transcodeSpec() path %q should keep the .json extension", transcoded)
+	}
+	data, err := os.ReadFile(transcoded)
+	if err != nil {
+		t.Fatalf("Failed to read transcoded spec: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("transcoded content = %q, want %q", data, content)
+	}
+
+	cleanup()
+	if _, err := os.Stat(transcoded); !os.IsNotExist(err) {
+		t.Error("cleanup should remove the transcoded spec")
+	}
+
+	// UTF-8 specs are used as-is
+	utf8Path := filepath.Join(tmpDir, "utf8.json")
+	if err := os.WriteFile(utf8Path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	same, cleanup, err := transcodeSpec(utf8Path, "")
+	if err != nil {
+		t.Fatalf("transcodeSpec() error = %v", err)
+	}
+	defer cleanup()
+	if same != utf8Path {
+		t.Errorf("transcodeSpec() = %q, want original path for UTF-8 spec", same)
+	}
+}
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+)
+
+// ErrorReportFileName is the name of the consolidated error report written to the output directory
+const ErrorReportFileName = "errors.txt"
+
+// writeErrorReport writes the failed specs, grouped by error category with suggestions,
+// to errors.txt in the output directory. Returns the report path, or "" if nothing failed,
+// in which case a report left over from a previous run is removed.
+func writeErrorReport(outputDir string, failures []SpecFailure) (string, error) {
+	reportPath := filepath.Join(outputDir, ErrorReportFileName)
+	if len(failures) == 0 {
+		if err := removeWithRetry(reportPath); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to remove stale error report: %w", err)
+		}
+		return "", nil
+	}
+
+	errs := make([]*generator.GenerationError, 0, len(failures))
+	specPaths := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		errs = append(errs, generator.AsGenerationError(failure.ServiceName, failure.Error))
+		specPaths = append(specPaths, fmt.Sprintf("  - %s (%s)", failure.ServiceName, failure.SpecPath))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "OpenAPI client generation failed for %d spec(s):\n", len(failures))
+	b.WriteString(strings.Join(specPaths, "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(generator.FormatList(errs))
+
+	if err := writeFileWithRetry(reportPath, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write error report: %w", err)
+	}
+
+	return reportPath, nil
+}
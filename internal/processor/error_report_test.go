@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+)
+
+func TestWriteErrorReport(t *testing.T) {
+	outputDir := t.TempDir()
+
+	failures := []SpecFailure{
+		{
+			SpecPath:    "/specs/funding-server-sdk/openapi.json",
+			ServiceName: "funding",
+			Error:       &generator.GenerationError{Code: generator.ErrCodeGeneratorFailed, PackageName: "fundingsdk", ExitCode: 1, Err: errors.New("exit status 1")},
+		},
+		{
+			SpecPath:    "/specs/holidays-server-sdk/openapi.json",
+			ServiceName: "holidays",
+			Error:       &generator.GenerationError{Code: generator.ErrCodeGeneratorNotInstalled, PackageName: "holidayssdk", ExitCode: -1, Err: errors.New("ogen not found")},
+		},
+		{
+			SpecPath:    "/specs/user-sdk/openapi.json",
+			ServiceName: "user",
+			Error:       errors.New("spec validation failed for user"),
+		},
+	}
+
+	reportPath, err := writeErrorReport(outputDir, failures)
+	if err != nil {
+		t.Fatalf("writeErrorReport() error = %v", err)
+	}
+	if reportPath != filepath.Join(outputDir, ErrorReportFileName) {
+		t.Errorf("writeErrorReport() path = %q, want errors.txt in output dir", reportPath)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read error report: %v", err)
+	}
+	report := string(data)
+
+	for _, want := range []string{
+		"failed for 3 spec(s)",
+		"funding (/specs/funding-server-sdk/openapi.json)",
+		"GENERATOR_FAILED (1)",
+		"GENERATOR_NOT_INSTALLED (1)\n  Suggestion: Install ogen",
+		"OTHER (1)\n  Suggestion:",
+		"  - user: generation failed for user [OTHER]: spec validation failed for user",
+	} {
+		if !contains(report, want) {
+			t.Errorf("error report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestWriteErrorReportNoFailures(t *testing.T) {
+	outputDir := t.TempDir()
+	stale := filepath.Join(outputDir, ErrorReportFileName)
+	if err := os.WriteFile(stale, []byte("old failures"), 0644); err != nil {
+		t.Fatalf("Failed to write stale report: %v", err)
+	}
+
+	reportPath, err := writeErrorReport(outputDir, nil)
+	if err != nil || reportPath != "" {
+		t.Fatalf("writeErrorReport() = %q, %v; want no report", reportPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, ErrorReportFileName)); !os.IsNotExist(err) {
+		t.Error("stale errors.txt should be removed when nothing failed")
+	}
+}
@@ -0,0 +1,200 @@
+package processor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+// FacadeFileName is the file written into the facade package
+const FacadeFileName = "api_gen.go"
+
+// facadeClient is a generated client exposed by the facade
+type facadeClient struct {
+	// Field is the API and ServerURLs field name, e.g. "Funding"
+	Field string
+	// Package is the client package name, e.g. "fundingsdk"
+	Package string
+	// ImportPath is the client's Go import path
+	ImportPath string
+}
+
+// writeFacade writes the facade package: a single API struct holding a client per generated
+// service, and a constructor building them from their server URLs. Every spec under specs_dir
+// and every previously fetched remote or git spec is considered, not only the target services,
+// so that a partial run keeps the clients generated by earlier runs in the facade. Services
+// without a generated client are left out.
+// Returns the path of the written file.
+func writeFacade(cfg config.Config) (string, error) {
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, "", cfg.SpecFilePatterns, cfg.FollowSymlinks, previousSpecSources(cfg)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover specs: %w", err)
+	}
+
+	facadeDir := filepath.Join(cfg.OutputDir, cfg.FacadePackagePath)
+	clients, err := collectFacadeClients(cfg, specs)
+	if err != nil {
+		return "", err
+	}
+	if len(clients) == 0 {
+		return "", fmt.Errorf("no generated clients found for the facade")
+	}
+
+	source, err := renderFacade(filepath.Base(facadeDir), clients)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(facadeDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create facade directory: %w", err)
+	}
+	facadePath := filepath.Join(facadeDir, FacadeFileName)
+	mode := cfg.ResolvedGeneratedFileMode()
+	if err := writeFileWithRetry(facadePath, source, mode); err != nil {
+		return "", fmt.Errorf("failed to write facade: %w", err)
+	}
+	// WriteFile keeps the permission of an existing file
+	if err := os.Chmod(facadePath, mode); err != nil {
+		return "", fmt.Errorf("failed to set facade file mode: %w", err)
+	}
+
+	log.Printf("Facade with %d clients written to: %s", len(clients), facadePath)
+	return facadePath, nil
+}
+
+// collectFacadeClients returns the generated clients of specs, sorted by package name
+func collectFacadeClients(cfg config.Config, specs []string) ([]facadeClient, error) {
+	seen := make(map[string]bool)
+	var clients []facadeClient
+	for _, specPath := range specs {
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		folderName := serviceName + "sdk"
+		if seen[folderName] {
+			continue
+		}
+
+		clientPath := clientOutputPath(cfg.OutputDir, cfg.ClientsSubdir, specPath, folderName, cfg.OutputMode)
+		if _, err := os.Stat(filepath.Join(clientPath, postprocessor.InternalClientFileName)); err != nil {
+			continue
+		}
+		importPath, err := goImportPath(clientPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve import path of %s: %w", folderName, err)
+		}
+
+		seen[folderName] = true
+		clients = append(clients, facadeClient{
+			Field:      strings.ToUpper(serviceName[:1]) + serviceName[1:],
+			Package:    folderName,
+			ImportPath: importPath,
+		})
+	}
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Package < clients[j].Package })
+	return clients, nil
+}
+
+// goImportPath returns the import path of a package directory from the module path declared
+// in the nearest go.mod above it
+func goImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for moduleDir := dir; ; {
+		data, err := os.ReadFile(filepath.Join(moduleDir, postprocessor.GoModFileName))
+		if err == nil {
+			modulePath := goModulePath(data)
+			if modulePath == "" {
+				return "", fmt.Errorf("no module directive in %s", filepath.Join(moduleDir, postprocessor.GoModFileName))
+			}
+			rel, err := filepath.Rel(moduleDir, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modulePath, nil
+			}
+			return modulePath + "/" + filepath.ToSlash(rel), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(moduleDir)
+		if parent == moduleDir {
+			return "", fmt.Errorf("%s is not inside a Go module (no go.mod)", dir)
+		}
+		moduleDir = parent
+	}
+}
+
+// goModulePath returns the module path declared in go.mod content, or "" if there is none
+func goModulePath(goMod []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(goMod))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// renderFacade renders the gofmt-formatted facade package source
+func renderFacade(packageName string, clients []facadeClient) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by openapi-go, DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s exposes every generated service client through a single API struct.\n", packageName)
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+
+	b.WriteString("import (\n\t\"fmt\"\n\n")
+	for _, client := range clients {
+		if filepath.Base(client.ImportPath) == client.Package {
+			fmt.Fprintf(&b, "\t%q\n", client.ImportPath)
+		} else {
+			fmt.Fprintf(&b, "\t%s %q\n", client.Package, client.ImportPath)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// API holds a client for each generated service.\n")
+	b.WriteString("type API struct {\n")
+	for _, client := range clients {
+		fmt.Fprintf(&b, "\t%s *%s.Client\n", client.Field, client.Package)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// ServerURLs holds the base URL of each service.\n")
+	b.WriteString("type ServerURLs struct {\n")
+	for _, client := range clients {
+		fmt.Fprintf(&b, "\t%s string\n", client.Field)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// New creates a client for each service from its base URL.\n")
+	b.WriteString("func New(urls ServerURLs) (*API, error) {\n")
+	b.WriteString("\tvar (\n\t\tapi API\n\t\terr error\n\t)\n")
+	for _, client := range clients {
+		fmt.Fprintf(&b, "\tif api.%s, err = %s.NewInternalClient(urls.%s); err != nil {\n", client.Field, client.Package, client.Field)
+		fmt.Fprintf(&b, "\t\treturn nil, fmt.Errorf(\"%s client: %%w\", err)\n", client.Package)
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("\treturn &api, nil\n}\n")
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format facade: %w", err)
+	}
+	return source, nil
+}
@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+func TestGoModulePath(t *testing.T) {
+	tests := map[string]string{
+		"module example.com/acme\n\ngo 1.24\n":        "example.com/acme",
+		"// comment\nmodule \"example.com/quoted\"\n": "example.com/quoted",
+		"go 1.24\n":                      "",
+		"modules example.com/other\n":    "",
+		"\tmodule\texample.com/tabbed\n": "example.com/tabbed",
+	}
+	for goMod, want := range tests {
+		if got := goModulePath([]byte(goMod)); got != want {
+			t.Errorf("goModulePath(%q) = %q, want %q", goMod, got, want)
+		}
+	}
+}
+
+// writeFacadeFixture creates a module with specs for funding, users and orders, where only
+// funding and users have a generated client
+func writeFacadeFixture(t *testing.T) config.Config {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/acme\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	cfg := config.Config{
+		SpecsDir:          filepath.Join(tmpDir, "specs"),
+		OutputDir:         filepath.Join(tmpDir, "output"),
+		ClientsSubdir:     config.DefaultClientsSubdir,
+		OutputMode:        config.OutputModeCentral,
+		SpecFilePatterns:  []string{"openapi.json"},
+		GenerateFacade:    true,
+		FacadePackagePath: "pkg/services",
+	}
+	for _, service := range []string{"funding-server-sdk", "users", "orders"} {
+		svcDir := filepath.Join(cfg.SpecsDir, service)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create service directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+	for _, folderName := range []string{"fundingsdk", "userssdk"} {
+		clientPath := filepath.Join(cfg.OutputDir, "clients", folderName)
+		if err := os.MkdirAll(clientPath, 0755); err != nil {
+			t.Fatalf("Failed to create client directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(clientPath, postprocessor.InternalClientFileName), []byte("package "+folderName+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write internal client: %v", err)
+		}
+	}
+	return cfg
+}
+
+func TestWriteFacade(t *testing.T) {
+	cfg := writeFacadeFixture(t)
+
+	facadePath, err := writeFacade(cfg)
+	if err != nil {
+		t.Fatalf("writeFacade() error = %v", err)
+	}
+	if want := filepath.Join(cfg.OutputDir, "pkg", "services", FacadeFileName); facadePath != want {
+		t.Errorf("facade path = %q, want %q", facadePath, want)
+	}
+
+	data, err := os.ReadFile(facadePath)
+	if err != nil {
+		t.Fatalf("Failed to read facade: %v", err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), facadePath, data, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("facade does not parse: %v\n%s", err, data)
+	}
+	if file.Name.Name != "services" {
+		t.Errorf("package = %q, want services", file.Name.Name)
+	}
+
+	var imports []string
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imports = append(imports, path)
+	}
+	wantImports := []string{"fmt", "example.com/acme/output/clients/fundingsdk", "example.com/acme/output/clients/userssdk"}
+	if strings.Join(imports, ",") != strings.Join(wantImports, ",") {
+		t.Errorf("imports = %v, want %v", imports, wantImports)
+	}
+
+	source := string(data)
+	for _, want := range []string{
+		"Funding *fundingsdk.Client",
+		"Users   *userssdk.Client",
+		"fundingsdk.NewInternalClient(urls.Funding)",
+		"userssdk.NewInternalClient(urls.Users)",
+		"func New(urls ServerURLs) (*API, error)",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("facade missing %q:\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "orders") {
+		t.Errorf("facade should skip services without a generated client:\n%s", source)
+	}
+}
+
+func TestWriteFacadeAlongsideSpec(t *testing.T) {
+	cfg := writeFacadeFixture(t)
+	cfg.OutputMode = config.OutputModeAlongsideSpec
+	clientPath := filepath.Join(cfg.SpecsDir, "orders", "client")
+	if err := os.MkdirAll(clientPath, 0755); err != nil {
+		t.Fatalf("Failed to create client directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(clientPath, postprocessor.InternalClientFileName), []byte("package orderssdk\n"), 0644); err != nil {
+		t.Fatalf("Failed to write internal client: %v", err)
+	}
+
+	facadePath, err := writeFacade(cfg)
+	if err != nil {
+		t.Fatalf("writeFacade() error = %v", err)
+	}
+	data, err := os.ReadFile(facadePath)
+	if err != nil {
+		t.Fatalf("Failed to read facade: %v", err)
+	}
+	// The directory name differs from the package name, so the import is aliased
+	if !strings.Contains(string(data), `orderssdk "example.com/acme/specs/orders/client"`) {
+		t.Errorf("facade missing aliased import of the orders client:\n%s", data)
+	}
+	if strings.Contains(string(data), "fundingsdk") {
+		t.Errorf("facade should only include clients generated alongside specs:\n%s", data)
+	}
+}
+
+func TestWriteFacadeErrors(t *testing.T) {
+	t.Run("no clients", func(t *testing.T) {
+		cfg := writeFacadeFixture(t)
+		cfg.ClientsSubdir = "other"
+		if _, err := writeFacade(cfg); err == nil || !strings.Contains(err.Error(), "no generated clients") {
+			t.Errorf("writeFacade() error = %v, want no generated clients", err)
+		}
+	})
+
+	t.Run("outside a module", func(t *testing.T) {
+		cfg := writeFacadeFixture(t)
+		if err := os.Remove(filepath.Join(filepath.Dir(cfg.SpecsDir), "go.mod")); err != nil {
+			t.Fatalf("Failed to remove go.mod: %v", err)
+		}
+		if _, err := writeFacade(cfg); err == nil || !strings.Contains(err.Error(), "not inside a Go module") {
+			t.Errorf("writeFacade() error = %v, want not inside a Go module", err)
+		}
+	})
+}
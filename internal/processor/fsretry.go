@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"errors"
+	"log"
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileSystem is the set of filesystem operations retried on transient errors.
+// Tests replace fsOps to inject failures.
+type fileSystem interface {
+	Remove(name string) error
+	WriteFile(name string, data []byte, perm os.FileMode) error
+}
+
+// osFileSystem implements fileSystem with the os package
+type osFileSystem struct{}
+
+func (osFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (osFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+var (
+	// fsOps performs the retried filesystem operations
+	fsOps fileSystem = osFileSystem{}
+
+	// fsRetryAttempts is the number of attempts for a filesystem operation failing with a transient error
+	fsRetryAttempts = 1
+
+	// fsRetryBackoff is the delay before the first retry; later retries wait proportionally longer
+	fsRetryBackoff = 100 * time.Millisecond
+)
+
+// setFSRetryAttempts sets how many times transient filesystem errors are attempted (values below 1 disable retries)
+func setFSRetryAttempts(attempts int) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	fsRetryAttempts = attempts
+}
+
+// isTransientFSError reports whether err is a filesystem error that may succeed on retry,
+// such as a busy file on a networked filesystem
+func isTransientFSError(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.ETXTBSY)
+}
+
+// retryFS runs op, retrying transient filesystem errors up to fsRetryAttempts times.
+// Other errors (e.g. permission denied) are returned immediately.
+func retryFS(description string, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !isTransientFSError(err) || attempt >= fsRetryAttempts {
+			return err
+		}
+		log.Printf("Transient filesystem error during %s (attempt %d/%d): %v",
+			description, attempt, fsRetryAttempts, err)
+		time.Sleep(fsRetryBackoff * time.Duration(attempt))
+	}
+}
+
+// removeWithRetry removes a file or empty directory, retrying transient errors
+func removeWithRetry(path string) error {
+	return retryFS("remove of "+path, func() error {
+		return fsOps.Remove(path)
+	})
+}
+
+// writeFileWithRetry writes a file, retrying transient errors
+func writeFileWithRetry(path string, data []byte, perm os.FileMode) error {
+	return retryFS("write of "+path, func() error {
+		return fsOps.WriteFile(path, data, perm)
+	})
+}
@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyFileSystem fails the first failures calls of each operation with err, then delegates to the OS
+type flakyFileSystem struct {
+	osFileSystem
+	err      error
+	failures int
+	removes  int
+	writes   int
+}
+
+func (f *flakyFileSystem) Remove(name string) error {
+	f.removes++
+	if f.removes <= f.failures {
+		return &os.PathError{Op: "remove", Path: name, Err: f.err}
+	}
+	return f.osFileSystem.Remove(name)
+}
+
+func (f *flakyFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	f.writes++
+	if f.writes <= f.failures {
+		return &os.PathError{Op: "open", Path: name, Err: f.err}
+	}
+	return f.osFileSystem.WriteFile(name, data, perm)
+}
+
+// useFlakyFileSystem installs a flaky filesystem and retry settings for the duration of the test
+func useFlakyFileSystem(t *testing.T, err error, failures, attempts int) *flakyFileSystem {
+	t.Helper()
+
+	flaky := &flakyFileSystem{err: err, failures: failures}
+	prevOps, prevAttempts, prevBackoff := fsOps, fsRetryAttempts, fsRetryBackoff
+	fsOps = flaky
+	setFSRetryAttempts(attempts)
+	fsRetryBackoff = time.Millisecond
+	t.Cleanup(func() {
+		fsOps, fsRetryAttempts, fsRetryBackoff = prevOps, prevAttempts, prevBackoff
+	})
+	return flaky
+}
+
+func TestCleanDirectoryRetriesTransientErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "oas_client_gen.go"), []byte("package client"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	flaky := useFlakyFileSystem(t, syscall.EBUSY, 1, 3)
+
+	if err := cleanDirectory(dir); err != nil {
+		t.Fatalf("cleanDirectory() error = %v, want success after retry", err)
+	}
+	if flaky.removes != 2 {
+		t.Errorf("remove attempts = %d, want 2", flaky.removes)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("directory not cleaned: %d entries left", len(entries))
+	}
+}
+
+func TestWriteFileRetriesTransientErrors(t *testing.T) {
+	flaky := useFlakyFileSystem(t, syscall.ETXTBSY, 2, 3)
+
+	reportPath, err := writeErrorReport(t.TempDir(), []SpecFailure{
+		{SpecPath: "/specs/funding/openapi.json", ServiceName: "funding", Error: errors.New("boom")},
+	})
+	if err != nil {
+		t.Fatalf("writeErrorReport() error = %v, want success after retries", err)
+	}
+	if flaky.writes != 3 {
+		t.Errorf("write attempts = %d, want 3", flaky.writes)
+	}
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("report not written: %v", err)
+	}
+}
+
+func TestFSRetryLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		attempts     int
+		wantAttempts int
+	}{
+		{"permission denied fails immediately", syscall.EACCES, 3, 1},
+		{"transient error gives up after attempts", syscall.EBUSY, 3, 3},
+		{"retries disabled", syscall.EBUSY, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := useFlakyFileSystem(t, tt.err, 10, tt.attempts)
+
+			err := writeFileWithRetry(filepath.Join(t.TempDir(), "openapi.lock"), []byte("{}"), 0644)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("writeFileWithRetry() error = %v, want %v", err, tt.err)
+			}
+			if flaky.writes != tt.wantAttempts {
+				t.Errorf("write attempts = %d, want %d", flaky.writes, tt.wantAttempts)
+			}
+		})
+	}
+}
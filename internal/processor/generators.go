@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
+)
+
+// NewGeneratorRegistry registers ogen and the generator plugins of the configuration, with
+// the generator selected by cfg.Generator (ogen if empty) as the default
+func NewGeneratorRegistry(cfg config.Config) (*generator.Registry, error) {
+	registry := generator.NewRegistry()
+	if err := registry.Register(generator.NewOgenGenerator()); err != nil {
+		return nil, err
+	}
+
+	for _, plugin := range cfg.GeneratorPlugins {
+		gen, err := generator.NewPluginGenerator(generator.PluginConfig{
+			Name:       plugin.Name,
+			Command:    plugin.Command,
+			Version:    plugin.Version,
+			ConfigPath: plugin.ConfigPath,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Register(gen); err != nil {
+			return nil, fmt.Errorf("failed to register generator plugin: %w", err)
+		}
+	}
+
+	if cfg.Generator != "" {
+		if err := registry.SetDefault(cfg.Generator); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// ConfigureGenerator selects the generator of the configuration for subsequent runs, retrying
+// retryable failures up to generator_retry_attempts times
+func ConfigureGenerator(cfg config.Config) error {
+	registry, err := NewGeneratorRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	gen, err := registry.GetDefault()
+	if err != nil {
+		return err
+	}
+
+	if gen.Name() != generator.OgenName {
+		log.Printf("Using generator plugin %s (version %s)", gen.Name(), gen.Version())
+	}
+	SetGenerator(generator.NewRetryingGenerator(gen, cfg.GeneratorRetryAttempts, cfg.GeneratorRetryBackoff))
+	return nil
+}
+
+// generatorInputs returns the files besides the spec that generated clients depend on: the
+// configuration file of the selected generator and the post-processing templates
+func generatorInputs(cfg config.Config) []string {
+	inputs := []string{paths.GetTemplatesDir()}
+	for _, plugin := range cfg.GeneratorPlugins {
+		if plugin.Name == cfg.Generator {
+			if plugin.ConfigPath != "" {
+				inputs = append(inputs, plugin.ConfigPath)
+			}
+			return inputs
+		}
+	}
+	return append(inputs, cfg.ResolvedOgenConfigPath())
+}
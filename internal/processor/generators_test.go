@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+func TestNewGeneratorRegistry(t *testing.T) {
+	cfg := config.Config{
+		Generator: "internal-codegen",
+		GeneratorPlugins: []config.GeneratorPlugin{
+			{Name: "internal-codegen", Command: []string{"internal-codegen"}, Version: "1.4.0"},
+			{Name: "docs-gen", Command: []string{"docs-gen", "--markdown"}},
+		},
+	}
+	registry, err := NewGeneratorRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewGeneratorRegistry() error = %v", err)
+	}
+
+	names := registry.List()
+	sort.Strings(names)
+	if strings.Join(names, ",") != "docs-gen,internal-codegen,ogen" {
+		t.Errorf("registered generators = %v", names)
+	}
+	gen, err := registry.GetDefault()
+	if err != nil || gen.Name() != "internal-codegen" || gen.Version() != "1.4.0" {
+		t.Errorf("GetDefault() = %v, %v; want internal-codegen 1.4.0", gen, err)
+	}
+
+	// ogen stays the default without a selection
+	registry, err = NewGeneratorRegistry(config.Config{})
+	if err != nil {
+		t.Fatalf("NewGeneratorRegistry() error = %v", err)
+	}
+	if gen, err := registry.GetDefault(); err != nil || gen.Name() != generator.OgenName {
+		t.Errorf("GetDefault() = %v, %v; want ogen", gen, err)
+	}
+
+	for name, invalid := range map[string]config.Config{
+		"unknown generator": {Generator: "missing"},
+		"reserved name":     {GeneratorPlugins: []config.GeneratorPlugin{{Name: generator.OgenName, Command: []string{"gen"}}}},
+		"no command":        {GeneratorPlugins: []config.GeneratorPlugin{{Name: "gen"}}},
+	} {
+		if _, err := NewGeneratorRegistry(invalid); err == nil {
+			t.Errorf("%s: NewGeneratorRegistry() should fail", name)
+		}
+	}
+}
+
+func TestRunWithGeneratorPlugin(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	// Fake plugin: reads the request from stdin and writes a client into its output_dir
+	script := `request=$(cat)
+output=$(echo "$request" | sed -n 's/.*"output_dir":"\([^"]*\)".*/\1/p')
+package=$(echo "$request" | sed -n 's/.*"package_name":"\([^"]*\)".*/\1/p')
+echo "package $package" > "$output/client_gen.go"`
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk")
+	cfg := config.Config{
+		SpecsDir:  specsDir,
+		OutputDir: t.TempDir(),
+		Generator: "internal-codegen",
+		GeneratorPlugins: []config.GeneratorPlugin{
+			{Name: "internal-codegen", Command: []string{"sh", "-c", script}, Version: "1.4.0"},
+		},
+	}
+	if err := ConfigureGenerator(cfg); err != nil {
+		t.Fatalf("ConfigureGenerator() error = %v", err)
+	}
+	if defaultGenerator.Name() != "internal-codegen" {
+		t.Fatalf("generator = %s, want the plugin", defaultGenerator.Name())
+	}
+
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+	clientFile := filepath.Join(cfg.OutputDir, "fundingsdk", "client_gen.go")
+	if data, err := os.ReadFile(clientFile); err != nil || string(data) != "package fundingsdk\n" {
+		t.Errorf("client_gen.go = %q, %v; want the plugin's output", data, err)
+	}
+}
+
+func TestRunRetriesTransientGeneratorFailure(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	// Fake plugin: fails with a file lock on the first attempt, then writes a client
+	attempts := filepath.Join(t.TempDir(), "attempts")
+	script := `echo attempt >> "` + attempts + `"
+if [ "$(wc -l < "` + attempts + `")" -eq 1 ]; then echo "file is locked" >&2; exit 1; fi
+output=$(sed -n 's/.*"output_dir":"\([^"]*\)".*/\1/p')
+echo "package fundingsdk" > "$output/client_gen.go"`
+	cfg := config.Config{
+		SpecsDir:               writeProgressTestSpecs(t, "funding-server-sdk"),
+		OutputDir:              t.TempDir(),
+		Generator:              "internal-codegen",
+		GeneratorPlugins:       []config.GeneratorPlugin{{Name: "internal-codegen", Command: []string{"sh", "-c", script}, Version: "1.4.0"}},
+		GeneratorRetryAttempts: 2,
+		GeneratorRetryBackoff:  time.Millisecond,
+	}
+	if err := ConfigureGenerator(cfg); err != nil {
+		t.Fatalf("ConfigureGenerator() error = %v", err)
+	}
+
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v, want the transient failure retried", err)
+	}
+	if data, err := os.ReadFile(attempts); err != nil || strings.Count(string(data), "attempt") != 2 {
+		t.Errorf("plugin attempts = %q, %v; want 2", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputDir, "fundingsdk", "client_gen.go")); err != nil {
+		t.Errorf("client_gen.go missing after the retry: %v", err)
+	}
+}
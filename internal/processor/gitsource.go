@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+// gitSpecsSubdir is the directory of git spec checkouts, under the cache directory
+const gitSpecsSubdir = "git-specs"
+
+// commitSHA matches a full commit hash, which never moves once checked out
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// fetchGitSpecs brings the checkouts of the git specs of the target services to their pinned
+// refs and returns the spec paths inside them. Each repository and ref has its own shallow
+// checkout under <cache_dir>/git-specs, kept between runs: a commit that is already checked out
+// is reused without fetching, while branches and tags are fetched again since they move.
+//
+// A failed fetch fails the run. With continue_on_error, or in offline mode, the previous
+// checkout is used instead when there is one.
+func fetchGitSpecs(ctx context.Context, cfg config.Config) ([]string, error) {
+	if len(cfg.GitSpecs) == 0 {
+		return nil, nil
+	}
+	serviceRegex, err := compileServiceRegex(cfg.TargetServices)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []string
+	for _, gitSpec := range cfg.GitSpecs {
+		source, err := config.ParseGitSource(gitSpec.Source)
+		if err != nil {
+			return nil, err
+		}
+		if !serviceRegex.MatchString(source.Service()) {
+			continue
+		}
+
+		checkoutDir := gitCheckoutDir(cfg, source)
+		specPath := filepath.Join(checkoutDir, filepath.FromSlash(source.Path))
+		if err := syncGitCheckout(ctx, checkoutDir, source); err != nil {
+			_, statErr := os.Stat(specPath)
+			var offlineErr *network.OfflineError
+			if statErr != nil || !(cfg.ContinueOnError || errors.As(err, &offlineErr)) {
+				return nil, fmt.Errorf("failed to fetch git spec %s: %w", gitSpec.Source, err)
+			}
+			log.Printf("Warning: failed to fetch git spec %s, using the previous checkout: %v", gitSpec.Source, err)
+		}
+
+		if _, err := os.Stat(specPath); err != nil {
+			return nil, fmt.Errorf("git spec %s: %s not found at %s", gitSpec.Source, source.Path, source.Ref)
+		}
+		specs = append(specs, specPath)
+	}
+	return specs, nil
+}
+
+// gitCheckoutDir returns the checkout directory of a repository and ref:
+// <cache_dir>/git-specs/<hash>/<repository name>. The repository name keeps specs at the
+// repository root named after the repository.
+func gitCheckoutDir(cfg config.Config, source config.GitSource) string {
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(source.Repository+"@"+source.Ref)))[:16]
+	return filepath.Join(cfg.CacheDir, gitSpecsSubdir, key, source.RepositoryName())
+}
+
+// syncGitCheckout checks out the source's ref in dir with a shallow fetch, initializing the
+// checkout on first use
+func syncGitCheckout(ctx context.Context, dir string, source config.GitSource) error {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	initialized := err == nil
+
+	if initialized && commitSHA.MatchString(source.Ref) {
+		if head, err := runGit(ctx, dir, "rev-parse", "HEAD"); err == nil && head == source.Ref {
+			log.Printf("Git spec checkout of %s at %s is up to date", source.Repository, source.Ref)
+			return nil
+		}
+	}
+
+	if !isLocalRepository(source.Repository) {
+		if err := network.Check(fmt.Sprintf("fetching %s", source.Repository),
+			"previous checkouts of git specs are used when available; otherwise disable offline mode"); err != nil {
+			return err
+		}
+	}
+
+	if !initialized {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create checkout directory: %w", err)
+		}
+		if _, err := runGit(ctx, dir, "init", "--quiet"); err != nil {
+			return err
+		}
+		if _, err := runGit(ctx, dir, "remote", "add", "--", "origin", source.Repository); err != nil {
+			return err
+		}
+	}
+
+	// "--" keeps the repository and ref from being parsed as options
+	if _, err := runGit(ctx, dir, "fetch", "--quiet", "--depth", "1", "--no-tags", "--", "origin", source.Ref); err != nil {
+		return err
+	}
+	if _, err := runGit(ctx, dir, "checkout", "--quiet", "--force", "FETCH_HEAD"); err != nil {
+		return err
+	}
+
+	head, _ := runGit(ctx, dir, "rev-parse", "--short", "HEAD")
+	log.Printf("Fetched git spec repository %s at %s (%s)", source.Repository, source.Ref, head)
+	return nil
+}
+
+// isLocalRepository reports whether a repository is on the local filesystem, so fetching it
+// needs no network
+func isLocalRepository(repository string) bool {
+	return strings.HasPrefix(repository, "file://") || filepath.IsAbs(repository)
+}
+
+// runGit runs a git command in dir and returns its trimmed output. Credential prompts are
+// disabled, so a repository needing credentials fails instead of hanging the run.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// checkedOutGitSpecs returns the specs of the existing git spec checkouts, without fetching
+func checkedOutGitSpecs(cfg config.Config) []string {
+	var specs []string
+	for _, gitSpec := range cfg.GitSpecs {
+		source, err := config.ParseGitSource(gitSpec.Source)
+		if err != nil {
+			continue
+		}
+		specPath := filepath.Join(gitCheckoutDir(cfg, source), filepath.FromSlash(source.Path))
+		if _, err := os.Stat(specPath); err == nil {
+			specs = append(specs, specPath)
+		}
+	}
+	return specs
+}
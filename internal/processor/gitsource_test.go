@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+const (
+	gitSpecV1 = `{"openapi":"3.0.0","info":{"title":"Funding","version":"1.0.0"},"paths":{},"components":{"schemas":{"Amount":{"$ref":"../schemas.json#/Amount"}}}}`
+	gitSpecV2 = `{"openapi":"3.0.0","info":{"title":"Funding","version":"2.0.0"},"paths":{}}`
+)
+
+// newGitSpecRepo creates a repository with the funding spec tagged v1 at its first commit and
+// changed on main at its second, and returns its path and the first commit
+func newGitSpecRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := filepath.Join(t.TempDir(), "funding")
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(name, content string) {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatalf("Failed to create repo: %v", err)
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	write("specs/funding-server-sdk/openapi.json", gitSpecV1)
+	write("specs/schemas.json", `{"Amount":{"type":"number"}}`)
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	first := git("rev-parse", "HEAD")
+
+	write("specs/funding-server-sdk/openapi.json", gitSpecV2)
+	git("commit", "--quiet", "-am", "v2")
+	return repo, first
+}
+
+func TestFetchGitSpecs(t *testing.T) {
+	repo, _ := newGitSpecRepo(t)
+	cfg := config.Config{
+		CacheDir: t.TempDir(),
+		GitSpecs: []config.GitSpec{
+			{Source: "file://" + repo + "//specs/funding-server-sdk/openapi.json@v1"},
+		},
+	}
+
+	specs, err := fetchGitSpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("fetchGitSpecs() error = %v", err)
+	}
+	if len(specs) != 1 || filepath.Base(filepath.Dir(specs[0])) != "funding-server-sdk" {
+		t.Fatalf("specs = %v, want the funding spec in its service directory", specs)
+	}
+	if data, _ := os.ReadFile(specs[0]); string(data) != gitSpecV1 {
+		t.Errorf("spec at v1 = %s", data)
+	}
+	// The whole tree is checked out, so relative $refs resolve
+	if _, err := os.Stat(filepath.Join(filepath.Dir(specs[0]), "..", "schemas.json")); err != nil {
+		t.Errorf("referenced file missing from the checkout: %v", err)
+	}
+
+	// Each ref has its own checkout; a branch follows its latest commit
+	cfg.GitSpecs[0].Source = "file://" + repo + "//specs/funding-server-sdk/openapi.json@main"
+	specs, err = fetchGitSpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("fetchGitSpecs() at main error = %v", err)
+	}
+	if data, _ := os.ReadFile(specs[0]); string(data) != gitSpecV2 {
+		t.Errorf("spec at main = %s", data)
+	}
+
+	cfg.TargetServices = "holidays"
+	if specs, err := fetchGitSpecs(context.Background(), cfg); err != nil || len(specs) != 0 {
+		t.Errorf("filtered fetchGitSpecs() = %v, %v; want no specs", specs, err)
+	}
+}
+
+func TestFetchGitSpecsReusesCheckouts(t *testing.T) {
+	repo, first := newGitSpecRepo(t)
+	cfg := config.Config{
+		CacheDir: t.TempDir(),
+		GitSpecs: []config.GitSpec{
+			{Source: "file://" + repo + "//specs/funding-server-sdk/openapi.json@" + first},
+			{Source: "file://" + repo + "//specs/funding-server-sdk/openapi.json@main"},
+		},
+	}
+	// Both entries name the same service, which a run rejects, so each is configured alone
+	pinned, branch := cfg, cfg
+	pinned.GitSpecs = cfg.GitSpecs[:1]
+	branch.GitSpecs = cfg.GitSpecs[1:]
+	for _, c := range []config.Config{pinned, branch} {
+		if _, err := fetchGitSpecs(context.Background(), c); err != nil {
+			t.Fatalf("fetchGitSpecs() error = %v", err)
+		}
+	}
+
+	// Without the repository, a checked out commit is reused, but a branch cannot be updated
+	if err := os.RemoveAll(repo); err != nil {
+		t.Fatalf("Failed to remove repo: %v", err)
+	}
+	specs, err := fetchGitSpecs(context.Background(), pinned)
+	if err != nil || len(specs) != 1 {
+		t.Fatalf("fetchGitSpecs() of a checked out commit = %v, %v", specs, err)
+	}
+	if data, _ := os.ReadFile(specs[0]); string(data) != gitSpecV1 {
+		t.Errorf("spec at %s = %s", first, data)
+	}
+
+	if _, err := fetchGitSpecs(context.Background(), branch); err == nil {
+		t.Error("fetching a branch of a missing repository should fail")
+	}
+	branch.ContinueOnError = true
+	if specs, err := fetchGitSpecs(context.Background(), branch); err != nil || len(specs) != 1 {
+		t.Errorf("fetchGitSpecs() with continue_on_error = %v, %v; want the previous checkout", specs, err)
+	}
+	if got := checkedOutGitSpecs(cfg); len(got) != 2 {
+		t.Errorf("checkedOutGitSpecs() = %v, want both checkouts", got)
+	}
+}
+
+func TestRunWithGitSpec(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	repo, _ := newGitSpecRepo(t)
+
+	cfg := config.Config{
+		SpecsDir:  t.TempDir(),
+		OutputDir: t.TempDir(),
+		CacheDir:  t.TempDir(),
+		GitSpecs: []config.GitSpec{
+			{Source: "file://" + repo + "//specs/funding-server-sdk/openapi.json@v1"},
+		},
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+	// The spec at v1 refs a schema elsewhere in the checkout, which is inlined
+	if !strings.Contains(gen.specContent, `"version": "1.0.0"`) || !strings.Contains(gen.specContent, `"type": "number"`) {
+		t.Errorf("generated spec content = %q, want the bundled spec at v1", gen.specContent)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputDir, "fundingsdk")); err != nil {
+		t.Errorf("funding client directory missing: %v", err)
+	}
+}
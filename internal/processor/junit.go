@@ -0,0 +1,163 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a run of the generator: one test case per spec
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is the outcome of one spec
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the failure or skip reason of a test case, with details as its text
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// add appends a test case to the suite and counts its outcome
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Skipped != nil {
+		s.Skipped++
+	}
+	s.TestCases = append(s.TestCases, testCase)
+}
+
+// generationJUnitSuite reports each spec of a generation run as a test case: failed specs
+// fail, specs served from the cache are skipped, and validation issues are the test output
+func generationJUnitSuite(m metrics.Metrics, issues map[string][]validation.Issue) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      "openapi-go generate",
+		Time:      junitSeconds(m.EndTime.Sub(m.StartTime)),
+		Timestamp: m.StartTime.Format(time.RFC3339),
+	}
+	for _, spec := range m.SpecMetrics {
+		testCase := junitTestCase{
+			Name:      spec.ServiceName,
+			ClassName: spec.SpecPath,
+			Time:      junitSeconds(time.Duration(spec.DurationMs) * time.Millisecond),
+			SystemOut: formatJUnitIssues(issues[spec.ServiceName]),
+		}
+		switch {
+		case !spec.Success:
+			testCase.Failure = &junitMessage{Message: firstLine(spec.Error), Text: spec.Error}
+		case spec.Cached:
+			testCase.Skipped = &junitMessage{Message: "client is up to date in the cache"}
+		}
+		suite.add(testCase)
+	}
+	return suite
+}
+
+// validationJUnitSuite reports each spec of a validation run as a test case: invalid specs
+// fail with their issues, and specs matching validator_skip_patterns are skipped
+func validationJUnitSuite(summary *ValidationSummary, startTime time.Time) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      "openapi-go validate",
+		Time:      junitSeconds(time.Since(startTime)),
+		Timestamp: startTime.Format(time.RFC3339),
+	}
+	for _, result := range summary.Specs {
+		testCase := junitTestCase{
+			Name:      result.Service,
+			ClassName: result.SpecPath,
+			Time:      junitSeconds(time.Duration(result.DurationMs) * time.Millisecond),
+		}
+		issues := formatJUnitIssues(result.Issues)
+		switch {
+		case result.Skipped:
+			testCase.Skipped = &junitMessage{Message: "spec matches validator_skip_patterns"}
+		case !result.Valid:
+			testCase.Failure = &junitMessage{Message: firstLine(result.Error), Text: strings.TrimSpace(result.Error + "\n" + issues)}
+		default:
+			testCase.SystemOut = issues
+		}
+		suite.add(testCase)
+	}
+	return suite
+}
+
+// writeJUnitReport writes a JUnit XML report with a single suite, creating its directory
+func writeJUnitReport(path string, suite junitTestSuite) error {
+	report := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create JUnit report directory: %w", err)
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := writeFileWithRetry(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+// formatJUnitIssues renders validation issues one per line
+func formatJUnitIssues(issues []validation.Issue) string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// junitSeconds renders a duration in seconds, as JUnit time attributes are
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// firstLine returns the first line of a multi-line message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
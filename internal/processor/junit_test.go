@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+// readJUnitReport decodes a JUnit report and returns its suite's test cases by name
+func readJUnitReport(t *testing.T, path string) (junitTestSuites, map[string]junitTestCase) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read JUnit report: %v", err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to decode JUnit report: %v\n%s", err, data)
+	}
+	if len(report.Suites) != 1 {
+		t.Fatalf("report has %d suites, want 1", len(report.Suites))
+	}
+	cases := make(map[string]junitTestCase)
+	for _, testCase := range report.Suites[0].TestCases {
+		cases[testCase.Name] = testCase
+	}
+	return report, cases
+}
+
+// writeJUnitTestSpecs writes two valid specs and one that is not an OpenAPI document
+func writeJUnitTestSpecs(t *testing.T) string {
+	t.Helper()
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk")
+	brokenDir := filepath.Join(specsDir, "broken-server-sdk")
+	if err := os.MkdirAll(brokenDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(brokenDir, "openapi.json"), []byte(`{"name": "not-a-spec"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	return specsDir
+}
+
+func TestGenerationJUnitReport(t *testing.T) {
+	useRecordingGenerator(t)
+	tmpDir := t.TempDir()
+	reportPath := filepath.Join(tmpDir, "reports", "junit.xml")
+	cfg := config.Config{
+		SpecsDir:        writeJUnitTestSpecs(t),
+		OutputDir:       filepath.Join(tmpDir, "output"),
+		WorkerCount:     1,
+		ContinueOnError: true,
+		EnableCache:     true,
+		CacheDir:        filepath.Join(tmpDir, "cache"),
+		JUnitReport:     reportPath,
+	}
+	// The second run serves the valid specs from the cache
+	for range 2 {
+		ProcessOpenAPISpecs(context.Background(), cfg)
+	}
+
+	report, cases := readJUnitReport(t, reportPath)
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 2 {
+		t.Errorf("report = %d tests, %d failures, %d skipped; want 3, 1, 2", report.Tests, report.Failures, report.Skipped)
+	}
+	if broken := cases["broken"]; broken.Failure == nil || !strings.Contains(broken.Failure.Text, "NOT_OPENAPI") {
+		t.Errorf("broken = %+v, want a failure with the error", broken)
+	}
+	if funding := cases["funding"]; funding.Skipped == nil || !strings.HasSuffix(funding.ClassName, filepath.Join("funding-server-sdk", "openapi.json")) || funding.Time == "" {
+		t.Errorf("funding = %+v, want it skipped as served from the cache", funding)
+	}
+}
+
+func TestValidationJUnitReport(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "junit.xml")
+	cfg := config.Config{
+		SpecsDir:              writeJUnitTestSpecs(t),
+		TargetServices:        ".*",
+		ValidatorSkipPatterns: []string{"holidays-*"},
+		JUnitReport:           reportPath,
+	}
+	if _, err := CheckOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("CheckOpenAPISpecs() error = %v", err)
+	}
+
+	report, cases := readJUnitReport(t, reportPath)
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 1 {
+		t.Errorf("report = %d tests, %d failures, %d skipped; want 3, 1, 1", report.Tests, report.Failures, report.Skipped)
+	}
+	if funding := cases["funding"]; funding.Failure != nil || funding.Skipped != nil {
+		t.Errorf("funding = %+v, want it to pass", funding)
+	}
+	if holidays := cases["holidays"]; holidays.Skipped == nil || !strings.Contains(holidays.Skipped.Message, "validator_skip_patterns") {
+		t.Errorf("holidays = %+v, want it skipped", holidays)
+	}
+	if broken := cases["broken"]; broken.Failure == nil || !strings.Contains(broken.Failure.Message, "invalid spec for broken") {
+		t.Errorf("broken = %+v, want a failure with the error", broken)
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// SpecList is the discovered spec inventory, one entry per spec
+type SpecList struct {
+	Specs []ListedSpec `json:"specs"`
+}
+
+// ListedSpec is a discovered spec and the client generated from it
+type ListedSpec struct {
+	// Service is the service name derived from the spec's directory
+	Service string `json:"service"`
+
+	// Package is the client package name, e.g. "fundingsdk"
+	Package string `json:"package"`
+
+	// SpecPath is the path of the spec file
+	SpecPath string `json:"spec_path"`
+
+	// Version is the declared OpenAPI version, or "unknown" if the spec cannot be parsed
+	Version string `json:"version"`
+
+	// Operations is the number of operations of the spec
+	Operations int `json:"operations"`
+
+	// ClientPath is the client directory of the spec
+	ClientPath string `json:"client_path"`
+
+	// Generated reports whether the client directory holds a generated client
+	Generated bool `json:"generated"`
+}
+
+// ListOpenAPISpecs discovers the configured specs and lists them with their clients. Remote and
+// git specs are included as last fetched.
+func ListOpenAPISpecs(cfg config.Config) (*SpecList, error) {
+	spec.SetSpecEncoding(cfg.SpecEncoding)
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks, previousSpecSources(cfg)...)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &SpecList{Specs: make([]ListedSpec, 0, len(specs))}
+	for _, specPath := range specs {
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		listed := ListedSpec{
+			Service:    serviceName,
+			Package:    serviceName + "sdk",
+			SpecPath:   specPath,
+			Version:    "unknown",
+			ClientPath: clientOutputPath(cfg.OutputDir, cfg.ClientsSubdir, specPath, serviceName+"sdk", cfg.OutputMode),
+		}
+		if doc, err := spec.LoadDocument(specPath); err == nil {
+			listed.Version = documentVersion(doc)
+			listed.Operations = spec.CountOperations(doc)
+		}
+		if _, err := os.Stat(filepath.Join(listed.ClientPath, postprocessor.InternalClientFileName)); err == nil {
+			listed.Generated = true
+		}
+		list.Specs = append(list.Specs, listed)
+	}
+	return list, nil
+}
+
+// Format renders one line per spec: service, version, operations, and the spec path
+func (l *SpecList) Format() string {
+	var b strings.Builder
+	for _, listed := range l.Specs {
+		generated := ""
+		if !listed.Generated {
+			generated = " (not generated)"
+		}
+		fmt.Fprintf(&b, "%-20s %-12s %4d operations  %s%s\n", listed.Service, listed.Version, listed.Operations, listed.SpecPath, generated)
+	}
+	fmt.Fprintf(&b, "%d specs\n", len(l.Specs))
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+func TestListOpenAPISpecs(t *testing.T) {
+	specsDir := t.TempDir()
+	writeLockTestSpec(t, specsDir, "funding-server-sdk",
+		`{"openapi":"3.0.3","paths":{"/deposits":{"get":{},"post":{}}}}`)
+	writeLockTestSpec(t, specsDir, "holidays-server-sdk", `{"openapi":"3.0.0","paths":{}}`)
+	writeLockTestSpec(t, specsDir, "broken-server-sdk", `{"openapi":`)
+
+	cfg := config.Config{SpecsDir: specsDir, OutputDir: t.TempDir(), OutputMode: config.OutputModeCentral}
+	fundingClient := filepath.Join(cfg.OutputDir, "fundingsdk")
+	if err := os.MkdirAll(fundingClient, 0755); err != nil {
+		t.Fatalf("Failed to create client dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(fundingClient, postprocessor.InternalClientFileName), []byte("package fundingsdk\n"), 0644); err != nil {
+		t.Fatalf("Failed to write client: %v", err)
+	}
+
+	list, err := ListOpenAPISpecs(cfg)
+	if err != nil {
+		t.Fatalf("ListOpenAPISpecs() error = %v", err)
+	}
+	listed := map[string]ListedSpec{}
+	for _, spec := range list.Specs {
+		listed[spec.Service] = spec
+	}
+	if len(listed) != 3 {
+		t.Fatalf("listed %d specs, want 3", len(listed))
+	}
+
+	if got := listed["funding"]; got.Version != "3.0.3" || got.Operations != 2 || !got.Generated || got.ClientPath != fundingClient {
+		t.Errorf("funding = %+v, want 3.0.3 with 2 operations, generated in %s", got, fundingClient)
+	}
+	if got := listed["holidays"]; got.Package != "holidayssdk" || got.Generated {
+		t.Errorf("holidays = %+v, want package holidayssdk, not generated", got)
+	}
+	if got := listed["broken"]; got.Version != "unknown" {
+		t.Errorf("broken = %+v, want version unknown", got)
+	}
+
+	if output := list.Format(); !strings.Contains(output, "(not generated)") || !strings.HasSuffix(output, "3 specs\n") {
+		t.Errorf("Format() = %q", output)
+	}
+}
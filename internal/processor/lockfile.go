@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+)
+
+// lockFile maps spec paths (relative to the specs directory, slash-separated) to expected sha256 hashes
+type lockFile struct {
+	Specs map[string]string `json:"specs"`
+}
+
+// loadLockFile reads a lockfile. Returns nil without error if no path is set or the file does not exist.
+func loadLockFile(path string) (*lockFile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var lock lockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if lock.Specs == nil {
+		lock.Specs = make(map[string]string)
+	}
+	return &lock, nil
+}
+
+// save writes the lockfile with entries sorted by spec path
+func (l *lockFile) save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	if err := writeFileWithRetry(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// lockKey returns the lockfile key for a spec: its path relative to the specs directory
+func lockKey(specsDir, specPath string) string {
+	rel, err := filepath.Rel(specsDir, specPath)
+	if err != nil {
+		rel = specPath
+	}
+	return filepath.ToSlash(rel)
+}
+
+// verifySpecChecksums checks every spec against the lockfile, if one exists.
+// Specs missing from the lockfile or with a different hash fail the run before generation.
+func verifySpecChecksums(lockPath, specsDir string, specs []string) error {
+	lock, err := loadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return nil
+	}
+
+	var problems []string
+	for _, specPath := range specs {
+		key := lockKey(specsDir, specPath)
+		expected, ok := lock.Specs[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: not listed in lockfile", key))
+			continue
+		}
+
+		actual, err := cache.ComputeFileHash(specPath)
+		if err != nil {
+			return fmt.Errorf("failed to hash spec %s: %w", key, err)
+		}
+		if actual != expected {
+			problems = append(problems, fmt.Sprintf("%s: sha256 %s does not match locked %s", key, actual, expected))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("spec checksum verification against %s failed (run with --update-lock to accept the changes):\n  %s",
+			lockPath, strings.Join(problems, "\n  "))
+	}
+
+	log.Printf("Verified checksums of %d spec(s) against %s", len(specs), lockPath)
+	return nil
+}
+
+// updateLockFile records the current hashes of the given specs in the lockfile.
+// Entries for other specs are kept if their files still exist, so filtered runs don't drop them.
+func updateLockFile(lockPath, specsDir string, specs []string) error {
+	if lockPath == "" {
+		return fmt.Errorf("lock_file must be set to update the lockfile")
+	}
+
+	lock, err := loadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		lock = &lockFile{Specs: make(map[string]string)}
+	}
+
+	for key := range lock.Specs {
+		if _, err := os.Stat(filepath.Join(specsDir, filepath.FromSlash(key))); os.IsNotExist(err) {
+			delete(lock.Specs, key)
+		}
+	}
+
+	for _, specPath := range specs {
+		hash, err := cache.ComputeFileHash(specPath)
+		if err != nil {
+			return fmt.Errorf("failed to hash spec %s: %w", specPath, err)
+		}
+		lock.Specs[lockKey(specsDir, specPath)] = hash
+	}
+
+	if err := lock.save(lockPath); err != nil {
+		return err
+	}
+
+	log.Printf("Updated %s with checksums of %d spec(s)", lockPath, len(specs))
+	return nil
+}
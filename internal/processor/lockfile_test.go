@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+// writeLockTestSpec writes a spec under specsDir/service and returns its path
+func writeLockTestSpec(t *testing.T, specsDir, service, content string) string {
+	t.Helper()
+
+	svcDir := filepath.Join(specsDir, service)
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create service directory: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	return specPath
+}
+
+func TestVerifySpecChecksums(t *testing.T) {
+	specsDir := t.TempDir()
+	lockPath := filepath.Join(t.TempDir(), config.DefaultLockFile)
+	funding := writeLockTestSpec(t, specsDir, "funding", `{"openapi":"3.0.0"}`)
+	holidays := writeLockTestSpec(t, specsDir, "holidays", `{"openapi":"3.0.1"}`)
+	specs := []string{funding, holidays}
+
+	// Without a lockfile nothing is verified
+	if err := verifySpecChecksums(lockPath, specsDir, specs); err != nil {
+		t.Fatalf("verifySpecChecksums() without lockfile error = %v", err)
+	}
+
+	fundingHash, err := cache.ComputeFileHash(funding)
+	if err != nil {
+		t.Fatalf("ComputeFileHash() error = %v", err)
+	}
+	holidaysHash, err := cache.ComputeFileHash(holidays)
+	if err != nil {
+		t.Fatalf("ComputeFileHash() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		locked  map[string]string
+		wantErr string
+	}{
+		{
+			name:   "matching checksums",
+			locked: map[string]string{"funding/openapi.json": fundingHash, "holidays/openapi.json": holidaysHash},
+		},
+		{
+			name:    "mismatching checksum",
+			locked:  map[string]string{"funding/openapi.json": fundingHash, "holidays/openapi.json": fundingHash},
+			wantErr: "holidays/openapi.json: sha256 " + holidaysHash + " does not match locked " + fundingHash,
+		},
+		{
+			name:    "spec missing from lockfile",
+			locked:  map[string]string{"funding/openapi.json": fundingHash},
+			wantErr: "holidays/openapi.json: not listed in lockfile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lock := &lockFile{Specs: tt.locked}
+			if err := lock.save(lockPath); err != nil {
+				t.Fatalf("save() error = %v", err)
+			}
+
+			err := verifySpecChecksums(lockPath, specsDir, specs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verifySpecChecksums() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("verifySpecChecksums() expected error")
+			}
+			if !contains(err.Error(), tt.wantErr) || !contains(err.Error(), "--update-lock") {
+				t.Errorf("verifySpecChecksums() error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateLockFile(t *testing.T) {
+	specsDir := t.TempDir()
+	lockPath := filepath.Join(t.TempDir(), config.DefaultLockFile)
+	funding := writeLockTestSpec(t, specsDir, "funding", `{"openapi":"3.0.0"}`)
+
+	// Entries for deleted specs are dropped; entries for existing specs outside the run are kept
+	holidays := writeLockTestSpec(t, specsDir, "holidays", `{"openapi":"3.0.1"}`)
+	stale := &lockFile{Specs: map[string]string{
+		"funding/openapi.json":  "outdated",
+		"holidays/openapi.json": "kept",
+		"removed/openapi.json":  "gone",
+	}}
+	if err := stale.save(lockPath); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	if err := updateLockFile(lockPath, specsDir, []string{funding}); err != nil {
+		t.Fatalf("updateLockFile() error = %v", err)
+	}
+
+	lock, err := loadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("loadLockFile() error = %v", err)
+	}
+	fundingHash, _ := cache.ComputeFileHash(funding)
+	if lock.Specs["funding/openapi.json"] != fundingHash {
+		t.Errorf("funding hash = %q, want %q", lock.Specs["funding/openapi.json"], fundingHash)
+	}
+	if lock.Specs["holidays/openapi.json"] != "kept" {
+		t.Errorf("holidays entry = %q, want it kept", lock.Specs["holidays/openapi.json"])
+	}
+	if _, ok := lock.Specs["removed/openapi.json"]; ok {
+		t.Error("entry for removed spec should be dropped")
+	}
+
+	// After updating all specs, verification passes
+	if err := updateLockFile(lockPath, specsDir, []string{funding, holidays}); err != nil {
+		t.Fatalf("updateLockFile() error = %v", err)
+	}
+	if err := verifySpecChecksums(lockPath, specsDir, []string{funding, holidays}); err != nil {
+		t.Errorf("verifySpecChecksums() after update error = %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// downgradeOpenAPI31 writes an OpenAPI 3.1 spec converted to 3.0 to a temporary file and
+// returns its path, with a cleanup removing it. Other specs are returned as is. parsed is the
+// spec's already decoded document, if any; it is not modified.
+func downgradeOpenAPI31(specPath, serviceName string, parsed map[string]interface{}) (string, func(), error) {
+	noop := func() {}
+
+	doc := spec.CopyDocument(parsed)
+	if doc == nil {
+		var err error
+		if doc, err = spec.LoadDocument(specPath); err != nil {
+			return "", noop, fmt.Errorf("failed to load spec for %s: %w", serviceName, err)
+		}
+	}
+	if !spec.IsOpenAPI31(doc) {
+		return specPath, noop, nil
+	}
+
+	notes := spec.DowngradeOpenAPI31(doc)
+	log.Printf("Converted %s from OpenAPI 3.1 to %s", serviceName, spec.OpenAPI30Version)
+	for _, note := range notes {
+		log.Printf("Warning: %s: %s", serviceName, note)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to encode spec for %s: %w", serviceName, err)
+	}
+
+	// The converted spec is always JSON, whatever the original format
+	return writeTempSpec("openapi-30-", "openapi.json", data)
+}
@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateClientForSpecDowngradesOpenAPI31(t *testing.T) {
+	spec31 := `openapi: 3.1.0
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: [string, "null"]
+`
+
+	tests := []struct {
+		name      string
+		downgrade bool
+		wantOpen  string
+	}{
+		{name: "converted to 3.0", downgrade: true, wantOpen: `"openapi": "3.0.3"`},
+		{name: "kept as 3.1 by default", downgrade: false, wantOpen: "openapi: 3.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := useRecordingGenerator(t)
+
+			tmpDir := t.TempDir()
+			specPath := filepath.Join(tmpDir, "pets-sdk", "openapi.yaml")
+			if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+				t.Fatalf("Failed to create spec dir: %v", err)
+			}
+			if err := os.WriteFile(specPath, []byte(spec31), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			opts := pipelineOptions{downgradeOpenAPI31: tt.downgrade}
+			if err := generateClientForSpec(ctx, specPath, "pets", "petssdk", filepath.Join(tmpDir, "output"), nil, opts); err != nil {
+				t.Fatalf("generateClientForSpec() error = %v", err)
+			}
+
+			if !contains(gen.specContent, tt.wantOpen) {
+				t.Errorf("generated spec should contain %s:\n%s", tt.wantOpen, gen.specContent)
+			}
+			if tt.downgrade && !contains(gen.specContent, `"nullable": true`) {
+				t.Errorf("type array should become a nullable type:\n%s", gen.specContent)
+			}
+		})
+	}
+}
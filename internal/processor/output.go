@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Output is the result of a read-only command (--stats, --validate, --changelog).
+// Results marshal to JSON with stable snake_case field names for scripts.
+type Output interface {
+	// Format renders the result as human-readable text
+	Format() string
+}
+
+// WriteOutput writes a command result to w as text, or as indented JSON if asJSON is set
+func WriteOutput(w io.Writer, out Output, asJSON bool) error {
+	if !asJSON {
+		_, err := io.WriteString(w, out.Format())
+		return err
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output as JSON: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+)
+
+// checkOutputSize fails with POST_PROCESS_FAILED if the files in the client directory add
+// up to more than maxBytes, removing the partial output so a runaway generation (e.g. a
+// recursive schema explosion) does not stay on disk. Top-level entries that keep reports true
+// for (e.g. the spec copy) are neither counted nor removed. A maxBytes of 0 disables the check.
+func checkOutputSize(clientPath, serviceName string, maxBytes int64, keep func(name string) bool) error {
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	size, err := directorySize(clientPath, keep)
+	if err != nil {
+		return fmt.Errorf("failed to measure generated client for %s: %w", serviceName, err)
+	}
+	if size <= maxBytes {
+		return nil
+	}
+
+	if err := cleanDirectoryExcept(clientPath, keep); err != nil {
+		log.Printf("Warning: Failed to remove oversized client output for %s: %v", serviceName, err)
+	}
+	return &generator.GenerationError{
+		Code:        generator.ErrCodePostProcessFailed,
+		PackageName: serviceName,
+		ExitCode:    -1,
+		Err:         fmt.Errorf("generated client is %d bytes, over max_output_bytes (%d); partial output removed", size, maxBytes),
+	}
+}
+
+// directorySize returns the total size of the regular files under dir, skipping the top-level
+// entries keep reports true for
+func directorySize(dir string, keep func(name string) bool) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if keep != nil && filepath.Dir(path) == filepath.Clean(dir) && keep(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+// sizedGenerator is a fake generator writing a file of a fixed size into the output directory
+type sizedGenerator struct {
+	size int
+}
+
+func (g *sizedGenerator) Name() string                              { return "sized" }
+func (g *sizedGenerator) Version() string                           { return "v0.0.0-test" }
+func (g *sizedGenerator) EnsureInstalled(ctx context.Context) error { return nil }
+func (g *sizedGenerator) IsInstalled() bool                         { return true }
+
+func (g *sizedGenerator) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	return os.WriteFile(filepath.Join(spec.OutputDir, "oas_schemas_gen.go"), make([]byte, g.size), 0644)
+}
+
+// writeSizedFiles creates files of the given sizes, including one in a subdirectory
+func writeSizedFiles(t *testing.T, dir string, sizes ...int) {
+	t.Helper()
+	for i, size := range sizes {
+		path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		if i == len(sizes)-1 {
+			path = filepath.Join(dir, "nested", "file.go")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+}
+
+func TestCheckOutputSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxBytes  int64
+		wantErr   bool
+		wantFiles bool
+	}{
+		{name: "no limit", maxBytes: 0, wantErr: false, wantFiles: true},
+		{name: "under the limit", maxBytes: 1000, wantErr: false, wantFiles: true},
+		{name: "exactly at the limit", maxBytes: 600, wantErr: false, wantFiles: true},
+		{name: "over the limit", maxBytes: 599, wantErr: true, wantFiles: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPath := t.TempDir()
+			writeSizedFiles(t, clientPath, 100, 200, 300)
+
+			err := checkOutputSize(clientPath, "funding", tt.maxBytes, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkOutputSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var genErr *generator.GenerationError
+				if !errors.As(err, &genErr) || genErr.Code != generator.ErrCodePostProcessFailed {
+					t.Errorf("checkOutputSize() error = %v, want %s", err, generator.ErrCodePostProcessFailed)
+				}
+				if !strings.Contains(err.Error(), "600 bytes") {
+					t.Errorf("error should report the client size: %v", err)
+				}
+			}
+
+			entries, readErr := os.ReadDir(clientPath)
+			if readErr != nil {
+				t.Fatalf("Failed to read client directory: %v", readErr)
+			}
+			if (len(entries) > 0) != tt.wantFiles {
+				t.Errorf("client directory has %d entries, want files kept = %v", len(entries), tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestProcessOpenAPISpecsMaxOutputBytes(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(&sizedGenerator{size: 4096})
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := config.Config{
+		SpecsDir:        filepath.Join(tmpDir, "specs"),
+		OutputDir:       filepath.Join(tmpDir, "output"),
+		WorkerCount:     1,
+		ContinueOnError: true,
+		MaxOutputBytes:  1024,
+	}
+
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+	if len(report.Result.FailedSpecs) != 1 {
+		t.Fatalf("FailedSpecs = %v, want the oversized client to fail", report.Result.FailedSpecs)
+	}
+	failure := report.Result.FailedSpecs[0]
+	if code := generator.AsGenerationError(failure.ServiceName, failure.Error).Code; code != generator.ErrCodePostProcessFailed {
+		t.Errorf("failure code = %s, want %s", code, generator.ErrCodePostProcessFailed)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(cfg.OutputDir, "fundingsdk"))
+	if err != nil {
+		t.Fatalf("Failed to read client directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("oversized output should be removed, found %d entries", len(entries))
+	}
+}
@@ -0,0 +1,168 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// writeOutputSpecs writes spec fixtures keyed by path relative to the returned specs directory
+func writeOutputSpecs(t *testing.T, fixtures map[string]string) string {
+	t.Helper()
+	specsDir := t.TempDir()
+	for name, content := range fixtures {
+		specPath := filepath.Join(specsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+			t.Fatalf("Failed to create spec directory: %v", err)
+		}
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+	return specsDir
+}
+
+// decodeJSONOutput writes out as JSON and decodes it, failing if it is not a JSON object
+// with all wantKeys at the top level
+func decodeJSONOutput(t *testing.T, out Output, wantKeys []string) map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, out, true); err != nil {
+		t.Fatalf("WriteOutput() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	for _, key := range wantKeys {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("JSON output is missing key %q:\n%s", key, buf.String())
+		}
+	}
+	return decoded
+}
+
+func TestWriteOutputText(t *testing.T) {
+	stats := &SpecStats{TotalSpecs: 2, OperationsByMethod: map[string]int{}, OpenAPIVersions: map[string]int{}}
+
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, stats, false); err != nil {
+		t.Fatalf("WriteOutput() error = %v", err)
+	}
+	if buf.String() != stats.Format() {
+		t.Errorf("WriteOutput() text = %q, want Format() output", buf.String())
+	}
+}
+
+func TestStatsJSONOutput(t *testing.T) {
+	specsDir := writeOutputSpecs(t, map[string]string{
+		"funding-server-sdk/openapi.json": `{"openapi": "3.0.3", "paths": {"/funds": {"get": {}}}}`,
+	})
+
+	stats, err := CollectSpecStats(config.Config{SpecsDir: specsDir, TargetServices: ".*"})
+	if err != nil {
+		t.Fatalf("CollectSpecStats() error = %v", err)
+	}
+
+	decoded := decodeJSONOutput(t, stats, []string{
+		"total_specs", "total_operations", "operations_by_method",
+		"specs_with_security", "specs_without_security", "openapi_versions",
+	})
+	if decoded["total_operations"] != float64(1) {
+		t.Errorf("total_operations = %v, want 1", decoded["total_operations"])
+	}
+}
+
+func TestValidateJSONOutput(t *testing.T) {
+	specsDir := writeOutputSpecs(t, map[string]string{
+		"broken-server-sdk/openapi.json": `{"name": "not-a-spec"}`,
+		"valid-server-sdk/openapi.json":  `{"openapi": "3.0.3", "paths": {}, "components": {"securitySchemes": {"apiKey": {"type": "apiKey", "in": "header", "name": "X-Key"}}}}`,
+	})
+
+	summary, err := CheckOpenAPISpecs(context.Background(), config.Config{
+		SpecsDir:        specsDir,
+		TargetServices:  ".*",
+		ValidationRules: []string{validation.UnusedSecuritySchemeRuleName},
+	})
+	if err != nil {
+		t.Fatalf("CheckOpenAPISpecs() error = %v", err)
+	}
+	if summary.Err() == nil {
+		t.Error("Err() = nil, want the broken spec reported")
+	}
+
+	decoded := decodeJSONOutput(t, summary, []string{"total_specs", "failed_specs", "specs"})
+	if decoded["total_specs"] != float64(2) || decoded["failed_specs"] != float64(1) {
+		t.Errorf("total_specs = %v, failed_specs = %v, want 2 and 1", decoded["total_specs"], decoded["failed_specs"])
+	}
+
+	specs, _ := decoded["specs"].([]interface{})
+	if len(specs) != 2 {
+		t.Fatalf("specs = %v, want 2 entries", decoded["specs"])
+	}
+	for _, raw := range specs {
+		result, _ := raw.(map[string]interface{})
+		for _, key := range []string{"service", "spec_path", "valid", "issues"} {
+			if _, ok := result[key]; !ok {
+				t.Errorf("spec result is missing key %q: %v", key, result)
+			}
+		}
+		issues, _ := result["issues"].([]interface{})
+		switch result["service"] {
+		case "broken":
+			if result["valid"] != false || result["error"] == "" {
+				t.Errorf("broken spec result = %v, want invalid with an error", result)
+			}
+		case "valid":
+			if result["valid"] != true || len(issues) != 1 {
+				t.Errorf("valid spec result = %v, want valid with one warning", result)
+			}
+		default:
+			t.Errorf("unexpected service %v", result["service"])
+		}
+	}
+}
+
+func TestChangelogJSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	previousPath := filepath.Join(dir, "previous.json")
+	currentPath := filepath.Join(dir, "funding", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(currentPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+	if err := os.WriteFile(previousPath, []byte(`{"openapi": "3.0.3", "paths": {"/funds": {"get": {}, "delete": {}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.WriteFile(currentPath, []byte(`{"openapi": "3.0.3", "paths": {"/funds": {"get": {}, "post": {}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	changelog, err := CompareSpecs("", previousPath, currentPath)
+	if err != nil {
+		t.Fatalf("CompareSpecs() error = %v", err)
+	}
+
+	decoded := decodeJSONOutput(t, changelog, []string{
+		"service", "breaking", "added", "deleted", "modified", "changed_components", "deleted_components",
+	})
+	if decoded["service"] != "funding" {
+		t.Errorf("service = %v, want funding", decoded["service"])
+	}
+	if added, _ := decoded["added"].([]interface{}); len(added) != 1 || added[0] != "POST /funds" {
+		t.Errorf("added = %v, want [POST /funds]", decoded["added"])
+	}
+	if breaking, _ := decoded["breaking"].([]interface{}); len(breaking) != 1 {
+		t.Errorf("breaking = %v, want the removed operation", decoded["breaking"])
+	}
+	// Empty lists are arrays, not null, so scripts can iterate them
+	if modified, ok := decoded["modified"].([]interface{}); !ok || len(modified) != 0 {
+		t.Errorf("modified = %#v, want an empty array", decoded["modified"])
+	}
+}
@@ -2,8 +2,11 @@ package processor
 
 import (
 	"context"
+	"log"
 
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
 )
 
 var (
@@ -26,14 +29,98 @@ func init() {
 // ApplyPostProcessors applies post-processing steps to the generated client code.
 // This uses the configured post-processor chain.
 func ApplyPostProcessors(ctx context.Context, clientPath, serviceName, specPath string) error {
-	spec := postprocessor.ProcessSpec{
+	return defaultPostProcessorChain.Process(ctx, newProcessSpec(clientPath, serviceName, specPath, nil))
+}
+
+// newProcessSpec describes a generated client for post-processors. Security is detected here,
+// once per client, so processors can branch on it without parsing the spec again. doc is the
+// decoded spec if the pipeline already parsed it, or nil.
+func newProcessSpec(clientPath, serviceName, specPath string, doc map[string]interface{}) postprocessor.ProcessSpec {
+	hasSecurity := false
+	if doc != nil {
+		hasSecurity = spec.DocumentHasSecurity(doc)
+	} else {
+		hasSecurity = postprocessor.DetectSecurity(specPath, clientPath)
+	}
+
+	return postprocessor.ProcessSpec{
 		ClientPath:  clientPath,
 		ServiceName: serviceName,
 		SpecPath:    specPath,
 		PackageName: serviceName,
+		HasSecurity: hasSecurity,
+		Document:    doc,
+	}
+}
+
+// postProcessClient runs the default and config-driven post-processors for a generated client,
+// waiting for a post-processing slot first if concurrency is limited
+func postProcessClient(ctx context.Context, opts pipelineOptions, clientPath, serviceName, specPath string, doc map[string]interface{}) error {
+	if opts.postProcessSlots != nil {
+		select {
+		case opts.postProcessSlots <- struct{}{}:
+			defer func() { <-opts.postProcessSlots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
-	return defaultPostProcessorChain.Process(ctx, spec)
+	log.Printf("Applying post-processors for %s...", serviceName)
+	processSpec := newProcessSpec(clientPath, serviceName, specPath, doc)
+	processSpec.FileMode = opts.generatedFileMode
+	if err := defaultPostProcessorChain.Process(ctx, processSpec); err != nil {
+		return err
+	}
+	return applyConfiguredPostProcessors(ctx, opts.postProcessors, processSpec)
+}
+
+// configuredPostProcessors builds the chain of optional post-processors enabled in configuration.
+// Returns nil if none are enabled.
+func configuredPostProcessors(cfg *config.Config) *postprocessor.Chain {
+	chain := postprocessor.NewChain()
+
+	// Drop unwanted generated files before anything else inspects the client
+	if len(cfg.PruneFiles) > 0 {
+		chain.Add(postprocessor.NewPruneFilesProcessor(cfg.PruneFiles))
+	}
+
+	if cfg.GenerateErrorTypes {
+		chain.Add(postprocessor.NewErrorTypesProcessor())
+	}
+
+	if cfg.GenerateFixtures {
+		chain.Add(postprocessor.NewFixturesProcessor())
+	}
+
+	if cfg.GenerateClientReadme {
+		chain.Add(postprocessor.NewClientReadmeProcessor())
+	}
+
+	// Each client becomes its own module before it is compiled
+	if cfg.OutputMode == config.OutputModeModulePerService {
+		chain.Add(postprocessor.NewGoModProcessor(cfg.ModulePathPrefix, cfg.ModuleGoVersion))
+		if cfg.ModuleTidy {
+			chain.Add(postprocessor.NewGoModTidyProcessor())
+		}
+	}
+
+	// Compile check runs last so it sees the final generated code
+	if cfg.CompileCheck {
+		chain.Add(postprocessor.NewCompileCheckProcessor(cfg.MinGoVersion))
+	}
+
+	if chain.Count() == 0 {
+		return nil
+	}
+	return chain
+}
+
+// applyConfiguredPostProcessors runs the config-driven post-processors, if any
+func applyConfiguredPostProcessors(ctx context.Context, chain *postprocessor.Chain, spec postprocessor.ProcessSpec) error {
+	if chain == nil {
+		return nil
+	}
+	return chain.Process(ctx, spec)
 }
 
 // SetPostProcessorChain allows overriding the default post-processor chain
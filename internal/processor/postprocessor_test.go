@@ -2,11 +2,16 @@ package processor
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
 )
 
@@ -187,3 +192,310 @@ func TestApplyPostProcessorsWithCustomChain(t *testing.T) {
 		t.Error("Expected internal client file was not created")
 	}
 }
+
+// securityRecorder is a post-processor that records the HasSecurity field it was given
+type securityRecorder struct {
+	hasSecurity []bool
+}
+
+func (p *securityRecorder) Name() string { return "SecurityRecorder" }
+
+func (p *securityRecorder) Process(ctx context.Context, spec postprocessor.ProcessSpec) error {
+	p.hasSecurity = append(p.hasSecurity, spec.HasSecurity)
+	return nil
+}
+
+func TestApplyPostProcessorsHasSecurity(t *testing.T) {
+	originalChain := GetPostProcessorChain()
+	defer SetPostProcessorChain(originalChain)
+
+	tests := []struct {
+		name string
+		spec string
+		want bool
+	}{
+		{
+			name: "secured spec",
+			spec: `{"openapi": "3.0.0", "components": {"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}}}}`,
+			want: true,
+		},
+		{
+			name: "unsecured spec",
+			spec: `{"openapi": "3.0.0", "paths": {}}`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			specPath := filepath.Join(tmpDir, "spec.json")
+			if err := os.WriteFile(specPath, []byte(tt.spec), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			recorder := &securityRecorder{}
+			chain := postprocessor.NewChain()
+			chain.Add(recorder)
+			SetPostProcessorChain(chain)
+
+			if err := ApplyPostProcessors(context.Background(), tmpDir, "testservice", specPath); err != nil {
+				t.Fatalf("ApplyPostProcessors() error = %v", err)
+			}
+			if len(recorder.hasSecurity) != 1 || recorder.hasSecurity[0] != tt.want {
+				t.Errorf("HasSecurity = %v, want [%v]", recorder.hasSecurity, tt.want)
+			}
+		})
+	}
+}
+
+// noopGenerator is a fake generator that succeeds without generating anything
+type noopGenerator struct{}
+
+func (g *noopGenerator) Name() string                              { return "noop" }
+func (g *noopGenerator) Version() string                           { return "v0.0.0-test" }
+func (g *noopGenerator) EnsureInstalled(ctx context.Context) error { return nil }
+func (g *noopGenerator) IsInstalled() bool                         { return true }
+
+func (g *noopGenerator) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	return nil
+}
+
+// concurrencyProcessor is a post-processor that records the maximum number of concurrent runs
+type concurrencyProcessor struct {
+	active    int32
+	maxActive int32
+	calls     int32
+}
+
+func (p *concurrencyProcessor) Name() string { return "ConcurrencyProbe" }
+
+func (p *concurrencyProcessor) Process(ctx context.Context, spec postprocessor.ProcessSpec) error {
+	atomic.AddInt32(&p.calls, 1)
+	active := atomic.AddInt32(&p.active, 1)
+	defer atomic.AddInt32(&p.active, -1)
+
+	for {
+		maxActive := atomic.LoadInt32(&p.maxActive)
+		if active <= maxActive || atomic.CompareAndSwapInt32(&p.maxActive, maxActive, active) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func TestPostProcessConcurrencyCap(t *testing.T) {
+	tests := []struct {
+		name       string
+		cap        int
+		wantAtMost int32
+	}{
+		{name: "cap of one serializes post-processing", cap: 1, wantAtMost: 1},
+		{name: "cap of two", cap: 2, wantAtMost: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := &concurrencyProcessor{}
+			chain := postprocessor.NewChain()
+			chain.Add(probe)
+
+			previousGenerator := defaultGenerator
+			previousChain := defaultPostProcessorChain
+			SetGenerator(&noopGenerator{})
+			SetPostProcessorChain(chain)
+			t.Cleanup(func() {
+				defaultGenerator = previousGenerator
+				defaultPostProcessorChain = previousChain
+			})
+
+			tmpDir := t.TempDir()
+			var specs []string
+			for i := 0; i < 8; i++ {
+				svcDir := filepath.Join(tmpDir, fmt.Sprintf("service-%d", i))
+				if err := os.MkdirAll(svcDir, 0755); err != nil {
+					t.Fatalf("Failed to create service directory: %v", err)
+				}
+				specPath := filepath.Join(svcDir, "openapi.json")
+				if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+					t.Fatalf("Failed to write spec: %v", err)
+				}
+				specs = append(specs, specPath)
+			}
+
+			opts := pipelineOptions{postProcessSlots: make(chan struct{}, tt.cap)}
+			result, err := generateClients(context.Background(), specs, filepath.Join(tmpDir, "output"), false, 8, nil, metrics.NewCollector(), opts)
+			if err != nil {
+				t.Fatalf("generateClients() error = %v", err)
+			}
+			if result.SuccessCount != len(specs) {
+				t.Errorf("SuccessCount = %d, want %d", result.SuccessCount, len(specs))
+			}
+
+			if calls := atomic.LoadInt32(&probe.calls); calls != int32(len(specs)) {
+				t.Errorf("post-processor called %d times, want %d", calls, len(specs))
+			}
+			if maxActive := atomic.LoadInt32(&probe.maxActive); maxActive > tt.wantAtMost {
+				t.Errorf("max concurrent post-processing = %d, want at most %d", maxActive, tt.wantAtMost)
+			}
+		})
+	}
+}
+
+func TestModulePerServiceWritesGoMod(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(&noopGenerator{})
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	var specs []string
+	for _, service := range []string{"funding-server-sdk", "holidays-server-sdk"} {
+		svcDir := filepath.Join(tmpDir, "specs", service)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create service directory: %v", err)
+		}
+		specPath := filepath.Join(svcDir, "openapi.json")
+		if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		specs = append(specs, specPath)
+	}
+
+	cfg := &config.Config{
+		OutputMode:       config.OutputModeModulePerService,
+		ModulePathPrefix: "github.com/acme/sdks",
+		ModuleGoVersion:  "1.22",
+	}
+	opts := pipelineOptions{outputMode: cfg.OutputMode, clientsSubdir: config.DefaultClientsSubdir, postProcessors: configuredPostProcessors(cfg)}
+	outputDir := filepath.Join(tmpDir, "output")
+	if _, err := generateClientsSequential(context.Background(), specs, outputDir, false, nil, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+
+	for _, folder := range []string{"fundingsdk", "holidayssdk"} {
+		data, err := os.ReadFile(filepath.Join(outputDir, "clients", folder, "go.mod"))
+		if err != nil {
+			t.Fatalf("expected go.mod for %s: %v", folder, err)
+		}
+		want := "module github.com/acme/sdks/" + folder + "\n\ngo 1.22\n"
+		if string(data) != want {
+			t.Errorf("%s go.mod = %q, want %q", folder, string(data), want)
+		}
+	}
+}
+
+func TestGenerateFixturesWritesFixtures(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(&noopGenerator{})
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create service directory: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.json")
+	content := `{"openapi":"3.0.0","paths":{"/withdrawals":{"post":{"operationId":"createWithdrawal",` +
+		`"requestBody":{"content":{"application/json":{"example":{"amount":100}}}},` +
+		`"responses":{"201":{"description":"Created"}}}}}}`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := &config.Config{GenerateFixtures: true}
+	opts := pipelineOptions{clientsSubdir: config.DefaultClientsSubdir, postProcessors: configuredPostProcessors(cfg)}
+	outputDir := filepath.Join(tmpDir, "output")
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, nil, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "clients", "fundingsdk", postprocessor.FixturesFileName))
+	if err != nil {
+		t.Fatalf("expected %s: %v", postprocessor.FixturesFileName, err)
+	}
+	if !contains(string(data), "var CreateWithdrawalFixtures = SpecOperationFixtures{") {
+		t.Errorf("fixtures missing createWithdrawal:\n%s", data)
+	}
+}
+
+func TestGenerateClientReadmeWritesReadme(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(&noopGenerator{})
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create service directory: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.json")
+	content := `{"openapi":"3.0.0","info":{"title":"Funding","version":"1.4.0"},` +
+		`"paths":{"/withdrawals":{"post":{"operationId":"createWithdrawal","tags":["withdrawals"],` +
+		`"responses":{"201":{"description":"Created"}}}}}}`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := &config.Config{GenerateClientReadme: true}
+	opts := pipelineOptions{clientsSubdir: config.DefaultClientsSubdir, postProcessors: configuredPostProcessors(cfg)}
+	outputDir := filepath.Join(tmpDir, "output")
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, nil, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "clients", "fundingsdk", postprocessor.ClientReadmeFileName))
+	if err != nil {
+		t.Fatalf("expected %s: %v", postprocessor.ClientReadmeFileName, err)
+	}
+	if !contains(string(data), "# Funding") || !contains(string(data), "`1.4.0`") || !contains(string(data), "`createWithdrawal`") {
+		t.Errorf("README missing title, version or operation:\n%s", data)
+	}
+}
+
+func TestGeneratedFileModeReachesPostProcessors(t *testing.T) {
+	previousGenerator := defaultGenerator
+	SetGenerator(&noopGenerator{})
+	t.Cleanup(func() { defaultGenerator = previousGenerator })
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create service directory: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := &config.Config{GeneratedFileMode: "0600"}
+	opts := pipelineOptions{clientsSubdir: config.DefaultClientsSubdir, generatedFileMode: cfg.ResolvedGeneratedFileMode()}
+	outputDir := filepath.Join(tmpDir, "output")
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, nil, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(outputDir, "clients", "fundingsdk", postprocessor.InternalClientFileName))
+	if err != nil {
+		t.Fatalf("expected %s: %v", postprocessor.InternalClientFileName, err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("%s mode = %v, want -rw-------", postprocessor.InternalClientFileName, info.Mode().Perm())
+	}
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// preprocessSpec runs the user-supplied preprocess command for a spec and returns
+// the path to a temporary file containing the command's stdout.
+// The spec path is appended as the last argument to the command.
+// The returned cleanup function removes the temporary file and is always safe to call.
+func preprocessSpec(ctx context.Context, command []string, specPath string) (string, func(), error) {
+	noop := func() {}
+	if len(command) == 0 {
+		return specPath, noop, nil
+	}
+
+	args := append(append([]string{}, command[1:]...), specPath)
+	cmd := exec.CommandContext(ctx, command[0], args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	log.Printf("Preprocessing spec %s with %q...", specPath, strings.Join(command, " "))
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", noop, fmt.Errorf("spec preprocessing cancelled: %w", ctx.Err())
+		}
+		return "", noop, fmt.Errorf("spec preprocess command failed for %s: %w\nStderr: %s",
+			specPath, err, stderr.String())
+	}
+
+	if stdout.Len() == 0 {
+		return "", noop, fmt.Errorf("spec preprocess command produced no output for %s", specPath)
+	}
+
+	return writeTempSpec("openapi-preprocessed-", specPath, stdout.Bytes())
+}
+
+// writeTempSpec writes spec content derived from specPath to a temporary file
+// and returns its path with a cleanup function that removes it.
+func writeTempSpec(prefix, specPath string, data []byte) (string, func(), error) {
+	noop := func() {}
+
+	// Keep the original extension so downstream tools can detect the format
+	tmpFile, err := os.CreateTemp("", prefix+"*"+filepath.Ext(specPath))
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temporary spec file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	// Pipeline copies are UTF-8, so spec_encoding must not be applied to them again
+	unmark := spec.MarkUTF8(tmpPath)
+	cleanup := func() {
+		unmark()
+		os.Remove(tmpPath)
+	}
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to write temporary spec file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to close temporary spec file: %w", err)
+	}
+
+	return tmpPath, cleanup, nil
+}
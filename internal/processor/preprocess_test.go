@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+// recordingGenerator is a fake generator that records the spec it was asked to generate
+type recordingGenerator struct {
+	specPath    string
+	specContent string
+	configPath  string
+	err         error
+
+	// files are written to the output directory, as the generated client
+	files map[string]string
+}
+
+func (g *recordingGenerator) Name() string                              { return "recording" }
+func (g *recordingGenerator) Version() string                           { return "v0.0.0-test" }
+func (g *recordingGenerator) EnsureInstalled(ctx context.Context) error { return nil }
+func (g *recordingGenerator) IsInstalled() bool                         { return true }
+
+func (g *recordingGenerator) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	g.specPath = spec.SpecPath
+	g.configPath = spec.ConfigPath
+	data, err := os.ReadFile(spec.SpecPath)
+	if err != nil {
+		return err
+	}
+	g.specContent = string(data)
+	for name, content := range g.files {
+		if err := os.MkdirAll(spec.OutputDir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(spec.OutputDir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return g.err
+}
+
+// useRecordingGenerator swaps in a recording generator and an empty post-processor chain
+func useRecordingGenerator(t *testing.T) *recordingGenerator {
+	t.Helper()
+
+	gen := &recordingGenerator{}
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(gen)
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	return gen
+}
+
+func TestPreprocessSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "openapi.json")
+	content := `{"openapi":"3.0.0"}`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("no command returns original spec", func(t *testing.T) {
+		path, cleanup, err := preprocessSpec(ctx, nil, specPath)
+		defer cleanup()
+		if err != nil {
+			t.Fatalf("preprocessSpec() error = %v", err)
+		}
+		if path != specPath {
+			t.Errorf("preprocessSpec() path = %q, want %q", path, specPath)
+		}
+	})
+
+	t.Run("cat writes output to temp file", func(t *testing.T) {
+		path, cleanup, err := preprocessSpec(ctx, []string{"cat"}, specPath)
+		if err != nil {
+			t.Fatalf("preprocessSpec() error = %v", err)
+		}
+		if path == specPath {
+			t.Fatal("preprocessSpec() should return a new file path")
+		}
+		if filepath.Ext(path) != ".json" {
+			t.Errorf("preprocessSpec() path extension = %q, want .json", filepath.Ext(path))
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read preprocessed spec: %v", err)
+		}
+		if string(data) != content {
+			t.Errorf("preprocessed content = %q, want %q", string(data), content)
+		}
+
+		cleanup()
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("cleanup() should remove the preprocessed spec file")
+		}
+	})
+
+	t.Run("failing command includes stderr", func(t *testing.T) {
+		_, cleanup, err := preprocessSpec(ctx, []string{"sh", "-c", "echo broken spec >&2; exit 1"}, specPath)
+		defer cleanup()
+		if err == nil {
+			t.Fatal("preprocessSpec() expected error for failing command")
+		}
+		if !contains(err.Error(), "broken spec") {
+			t.Errorf("preprocessSpec() error = %q, should contain stderr", err.Error())
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		cancelledCtx, cancelFn := context.WithCancel(context.Background())
+		cancelFn()
+
+		_, cleanup, err := preprocessSpec(cancelledCtx, []string{"sleep", "5"}, specPath)
+		defer cleanup()
+		if err == nil {
+			t.Fatal("preprocessSpec() expected error for cancelled context")
+		}
+	})
+}
+
+func TestGenerateClientForSpecUsesPreprocessedSpec(t *testing.T) {
+	gen := useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "funding-server-sdk", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0","info":{"title":"Original"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := pipelineOptions{
+		preprocessCommand: []string{"sed", "s/Original/Transformed/"},
+	}
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := generateClientForSpec(ctx, specPath, "funding", "fundingsdk", outputDir, nil, opts); err != nil {
+		t.Fatalf("generateClientForSpec() error = %v", err)
+	}
+
+	if gen.specPath == specPath {
+		t.Error("generator should receive the preprocessed spec, not the original")
+	}
+	if !contains(gen.specContent, "Transformed") {
+		t.Errorf("generator spec content = %q, want transformed output", gen.specContent)
+	}
+	if _, err := os.Stat(gen.specPath); !os.IsNotExist(err) {
+		t.Error("preprocessed spec should be removed after generation")
+	}
+}
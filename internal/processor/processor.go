@@ -2,10 +2,12 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,7 +15,12 @@ import (
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/tracing"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/worker"
 )
 
@@ -30,6 +37,88 @@ type ProcessingResult struct {
 	FailedSpecs  []SpecFailure
 }
 
+// pipelineOptions holds per-spec pipeline settings derived from configuration
+type pipelineOptions struct {
+	// preprocessCommand is run against each spec before generation (optional)
+	preprocessCommand []string
+
+	// validator runs the configured validation rules before generation (optional)
+	validator *validation.Validator
+
+	// traceCache logs the inputs and outcome of each cache decision
+	traceCache bool
+
+	// skipValidation reports specs exempt from validation by validator_skip_patterns (optional)
+	skipValidation func(specPath string) bool
+
+	// validationResults records validation issues for the run report (optional)
+	validationResults *validationRecorder
+
+	// failOnWarnings makes validation warnings fail the spec like errors
+	failOnWarnings bool
+
+	// postProcessors are config-driven post-processors run after the default chain (optional)
+	postProcessors *postprocessor.Chain
+
+	// failOnOperationDrop fails a spec that lost more than this fraction of its operations
+	// since its client was last generated (0 disables the check)
+	failOnOperationDrop float64
+
+	// generatedFileMode is the permission of files written by post-processors (0 uses the default)
+	generatedFileMode os.FileMode
+
+	// postProcessSlots limits concurrent post-processing across services (nil means no limit)
+	postProcessSlots chan struct{}
+
+	// specEncoding overrides byte order mark detection when transcoding specs to UTF-8 (optional)
+	specEncoding string
+
+	// excludeDeprecated removes operations marked deprecated before generation
+	excludeDeprecated bool
+
+	// convertSwagger2 converts Swagger 2.0 specs to OpenAPI 3.0 before generation
+	convertSwagger2 bool
+
+	// downgradeOpenAPI31 converts OpenAPI 3.1 specs to 3.0 before generation
+	downgradeOpenAPI31 bool
+
+	// progress receives progress events for embedding (nil discards them)
+	progress *progressReporter
+
+	// outputMode selects where clients are written (config.OutputModeCentral or config.OutputModeAlongsideSpec)
+	outputMode string
+
+	// clientsSubdir is the directory under the output directory holding the clients ("" for none)
+	clientsSubdir string
+
+	// subprocessGracePeriod is how long the generator subprocess gets to exit after SIGTERM on cancellation
+	subprocessGracePeriod time.Duration
+
+	// ogenConfigPath is the generator configuration file (empty uses the repository default)
+	ogenConfigPath string
+
+	// bundleSpecFile is the file name in the client directory for the bundled spec ("" disables it)
+	bundleSpecFile string
+
+	// copySpecToOutput copies the source spec into the client directory as openapi.json or openapi.yaml
+	copySpecToOutput bool
+
+	// maxOutputBytes fails services whose generated client is larger than this (0 disables the check)
+	maxOutputBytes int64
+
+	// failureBreaker aborts the batch after repeated generator failures with the same cause (optional)
+	failureBreaker *failureBreaker
+
+	// surfaceChanges records how each bundled spec changed since the previous run (optional)
+	surfaceChanges *surfaceRecorder
+
+	// tracer records spans per spec and stage (nil disables tracing)
+	tracer *tracing.Tracer
+
+	// dependencies links specs to the shared files they $ref, for cache entries (nil without caching)
+	dependencies *dependencyGraph
+}
+
 // SpecFailure represents a failed spec generation
 type SpecFailure struct {
 	SpecPath    string
@@ -44,10 +133,18 @@ type SpecFailure struct {
 // Parameters:
 // - ctx: Context for cancellation and timeouts
 // - cfg: Configuration containing specs directory, output directory, and target services pattern
-// - optionalLogger: Optional structured logger (if not provided, uses standard log package)
+// - optionalLogger: Optional structured logger (if not provided, uses standard log package) and/or Options
 //
 // Returns an error if the process fails at any stage.
 func ProcessOpenAPISpecs(ctx context.Context, cfg config.Config, optionalLogger ...interface{}) error {
+	_, err := ProcessOpenAPISpecsWithResult(ctx, cfg, optionalLogger...)
+	return err
+}
+
+// ProcessOpenAPISpecsWithResult behaves like ProcessOpenAPISpecs but also returns a RunReport
+// with the validation and generation results, for embedding the generator as a library.
+// The report is always non-nil and reflects whatever was completed, even when an error is returned.
+func ProcessOpenAPISpecsWithResult(ctx context.Context, cfg config.Config, optionalLogger ...interface{}) (report *RunReport, err error) {
 	// Extract logger if provided (for future migration to structured logging)
 	// For now, we still use log.Printf in most places, but this allows gradual migration
 	var _ interface{} = nil
@@ -56,46 +153,184 @@ func ProcessOpenAPISpecs(ctx context.Context, cfg config.Config, optionalLogger
 		// Future: Use structured logger throughout
 	}
 
-	// Initialize metrics collector
-	metricsCollector := metrics.NewCollector()
+	report = &RunReport{}
+
+	// Registered first so run completion is reported after everything else
+	progress := newProgressReporter(optionsFrom(optionalLogger).ProgressCallback)
+	defer func() {
+		progress.emit(ProgressEvent{Type: ProgressRunComplete, TotalSpecs: len(report.Specs), Error: err})
+	}()
+
+	// Offline mode applies to every network operation in the run
+	network.SetOffline(cfg.Offline)
+	if err := network.SetProxy(cfg.SpecFetchProxy); err != nil {
+		return report, err
+	}
+	setFSRetryAttempts(cfg.FSRetryAttempts)
+
+	// Trace the run when otel_endpoint (or an exporter in Options) is set; the spans are
+	// exported once everything else has finished
+	tracer := newRunTracer(cfg, optionsFrom(optionalLogger).TraceExporter)
+	ctx, runSpan := tracer.Start(ctx, spanRun, nil)
 	defer func() {
+		runSpan.End(err)
+		flushTrace(tracer)
+	}()
+
+	spec.SetParseCacheSize(cfg.ParseCacheSize)
+	spec.SetIOConcurrency(cfg.IOConcurrency)
+	spec.SetSpecEncoding(cfg.SpecEncoding)
+	parsesAtStart := spec.ParseCacheStats()
+	validationResults := newValidationRecorder()
+	surfaceChanges := newSurfaceRecorder()
+
+	// Initialize metrics collector, unless the caller provides one
+	metricsCollector := optionsFrom(optionalLogger).MetricsCollector
+	if metricsCollector == nil {
+		metricsCollector = metrics.NewCollector()
+	}
+	metricsCollector.SetLabels(cfg.MetricsLabels)
+
+	// Serve health and live metrics for the duration of the run
+	if cfg.MetricsAddr != "" {
+		serverCtx, stopServer := context.WithCancel(ctx)
+		serverDone := make(chan struct{})
+		go func() {
+			defer close(serverDone)
+			if err := metrics.NewServer(cfg.MetricsAddr, metricsCollector).Run(serverCtx); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}()
+		defer func() {
+			stopServer()
+			<-serverDone
+		}()
+	}
+	defer func() {
+		// Count spec reads of this run by whether the parsed-spec cache served them
+		parses := spec.ParseCacheStats().Sub(parsesAtStart)
+		metricsCollector.RecordParses(parses.Reused, parses.Parsed)
+
 		// Finalize and export metrics
 		metricsCollector.Finalize()
 
 		// Export to file
-		metricsPath := filepath.Join(cfg.OutputDir, ".openapi-metrics.json")
-		if err := metricsCollector.Export(metricsPath); err != nil {
+		metricsPath := cfg.ResolvedMetricsPath()
+		if err := os.MkdirAll(filepath.Dir(metricsPath), 0755); err != nil {
+			log.Printf("Warning: Failed to create metrics directory: %v", err)
+		} else if err := metricsCollector.Export(metricsPath); err != nil {
 			log.Printf("Warning: Failed to export metrics: %v", err)
 		} else {
 			log.Printf("Metrics exported to: %s", metricsPath)
+			report.MetricsPath = metricsPath
+		}
+
+		// Export to InfluxDB line protocol if configured
+		if cfg.InfluxFile != "" {
+			if err := metricsCollector.ExportLineProtocol(cfg.InfluxFile); err != nil {
+				log.Printf("Warning: Failed to export line protocol metrics: %v", err)
+			} else {
+				log.Printf("Line protocol metrics exported to: %s", cfg.InfluxFile)
+			}
+		}
+		if cfg.InfluxEndpoint != "" {
+			if err := metricsCollector.PushLineProtocol(context.Background(), cfg.InfluxEndpoint); err != nil {
+				log.Printf("Warning: Failed to push line protocol metrics: %v", err)
+			} else {
+				log.Printf("Line protocol metrics pushed to: %s", cfg.InfluxEndpoint)
+			}
 		}
 
 		// Log summary
 		log.Printf("%s", metricsCollector.Summary())
 		log.Printf("Success rate: %.1f%%", metricsCollector.SuccessRate())
 		log.Printf("Cache hit rate: %.1f%%", metricsCollector.CacheHitRate())
+		log.Printf("Spec parses: %d reused from cache, %d parsed", parses.Reused, parses.Parsed)
+
+		report.Metrics = metricsCollector.GetMetrics()
+		report.ValidationIssues = validationResults.Issues()
+		report.SurfaceDelta = surfaceChanges.Delta()
+		logSurfaceDelta(report.SurfaceDelta)
+
+		// The JUnit report is written whatever the generation outcome, like the metrics
+		if cfg.JUnitReport != "" {
+			if err := writeJUnitReport(cfg.JUnitReport, generationJUnitSuite(report.Metrics, report.ValidationIssues)); err != nil {
+				log.Printf("Warning: %v", err)
+			} else {
+				log.Printf("JUnit report written to: %s", cfg.JUnitReport)
+			}
+		}
+
+		// The validation report is written whatever the generation outcome
+		if cfg.EmitValidationReportAlways {
+			if reportPath, err := writeValidationReport(cfg.OutputDir, report.ValidationIssues); err != nil {
+				log.Printf("Warning: %v", err)
+			} else {
+				log.Printf("Validation report written to: %s", reportPath)
+			}
+		}
 	}()
 
 	// Setup the client output directory
-	clientOutputDir := filepath.Join(cfg.OutputDir, "clients")
+	clientOutputDir := filepath.Join(cfg.OutputDir, cfg.ClientsSubdir)
 	if err := os.MkdirAll(clientOutputDir, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create client output directory: %w", err)
+		return report, fmt.Errorf("failed to create client output directory: %w", err)
 	}
 
-	// Find OpenAPI specs
-	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns)
+	// Download remote specs and check out git specs, then find OpenAPI specs
+	sourceSpecs, err := fetchSpecSources(ctx, cfg)
 	if err != nil {
-		return err
+		return report, err
+	}
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks, sourceSpecs...)
+	if err != nil {
+		return report, err
+	}
+	report.Specs = specs
+	runSpan.SetAttribute(attrSpecCount, strconv.Itoa(len(specs)))
+	progress.emit(ProgressEvent{Type: ProgressDiscoveryComplete, TotalSpecs: len(specs)})
+
+	// Verify spec checksums against the lockfile (or record them when updating)
+	if cfg.UpdateLock {
+		if err := updateLockFile(cfg.LockFile, cfg.SpecsDir, specs); err != nil {
+			return report, err
+		}
+	} else if err := verifySpecChecksums(cfg.LockFile, cfg.SpecsDir, specs); err != nil {
+		return report, err
+	}
+
+	// Report API changes against the committed fingerprint baseline (or rewrite it)
+	if cfg.BaselineFingerprints != "" {
+		if cfg.UpdateBaseline {
+			if err := updateBaselineFile(cfg.BaselineFingerprints, cfg.SpecsDir, specs); err != nil {
+				return report, err
+			}
+		} else if report.BaselineChanges, err = compareWithBaseline(cfg.BaselineFingerprints, cfg.SpecsDir, specs, cfg.BaselineFailOnBreaking); err != nil {
+			return report, err
+		}
 	}
 
 	// Initialize cache if enabled
 	var specCache *cache.Cache
+	var dependencies *dependencyGraph
 	if cfg.EnableCache {
-		specCache, err = cache.NewCache(cache.Config{CacheDir: cfg.CacheDir})
+		// Scan external $refs so a changed shared file regenerates exactly its dependents
+		dependencies = buildDependencyGraph(specs)
+		logDependencyGraph(dependencies)
+
+		specCache, err = cache.NewCache(runCacheConfig(cfg))
 		if err != nil {
 			log.Printf("Warning: Failed to initialize cache, proceeding without caching: %v", err)
 			specCache = nil
 		} else {
+			// Shared component files no spec $refs invalidate every client when they change
+			invalidated, err := specCache.InvalidateOnSharedChanges(dependencies.unreferenced(cfg.SharedComponentFiles))
+			if err != nil {
+				log.Printf("Warning: Failed to check shared component files: %v", err)
+			} else if invalidated {
+				log.Printf("Shared component files changed, regenerating all clients")
+			}
+
 			// Prune invalid cache entries
 			pruned, err := specCache.PruneInvalid()
 			if err != nil {
@@ -106,26 +341,90 @@ func ProcessOpenAPISpecs(ctx context.Context, cfg config.Config, optionalLogger
 		}
 	}
 
-	// Generate clients in parallel
-	result, err := generateClients(ctx, specs, cfg.OutputDir, cfg.ContinueOnError, cfg.WorkerCount, specCache, metricsCollector)
+	opts := pipelineOptions{
+		preprocessCommand:     cfg.SpecPreprocessCommand,
+		validationResults:     validationResults,
+		failOnWarnings:        cfg.FailOnWarnings,
+		postProcessors:        configuredPostProcessors(&cfg),
+		generatedFileMode:     cfg.ResolvedGeneratedFileMode(),
+		specEncoding:          cfg.SpecEncoding,
+		excludeDeprecated:     cfg.ExcludeDeprecated,
+		downgradeOpenAPI31:    cfg.DowngradeOpenAPI31,
+		convertSwagger2:       cfg.ConvertSwagger2,
+		outputMode:            cfg.OutputMode,
+		clientsSubdir:         cfg.ClientsSubdir,
+		bundleSpecFile:        cfg.BundleSpecFile,
+		copySpecToOutput:      cfg.CopySpecToOutput,
+		traceCache:            cfg.TraceCache,
+		skipValidation:        newValidationSkipMatcher(cfg.SpecsDir, cfg.ValidatorSkipPatterns),
+		surfaceChanges:        surfaceChanges,
+		maxOutputBytes:        cfg.MaxOutputBytes,
+		failureBreaker:        newFailureBreaker(cfg.ConsecutiveFailureLimit),
+		failOnOperationDrop:   cfg.FailOnOperationDrop,
+		subprocessGracePeriod: cfg.SubprocessGracePeriod,
+		ogenConfigPath:        cfg.OgenConfigPath,
+		progress:              progress,
+		tracer:                tracer,
+		dependencies:          dependencies,
+	}
+	if cfg.PostProcessConcurrency > 0 {
+		opts.postProcessSlots = make(chan struct{}, cfg.PostProcessConcurrency)
+	}
+
+	if _, err := spec.LookupEncoding(cfg.SpecEncoding); err != nil {
+		return report, fmt.Errorf("invalid spec_encoding: %w", err)
+	}
+
+	// Build the validator for optional rules enabled in configuration
+	opts.validator, err = newConfiguredValidator(cfg)
 	if err != nil {
-		return err
+		return report, err
+	}
+
+	// Generate clients in parallel, tracking the cycle's progress for live metrics
+	metricsCollector.StartCycle(len(specs))
+	result, err := generateClients(ctx, specs, cfg.OutputDir, cfg.ContinueOnError, cfg.WorkerCount, specCache, metricsCollector, opts)
+	metricsCollector.EndCycle()
+	report.Result = result
+
+	// Write a durable error report with suggestions when anything failed
+	if cfg.EmitErrorReport && result != nil {
+		if reportPath, reportErr := writeErrorReport(cfg.OutputDir, result.FailedSpecs); reportErr != nil {
+			log.Printf("Warning: %v", reportErr)
+		} else if reportPath != "" {
+			log.Printf("Error report written to: %s", reportPath)
+		}
+	}
+
+	if err != nil {
+		return report, err
 	}
 
 	// Log results
 	logProcessingResult(result)
 
+	// Expose every generated client through the facade package
+	if cfg.GenerateFacade {
+		if _, err := writeFacade(cfg); err != nil {
+			return report, fmt.Errorf("failed to generate facade: %w", err)
+		}
+	}
+
 	// Return error if any specs failed (unless continue-on-error is enabled)
 	if !cfg.ContinueOnError && result.SuccessCount < result.TotalSpecs {
-		return fmt.Errorf("failed to generate %d/%d clients",
+		return report, fmt.Errorf("failed to generate %d/%d clients",
 			len(result.FailedSpecs), result.TotalSpecs)
 	}
 
-	return nil
+	return report, nil
 }
 
 // findOpenAPISpecs searches for OpenAPI specs in the given directory.
-func findOpenAPISpecs(specsDir string, targetServices string, specFilePatterns []string) ([]string, error) {
+// When followSymlinks is set, symlinked directories are traversed as well.
+// Directory listings are cached between calls (see discoveryCache), so repeated runs only
+// read directories that changed. Specs from other sources (remote and git specs) are added
+// to the discovered specs.
+func findOpenAPISpecs(specsDir string, targetServices string, specFilePatterns []string, followSymlinks bool, sourceSpecs ...string) ([]string, error) {
 	// Compile service regex for filtering
 	serviceRegex, err := compileServiceRegex(targetServices)
 	if err != nil {
@@ -137,52 +436,46 @@ func findOpenAPISpecs(specsDir string, targetServices string, specFilePatterns [
 		specFilePatterns = []string{"openapi.json", "openapi.yaml", "openapi.yml"}
 	}
 
-	var specs []string
-
-	err = filepath.Walk(specsDir, func(path string, info os.FileInfo, err error) error {
-		// Skip directories and errors
-		if err != nil || info.IsDir() {
-			return nil
-		}
-
-		// Check if filename matches any of the spec file patterns
-		filename := filepath.Base(path)
-		isSpecFile := false
+	// Check if filename matches any of the spec file patterns
+	isSpecFile := func(filename string) bool {
 		for _, pattern := range specFilePatterns {
 			if filename == pattern {
-				isSpecFile = true
-				break
+				return true
 			}
 		}
+		return false
+	}
 
-		if !isSpecFile {
-			return nil
-		}
+	before := specDiscovery.stats()
+	candidates := specDiscovery.findFiles(specsDir, followSymlinks, isSpecFile)
+	after := specDiscovery.stats()
+	log.Printf("Spec discovery: read %d directories, reused %d unchanged", after.read-before.read, after.reused-before.reused)
+	candidates = append(candidates, sourceSpecs...)
 
+	var specs []string
+	for _, path := range candidates {
 		// Check if service name matches the filter
 		serviceDir := filepath.Base(filepath.Dir(path))
-		if !serviceRegex.MatchString(serviceDir) {
-			return nil
+		if serviceRegex.MatchString(serviceDir) {
+			specs = append(specs, path)
 		}
-
-		specs = append(specs, path)
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to find OpenAPI specs: %w", err)
 	}
 
 	if len(specs) == 0 {
 		return nil, fmt.Errorf("no OpenAPI specs found for target services")
 	}
 
+	// Specs sharing a client package would overwrite each other's output
+	if err := checkServiceNameCollisions(specs); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Found %d OpenAPI specs matching the criteria", len(specs))
 	return specs, nil
 }
 
 // generateClients generates clients for all found OpenAPI specs using parallel processing.
-func generateClients(ctx context.Context, specs []string, outputDir string, continueOnError bool, workerCount int, specCache *cache.Cache, metricsCollector *metrics.Collector) (*ProcessingResult, error) {
+func generateClients(ctx context.Context, specs []string, outputDir string, continueOnError bool, workerCount int, specCache *cache.Cache, metricsCollector *metrics.Collector, opts pipelineOptions) (*ProcessingResult, error) {
 	result := &ProcessingResult{
 		TotalSpecs:   len(specs),
 		SuccessCount: 0,
@@ -191,7 +484,7 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 
 	// If only one spec or worker count is 1, process sequentially
 	if len(specs) == 1 || workerCount == 1 {
-		return generateClientsSequential(ctx, specs, outputDir, continueOnError, specCache, metricsCollector)
+		return generateClientsSequential(ctx, specs, outputDir, continueOnError, specCache, metricsCollector, opts)
 	}
 
 	log.Printf("Processing %d specs with %d parallel workers", len(specs), workerCount)
@@ -200,6 +493,7 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 	pool := worker.NewPool(worker.Config{
 		WorkerCount:   workerCount,
 		TaskQueueSize: len(specs),
+		StopOnError:   !continueOnError,
 	})
 
 	// Create tasks for each spec
@@ -214,64 +508,83 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 		task := worker.Task{
 			ID: serviceName,
 			Execute: func(taskCtx context.Context) error {
+				// Specs still queued when the failure breaker trips are skipped
+				if opts.failureBreaker.Err() != nil {
+					return errBatchAborted
+				}
+
 				// Start timing for metrics
 				startTime := time.Now()
+				opts.progress.specStarted(currentSpecPath, serviceName)
+				// Tasks don't run under ctx, so the spec span is carried into the task context
+				_, specSpan := opts.tracer.Start(ctx, spanSpec, specSpanAttributes(currentSpecPath, serviceName))
+				taskCtx = tracing.ContextWithSpan(taskCtx, specSpan)
+				// Parse the spec once for every stage of this task
+				snapshot := takeSpecSnapshot(currentSpecPath, specCache, opts.dependencies)
+				operationCount := snapshot.operationCount
+				clientPath := clientOutputPath(outputDir, opts.clientsSubdir, currentSpecPath, folderName, opts.outputMode)
 
 				// Check cache if available
 				if specCache != nil {
-					valid, err := specCache.IsValid(currentSpecPath, defaultGenerator.Version())
+					valid, err := isCachedClientValid(specCache, snapshot, clientPath, opts.traceCache)
 					if err != nil {
 						log.Printf("Warning: Cache check failed for %s: %v", serviceName, err)
 					} else if valid {
 						log.Printf("⚡ Using cached client for %s (spec unchanged)", folderName)
 
 						// Record cached metric
-						metricsCollector.RecordSpec(metrics.SpecMetric{
-							SpecPath:    currentSpecPath,
-							ServiceName: serviceName,
-							Success:     true,
-							Cached:      true,
-							DurationMs:  time.Since(startTime).Milliseconds(),
-							GeneratedAt: time.Now(),
-						})
+						finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+							SpecPath:       currentSpecPath,
+							ServiceName:    serviceName,
+							Success:        true,
+							Cached:         true,
+							DurationMs:     time.Since(startTime).Milliseconds(),
+							OperationCount: operationCount,
+							GeneratedAt:    time.Now(),
+						}, nil)
 						return nil
 					}
 				}
 
 				log.Printf("Processing service: %s (spec: %s)", serviceName, currentSpecPath)
-				clientPath := filepath.Join(outputDir, "clients", folderName)
 
 				// Generate client
-				genErr := generateClientForSpec(taskCtx, currentSpecPath, serviceName, folderName, outputDir)
+				genErr := checkOperationDrop(specCache, snapshot, opts.failOnOperationDrop)
+				if genErr == nil {
+					genErr = generateClientForSpec(taskCtx, currentSpecPath, serviceName, folderName, outputDir, snapshot, opts)
+				}
 				duration := time.Since(startTime).Milliseconds()
+				opts.failureBreaker.Record(serviceName, genErr)
 
 				if genErr != nil {
 					// Record failed metric
-					metricsCollector.RecordSpec(metrics.SpecMetric{
-						SpecPath:    currentSpecPath,
-						ServiceName: serviceName,
-						Success:     false,
-						Cached:      false,
-						DurationMs:  duration,
-						Error:       genErr.Error(),
-						GeneratedAt: time.Now(),
-					})
+					finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+						SpecPath:       currentSpecPath,
+						ServiceName:    serviceName,
+						Success:        false,
+						Cached:         false,
+						DurationMs:     duration,
+						OperationCount: operationCount,
+						Error:          genErr.Error(),
+						GeneratedAt:    time.Now(),
+					}, genErr)
 					return genErr
 				}
 
 				// Record successful metric
-				metricsCollector.RecordSpec(metrics.SpecMetric{
-					SpecPath:    currentSpecPath,
-					ServiceName: serviceName,
-					Success:     true,
-					Cached:      false,
-					DurationMs:  duration,
-					GeneratedAt: time.Now(),
-				})
+				finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+					SpecPath:       currentSpecPath,
+					ServiceName:    serviceName,
+					Success:        true,
+					Cached:         false,
+					DurationMs:     duration,
+					OperationCount: operationCount,
+					GeneratedAt:    time.Now(),
+				}, nil)
 
 				// Update cache on success
 				if specCache != nil {
-					if err := specCache.Set(currentSpecPath, clientPath, serviceName, defaultGenerator.Version()); err != nil {
+					if err := specCache.SetFor(currentSpecPath, clientPath, serviceName, defaultGenerator.Version(), snapshot.cacheInfo); err != nil {
 						log.Printf("Warning: Failed to update cache for %s: %v", serviceName, err)
 					}
 				}
@@ -284,6 +597,10 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 
 	// Process all tasks in parallel
 	results, err := pool.ProcessBatch(ctx, tasks)
+	poolMetrics := pool.Metrics()
+	log.Printf("Worker pool: %d/%d tasks processed by %d workers, peak queue depth %d, tasks per worker %v",
+		poolMetrics.TasksProcessed, poolMetrics.TasksSubmitted, poolMetrics.WorkerCount,
+		poolMetrics.PeakQueueDepth, poolMetrics.TasksPerWorker)
 	if err != nil {
 		return result, fmt.Errorf("parallel processing failed: %w", err)
 	}
@@ -291,6 +608,9 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 	// Collect results with thread-safe access
 	var mu sync.Mutex
 	for _, taskResult := range results {
+		if errors.Is(taskResult.Error, errBatchAborted) {
+			continue
+		}
 		if taskResult.Error != nil {
 			// Find the corresponding spec path
 			var specPath string
@@ -327,11 +647,21 @@ func generateClients(ctx context.Context, specs []string, outputDir string, cont
 		}
 	}
 
+	if err := opts.failureBreaker.Err(); err != nil {
+		return result, abortedBatchError(err, result)
+	}
+
 	return result, nil
 }
 
+// abortedBatchError summarizes a batch stopped by the failure breaker
+func abortedBatchError(err error, result *ProcessingResult) error {
+	notAttempted := result.TotalSpecs - result.SuccessCount - len(result.FailedSpecs)
+	return fmt.Errorf("%w (%d succeeded, %d failed, %d not attempted)", err, result.SuccessCount, len(result.FailedSpecs), notAttempted)
+}
+
 // generateClientsSequential generates clients sequentially (fallback for single spec or single worker).
-func generateClientsSequential(ctx context.Context, specs []string, outputDir string, continueOnError bool, specCache *cache.Cache, metricsCollector *metrics.Collector) (*ProcessingResult, error) {
+func generateClientsSequential(ctx context.Context, specs []string, outputDir string, continueOnError bool, specCache *cache.Cache, metricsCollector *metrics.Collector, opts pipelineOptions) (*ProcessingResult, error) {
 	result := &ProcessingResult{
 		TotalSpecs:   len(specs),
 		SuccessCount: 0,
@@ -349,14 +679,19 @@ func generateClientsSequential(ctx context.Context, specs []string, outputDir st
 		serviceDir := filepath.Base(filepath.Dir(specPath))
 		serviceName := normalizeServiceName(serviceDir)
 		folderName := serviceName + "sdk"
-		clientPath := filepath.Join(outputDir, "clients", folderName)
+		clientPath := clientOutputPath(outputDir, opts.clientsSubdir, specPath, folderName, opts.outputMode)
 
 		// Start timing for metrics
 		startTime := time.Now()
+		opts.progress.specStarted(specPath, serviceName)
+		specCtx, specSpan := opts.tracer.Start(ctx, spanSpec, specSpanAttributes(specPath, serviceName))
+		// Parse the spec once for every stage of this spec
+		snapshot := takeSpecSnapshot(specPath, specCache, opts.dependencies)
+		operationCount := snapshot.operationCount
 
 		// Check cache if available
 		if specCache != nil {
-			valid, err := specCache.IsValid(specPath, defaultGenerator.Version())
+			valid, err := isCachedClientValid(specCache, snapshot, clientPath, opts.traceCache)
 			if err != nil {
 				log.Printf("Warning: Cache check failed for %s: %v", serviceName, err)
 			} else if valid {
@@ -364,22 +699,27 @@ func generateClientsSequential(ctx context.Context, specs []string, outputDir st
 				result.SuccessCount++
 
 				// Record cached metric
-				metricsCollector.RecordSpec(metrics.SpecMetric{
-					SpecPath:    specPath,
-					ServiceName: serviceName,
-					Success:     true,
-					Cached:      true,
-					DurationMs:  time.Since(startTime).Milliseconds(),
-					GeneratedAt: time.Now(),
-				})
+				finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+					SpecPath:       specPath,
+					ServiceName:    serviceName,
+					Success:        true,
+					Cached:         true,
+					DurationMs:     time.Since(startTime).Milliseconds(),
+					OperationCount: operationCount,
+					GeneratedAt:    time.Now(),
+				}, nil)
 				continue
 			}
 		}
 
 		log.Printf("Processing service: %s (spec: %s)", serviceName, specPath)
 
-		err := generateClientForSpec(ctx, specPath, serviceName, folderName, outputDir)
+		err := checkOperationDrop(specCache, snapshot, opts.failOnOperationDrop)
+		if err == nil {
+			err = generateClientForSpec(specCtx, specPath, serviceName, folderName, outputDir, snapshot, opts)
+		}
 		duration := time.Since(startTime).Milliseconds()
+		opts.failureBreaker.Record(serviceName, err)
 
 		if err != nil {
 			failure := SpecFailure{
@@ -392,37 +732,44 @@ func generateClientsSequential(ctx context.Context, specs []string, outputDir st
 			log.Printf("❌ Failed to generate client for %s: %v", folderName, err)
 
 			// Record failed metric
-			metricsCollector.RecordSpec(metrics.SpecMetric{
-				SpecPath:    specPath,
-				ServiceName: serviceName,
-				Success:     false,
-				Cached:      false,
-				DurationMs:  duration,
-				Error:       err.Error(),
-				GeneratedAt: time.Now(),
-			})
+			finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+				SpecPath:       specPath,
+				ServiceName:    serviceName,
+				Success:        false,
+				Cached:         false,
+				DurationMs:     duration,
+				OperationCount: operationCount,
+				Error:          err.Error(),
+				GeneratedAt:    time.Now(),
+			}, err)
 
 			// Fail fast unless continue-on-error is enabled
 			if !continueOnError {
 				return result, fmt.Errorf("generation failed for %s: %w", serviceName, err)
 			}
+
+			// Stop even with continue-on-error once failures keep repeating
+			if breakerErr := opts.failureBreaker.Err(); breakerErr != nil {
+				return result, abortedBatchError(breakerErr, result)
+			}
 		} else {
 			result.SuccessCount++
 			log.Printf("✅ Successfully generated client for %s", folderName)
 
 			// Record successful metric
-			metricsCollector.RecordSpec(metrics.SpecMetric{
-				SpecPath:    specPath,
-				ServiceName: serviceName,
-				Success:     true,
-				Cached:      false,
-				DurationMs:  duration,
-				GeneratedAt: time.Now(),
-			})
+			finishSpec(metricsCollector, opts.progress, specSpan, metrics.SpecMetric{
+				SpecPath:       specPath,
+				ServiceName:    serviceName,
+				Success:        true,
+				Cached:         false,
+				DurationMs:     duration,
+				OperationCount: operationCount,
+				GeneratedAt:    time.Now(),
+			}, nil)
 
 			// Update cache on success
 			if specCache != nil {
-				if err := specCache.Set(specPath, clientPath, serviceName, defaultGenerator.Version()); err != nil {
+				if err := specCache.SetFor(specPath, clientPath, serviceName, defaultGenerator.Version(), snapshot.cacheInfo); err != nil {
 					log.Printf("Warning: Failed to update cache for %s: %v", serviceName, err)
 				}
 			}
@@ -432,6 +779,55 @@ func generateClientsSequential(ctx context.Context, specs []string, outputDir st
 	return result, nil
 }
 
+// runCacheConfig returns the cache settings of a run
+func runCacheConfig(cfg config.Config) cache.Config {
+	return cache.Config{
+		CacheDir:               cfg.CacheDir,
+		RegenerateOnDocChanges: cfg.RegenerateOnDocChanges,
+		IgnoreResponseHeaders:  cfg.IgnoreResponseHeaderChanges,
+		ExcludeDeprecated:      cfg.ExcludeDeprecated,
+		DowngradeOpenAPI31:     cfg.DowngradeOpenAPI31,
+		ConvertSwagger2:        cfg.ConvertSwagger2,
+		GeneratorVersion:       defaultGenerator.Version(),
+		GeneratorConfigHash:    cache.HashInputs(generatorInputs(cfg)),
+		MaxEntries:             cfg.CacheMaxEntries,
+		Artifacts:              cfg.CacheArtifacts,
+	}
+}
+
+// isCachedClientValid reports whether the cached client for a spec can be reused
+// (see decideCachedClient). A missing client directory is restored from its archive.
+func isCachedClientValid(specCache *cache.Cache, snapshot *specSnapshot, clientPath string, trace bool) (bool, error) {
+	decision, err := decideCachedClient(specCache, snapshot, clientPath, trace)
+	if err == nil && decision.Hit && decision.Reason == cache.ReasonHitArtifact {
+		if err := specCache.RestoreArtifact(snapshot.path); err != nil {
+			log.Printf("Warning: Failed to restore cached client, regenerating it: %v", err)
+			return false, nil
+		}
+		log.Printf("Restored cached client %s from its archive", clientPath)
+	}
+	return decision.Hit, err
+}
+
+// decideCachedClient decides whether the cached client for a spec can be reused.
+// The cache entry must also point at clientPath, so changing output_mode regenerates clients.
+// With trace, the inputs and outcome of the decision are logged as one "cache-trace" line.
+func decideCachedClient(specCache *cache.Cache, snapshot *specSnapshot, clientPath string, trace bool) (cache.Decision, error) {
+	if err := specCache.CheckSpecVersion(snapshot.path, snapshot.cacheInfo.Version); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	decision, err := specCache.DecideFor(snapshot.path, defaultGenerator.Version(), snapshot.cacheInfo)
+	if err == nil && decision.Hit && decision.OutputPath != clientPath {
+		decision.Hit = false
+		decision.Reason = cache.ReasonOutputPathChanged
+	}
+	if trace {
+		log.Printf("cache-trace %s", decision)
+	}
+	return decision, err
+}
+
 // logProcessingResult logs a summary of the processing results
 func logProcessingResult(result *ProcessingResult) {
 	log.Printf("=====================================")
@@ -451,46 +847,187 @@ func logProcessingResult(result *ProcessingResult) {
 	log.Printf("=====================================")
 }
 
-// generateClientForSpec generates a client for a single OpenAPI spec.
-func generateClientForSpec(ctx context.Context, specPath, serviceName, folderName, outputDir string) error {
+// prepareSpec transcodes, bundles, preprocesses, converts and filters a spec before
+// validation and generation. It returns the path of the prepared spec and a cleanup removing
+// any temporary files. The snapshot, if any, saves parsing the spec again when it is bundled or filtered.
+func prepareSpec(ctx context.Context, specPath, serviceName string, snapshot *specSnapshot, opts pipelineOptions) (string, func(), error) {
+	noop := func() {}
+	sourcePath := specPath
+	var cleanups []func()
+	cleanup := func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+
+	// Convert non-UTF-8 specs (e.g., UTF-16 exports) before anything else reads them
+	specPath, cleanupTranscoded, err := transcodeSpec(specPath, opts.specEncoding)
+	if err != nil {
+		return "", noop, err
+	}
+	cleanups = append(cleanups, cleanupTranscoded)
+
+	// Inline external $refs, so the rest of the pipeline gets a self-contained spec that
+	// still resolves when it is rewritten to a temporary file
+	specPath, cleanupBundled, err := bundleSpec(sourcePath, specPath, serviceName, snapshot)
+	if err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	cleanups = append(cleanups, cleanupBundled)
+
+	// Run the preprocess command; its output replaces the spec for the rest of the pipeline
+	specPath, cleanupPreprocessed, err := preprocessSpec(ctx, opts.preprocessCommand, specPath)
+	if err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to preprocess spec for %s: %w", serviceName, err)
+	}
+	cleanups = append(cleanups, cleanupPreprocessed)
+
+	// Upgrade Swagger 2.0 specs, which generators for OpenAPI 3 reject
+	if opts.convertSwagger2 {
+		var cleanupConverted func()
+		specPath, cleanupConverted, err = convertSwagger2(specPath, serviceName, snapshot.documentFor(specPath))
+		if err != nil {
+			cleanup()
+			return "", noop, err
+		}
+		cleanups = append(cleanups, cleanupConverted)
+	}
+
+	// Convert OpenAPI 3.1 constructs for generators that only read 3.0
+	if opts.downgradeOpenAPI31 {
+		var cleanupDowngraded func()
+		specPath, cleanupDowngraded, err = downgradeOpenAPI31(specPath, serviceName, snapshot.documentFor(specPath))
+		if err != nil {
+			cleanup()
+			return "", noop, err
+		}
+		cleanups = append(cleanups, cleanupDowngraded)
+	}
+
+	// Drop deprecated operations so the SDK doesn't expose retiring endpoints
+	if opts.excludeDeprecated {
+		var cleanupFiltered func()
+		specPath, cleanupFiltered, err = excludeDeprecatedOperations(specPath, serviceName, snapshot.documentFor(specPath))
+		if err != nil {
+			cleanup()
+			return "", noop, err
+		}
+		cleanups = append(cleanups, cleanupFiltered)
+	}
+
+	return specPath, cleanup, nil
+}
+
+// generateClientForSpec generates a client for a single OpenAPI spec. Stages reuse the
+// snapshot's parsed document where they read the unmodified spec; a nil snapshot makes
+// each stage parse the spec itself.
+func generateClientForSpec(ctx context.Context, specPath, serviceName, folderName, outputDir string, snapshot *specSnapshot, opts pipelineOptions) error {
 	// Create the client directory
-	clientPath := filepath.Join(outputDir, "clients", folderName)
+	clientPath := clientOutputPath(outputDir, opts.clientsSubdir, specPath, folderName, opts.outputMode)
 	if err := os.MkdirAll(clientPath, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create client directory for %s: %w", serviceName, err)
 	}
 
-	// Clean existing files in the client directory
+	// The bundle shipped by the previous run is the baseline for the API surface delta
+	var previousBundle map[string]interface{}
+	if opts.bundleSpecFile != "" {
+		previousBundle = loadPreviousBundle(filepath.Join(clientPath, opts.bundleSpecFile))
+	}
+
+	// Clean existing files in the client directory. The spec copy is kept: it is not generated
+	// code, and is rewritten once the client is generated.
+	var keep func(name string) bool
+	if opts.copySpecToOutput {
+		copyName := specCopyFileName(specPath)
+		keep = func(name string) bool { return name == copyName }
+	}
 	log.Printf("Cleaning existing files for %s...", folderName)
-	if err := cleanDirectory(clientPath); err != nil {
+	if err := cleanDirectoryExcept(clientPath, keep); err != nil {
 		return fmt.Errorf("failed to clean client directory for %s: %w", serviceName, err)
 	}
 
+	sourcePath := specPath
+	specPath, cleanup, err := prepareSpec(ctx, specPath, serviceName, snapshot, opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	doc := snapshot.documentFor(specPath)
+
+	// Validate the spec against the configured rules
+	if opts.skipValidation != nil && opts.skipValidation(sourcePath) {
+		log.Printf("Skipping validation for %s: spec matches validator_skip_patterns", serviceName)
+	} else {
+		_, validateSpan := opts.tracer.Start(ctx, spanValidate, nil)
+		err := validateSpecDocument(opts.validator, specPath, doc, serviceName, opts.failOnWarnings, opts.validationResults)
+		validateSpan.End(err)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Run the client generator
-	if err := runGenerator(ctx, folderName, specPath, clientPath); err != nil {
+	generateCtx, generateSpan := opts.tracer.Start(ctx, spanGenerate, nil)
+	err = runGenerator(generateCtx, folderName, specPath, clientPath, opts)
+	generateSpan.End(err)
+	if err != nil {
 		return err
 	}
 
+	// Ship the resolved single-file spec with the client
+	if opts.bundleSpecFile != "" {
+		bundled, err := writeBundledSpec(sourcePath, specPath, clientPath, opts.bundleSpecFile)
+		if err != nil {
+			return fmt.Errorf("failed to bundle spec for %s: %w", serviceName, err)
+		}
+		if previousBundle != nil {
+			opts.surfaceChanges.Record(serviceName, spec.CompareDocuments(previousBundle, bundled))
+		}
+	}
+
+	// Ship the source spec as-is with the client, for traceability
+	if opts.copySpecToOutput {
+		if err := writeSpecCopy(sourcePath, clientPath); err != nil {
+			return fmt.Errorf("failed to copy spec for %s: %w", serviceName, err)
+		}
+	}
+
 	// Apply post-processors to the generated client
-	log.Printf("Applying post-processors for %s...", folderName)
-	if err := ApplyPostProcessors(ctx, clientPath, folderName, specPath); err != nil {
+	postProcessCtx, postProcessSpan := opts.tracer.Start(ctx, spanPostProcess, nil)
+	err = postProcessClient(postProcessCtx, opts, clientPath, folderName, specPath, doc)
+	postProcessSpan.End(err)
+	if err != nil {
 		return fmt.Errorf("failed to apply post-processors for %s: %w", folderName, err)
 	}
 
+	// Guard against runaway output filling the disk
+	if err := checkOutputSize(clientPath, folderName, opts.maxOutputBytes, keep); err != nil {
+		return err
+	}
+
 	log.Printf("Successfully generated client for %s", folderName)
 	return nil
 }
 
 // runGenerator executes the configured generator to create client code from an OpenAPI spec.
-func runGenerator(ctx context.Context, serviceName, specPath, outputDir string) error {
+func runGenerator(ctx context.Context, serviceName, specPath, outputDir string, opts pipelineOptions) error {
 	log.Printf("Generating client for %s using %s...", serviceName, defaultGenerator.Name())
 
+	configPath := opts.ogenConfigPath
+	if configPath == "" {
+		configPath = paths.GetOgenConfigPath()
+	}
+
 	// Create generate spec
 	spec := generator.GenerateSpec{
 		SpecPath:    specPath,
 		OutputDir:   outputDir,
 		PackageName: serviceName,
-		ConfigPath:  paths.GetOgenConfigPath(),
+		ConfigPath:  configPath,
 		Clean:       true,
+		GracePeriod: opts.subprocessGracePeriod,
 	}
 
 	// Generate client code
@@ -7,8 +7,12 @@ import (
 	"testing"
 	"time"
 
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
 )
 
 func TestFindOpenAPISpecs(t *testing.T) {
@@ -242,7 +246,7 @@ paths: {}`
 			if patterns == nil {
 				patterns = []string{"openapi.json"} // default for existing tests
 			}
-			specs, err := findOpenAPISpecs(tmpDir, tt.targetServices, patterns)
+			specs, err := findOpenAPISpecs(tmpDir, tt.targetServices, patterns, false)
 
 			// Check error expectations
 			if (err != nil) != tt.wantErr {
@@ -283,6 +287,80 @@ paths: {}`
 	}
 }
 
+func TestFindOpenAPISpecsSymlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	externalDir := filepath.Join(tmpDir, "external", "funding-server-sdk")
+
+	// Regular spec inside the specs directory
+	regularDir := filepath.Join(specsDir, "holidays-server-sdk")
+	if err := os.MkdirAll(regularDir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(regularDir, "openapi.json"), []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	// Spec outside the specs directory, reachable only through a symlink
+	if err := os.MkdirAll(externalDir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(externalDir, "openapi.json"), []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	if err := os.Symlink(externalDir, filepath.Join(specsDir, "funding-server-sdk")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	// Symlink loop back to the specs directory
+	if err := os.Symlink(specsDir, filepath.Join(regularDir, "loop")); err != nil {
+		t.Fatalf("Failed to create loop symlink: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		followSymlinks bool
+		expectedCount  int
+	}{
+		{
+			name:           "symlinks not followed",
+			followSymlinks: false,
+			expectedCount:  1,
+		},
+		{
+			name:           "symlinks followed with loop guard",
+			followSymlinks: true,
+			expectedCount:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := findOpenAPISpecs(specsDir, "", nil, tt.followSymlinks)
+			if err != nil {
+				t.Fatalf("findOpenAPISpecs() error = %v", err)
+			}
+			if len(specs) != tt.expectedCount {
+				t.Errorf("findOpenAPISpecs() found %d specs (%v), expected %d", len(specs), specs, tt.expectedCount)
+			}
+
+			if tt.followSymlinks {
+				// Symlinked specs keep the symlink path so service names resolve correctly
+				expected := filepath.Join(specsDir, "funding-server-sdk", "openapi.json")
+				found := false
+				for _, spec := range specs {
+					if spec == expected {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("findOpenAPISpecs() = %v, want to include %s", specs, expected)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateClients(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -346,7 +424,7 @@ func TestGenerateClients(t *testing.T) {
 			// Create metrics collector for test
 		metricsCollector := metrics.NewCollector()
 
-		result, err := generateClients(ctx, specs, outputDir, tt.continueOnError, 4, nil, metricsCollector)
+		result, err := generateClients(ctx, specs, outputDir, tt.continueOnError, 4, nil, metricsCollector, pipelineOptions{})
 
 			// Check error expectations
 			if (err != nil) != tt.wantErr {
@@ -522,3 +600,164 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+// outputDirRecorder is a fake generator that records the output directory of each generation
+type outputDirRecorder struct {
+	noopGenerator
+	outputDirs []string
+}
+
+func (g *outputDirRecorder) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	g.outputDirs = append(g.outputDirs, spec.OutputDir)
+	return nil
+}
+
+func TestGenerateClientsAlongsideSpec(t *testing.T) {
+	recorder := &outputDirRecorder{}
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(recorder)
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+	specPath := filepath.Join(specDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	specCache, err := cache.NewCache(cache.Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	outputDir := filepath.Join(tmpDir, "generated")
+	opts := pipelineOptions{outputMode: config.OutputModeAlongsideSpec, clientsSubdir: config.DefaultClientsSubdir}
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, specCache, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+
+	wantPath := filepath.Join(specDir, "client")
+	if len(recorder.outputDirs) != 1 || recorder.outputDirs[0] != wantPath {
+		t.Errorf("generator output dirs = %v, want [%s]", recorder.outputDirs, wantPath)
+	}
+	entry, ok := specCache.Get(specPath)
+	if !ok {
+		t.Fatal("expected cache entry for spec")
+	}
+	if entry.OutputPath != wantPath {
+		t.Errorf("cache OutputPath = %q, want %q", entry.OutputPath, wantPath)
+	}
+
+	// Switching back to the central layout must not reuse the client generated next to the spec
+	opts.outputMode = config.OutputModeCentral
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, specCache, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+	centralPath := filepath.Join(outputDir, "clients", "fundingsdk")
+	if len(recorder.outputDirs) != 2 || recorder.outputDirs[1] != centralPath {
+		t.Errorf("generator output dirs = %v, want second generation in %s", recorder.outputDirs, centralPath)
+	}
+}
+
+func TestGenerateClientsClientsSubdir(t *testing.T) {
+	recorder := &outputDirRecorder{}
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(recorder)
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+	specPath := filepath.Join(specDir, "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	specCache, err := cache.NewCache(cache.Config{CacheDir: filepath.Join(tmpDir, "cache")})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	outputDir := filepath.Join(tmpDir, "generated")
+
+	tests := []struct {
+		clientsSubdir string
+		want          string
+	}{
+		{clientsSubdir: "sdk", want: filepath.Join(outputDir, "sdk", "fundingsdk")},
+		{clientsSubdir: "", want: filepath.Join(outputDir, "fundingsdk")},
+	}
+	for i, tt := range tests {
+		opts := pipelineOptions{outputMode: config.OutputModeCentral, clientsSubdir: tt.clientsSubdir}
+		if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, specCache, metrics.NewCollector(), opts); err != nil {
+			t.Fatalf("generateClientsSequential() error = %v", err)
+		}
+
+		// Each subdir change regenerates, since the cached client lives elsewhere
+		if len(recorder.outputDirs) != i+1 || recorder.outputDirs[i] != tt.want {
+			t.Errorf("clients_subdir %q: generator output dirs = %v, want generation in %s", tt.clientsSubdir, recorder.outputDirs, tt.want)
+		}
+		entry, ok := specCache.Get(specPath)
+		if !ok || entry.OutputPath != tt.want {
+			t.Errorf("clients_subdir %q: cache entry = %+v, want OutputPath %s", tt.clientsSubdir, entry, tt.want)
+		}
+	}
+}
+
+func TestOgenConfigPathReachesGenerator(t *testing.T) {
+	customConfig := filepath.Join(t.TempDir(), "ogen.yml")
+	if err := os.WriteFile(customConfig, []byte("generator:\n  features:\n    enable: []\n"), 0644); err != nil {
+		t.Fatalf("Failed to write ogen config: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ogenConfigPath string
+		want           string
+	}{
+		{name: "custom config", ogenConfigPath: customConfig, want: customConfig},
+		{name: "repository default", ogenConfigPath: "", want: paths.GetOgenConfigPath()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := useRecordingGenerator(t)
+
+			tmpDir := t.TempDir()
+			specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+			if err := os.MkdirAll(specDir, 0755); err != nil {
+				t.Fatalf("Failed to create spec dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(specDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			cfg := config.Config{
+				SpecsDir:       filepath.Join(tmpDir, "specs"),
+				OutputDir:      filepath.Join(tmpDir, "output"),
+				WorkerCount:    1,
+				OgenConfigPath: tt.ogenConfigPath,
+			}
+			if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+				t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+			}
+			if gen.configPath != tt.want {
+				t.Errorf("GenerateSpec.ConfigPath = %q, want %q", gen.configPath, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/tracing"
+)
+
+// ProgressEventType identifies the kind of progress event
+type ProgressEventType string
+
+const (
+	// ProgressDiscoveryComplete is emitted once specs have been discovered (TotalSpecs is set)
+	ProgressDiscoveryComplete ProgressEventType = "discovery_complete"
+
+	// ProgressSpecStarted is emitted when processing of a spec begins
+	ProgressSpecStarted ProgressEventType = "spec_started"
+
+	// ProgressSpecFinished is emitted when a spec is done (Success, Cached, Error and Duration are set)
+	ProgressSpecFinished ProgressEventType = "spec_finished"
+
+	// ProgressRunComplete is emitted last, whatever the outcome (Error is set if the run failed)
+	ProgressRunComplete ProgressEventType = "run_complete"
+)
+
+// ProgressEvent describes a step of a run, for library consumers building their own UI
+type ProgressEvent struct {
+	// Type is the kind of event
+	Type ProgressEventType
+
+	// SpecPath and ServiceName identify the spec (spec events only)
+	SpecPath    string
+	ServiceName string
+
+	// TotalSpecs is the number of discovered specs (discovery and run events)
+	TotalSpecs int
+
+	// Success reports whether the spec was generated or reused from cache (finished events only)
+	Success bool
+
+	// Cached reports whether the cached client was reused (finished events only)
+	Cached bool
+
+	// Duration is how long the spec took (finished events only)
+	Duration time.Duration
+
+	// Error is the spec or run error, if any
+	Error error
+}
+
+// ProgressCallback receives progress events. Events are delivered one at a time,
+// but may come from worker goroutines, so the callback should return quickly.
+type ProgressCallback func(ProgressEvent)
+
+// Options are optional settings for embedding the generator as a library.
+// Pass them to ProcessOpenAPISpecs or ProcessOpenAPISpecsWithResult after the config.
+type Options struct {
+	// ProgressCallback is called for discovery, spec start/finish and run completion (optional)
+	ProgressCallback ProgressCallback
+
+	// MetricsCollector records the run instead of a new collector, for callers keeping one
+	// across runs (optional). It is not reset; call Reset between runs.
+	MetricsCollector *metrics.Collector
+
+	// TraceExporter receives the run's trace instead of otel_endpoint, enabling tracing even
+	// when otel_endpoint is not set (optional)
+	TraceExporter tracing.Exporter
+}
+
+// optionsFrom returns the Options among the optional arguments, if any
+func optionsFrom(optional []interface{}) Options {
+	for _, arg := range optional {
+		switch opts := arg.(type) {
+		case Options:
+			return opts
+		case *Options:
+			if opts != nil {
+				return *opts
+			}
+		}
+	}
+	return Options{}
+}
+
+// progressReporter serializes progress events to the callback; a nil reporter discards events
+type progressReporter struct {
+	mu       sync.Mutex
+	callback ProgressCallback
+}
+
+// newProgressReporter returns a reporter for the callback, or nil if there is no callback
+func newProgressReporter(callback ProgressCallback) *progressReporter {
+	if callback == nil {
+		return nil
+	}
+	return &progressReporter{callback: callback}
+}
+
+// emit delivers an event to the callback
+func (p *progressReporter) emit(event ProgressEvent) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callback(event)
+}
+
+// specStarted reports that processing of a spec began
+func (p *progressReporter) specStarted(specPath, serviceName string) {
+	p.emit(ProgressEvent{Type: ProgressSpecStarted, SpecPath: specPath, ServiceName: serviceName})
+}
+
+// finishSpec records a spec's metrics, ends its trace span and reports its outcome to the progress callback
+func finishSpec(collector *metrics.Collector, progress *progressReporter, span *tracing.Span, metric metrics.SpecMetric, err error) {
+	collector.RecordSpec(metric)
+	span.SetAttribute(attrOutcome, specOutcome(metric))
+	span.End(err)
+	progress.emit(ProgressEvent{
+		Type:        ProgressSpecFinished,
+		SpecPath:    metric.SpecPath,
+		ServiceName: metric.ServiceName,
+		Success:     metric.Success,
+		Cached:      metric.Cached,
+		Duration:    time.Duration(metric.DurationMs) * time.Millisecond,
+		Error:       err,
+	})
+}
+
+// specOutcome returns the openapi.outcome trace attribute of a finished spec
+func specOutcome(metric metrics.SpecMetric) string {
+	switch {
+	case !metric.Success:
+		return "failed"
+	case metric.Cached:
+		return "cached"
+	default:
+		return "success"
+	}
+}
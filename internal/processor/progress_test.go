@@ -0,0 +1,148 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+// writeProgressTestSpecs writes one spec per service and returns the specs directory
+func writeProgressTestSpecs(t *testing.T, services ...string) string {
+	t.Helper()
+
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	for _, svc := range services {
+		svcDir := filepath.Join(specsDir, svc)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create spec dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+	return specsDir
+}
+
+func TestProgressCallbackSequence(t *testing.T) {
+	useRecordingGenerator(t)
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk")
+
+	var events []ProgressEvent
+	opts := Options{ProgressCallback: func(event ProgressEvent) {
+		events = append(events, event)
+	}}
+
+	cfg := config.Config{
+		SpecsDir:    specsDir,
+		OutputDir:   filepath.Join(t.TempDir(), "output"),
+		WorkerCount: 1,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := ProcessOpenAPISpecs(ctx, cfg, nil, opts); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	want := []struct {
+		eventType ProgressEventType
+		service   string
+	}{
+		{ProgressDiscoveryComplete, ""},
+		{ProgressSpecStarted, "funding"},
+		{ProgressSpecFinished, "funding"},
+		{ProgressSpecStarted, "holidays"},
+		{ProgressSpecFinished, "holidays"},
+		{ProgressRunComplete, ""},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Type != w.eventType || events[i].ServiceName != w.service {
+			t.Errorf("event[%d] = %s/%s, want %s/%s", i, events[i].Type, events[i].ServiceName, w.eventType, w.service)
+		}
+	}
+
+	if events[0].TotalSpecs != 2 {
+		t.Errorf("discovery TotalSpecs = %d, want 2", events[0].TotalSpecs)
+	}
+	for _, i := range []int{2, 4} {
+		if !events[i].Success || events[i].Error != nil {
+			t.Errorf("event[%d] = %+v, want successful finish", i, events[i])
+		}
+	}
+	if last := events[len(events)-1]; last.Error != nil || last.TotalSpecs != 2 {
+		t.Errorf("run complete = %+v, want no error and 2 specs", last)
+	}
+}
+
+func TestProgressCallbackReportsFailures(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	gen.err = errors.New("ogen exploded")
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk", "payments-server-sdk")
+
+	started := 0
+	var finished []ProgressEvent
+	var runComplete *ProgressEvent
+	opts := &Options{ProgressCallback: func(event ProgressEvent) {
+		switch event.Type {
+		case ProgressSpecStarted:
+			started++
+		case ProgressSpecFinished:
+			finished = append(finished, event)
+		case ProgressRunComplete:
+			runComplete = &event
+		}
+	}}
+
+	cfg := config.Config{
+		SpecsDir:        specsDir,
+		OutputDir:       filepath.Join(t.TempDir(), "output"),
+		WorkerCount:     3,
+		ContinueOnError: true,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := ProcessOpenAPISpecsWithResult(ctx, cfg, opts); err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+
+	if started != 3 || len(finished) != 3 {
+		t.Errorf("started %d, finished %d, want 3 each", started, len(finished))
+	}
+	for _, event := range finished {
+		if event.Success || event.Error == nil {
+			t.Errorf("finished event = %+v, want failure with error", event)
+		}
+	}
+	if runComplete == nil {
+		t.Fatal("run complete event not emitted")
+	}
+}
+
+func TestProgressCallbackRunErrorWithoutCallback(t *testing.T) {
+	// A nil callback must be safe, including on early failures
+	cfg := config.Config{
+		SpecsDir:  filepath.Join(t.TempDir(), "missing"),
+		OutputDir: filepath.Join(t.TempDir(), "output"),
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg, Options{}); err == nil {
+		t.Fatal("ProcessOpenAPISpecs() expected error for missing specs")
+	}
+
+	var last ProgressEvent
+	opts := Options{ProgressCallback: func(event ProgressEvent) { last = event }}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg, opts); err == nil {
+		t.Fatal("ProcessOpenAPISpecs() expected error for missing specs")
+	}
+	if last.Type != ProgressRunComplete || last.Error == nil {
+		t.Errorf("last event = %+v, want run complete with error", last)
+	}
+}
@@ -0,0 +1,193 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+const (
+	// remoteSpecsSubdir is the staging directory for downloaded specs, under the cache directory
+	remoteSpecsSubdir = "remote-specs"
+
+	// remoteFetchTimeout bounds the download of each remote spec
+	remoteFetchTimeout = 30 * time.Second
+
+	// maxRemoteSpecBytes caps the size of a downloaded spec
+	maxRemoteSpecBytes = 64 << 20
+)
+
+// remoteSpecExtensions are the staged spec file extensions, so a spec whose format changed
+// replaces the previous download
+var remoteSpecExtensions = []string{".json", ".yaml", ".yml"}
+
+// remoteStagingDir returns the directory downloaded specs are staged in
+func remoteStagingDir(cfg config.Config) string {
+	return filepath.Join(cfg.CacheDir, remoteSpecsSubdir)
+}
+
+// fetchRemoteSpecs downloads the remote specs of the target services into the staging
+// directory, as <staging dir>/<service>/openapi.<ext>, and returns their paths. The staged
+// file is only rewritten when the content hash changed, so unchanged specs stay cached.
+//
+// A failed download fails the run. With continue_on_error, or in offline mode, the previous
+// download is used instead when there is one.
+func fetchRemoteSpecs(ctx context.Context, cfg config.Config) ([]string, error) {
+	if len(cfg.RemoteSpecs) == 0 {
+		return nil, nil
+	}
+	serviceRegex, err := compileServiceRegex(cfg.TargetServices)
+	if err != nil {
+		return nil, err
+	}
+
+	stagingDir := remoteStagingDir(cfg)
+	client := network.NewHTTPClient(remoteFetchTimeout)
+	var specs []string
+	for _, remote := range cfg.RemoteSpecs {
+		if !serviceRegex.MatchString(remote.Service) {
+			continue
+		}
+
+		specPath, err := fetchRemoteSpec(ctx, client, filepath.Join(stagingDir, remote.Service), remote)
+		if err != nil {
+			staged := stagedRemoteSpec(filepath.Join(stagingDir, remote.Service))
+			var offlineErr *network.OfflineError
+			if staged == "" || !(cfg.ContinueOnError || errors.As(err, &offlineErr)) {
+				return nil, fmt.Errorf("failed to fetch remote spec %s: %w", remote.Service, err)
+			}
+			log.Printf("Warning: failed to fetch remote spec %s, using the previous download %s: %v", remote.Service, staged, err)
+			specPath = staged
+		}
+		specs = append(specs, specPath)
+	}
+	return specs, nil
+}
+
+// fetchRemoteSpec downloads a remote spec into serviceDir and returns the staged path
+func fetchRemoteSpec(ctx context.Context, client *http.Client, serviceDir string, remote config.RemoteSpec) (string, error) {
+	if err := network.Check(fmt.Sprintf("fetching remote spec %s", remote.Service),
+		"previously downloaded remote specs are used when available; otherwise disable offline mode"); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range remote.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+	if remote.TokenEnv != "" {
+		token := os.Getenv(remote.TokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("token_env %s is not set", remote.TokenEnv)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("download failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSpecBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(data) > maxRemoteSpecBytes {
+		return "", fmt.Errorf("spec exceeds %d bytes", maxRemoteSpecBytes)
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	specPath := filepath.Join(serviceDir, "openapi"+remoteSpecExtension(remote.URL, resp.Header.Get("Content-Type"), data))
+	if staged, err := cache.ComputeFileHash(specPath); err == nil && staged == hash {
+		log.Printf("Remote spec %s unchanged (sha256 %s)", remote.Service, hash)
+		return specPath, nil
+	}
+
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	for _, ext := range remoteSpecExtensions {
+		if stale := filepath.Join(serviceDir, "openapi"+ext); stale != specPath {
+			os.Remove(stale)
+		}
+	}
+	if err := writeFileWithRetry(specPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to stage spec: %w", err)
+	}
+	log.Printf("Fetched remote spec %s from %s (sha256 %s)", remote.Service, remote.URL, hash)
+	return specPath, nil
+}
+
+// remoteSpecExtension picks the staged file extension from the URL path, then the content
+// type, then the content itself
+func remoteSpecExtension(rawURL, contentType string, data []byte) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		ext := strings.ToLower(path.Ext(parsed.Path))
+		for _, known := range remoteSpecExtensions {
+			if ext == known {
+				return ext
+			}
+		}
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			return ".json"
+		case strings.HasSuffix(mediaType, "yaml"):
+			return ".yaml"
+		}
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// stagedRemoteSpec returns the previously downloaded spec in serviceDir, or ""
+func stagedRemoteSpec(serviceDir string) string {
+	for _, ext := range remoteSpecExtensions {
+		specPath := filepath.Join(serviceDir, "openapi"+ext)
+		if _, err := os.Stat(specPath); err == nil {
+			return specPath
+		}
+	}
+	return ""
+}
+
+// stagedRemoteSpecs returns the previously downloaded specs of all remote sources, without
+// fetching them
+func stagedRemoteSpecs(cfg config.Config) []string {
+	var specs []string
+	for _, remote := range cfg.RemoteSpecs {
+		if staged := stagedRemoteSpec(filepath.Join(remoteStagingDir(cfg), remote.Service)); staged != "" {
+			specs = append(specs, staged)
+		}
+	}
+	return specs
+}
@@ -0,0 +1,187 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+const (
+	remoteFundingSpec  = `{"openapi":"3.0.0","info":{"title":"Funding","version":"1.0.0"},"paths":{}}`
+	remoteHolidaysSpec = "openapi: 3.0.0\ninfo:\n  title: Holidays\n  version: 1.0.0\npaths: {}\n"
+)
+
+// newRemoteSpecServer serves the funding spec as JSON behind a bearer token and an API key,
+// and the holidays spec as YAML under a path without extension
+func newRemoteSpecServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/funding/openapi.json":
+			if r.Header.Get("Authorization") != "Bearer s3cret" || r.Header.Get("X-Api-Key") != "key-123" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(remoteFundingSpec))
+		case "/holidays/spec":
+			w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+			w.Write([]byte(remoteHolidaysSpec))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestFetchRemoteSpecs(t *testing.T) {
+	server, _ := newRemoteSpecServer(t)
+	t.Setenv("FUNDING_TOKEN", "s3cret")
+	t.Setenv("FUNDING_API_KEY", "key-123")
+
+	cfg := config.Config{
+		CacheDir: t.TempDir(),
+		RemoteSpecs: []config.RemoteSpec{
+			{
+				Service:  "funding-server-sdk",
+				URL:      server.URL + "/funding/openapi.json",
+				Headers:  map[string]string{"x-api-key": "${FUNDING_API_KEY}"},
+				TokenEnv: "FUNDING_TOKEN",
+			},
+			{Service: "holidays-server-sdk", URL: server.URL + "/holidays/spec"},
+		},
+	}
+	specs, err := fetchRemoteSpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("fetchRemoteSpecs() error = %v", err)
+	}
+
+	stagingDir := filepath.Join(cfg.CacheDir, remoteSpecsSubdir)
+	want := []string{
+		filepath.Join(stagingDir, "funding-server-sdk", "openapi.json"),
+		filepath.Join(stagingDir, "holidays-server-sdk", "openapi.yaml"),
+	}
+	if strings.Join(specs, ",") != strings.Join(want, ",") {
+		t.Fatalf("staged specs = %v, want %v", specs, want)
+	}
+	for i, content := range []string{remoteFundingSpec, remoteHolidaysSpec} {
+		if data, _ := os.ReadFile(specs[i]); string(data) != content {
+			t.Errorf("%s = %q, want %q", specs[i], data, content)
+		}
+	}
+
+	// Unchanged content is not rewritten, so the staged spec keeps its mtime
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(specs[0], old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	cfg.TargetServices = "funding"
+	specs, err = fetchRemoteSpecs(context.Background(), cfg)
+	if err != nil || len(specs) != 1 || specs[0] != want[0] {
+		t.Fatalf("filtered fetchRemoteSpecs() = %v, %v; want only funding", specs, err)
+	}
+	if info, err := os.Stat(specs[0]); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("unchanged remote spec was rewritten")
+	}
+}
+
+func TestFetchRemoteSpecsFailures(t *testing.T) {
+	server, requests := newRemoteSpecServer(t)
+	cfg := config.Config{
+		CacheDir: t.TempDir(),
+		RemoteSpecs: []config.RemoteSpec{
+			{Service: "funding-server-sdk", URL: server.URL + "/funding/openapi.json"},
+		},
+	}
+
+	// Without credentials the download fails, and there is no previous download to fall back to
+	_, err := fetchRemoteSpecs(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Fatalf("fetchRemoteSpecs() error = %v, want a 401 failure", err)
+	}
+
+	// With continue_on_error, a previous download is used instead
+	staged := filepath.Join(cfg.CacheDir, remoteSpecsSubdir, "funding-server-sdk", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(staged), 0755); err != nil {
+		t.Fatalf("Failed to create staging dir: %v", err)
+	}
+	if err := os.WriteFile(staged, []byte(remoteFundingSpec), 0644); err != nil {
+		t.Fatalf("Failed to write staged spec: %v", err)
+	}
+	if _, err := fetchRemoteSpecs(context.Background(), cfg); err == nil {
+		t.Error("without continue_on_error, a failed download should fail even with a previous download")
+	}
+	cfg.ContinueOnError = true
+	if specs, err := fetchRemoteSpecs(context.Background(), cfg); err != nil || len(specs) != 1 || specs[0] != staged {
+		t.Errorf("fetchRemoteSpecs() with continue_on_error = %v, %v; want the previous download", specs, err)
+	}
+
+	// Offline, the previous download is used without any request
+	cfg.ContinueOnError = false
+	network.SetOffline(true)
+	t.Cleanup(func() { network.SetOffline(false) })
+	before := requests.Load()
+	if specs, err := fetchRemoteSpecs(context.Background(), cfg); err != nil || len(specs) != 1 || specs[0] != staged {
+		t.Errorf("offline fetchRemoteSpecs() = %v, %v; want the previous download", specs, err)
+	}
+	if requests.Load() != before {
+		t.Error("offline mode should not send requests")
+	}
+}
+
+func TestRunWithRemoteSpec(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	server, _ := newRemoteSpecServer(t)
+
+	// Remote specs are generated even when specs_dir has no spec of its own
+	cfg := config.Config{
+		SpecsDir:  t.TempDir(),
+		OutputDir: t.TempDir(),
+		CacheDir:  t.TempDir(),
+		RemoteSpecs: []config.RemoteSpec{
+			{Service: "holidays-server-sdk", URL: server.URL + "/holidays/spec"},
+		},
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	if want := filepath.Join(cfg.CacheDir, remoteSpecsSubdir, "holidays-server-sdk", "openapi.yaml"); gen.specPath != want {
+		t.Errorf("generated spec = %s, want %s", gen.specPath, want)
+	}
+	if gen.specContent != remoteHolidaysSpec {
+		t.Errorf("generated spec content = %q", gen.specContent)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputDir, "holidayssdk")); err != nil {
+		t.Errorf("holidays client directory missing: %v", err)
+	}
+}
+
+func TestRemoteSpecExtension(t *testing.T) {
+	tests := []struct {
+		url, contentType, data, want string
+	}{
+		{"https://specs.corp/funding/openapi.yml?ref=main", "text/plain", "{}", ".yml"},
+		{"https://specs.corp/funding", "application/json", "openapi: 3.0.0", ".json"},
+		{"https://specs.corp/funding", "application/vnd.oai.openapi+yaml", "{}", ".yaml"},
+		{"https://specs.corp/funding", "", "  {\"openapi\": \"3.0.0\"}", ".json"},
+		{"https://specs.corp/funding", "text/plain", "openapi: 3.0.0", ".yaml"},
+	}
+	for _, tt := range tests {
+		if got := remoteSpecExtension(tt.url, tt.contentType, []byte(tt.data)); got != tt.want {
+			t.Errorf("remoteSpecExtension(%q, %q) = %q, want %q", tt.url, tt.contentType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// repairedSuffix is inserted before the extension of repaired copies, e.g. openapi.repaired.yaml
+const repairedSuffix = ".repaired"
+
+// RepairSummary reports the fixes applied to the discovered specs
+type RepairSummary struct {
+	// TotalSpecs is the number of discovered specs
+	TotalSpecs int `json:"total_specs"`
+
+	// InPlace reports whether specs were fixed in place rather than copied
+	InPlace bool `json:"in_place"`
+
+	// Specs holds the result of each spec that was changed or could not be repaired
+	Specs []SpecRepair `json:"specs"`
+}
+
+// SpecRepair is the repair result of one spec
+type SpecRepair struct {
+	// Service is the service name derived from the spec's directory
+	Service string `json:"service"`
+
+	// SpecPath is the path of the spec file
+	SpecPath string `json:"spec_path"`
+
+	// OutputPath is where the repaired spec was written (the spec itself when fixing in place)
+	OutputPath string `json:"output_path,omitempty"`
+
+	// Changes are the applied fixes
+	Changes []spec.RepairChange `json:"changes"`
+
+	// Error explains why the spec could not be repaired
+	Error string `json:"error,omitempty"`
+}
+
+// Err returns an error reporting how many specs could not be repaired, or nil
+func (s *RepairSummary) Err() error {
+	failed := 0
+	for _, result := range s.Specs {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to repair %d/%d specs", failed, s.TotalSpecs)
+	}
+	return nil
+}
+
+// Format renders the changes of each spec as a diff of the changed values, followed by
+// the overall result
+func (s *RepairSummary) Format() string {
+	var b strings.Builder
+	changed, changes := 0, 0
+	for _, result := range s.Specs {
+		if result.Error != "" {
+			fmt.Fprintf(&b, "❌ %s: %s\n", result.Service, result.Error)
+			continue
+		}
+		changed++
+		changes += len(result.Changes)
+
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", result.SpecPath, result.OutputPath)
+		for _, change := range result.Changes {
+			if change.Old != "" {
+				fmt.Fprintf(&b, "- %s: %q\n", change.Pointer, change.Old)
+			}
+			fmt.Fprintf(&b, "+ %s: %q  (%s)\n", change.Pointer, change.New, change.Reason)
+		}
+	}
+	if err := s.Err(); err != nil {
+		fmt.Fprintf(&b, "%v\n", err)
+	}
+	fmt.Fprintf(&b, "Repaired %d/%d specs with %d changes\n", changed, s.TotalSpecs, changes)
+	return b.String()
+}
+
+// RepairOpenAPISpecs applies the conservative fixes of spec.Repair to every discovered spec.
+// Repaired specs are written next to the original as <name>.repaired.<ext>, or over the
+// original with inPlace. Specs that need no fixes are left alone. All specs are processed
+// even if some fail; the error only reports failures to discover specs.
+func RepairOpenAPISpecs(cfg config.Config, inPlace bool) (*RepairSummary, error) {
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &RepairSummary{TotalSpecs: len(specs), InPlace: inPlace}
+	for _, specPath := range specs {
+		result := SpecRepair{
+			Service:  normalizeServiceName(filepath.Base(filepath.Dir(specPath))),
+			SpecPath: specPath,
+		}
+		result.OutputPath, result.Changes, err = repairSpecFile(specPath, inPlace)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if len(result.Changes) > 0 || result.Error != "" {
+			summary.Specs = append(summary.Specs, result)
+		}
+	}
+	return summary, nil
+}
+
+// repairSpecFile repairs a spec and writes the result, returning where it was written
+// ("" if the spec needed no fixes) and the applied fixes
+func repairSpecFile(specPath string, inPlace bool) (string, []spec.RepairChange, error) {
+	info, err := os.Stat(specPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	ext := filepath.Ext(specPath)
+	repaired, changes, err := spec.Repair(data, ext)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(changes) == 0 {
+		return "", nil, nil
+	}
+
+	outputPath := specPath
+	if !inPlace {
+		outputPath = strings.TrimSuffix(specPath, ext) + repairedSuffix + ext
+	}
+	if err := writeFileWithRetry(outputPath, repaired, info.Mode().Perm()); err != nil {
+		return "", nil, fmt.Errorf("failed to write repaired spec: %w", err)
+	}
+	return outputPath, changes, nil
+}
@@ -0,0 +1,92 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+func TestRepairOpenAPISpecs(t *testing.T) {
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	brokenPath := filepath.Join(specsDir, "funding-server-sdk", "openapi.yaml")
+	broken := "openapi: \"3.0\"\npaths:\n  /withdrawals:\n    get:\n      summary: List withdrawals\n"
+	if err := os.MkdirAll(filepath.Dir(brokenPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(brokenPath, []byte(broken), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	validPath := writeLockTestSpec(t, specsDir, "holidays-server-sdk",
+		`{"openapi":"3.0.3","info":{"version":"1.0.0"},"paths":{"/holidays":{"get":{"operationId":"listHolidays"}}}}`)
+	writeLockTestSpec(t, specsDir, "payments-server-sdk", `{"openapi": `)
+	cfg := config.Config{SpecsDir: specsDir}
+
+	summary, err := RepairOpenAPISpecs(cfg, false)
+	if err != nil {
+		t.Fatalf("RepairOpenAPISpecs() error = %v", err)
+	}
+	if summary.TotalSpecs != 3 || len(summary.Specs) != 2 {
+		t.Fatalf("summary = %+v, want 3 specs with funding repaired and payments failed", summary)
+	}
+
+	funding := summary.Specs[0]
+	copyPath := filepath.Join(specsDir, "funding-server-sdk", "openapi.repaired.yaml")
+	if funding.Service != "funding" || funding.OutputPath != copyPath || len(funding.Changes) != 3 {
+		t.Errorf("funding = %+v, want 3 changes written to %s", funding, copyPath)
+	}
+	if data, _ := os.ReadFile(brokenPath); string(data) != broken {
+		t.Error("without in-place, the original spec must be left unchanged")
+	}
+	repaired, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatalf("Failed to read repaired copy: %v", err)
+	}
+	for _, want := range []string{`openapi: "3.0.0"`, "version: 0.0.0", "operationId: getWithdrawals"} {
+		if !strings.Contains(string(repaired), want) {
+			t.Errorf("repaired copy should contain %q:\n%s", want, repaired)
+		}
+	}
+
+	if payments := summary.Specs[1]; payments.Service != "payments" || payments.Error == "" {
+		t.Errorf("payments = %+v, want an error", payments)
+	}
+	if err := summary.Err(); err == nil || !strings.Contains(err.Error(), "1/3") {
+		t.Errorf("Err() = %v, want 1/3 specs failed", err)
+	}
+
+	text := summary.Format()
+	for _, want := range []string{
+		"--- " + brokenPath + "\n+++ " + copyPath,
+		`- /openapi: "3.0"`,
+		`+ /openapi: "3.0.0"`,
+		`+ /info/version: "0.0.0"`,
+		`+ /paths/~1withdrawals/get/operationId: "getWithdrawals"  (missing operationId derived from GET /withdrawals)`,
+		"Repaired 1/3 specs with 3 changes",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Format() should contain %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, validPath) {
+		t.Errorf("Format() should not list the valid spec:\n%s", text)
+	}
+
+	// In place, the spec itself is fixed, and a second pass finds nothing to do
+	if err := os.Remove(filepath.Join(specsDir, "payments-server-sdk", "openapi.json")); err != nil {
+		t.Fatalf("Failed to remove spec: %v", err)
+	}
+	summary, err = RepairOpenAPISpecs(cfg, true)
+	if err != nil || len(summary.Specs) != 1 || summary.Specs[0].OutputPath != brokenPath {
+		t.Fatalf("in-place RepairOpenAPISpecs() = %+v, %v", summary, err)
+	}
+	if data, _ := os.ReadFile(brokenPath); string(data) != string(repaired) {
+		t.Errorf("in-place repair = %q, want the same content as the copy", data)
+	}
+	summary, err = RepairOpenAPISpecs(cfg, true)
+	if err != nil || len(summary.Specs) != 0 || summary.Err() != nil {
+		t.Errorf("second RepairOpenAPISpecs() = %+v, %v; want no changes", summary, err)
+	}
+}
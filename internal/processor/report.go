@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// RunReport gives programmatic access to the outcome of a run, for library use.
+// It mirrors what is logged and written to side-effect files such as .openapi-metrics.json.
+type RunReport struct {
+	// Specs are the spec paths discovered for this run
+	Specs []string
+
+	// Result contains generation results (nil if the run failed before generation)
+	Result *ProcessingResult
+
+	// ValidationIssues are the validation issues found per service name
+	ValidationIssues map[string][]validation.Issue
+
+	// SurfaceDelta aggregates how the operations of each service changed since the bundle
+	// shipped by the previous run (only services with bundle_spec_file output are compared)
+	SurfaceDelta SurfaceDelta
+
+	// BaselineChanges are the changes of each spec against baseline_fingerprints, sorted by
+	// service (only specs whose operations or components changed)
+	BaselineChanges []*Changelog
+
+	// Metrics is a snapshot of the collected metrics, as exported to MetricsPath
+	Metrics metrics.Metrics
+
+	// MetricsPath is the path of the exported metrics file (empty if export failed)
+	MetricsPath string
+}
+
+// validationRecorder collects validation issues from concurrent spec processing
+type validationRecorder struct {
+	mu     sync.Mutex
+	issues map[string][]validation.Issue
+}
+
+// newValidationRecorder creates an empty validation recorder
+func newValidationRecorder() *validationRecorder {
+	return &validationRecorder{
+		issues: make(map[string][]validation.Issue),
+	}
+}
+
+// Record stores the validation issues for a service
+func (r *validationRecorder) Record(serviceName string, issues []validation.Issue) {
+	if r == nil || len(issues) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.issues[serviceName] = append(r.issues[serviceName], issues...)
+}
+
+// Issues returns a copy of the recorded issues
+func (r *validationRecorder) Issues() map[string][]validation.Issue {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	issues := make(map[string][]validation.Issue, len(r.issues))
+	for service, serviceIssues := range r.issues {
+		issues[service] = append([]validation.Issue(nil), serviceIssues...)
+	}
+	return issues
+}
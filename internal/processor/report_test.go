@@ -0,0 +1,203 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+func TestProcessOpenAPISpecsWithResult(t *testing.T) {
+	useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	for _, svc := range []string{"funding-server-sdk", "holidays-server-sdk"} {
+		svcDir := filepath.Join(specsDir, svc)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create spec dir: %v", err)
+		}
+		spec := `{"openapi":"3.0.0","components":{"schemas":{"Age":{"type":"integer","example":"old"}}}}`
+		if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(spec), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	cfg := config.Config{
+		SpecsDir:        specsDir,
+		OutputDir:       filepath.Join(tmpDir, "output"),
+		WorkerCount:     2,
+		ValidationRules: []string{validation.ExamplesRuleName},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	report, err := ProcessOpenAPISpecsWithResult(ctx, cfg)
+	if err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+
+	if len(report.Specs) != 2 {
+		t.Errorf("report.Specs = %v, want 2 specs", report.Specs)
+	}
+	if report.Result == nil || report.Result.SuccessCount != 2 {
+		t.Fatalf("report.Result = %+v, want 2 successes", report.Result)
+	}
+	for _, service := range []string{"funding", "holidays"} {
+		if len(report.ValidationIssues[service]) != 1 {
+			t.Errorf("ValidationIssues[%q] = %v, want 1 issue", service, report.ValidationIssues[service])
+		}
+	}
+
+	// The report must match the exported metrics file
+	if report.MetricsPath == "" {
+		t.Fatal("report.MetricsPath is empty")
+	}
+	data, err := os.ReadFile(report.MetricsPath)
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	var exported metrics.Metrics
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to parse metrics file: %v", err)
+	}
+
+	if exported.TotalSpecs != report.Metrics.TotalSpecs {
+		t.Errorf("exported TotalSpecs = %d, report = %d", exported.TotalSpecs, report.Metrics.TotalSpecs)
+	}
+	if exported.SuccessfulSpecs != report.Metrics.SuccessfulSpecs {
+		t.Errorf("exported SuccessfulSpecs = %d, report = %d", exported.SuccessfulSpecs, report.Metrics.SuccessfulSpecs)
+	}
+	if exported.SuccessfulSpecs != report.Result.SuccessCount {
+		t.Errorf("exported SuccessfulSpecs = %d, result SuccessCount = %d", exported.SuccessfulSpecs, report.Result.SuccessCount)
+	}
+	if len(exported.SpecMetrics) != len(report.Metrics.SpecMetrics) {
+		t.Errorf("exported %d spec metrics, report has %d", len(exported.SpecMetrics), len(report.Metrics.SpecMetrics))
+	}
+}
+
+func TestProcessOpenAPISpecsWithResultParseCounts(t *testing.T) {
+	useRecordingGenerator(t)
+
+	// Start from an empty parsed-spec cache
+	spec.SetParseCacheSize(0)
+	t.Cleanup(func() { spec.SetParseCacheSize(spec.DefaultParseCacheSize) })
+
+	// Identical content in two services is parsed once, then reused by every later read
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	for _, svc := range []string{"funding-server-sdk", "holidays-server-sdk"} {
+		svcDir := filepath.Join(specsDir, svc)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create spec dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	cfg := config.Config{
+		SpecsDir:        specsDir,
+		OutputDir:       filepath.Join(tmpDir, "output"),
+		WorkerCount:     1,
+		ParseCacheSize:  spec.DefaultParseCacheSize,
+		ValidationRules: []string{validation.ExamplesRuleName},
+	}
+
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+
+	// Each spec is read once per task, so the second service reuses the first one's parse
+	if report.Metrics.ParsesExecuted != 1 {
+		t.Errorf("ParsesExecuted = %d, want 1", report.Metrics.ParsesExecuted)
+	}
+	if report.Metrics.ParsesReused != 1 {
+		t.Errorf("ParsesReused = %d, want 1", report.Metrics.ParsesReused)
+	}
+
+	data, err := os.ReadFile(report.MetricsPath)
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	var exported metrics.Metrics
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to parse metrics file: %v", err)
+	}
+	if exported.ParsesReused != report.Metrics.ParsesReused || exported.ParsesExecuted != report.Metrics.ParsesExecuted {
+		t.Errorf("exported parses = %d/%d, report = %d/%d", exported.ParsesReused, exported.ParsesExecuted,
+			report.Metrics.ParsesReused, report.Metrics.ParsesExecuted)
+	}
+}
+
+func TestProcessOpenAPISpecsWithResultError(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.Config{
+		SpecsDir:  filepath.Join(tmpDir, "missing"),
+		OutputDir: filepath.Join(tmpDir, "output"),
+	}
+
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("ProcessOpenAPISpecsWithResult() expected error for missing specs")
+	}
+	if report == nil {
+		t.Fatal("report should be non-nil even on error")
+	}
+	if report.Result != nil {
+		t.Errorf("report.Result = %+v, want nil when discovery fails", report.Result)
+	}
+}
+
+func TestProcessOpenAPISpecsMetricsPath(t *testing.T) {
+	useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	metricsPath := filepath.Join(tmpDir, "artifacts", "ci", "generation-metrics.json")
+	cfg := config.Config{
+		SpecsDir:    filepath.Join(tmpDir, "specs"),
+		OutputDir:   filepath.Join(tmpDir, "output"),
+		WorkerCount: 1,
+		MetricsPath: metricsPath,
+	}
+
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+	if report.MetricsPath != metricsPath {
+		t.Errorf("report.MetricsPath = %q, want %q", report.MetricsPath, metricsPath)
+	}
+
+	data, err := os.ReadFile(metricsPath)
+	if err != nil {
+		t.Fatalf("metrics should be written to the configured path: %v", err)
+	}
+	var exported metrics.Metrics
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to parse metrics file: %v", err)
+	}
+	if exported.TotalSpecs != 1 {
+		t.Errorf("exported TotalSpecs = %d, want 1", exported.TotalSpecs)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputDir, config.DefaultMetricsFileName)); !os.IsNotExist(err) {
+		t.Errorf("metrics should not also be written to the output directory")
+	}
+}
@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/cache"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// specSnapshot is a spec parsed once at the start of a task and shared by the cache check,
+// metrics, validation and post-processing, so each stage doesn't parse the spec again.
+// The document is read-only; stages that modify a spec must copy it first.
+type specSnapshot struct {
+	path           string
+	doc            map[string]interface{} // nil if the spec could not be parsed
+	operationCount int
+	cacheInfo      cache.SpecInfo
+
+	// bundled reports whether doc is the spec with its external $refs inlined; bundledPath is
+	// where prepareSpec wrote it, once it has
+	bundled     bool
+	bundledPath string
+}
+
+// takeSpecSnapshot parses a spec, or takes the document parsed when scanning the dependency
+// graph (graph may be nil), and describes it for the cache (specCache may be nil) along with
+// the hashes of the files it $refs. A spec with external $refs is bundled first, so its
+// fingerprint and operation count cover the referenced files. A spec that cannot be parsed or
+// bundled gets an empty or unbundled snapshot; the pipeline reports the error when it
+// prepares the spec itself.
+func takeSpecSnapshot(specPath string, specCache *cache.Cache, graph *dependencyGraph) *specSnapshot {
+	snapshot := &specSnapshot{path: specPath}
+	doc := graph.takeDocument(specPath)
+	if doc == nil {
+		doc, _ = spec.LoadDocument(specPath)
+	}
+	if doc != nil && spec.HasExternalRefs(doc) {
+		if bundled, err := spec.BundleDocument(spec.RefLocation{Path: specPath, Root: doc}); err == nil {
+			doc = bundled
+			snapshot.bundled = true
+		}
+	}
+	if doc != nil {
+		snapshot.doc = doc
+		snapshot.operationCount = spec.CountOperations(doc)
+	}
+	if specCache != nil {
+		snapshot.cacheInfo = specCache.Describe(snapshot.doc)
+		snapshot.cacheInfo.Dependencies = graph.DependencyHashes(specPath)
+	}
+	return snapshot
+}
+
+// operationDropWarning is the fraction of operations a spec can lose before a warning is
+// logged, when fail_on_operation_drop does not fail it
+const operationDropWarning = 0.5
+
+// checkOperationDrop compares the snapshot's operation count with the count cached when the
+// client was last generated. A drop over maxDrop fails the spec before generation replaces
+// the client; a drop over half the operations is logged otherwise.
+func checkOperationDrop(specCache *cache.Cache, snapshot *specSnapshot, maxDrop float64) error {
+	// Specs that cannot be parsed fail on their own; their count of 0 is meaningless
+	if specCache == nil || snapshot.doc == nil {
+		return nil
+	}
+
+	if err := specCache.CheckOperationCount(snapshot.path, snapshot.operationCount, maxDrop); err != nil {
+		return fmt.Errorf("operation count regression: %w (raise fail_on_operation_drop if this is intended)", err)
+	}
+	if err := specCache.CheckOperationCount(snapshot.path, snapshot.operationCount, operationDropWarning); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return nil
+}
+
+// documentFor returns the parsed document if specPath is the snapshotted spec (or its bundle),
+// or nil if the spec was rewritten (transcoded, preprocessed, filtered) and must be loaded again.
+// The document of a bundled spec is only returned for its bundle, since the spec file itself
+// still has external refs.
+func (s *specSnapshot) documentFor(specPath string) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	if s.bundled {
+		if s.bundledPath == "" || specPath != s.bundledPath {
+			return nil
+		}
+		return s.doc
+	}
+	if specPath != s.path {
+		return nil
+	}
+	return s.doc
+}
@@ -0,0 +1,293 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+func TestTakeSpecSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	content := `openapi: 3.0.0
+paths:
+  /users:
+    get: {operationId: listUsers}
+    post: {operationId: createUser}
+  /health:
+    get: {operationId: health}
+`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	snapshot := takeSpecSnapshot(specPath, nil, nil)
+	if snapshot.operationCount != 3 {
+		t.Errorf("operationCount = %d, want 3", snapshot.operationCount)
+	}
+	if snapshot.documentFor(specPath) == nil {
+		t.Error("documentFor() should return the document for the snapshotted spec")
+	}
+	if snapshot.documentFor(filepath.Join(dir, "filtered.json")) != nil {
+		t.Error("documentFor() should return nil for a rewritten spec")
+	}
+
+	missing := takeSpecSnapshot(filepath.Join(dir, "missing.json"), nil, nil)
+	if missing.operationCount != 0 || missing.doc != nil {
+		t.Errorf("snapshot of missing spec = %+v, want empty", missing)
+	}
+
+	var none *specSnapshot
+	if none.documentFor(specPath) != nil {
+		t.Error("documentFor() on a nil snapshot should return nil")
+	}
+}
+
+func TestSpecParsedOncePerTask(t *testing.T) {
+	useRecordingGenerator(t)
+
+	// Disable the parsed-spec cache so every read of a spec counts as a parse
+	spec.SetParseCacheSize(0)
+	t.Cleanup(func() { spec.SetParseCacheSize(spec.DefaultParseCacheSize) })
+
+	const specCount = 2
+	for _, workerCount := range []int{1, specCount} {
+		t.Run(fmt.Sprintf("workers=%d", workerCount), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			specsDir := filepath.Join(tmpDir, "specs")
+			for i := 0; i < specCount; i++ {
+				svcDir := filepath.Join(specsDir, fmt.Sprintf("svc%d-server-sdk", i))
+				if err := os.MkdirAll(svcDir, 0755); err != nil {
+					t.Fatalf("Failed to create spec dir: %v", err)
+				}
+				// Distinct content per service, with security so detection has something to find
+				content := fmt.Sprintf(`{"openapi":"3.0.0","info":{"title":"svc%d","version":"1.0.0"},`+
+					`"security":[{"key":[]}],"paths":{"/ping":{"get":{"operationId":"ping",`+
+					`"responses":{"404":{"description":"Not found"}}}}}}`, i)
+				if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write spec: %v", err)
+				}
+			}
+
+			cfg := config.Config{
+				SpecsDir:           specsDir,
+				OutputDir:          filepath.Join(tmpDir, "output"),
+				WorkerCount:        workerCount,
+				EnableCache:        true,
+				CacheDir:           filepath.Join(tmpDir, "cache"),
+				ValidationRules:    []string{validation.ExamplesRuleName},
+				GenerateErrorTypes: true,
+			}
+
+			// The first run generates every client, the second is served from the cache;
+			// either way each spec is parsed once
+			for run := 1; run <= 2; run++ {
+				report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+				if err != nil {
+					t.Fatalf("run %d: ProcessOpenAPISpecsWithResult() error = %v", run, err)
+				}
+				if report.Metrics.ParsesExecuted != specCount {
+					t.Errorf("run %d: ParsesExecuted = %d, want %d (one per spec)", run, report.Metrics.ParsesExecuted, specCount)
+				}
+			}
+		})
+	}
+}
+
+func TestOperationDropFailsSpec(t *testing.T) {
+	gen := useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.yaml")
+	writeOperations := func(count int) {
+		content := "openapi: 3.0.0\npaths:\n"
+		for i := 0; i < count; i++ {
+			content += fmt.Sprintf("  /op%d:\n    get: {operationId: op%d}\n", i, i)
+		}
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	cfg := config.Config{
+		SpecsDir:            filepath.Join(tmpDir, "specs"),
+		OutputDir:           filepath.Join(tmpDir, "output"),
+		WorkerCount:         1,
+		EnableCache:         true,
+		CacheDir:            filepath.Join(tmpDir, "cache"),
+		FailOnOperationDrop: 0.5,
+	}
+
+	writeOperations(8)
+	if _, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg); err != nil {
+		t.Fatalf("first run error = %v", err)
+	}
+
+	// Losing 6 of 8 operations exceeds the limit: the spec fails before the generator runs
+	writeOperations(2)
+	gen.specContent = ""
+	_, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err == nil || !contains(err.Error(), "operation count regression") {
+		t.Fatalf("run after the drop error = %v, want an operation count regression", err)
+	}
+	if gen.specContent != "" {
+		t.Error("the generator should not run for a spec that failed the operation drop check")
+	}
+
+	// Losing 4 of 8 is within the limit
+	writeOperations(4)
+	if _, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg); err != nil {
+		t.Fatalf("run within the limit error = %v", err)
+	}
+	if !contains(gen.specContent, "/op3:") {
+		t.Error("the spec within the limit should be generated")
+	}
+}
+
+func TestTraceCacheLogsDecisions(t *testing.T) {
+	useRecordingGenerator(t)
+
+	var logs bytes.Buffer
+	previousOutput := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(previousOutput) })
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.0\npaths:\n  /a:\n    get: {operationId: a}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := config.Config{
+		SpecsDir:    filepath.Join(tmpDir, "specs"),
+		OutputDir:   filepath.Join(tmpDir, "output"),
+		WorkerCount: 1,
+		EnableCache: true,
+		CacheDir:    filepath.Join(tmpDir, "cache"),
+		TraceCache:  true,
+	}
+	traceLine := func() string {
+		t.Helper()
+		defer logs.Reset()
+		if _, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg); err != nil {
+			t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+		}
+		for _, line := range strings.Split(logs.String(), "\n") {
+			if i := strings.Index(line, "cache-trace "); i >= 0 {
+				return line[i:]
+			}
+		}
+		t.Fatalf("no cache-trace line in logs:\n%s", logs.String())
+		return ""
+	}
+	factors := []string{"spec=", "stored_hash=", "current_hash=", "stored_generator=", "current_generator=", "stored_config=", "current_config=", "fingerprint=", "decision=", "reason="}
+
+	traceLine() // first run: no entry
+
+	// A code-affecting change is a miss, explained by the fingerprint comparison
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.0\npaths:\n  /b:\n    get: {operationId: b}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	miss := traceLine()
+	for _, want := range append(factors, "decision=miss", "reason=spec_changed", "fingerprint=differs:operations", "stored_generator=v0.0.0-test") {
+		if !contains(miss, want) {
+			t.Errorf("miss trace missing %q: %s", want, miss)
+		}
+	}
+
+	hit := traceLine()
+	for _, want := range append(factors, "decision=hit", "reason=hit", "fingerprint=not_compared") {
+		if !contains(hit, want) {
+			t.Errorf("hit trace missing %q: %s", want, hit)
+		}
+	}
+}
+
+func TestCachedClientRestoredFromArchive(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	gen.files = map[string]string{"oas_client_gen.go": "package fundingsdk\n"}
+	tmpDir := t.TempDir()
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk")
+	cfg := config.Config{
+		SpecsDir:       specsDir,
+		OutputDir:      filepath.Join(tmpDir, "output"),
+		WorkerCount:    1,
+		EnableCache:    true,
+		CacheDir:       filepath.Join(tmpDir, "cache"),
+		CacheArtifacts: true,
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+	clientPath := filepath.Join(cfg.OutputDir, "fundingsdk")
+
+	// A clean checkout keeps the cache directory but not the output
+	if err := os.RemoveAll(cfg.OutputDir); err != nil {
+		t.Fatalf("Failed to remove output: %v", err)
+	}
+	gen.specPath = ""
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+	if gen.specPath != "" {
+		t.Error("the client should be restored from its archive, not regenerated")
+	}
+	if data, err := os.ReadFile(filepath.Join(clientPath, "oas_client_gen.go")); err != nil || string(data) != "package fundingsdk\n" {
+		t.Errorf("restored client = %q, %v", data, err)
+	}
+}
+
+func TestEditedOgenConfigRegeneratesCachedClients(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	tmpDir := t.TempDir()
+	ogenConfig := filepath.Join(tmpDir, "ogen.yml")
+	if err := os.WriteFile(ogenConfig, []byte("generator: {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write ogen config: %v", err)
+	}
+	cfg := config.Config{
+		SpecsDir:       writeProgressTestSpecs(t, "funding-server-sdk"),
+		OutputDir:      filepath.Join(tmpDir, "output"),
+		WorkerCount:    1,
+		EnableCache:    true,
+		CacheDir:       filepath.Join(tmpDir, "cache"),
+		OgenConfigPath: ogenConfig,
+	}
+	run := func() bool {
+		t.Helper()
+		gen.specPath = ""
+		if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+			t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+		}
+		return gen.specPath != ""
+	}
+
+	if !run() {
+		t.Fatal("the first run should generate the client")
+	}
+	if run() {
+		t.Error("an unchanged ogen config should serve the client from the cache")
+	}
+	if err := os.WriteFile(ogenConfig, []byte("generator:\n  features:\n    enable: [paths/client]\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit ogen config: %v", err)
+	}
+	if !run() {
+		t.Error("an edited ogen config should regenerate the client")
+	}
+}
@@ -0,0 +1,27 @@
+package processor
+
+import (
+	"context"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+)
+
+// fetchSpecSources downloads the remote specs and checks out the git specs of the target
+// services, returning their paths to add to the specs discovered under specs_dir
+func fetchSpecSources(ctx context.Context, cfg config.Config) ([]string, error) {
+	remoteSpecs, err := fetchRemoteSpecs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	gitSpecs, err := fetchGitSpecs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return append(remoteSpecs, gitSpecs...), nil
+}
+
+// previousSpecSources returns the remote specs and git specs as last downloaded or checked
+// out, without any network access
+func previousSpecSources(cfg config.Config) []string {
+	return append(stagedRemoteSpecs(cfg), checkedOutGitSpecs(cfg)...)
+}
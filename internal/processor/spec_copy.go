@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// specCopyBaseName is the file name, without extension, of the spec copy in a client directory
+const specCopyBaseName = "openapi"
+
+// specCopyFileName returns the name of the spec copy for a source spec: openapi.json for JSON
+// specs and openapi.yaml for YAML specs (.yaml or .yml)
+func specCopyFileName(sourcePath string) string {
+	if strings.EqualFold(filepath.Ext(sourcePath), ".json") {
+		return specCopyBaseName + ".json"
+	}
+	return specCopyBaseName + ".yaml"
+}
+
+// writeSpecCopy copies the source spec into the client directory byte for byte, before
+// transcoding, preprocessing or deprecated-operation filtering, so consumers can trace a
+// client back to the exact spec it was generated from
+func writeSpecCopy(sourcePath, clientPath string) error {
+	data, err := spec.ReadFile(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	copyPath := filepath.Join(clientPath, specCopyFileName(sourcePath))
+	if err := writeFileWithRetry(copyPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write spec copy: %w", err)
+	}
+
+	log.Printf("Spec copied to: %s", copyPath)
+	return nil
+}
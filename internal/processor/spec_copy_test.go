@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+func TestSpecCopyFileName(t *testing.T) {
+	tests := map[string]string{
+		"specs/funding-server-sdk/openapi.json": "openapi.json",
+		"specs/funding-server-sdk/api.JSON":     "openapi.json",
+		"specs/funding-server-sdk/openapi.yaml": "openapi.yaml",
+		"specs/funding-server-sdk/openapi.yml":  "openapi.yaml",
+	}
+	for sourcePath, want := range tests {
+		if got := specCopyFileName(sourcePath); got != want {
+			t.Errorf("specCopyFileName(%q) = %q, want %q", sourcePath, got, want)
+		}
+	}
+}
+
+func TestCopySpecToOutput(t *testing.T) {
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create service directory: %v", err)
+	}
+	specPath := filepath.Join(svcDir, "openapi.yml")
+	// Comments and key order only survive a raw copy
+	original := "# Funding API\nopenapi: 3.0.0\npaths: {}\ninfo: {title: Funding, version: 1.0.0}\n"
+	if err := os.WriteFile(specPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	opts := pipelineOptions{clientsSubdir: config.DefaultClientsSubdir, copySpecToOutput: true, maxOutputBytes: 1}
+	outputDir := filepath.Join(tmpDir, "output")
+	clientPath := filepath.Join(outputDir, "clients", "fundingsdk")
+	copyPath := filepath.Join(clientPath, "openapi.yaml")
+
+	// The copy does not count toward max_output_bytes
+	SetGenerator(&noopGenerator{})
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, nil, metrics.NewCollector(), opts); err != nil {
+		t.Fatalf("generateClientsSequential() error = %v", err)
+	}
+	data, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatalf("expected spec copy: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("spec copy = %q, want the source spec unchanged %q", data, original)
+	}
+
+	// A failed regeneration still cleans the generated files but keeps the copy
+	stale := filepath.Join(clientPath, "oas_client_gen.go")
+	if err := os.WriteFile(stale, []byte("package fundingsdk\n"), 0644); err != nil {
+		t.Fatalf("Failed to write stale file: %v", err)
+	}
+	SetGenerator(&failingGenerator{code: generator.ErrCodeGeneratorFailed})
+	if _, err := generateClientsSequential(context.Background(), []string{specPath}, outputDir, false, nil, metrics.NewCollector(), opts); err == nil {
+		t.Fatal("generateClientsSequential() should fail with a failing generator")
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale generated file should be cleaned, stat error = %v", err)
+	}
+	if _, err := os.Stat(copyPath); err != nil {
+		t.Errorf("spec copy should survive cleaning: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// SpecStats summarizes the discovered spec inventory
+type SpecStats struct {
+	// TotalSpecs is the number of discovered specs
+	TotalSpecs int `json:"total_specs"`
+
+	// TotalOperations is the number of operations across all parsed specs
+	TotalOperations int `json:"total_operations"`
+
+	// OperationsByMethod counts operations per HTTP method (e.g., "GET")
+	OperationsByMethod map[string]int `json:"operations_by_method"`
+
+	// SpecsWithSecurity counts specs defining security schemes or requirements
+	SpecsWithSecurity int `json:"specs_with_security"`
+
+	// SpecsWithoutSecurity counts parsed specs without any security
+	SpecsWithoutSecurity int `json:"specs_without_security"`
+
+	// OpenAPIVersions counts specs per declared version (e.g., "3.0.3", "swagger 2.0")
+	OpenAPIVersions map[string]int `json:"openapi_versions"`
+
+	// UnparsedSpecs lists specs that could not be parsed; they are excluded from the other aggregates
+	UnparsedSpecs []string `json:"unparsed_specs,omitempty"`
+}
+
+// AverageOperations returns the average number of operations per parsed spec
+func (s *SpecStats) AverageOperations() float64 {
+	parsed := s.TotalSpecs - len(s.UnparsedSpecs)
+	if parsed <= 0 {
+		return 0
+	}
+	return float64(s.TotalOperations) / float64(parsed)
+}
+
+// CollectSpecStats discovers the configured specs and summarizes them without generating anything.
+// Remote and git specs are included as last fetched.
+func CollectSpecStats(cfg config.Config) (*SpecStats, error) {
+	spec.SetSpecEncoding(cfg.SpecEncoding)
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks, previousSpecSources(cfg)...)
+	if err != nil {
+		return nil, err
+	}
+	return computeSpecStats(specs), nil
+}
+
+// computeSpecStats parses each spec and aggregates inventory numbers
+func computeSpecStats(specPaths []string) *SpecStats {
+	stats := &SpecStats{
+		TotalSpecs:         len(specPaths),
+		OperationsByMethod: make(map[string]int),
+		OpenAPIVersions:    make(map[string]int),
+	}
+
+	for _, specPath := range specPaths {
+		doc, err := spec.LoadDocument(specPath)
+		if err != nil {
+			stats.UnparsedSpecs = append(stats.UnparsedSpecs, specPath)
+			continue
+		}
+
+		stats.OpenAPIVersions[documentVersion(doc)]++
+
+		hasSecurity := documentHasGlobalSecurity(doc)
+		paths, _ := doc["paths"].(map[string]interface{})
+		for _, rawItem := range paths {
+			item, ok := rawItem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, method := range spec.HTTPMethods {
+				operation, ok := item[method].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				stats.TotalOperations++
+				stats.OperationsByMethod[strings.ToUpper(method)]++
+				if requirements, ok := operation["security"].([]interface{}); ok && len(requirements) > 0 {
+					hasSecurity = true
+				}
+			}
+		}
+
+		if hasSecurity {
+			stats.SpecsWithSecurity++
+		} else {
+			stats.SpecsWithoutSecurity++
+		}
+	}
+
+	return stats
+}
+
+// documentVersion returns the declared OpenAPI version of a spec document
+func documentVersion(doc map[string]interface{}) string {
+	if version, ok := doc["openapi"].(string); ok && version != "" {
+		return version
+	}
+	if version, ok := doc["swagger"].(string); ok && version != "" {
+		return "swagger " + version
+	}
+	return "unknown"
+}
+
+// documentHasGlobalSecurity reports whether a spec has global security requirements or defines security schemes
+func documentHasGlobalSecurity(doc map[string]interface{}) bool {
+	if requirements, ok := doc["security"].([]interface{}); ok && len(requirements) > 0 {
+		return true
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	return len(schemes) > 0
+}
+
+// Format renders the stats as a human-readable report
+func (s *SpecStats) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Specs:                  %d\n", s.TotalSpecs)
+	fmt.Fprintf(&b, "Operations:             %d\n", s.TotalOperations)
+	fmt.Fprintf(&b, "Avg operations/spec:    %.1f\n", s.AverageOperations())
+	fmt.Fprintf(&b, "Specs with security:    %d\n", s.SpecsWithSecurity)
+	fmt.Fprintf(&b, "Specs without security: %d\n", s.SpecsWithoutSecurity)
+
+	b.WriteString("Operations by method:\n")
+	for _, method := range spec.HTTPMethods {
+		if count := s.OperationsByMethod[strings.ToUpper(method)]; count > 0 {
+			fmt.Fprintf(&b, "  %-8s %d\n", strings.ToUpper(method), count)
+		}
+	}
+
+	b.WriteString("OpenAPI versions:\n")
+	versions := make([]string, 0, len(s.OpenAPIVersions))
+	for version := range s.OpenAPIVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		fmt.Fprintf(&b, "  %-12s %d\n", version, s.OpenAPIVersions[version])
+	}
+
+	if len(s.UnparsedSpecs) > 0 {
+		fmt.Fprintf(&b, "Unparsed specs (%d):\n", len(s.UnparsedSpecs))
+		for _, specPath := range s.UnparsedSpecs {
+			fmt.Fprintf(&b, "  - %s\n", specPath)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestComputeSpecStats(t *testing.T) {
+	fixtures := map[string]string{
+		"funding-server-sdk/openapi.json": `{
+			"openapi": "3.0.3",
+			"security": [{"bearerAuth": []}],
+			"components": {"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}}},
+			"paths": {
+				"/withdrawals": {"get": {}, "post": {}},
+				"/withdrawals/{id}": {"parameters": [], "get": {}, "delete": {}}
+			}
+		}`,
+		"holidays-server-sdk/openapi.yaml": `openapi: 3.0.3
+paths:
+  /holidays:
+    get: {}
+`,
+		"admin-server-sdk/openapi.json": `{
+			"openapi": "3.1.0",
+			"paths": {
+				"/admin": {"put": {"security": [{"apiKey": []}]}, "patch": {}}
+			}
+		}`,
+		"broken-server-sdk/openapi.json": `{"openapi": `,
+	}
+
+	dir := t.TempDir()
+	var specs []string
+	for name, content := range fixtures {
+		specPath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+			t.Fatalf("Failed to create spec directory: %v", err)
+		}
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		specs = append(specs, specPath)
+	}
+
+	stats := computeSpecStats(specs)
+
+	if stats.TotalSpecs != 4 {
+		t.Errorf("TotalSpecs = %d, want 4", stats.TotalSpecs)
+	}
+	if stats.TotalOperations != 7 {
+		t.Errorf("TotalOperations = %d, want 7", stats.TotalOperations)
+	}
+	wantMethods := map[string]int{"GET": 3, "POST": 1, "DELETE": 1, "PUT": 1, "PATCH": 1}
+	if !reflect.DeepEqual(stats.OperationsByMethod, wantMethods) {
+		t.Errorf("OperationsByMethod = %v, want %v", stats.OperationsByMethod, wantMethods)
+	}
+	if stats.SpecsWithSecurity != 2 || stats.SpecsWithoutSecurity != 1 {
+		t.Errorf("security = %d with, %d without, want 2 with, 1 without", stats.SpecsWithSecurity, stats.SpecsWithoutSecurity)
+	}
+	wantVersions := map[string]int{"3.0.3": 2, "3.1.0": 1}
+	if !reflect.DeepEqual(stats.OpenAPIVersions, wantVersions) {
+		t.Errorf("OpenAPIVersions = %v, want %v", stats.OpenAPIVersions, wantVersions)
+	}
+	if len(stats.UnparsedSpecs) != 1 || filepath.Base(filepath.Dir(stats.UnparsedSpecs[0])) != "broken-server-sdk" {
+		t.Errorf("UnparsedSpecs = %v, want the broken spec", stats.UnparsedSpecs)
+	}
+	if avg := stats.AverageOperations(); avg < 2.33 || avg > 2.34 {
+		t.Errorf("AverageOperations() = %v, want 7/3", avg)
+	}
+
+	report := stats.Format()
+	for _, want := range []string{"Specs:                  4", "Operations:             7", "  GET      3", "  3.0.3        2"} {
+		if !contains(report, want) {
+			t.Errorf("Format() missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestSpecStatsAverageOperationsEmpty(t *testing.T) {
+	if avg := computeSpecStats(nil).AverageOperations(); avg != 0 {
+		t.Errorf("AverageOperations() = %v, want 0", avg)
+	}
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// SurfaceDelta summarizes how much the API surface changed across all services of a run
+type SurfaceDelta struct {
+	// Services is the number of services compared against their previous version
+	Services int
+
+	// Added, Modified and Deleted count operations across all compared services
+	Added    int
+	Modified int
+	Deleted  int
+
+	// BreakingServices lists the services with breaking changes, sorted
+	BreakingServices []string
+}
+
+// HasChanges reports whether any operation was added, modified or deleted
+func (d SurfaceDelta) HasChanges() bool {
+	return d.Added > 0 || d.Modified > 0 || d.Deleted > 0
+}
+
+// String returns a one-line summary, e.g. "+3 added, ~1 modified, -0 deleted operations across 2 service(s)"
+func (d SurfaceDelta) String() string {
+	return fmt.Sprintf("+%d added, ~%d modified, -%d deleted operations across %d service(s)",
+		d.Added, d.Modified, d.Deleted, d.Services)
+}
+
+// summarizeSurface aggregates per-service comparisons into a run-level delta
+func summarizeSurface(comparisons map[string]*spec.Comparison) SurfaceDelta {
+	delta := SurfaceDelta{Services: len(comparisons)}
+	for service, comparison := range comparisons {
+		delta.Added += len(comparison.Added)
+		delta.Modified += len(comparison.Modified)
+		delta.Deleted += len(comparison.Deleted)
+		if len(comparison.Breaking()) > 0 {
+			delta.BreakingServices = append(delta.BreakingServices, service)
+		}
+	}
+	sort.Strings(delta.BreakingServices)
+	return delta
+}
+
+// logSurfaceDelta logs the run-level API surface delta, if any service was compared
+func logSurfaceDelta(delta SurfaceDelta) {
+	if delta.Services == 0 {
+		return
+	}
+	log.Printf("API surface delta: %s", delta)
+	if len(delta.BreakingServices) > 0 {
+		log.Printf("Breaking changes in: %s", strings.Join(delta.BreakingServices, ", "))
+	}
+}
+
+// surfaceRecorder collects per-service comparisons from concurrent spec processing
+type surfaceRecorder struct {
+	mu          sync.Mutex
+	comparisons map[string]*spec.Comparison
+}
+
+// newSurfaceRecorder creates an empty surface recorder
+func newSurfaceRecorder() *surfaceRecorder {
+	return &surfaceRecorder{
+		comparisons: make(map[string]*spec.Comparison),
+	}
+}
+
+// Record stores the comparison of a service with its previous version
+func (r *surfaceRecorder) Record(serviceName string, comparison *spec.Comparison) {
+	if r == nil || comparison == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.comparisons[serviceName] = comparison
+}
+
+// Delta returns the aggregated delta of the recorded comparisons
+func (r *surfaceRecorder) Delta() SurfaceDelta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return summarizeSurface(r.comparisons)
+}
+
+// loadPreviousBundle reads the bundled spec a previous run shipped with the client, before the
+// client directory is cleaned. Returns nil if there is none or it cannot be read.
+func loadPreviousBundle(bundlePath string) map[string]interface{} {
+	if _, err := os.Stat(bundlePath); err != nil {
+		return nil
+	}
+	doc, err := spec.LoadDocument(bundlePath)
+	if err != nil {
+		log.Printf("Warning: Failed to load previous bundled spec %s, skipping API surface comparison: %v", bundlePath, err)
+		return nil
+	}
+	return doc
+}
@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+func TestSummarizeSurface(t *testing.T) {
+	comparisons := map[string]*spec.Comparison{
+		"funding": {
+			Added:    []string{"GET /funds", "POST /funds"},
+			Deleted:  []string{"DELETE /funds/{id}"},
+			Modified: []spec.OperationChange{{Operation: "GET /funds/{id}"}},
+		},
+		"holidays": {
+			Added: []string{"GET /holidays"},
+			Modified: []spec.OperationChange{
+				{Operation: "GET /holidays/{id}", BreakingReasons: []string{"parameter `query:year` is now required"}},
+				{Operation: "PUT /holidays/{id}"},
+			},
+		},
+		"accounts": {
+			ChangedComponents: []string{"schemas/Account"},
+		},
+	}
+
+	delta := summarizeSurface(comparisons)
+
+	want := SurfaceDelta{
+		Services:         3,
+		Added:            3,
+		Modified:         3,
+		Deleted:          1,
+		BreakingServices: []string{"funding", "holidays"},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Errorf("summarizeSurface() = %+v, want %+v", delta, want)
+	}
+	if got := delta.String(); got != "+3 added, ~3 modified, -1 deleted operations across 3 service(s)" {
+		t.Errorf("String() = %q", got)
+	}
+	if !delta.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+	if (SurfaceDelta{Services: 1}).HasChanges() {
+		t.Error("HasChanges() = true for a delta without operation changes")
+	}
+}
+
+func TestSurfaceRecorder(t *testing.T) {
+	var nilRecorder *surfaceRecorder
+	nilRecorder.Record("funding", &spec.Comparison{})
+
+	recorder := newSurfaceRecorder()
+	recorder.Record("funding", &spec.Comparison{Added: []string{"GET /funds"}})
+	recorder.Record("holidays", nil)
+
+	delta := recorder.Delta()
+	if delta.Services != 1 || delta.Added != 1 {
+		t.Errorf("Delta() = %+v, want one service with one added operation", delta)
+	}
+}
+
+func TestProcessOpenAPISpecsWithResultSurfaceDelta(t *testing.T) {
+	useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	writeSpecs := func(specs map[string]string) {
+		t.Helper()
+		for service, content := range specs {
+			dir := filepath.Join(specsDir, service)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("Failed to create spec dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "openapi.json"), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+		}
+	}
+
+	writeSpecs(map[string]string{
+		"funding-server-sdk": `{"openapi":"3.0.0","paths":{
+			"/funds":{"get":{"responses":{"200":{"description":"OK"}}}},
+			"/funds/{id}":{"delete":{"responses":{"204":{"description":"Deleted"}}}}}}`,
+		"holidays-server-sdk": `{"openapi":"3.0.0","paths":{
+			"/holidays":{"get":{"responses":{"200":{"description":"OK"}}}}}}`,
+	})
+
+	cfg := config.Config{
+		SpecsDir:       specsDir,
+		OutputDir:      filepath.Join(tmpDir, "output"),
+		WorkerCount:    1,
+		BundleSpecFile: "bundled-openapi.json",
+	}
+
+	// Without previous bundles there is nothing to compare
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("first run error = %v", err)
+	}
+	if report.SurfaceDelta.Services != 0 {
+		t.Errorf("first run SurfaceDelta = %+v, want no compared services", report.SurfaceDelta)
+	}
+
+	// funding drops an operation (breaking); holidays gains one
+	writeSpecs(map[string]string{
+		"funding-server-sdk": `{"openapi":"3.0.0","paths":{
+			"/funds":{"get":{"responses":{"200":{"description":"OK"}}}}}}`,
+		"holidays-server-sdk": `{"openapi":"3.0.0","paths":{
+			"/holidays":{"get":{"responses":{"200":{"description":"OK"}}},
+				"post":{"responses":{"201":{"description":"Created"}}}}}}`,
+	})
+
+	report, err = ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("second run error = %v", err)
+	}
+	want := SurfaceDelta{Services: 2, Added: 1, Deleted: 1, BreakingServices: []string{"funding"}}
+	if !reflect.DeepEqual(report.SurfaceDelta, want) {
+		t.Errorf("SurfaceDelta = %+v, want %+v", report.SurfaceDelta, want)
+	}
+}
@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// convertSwagger2 writes a Swagger 2.0 spec converted to OpenAPI 3.0 to a temporary file and
+// returns its path, with a cleanup removing it. Other specs are returned as is. parsed is the
+// spec's already decoded document, if any; it is not modified.
+func convertSwagger2(specPath, serviceName string, parsed map[string]interface{}) (string, func(), error) {
+	noop := func() {}
+
+	doc := parsed
+	if doc == nil {
+		var err error
+		if doc, err = spec.LoadDocument(specPath); err != nil {
+			return "", noop, fmt.Errorf("failed to load spec for %s: %w", serviceName, err)
+		}
+	}
+	if !spec.IsSwagger2(doc) {
+		return specPath, noop, nil
+	}
+
+	converted, notes := spec.ConvertSwagger2(doc)
+	log.Printf("Converted %s from Swagger 2.0 to OpenAPI %s", serviceName, spec.OpenAPI30Version)
+	for _, note := range notes {
+		log.Printf("Warning: %s: %s", serviceName, note)
+	}
+
+	data, err := json.MarshalIndent(converted, "", "  ")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to encode spec for %s: %w", serviceName, err)
+	}
+
+	// The converted spec is always JSON, whatever the original format
+	return writeTempSpec("openapi-swagger2-", "openapi.json", data)
+}
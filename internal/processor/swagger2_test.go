@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateClientForSpecConvertsSwagger2(t *testing.T) {
+	swagger := `{"swagger":"2.0","info":{"title":"Pets","version":"1.0"},"host":"pets.example.com",
+		"paths":{"/pets":{"get":{"operationId":"listPets","deprecated":true,
+		"responses":{"200":{"description":"ok","schema":{"$ref":"#/definitions/Pet"}}}}}},
+		"definitions":{"Pet":{"type":"object"}}}`
+
+	tests := []struct {
+		name     string
+		convert  bool
+		wantText string
+	}{
+		{name: "converted to OpenAPI 3.0", convert: true, wantText: `"$ref": "#/components/schemas/Pet"`},
+		{name: "passed through by default", convert: false, wantText: `"swagger":"2.0"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := useRecordingGenerator(t)
+
+			tmpDir := t.TempDir()
+			specPath := filepath.Join(tmpDir, "pets-sdk", "swagger.json")
+			if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+				t.Fatalf("Failed to create spec dir: %v", err)
+			}
+			if err := os.WriteFile(specPath, []byte(swagger), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			opts := pipelineOptions{convertSwagger2: tt.convert}
+			if err := generateClientForSpec(ctx, specPath, "pets", "petssdk", filepath.Join(tmpDir, "output"), nil, opts); err != nil {
+				t.Fatalf("generateClientForSpec() error = %v", err)
+			}
+			if !contains(gen.specContent, tt.wantText) {
+				t.Errorf("generated spec should contain %s:\n%s", tt.wantText, gen.specContent)
+			}
+		})
+	}
+
+	// Later stages see the converted spec: the only operation is deprecated and gets excluded
+	gen := useRecordingGenerator(t)
+	specPath := filepath.Join(t.TempDir(), "pets-sdk", "swagger.json")
+	if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(specPath, []byte(swagger), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	opts := pipelineOptions{convertSwagger2: true, excludeDeprecated: true}
+	if err := generateClientForSpec(context.Background(), specPath, "pets", "petssdk", t.TempDir(), nil, opts); err != nil {
+		t.Fatalf("generateClientForSpec() error = %v", err)
+	}
+	if contains(gen.specContent, "listPets") || !contains(gen.specContent, `"openapi": "3.0.3"`) {
+		t.Errorf("generated spec should be converted and filtered:\n%s", gen.specContent)
+	}
+}
@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"context"
+	"log"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/tracing"
+)
+
+// Span names of a run's trace: the run, each spec, and the stages of a generated spec
+const (
+	spanRun         = "openapi-go.run"
+	spanSpec        = "openapi-go.spec"
+	spanValidate    = "validate"
+	spanGenerate    = "generate"
+	spanPostProcess = "post-process"
+)
+
+// Span attributes
+const (
+	attrSpecCount = "openapi.spec_count"
+	attrService   = "openapi.service"
+	attrSpecPath  = "openapi.spec_path"
+	attrOutcome   = "openapi.outcome"
+)
+
+// newRunTracer returns the tracer of a run: the exporter from Options if given, otherwise
+// an OTLP exporter for otel_endpoint. Returns nil (tracing disabled) when neither is set.
+func newRunTracer(cfg config.Config, exporter tracing.Exporter) *tracing.Tracer {
+	if exporter == nil {
+		if cfg.OTelEndpoint == "" {
+			return nil
+		}
+		exporter = tracing.NewOTLPExporter(cfg.OTelEndpoint)
+	}
+	return tracing.NewTracer(exporter)
+}
+
+// specSpanAttributes returns the attributes of a spec span
+func specSpanAttributes(specPath, serviceName string) map[string]string {
+	return map[string]string{attrService: serviceName, attrSpecPath: specPath}
+}
+
+// flushTrace exports the run's spans. Failures are logged, never failing the run.
+func flushTrace(tracer *tracing.Tracer) {
+	if tracer == nil {
+		return
+	}
+	if err := tracer.Flush(context.Background()); err != nil {
+		log.Printf("Warning: Failed to export trace: %v", err)
+		return
+	}
+	log.Printf("Trace exported")
+}
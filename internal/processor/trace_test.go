@@ -0,0 +1,176 @@
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/tracing"
+)
+
+// traceTree indexes exported spans for asserting a run's span hierarchy
+type traceTree struct {
+	run    tracing.SpanData
+	specs  map[string]tracing.SpanData   // by service
+	stages map[string][]tracing.SpanData // stage spans by the service of their parent
+}
+
+func newTraceTree(t *testing.T, spans []tracing.SpanData) *traceTree {
+	t.Helper()
+	tree := &traceTree{specs: map[string]tracing.SpanData{}, stages: map[string][]tracing.SpanData{}}
+
+	runs := 0
+	for _, span := range spans {
+		switch span.Name {
+		case spanRun:
+			tree.run = span
+			runs++
+		case spanSpec:
+			tree.specs[span.Attributes[attrService]] = span
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("trace has %d run spans, want 1", runs)
+	}
+
+	for _, span := range spans {
+		if span.TraceID != tree.run.TraceID {
+			t.Errorf("span %s belongs to another trace", span.Name)
+		}
+		if span.Name == spanRun || span.Name == spanSpec {
+			continue
+		}
+		found := false
+		for service, spec := range tree.specs {
+			if span.ParentID == spec.SpanID {
+				tree.stages[service] = append(tree.stages[service], span)
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("stage span %s has no spec span parent", span.Name)
+		}
+	}
+	return tree
+}
+
+// stageNames returns the sorted names of a service's stage spans
+func (tree *traceTree) stageNames(service string) []string {
+	var names []string
+	for _, span := range tree.stages[service] {
+		names = append(names, span.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRunTraceHierarchy(t *testing.T) {
+	useRecordingGenerator(t)
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk")
+	exporter := &tracing.InMemoryExporter{}
+
+	cfg := config.Config{SpecsDir: specsDir, OutputDir: t.TempDir(), WorkerCount: 1}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg, Options{TraceExporter: exporter}); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	tree := newTraceTree(t, exporter.Spans())
+	if tree.run.ParentID != (tracing.SpanID{}) || tree.run.Attributes[attrSpecCount] != "2" || tree.run.StatusCode != tracing.StatusOK {
+		t.Errorf("run span = %+v, want an OK root span with 2 specs", tree.run)
+	}
+	if len(tree.specs) != 2 {
+		t.Fatalf("spec spans for %d services, want 2", len(tree.specs))
+	}
+	for _, service := range []string{"funding", "holidays"} {
+		spec := tree.specs[service]
+		if spec.ParentID != tree.run.SpanID {
+			t.Errorf("%s spec span is not a child of the run span", service)
+		}
+		if spec.Attributes[attrOutcome] != "success" || spec.StatusCode != tracing.StatusOK {
+			t.Errorf("%s spec span outcome = %q, status %d; want success", service, spec.Attributes[attrOutcome], spec.StatusCode)
+		}
+		if want := filepath.Join(specsDir, service+"-server-sdk", "openapi.json"); spec.Attributes[attrSpecPath] != want {
+			t.Errorf("%s spec path = %q, want %q", service, spec.Attributes[attrSpecPath], want)
+		}
+		names := tree.stageNames(service)
+		if want := []string{spanGenerate, spanPostProcess, spanValidate}; len(names) != 3 || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+			t.Errorf("%s stage spans = %v, want %v", service, names, want)
+		}
+	}
+}
+
+func TestRunTraceRecordsFailures(t *testing.T) {
+	gen := &failingGenerator{code: generator.ErrCodeGeneratorFailed}
+	previous := defaultGenerator
+	SetGenerator(gen)
+	t.Cleanup(func() { defaultGenerator = previous })
+
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk")
+	exporter := &tracing.InMemoryExporter{}
+
+	// Parallel workers run tasks outside the run's context, so parentage must still hold
+	cfg := config.Config{SpecsDir: specsDir, OutputDir: t.TempDir(), WorkerCount: 2, ContinueOnError: true}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg, Options{TraceExporter: exporter}); err != nil {
+		t.Fatalf("ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	tree := newTraceTree(t, exporter.Spans())
+	for _, service := range []string{"funding", "holidays"} {
+		spec := tree.specs[service]
+		if spec.ParentID != tree.run.SpanID {
+			t.Errorf("%s spec span is not a child of the run span", service)
+		}
+		if spec.Attributes[attrOutcome] != "failed" || spec.StatusCode != tracing.StatusError || spec.StatusMessage == "" {
+			t.Errorf("%s spec span = %+v, want a failed outcome with an error status", service, spec)
+		}
+		names := tree.stageNames(service)
+		if len(names) != 2 || names[0] != spanGenerate || names[1] != spanValidate {
+			t.Errorf("%s stage spans = %v, want generate and validate only", service, names)
+		}
+		for _, stage := range tree.stages[service] {
+			if stage.Name == spanGenerate && stage.StatusCode != tracing.StatusError {
+				t.Errorf("%s generate span status = %d, want error", service, stage.StatusCode)
+			}
+		}
+	}
+}
+
+func TestRunTraceCachedSpec(t *testing.T) {
+	useRecordingGenerator(t)
+	specsDir := writeProgressTestSpecs(t, "funding-server-sdk")
+	cfg := config.Config{
+		SpecsDir:    specsDir,
+		OutputDir:   t.TempDir(),
+		WorkerCount: 1,
+		EnableCache: true,
+		CacheDir:    t.TempDir(),
+	}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg); err != nil {
+		t.Fatalf("first ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	exporter := &tracing.InMemoryExporter{}
+	if err := ProcessOpenAPISpecs(context.Background(), cfg, Options{TraceExporter: exporter}); err != nil {
+		t.Fatalf("second ProcessOpenAPISpecs() error = %v", err)
+	}
+
+	tree := newTraceTree(t, exporter.Spans())
+	if outcome := tree.specs["funding"].Attributes[attrOutcome]; outcome != "cached" {
+		t.Errorf("funding outcome = %q, want cached", outcome)
+	}
+	if names := tree.stageNames("funding"); len(names) != 0 {
+		t.Errorf("cached spec has stage spans %v", names)
+	}
+}
+
+func TestNewRunTracerDisabledWithoutEndpoint(t *testing.T) {
+	if tracer := newRunTracer(config.Config{}, nil); tracer != nil {
+		t.Error("newRunTracer() should return nil without otel_endpoint or an exporter")
+	}
+	if tracer := newRunTracer(config.Config{OTelEndpoint: "http://localhost:4318"}, nil); tracer == nil {
+		t.Error("newRunTracer() should trace with otel_endpoint set")
+	}
+}
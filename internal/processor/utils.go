@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
 )
 
 // compileServiceRegex creates a regex for filtering services.
@@ -60,9 +63,50 @@ func normalizeServiceName(service string) string {
 	return strings.Join(parts, "")
 }
 
+// checkServiceNameCollisions returns an error if several specs normalize to the same service name,
+// listing the conflicting spec paths for each name
+func checkServiceNameCollisions(specs []string) error {
+	specsByName := make(map[string][]string)
+	for _, specPath := range specs {
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		specsByName[serviceName] = append(specsByName[serviceName], specPath)
+	}
+
+	var collisions []string
+	for serviceName, paths := range specsByName {
+		if len(paths) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s: %s", serviceName, strings.Join(paths, ", ")))
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	sort.Strings(collisions)
+	return fmt.Errorf("multiple specs map to the same service name, so their clients would overwrite each other:\n  %s",
+		strings.Join(collisions, "\n  "))
+}
+
+// clientOutputPath returns the directory a service's client is generated into.
+// In alongside-spec mode the client lives in a "client" directory next to its spec;
+// otherwise it goes to <outputDir>/<clientsSubdir>/<folderName> (an empty clientsSubdir
+// places it directly under outputDir).
+func clientOutputPath(outputDir, clientsSubdir, specPath, folderName, outputMode string) string {
+	if outputMode == config.OutputModeAlongsideSpec {
+		return filepath.Join(filepath.Dir(specPath), "client")
+	}
+	return filepath.Join(outputDir, clientsSubdir, folderName)
+}
+
 // cleanDirectory removes all files in the specified directory.
 // It returns an error if the directory doesn't exist or if there's an issue removing files.
 func cleanDirectory(dir string) error {
+	return cleanDirectoryExcept(dir, nil)
+}
+
+// cleanDirectoryExcept removes all files in the specified directory except the top-level
+// entries whose name keep reports true for. A nil keep removes everything.
+func cleanDirectoryExcept(dir string, keep func(name string) bool) error {
 	// Check if directory exists
 	_, err := os.Stat(dir)
 	if os.IsNotExist(err) {
@@ -80,6 +124,9 @@ func cleanDirectory(dir string) error {
 
 	// Remove each file
 	for _, entry := range entries {
+		if keep != nil && keep(entry.Name()) {
+			continue
+		}
 		path := filepath.Join(dir, entry.Name())
 		if entry.IsDir() {
 			// Recursively clean subdirectories
@@ -87,12 +134,12 @@ func cleanDirectory(dir string) error {
 				return err
 			}
 			// Remove the now-empty directory
-			if err := os.Remove(path); err != nil {
+			if err := removeWithRetry(path); err != nil {
 				return fmt.Errorf("failed to remove directory %s: %w", path, err)
 			}
 		} else {
 			// Remove file
-			if err := os.Remove(path); err != nil {
+			if err := removeWithRetry(path); err != nil {
 				return fmt.Errorf("failed to remove file %s: %w", path, err)
 			}
 		}
@@ -100,3 +147,69 @@ func cleanDirectory(dir string) error {
 
 	return nil
 }
+
+// walkSpecTree walks the directory tree rooted at root, calling fn for each file and directory.
+// Without followSymlinks it behaves like filepath.Walk. With followSymlinks, symlinked
+// directories are traversed too; paths passed to fn keep the symlink names, and each real
+// directory is visited at most once to guard against symlink loops.
+func walkSpecTree(root string, followSymlinks bool, fn filepath.WalkFunc) error {
+	if !followSymlinks {
+		return filepath.Walk(root, fn)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	visited := make(map[string]bool)
+	return walkFollowingSymlinks(root, info, visited, fn)
+}
+
+// walkFollowingSymlinks is the recursive step of walkSpecTree when following symlinks
+func walkFollowingSymlinks(path string, info os.FileInfo, visited map[string]bool, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	// Guard against loops by tracking resolved directory paths
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fn(path, info, err)
+	}
+	if visited[realPath] {
+		return nil
+	}
+	visited[realPath] = true
+
+	if err := fn(path, info, nil); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fn(path, info, err)
+	}
+
+	for _, entry := range entries {
+		childPath := filepath.Join(path, entry.Name())
+
+		// os.Stat follows symlinks, so symlinked directories report IsDir
+		childInfo, err := os.Stat(childPath)
+		if err != nil {
+			if err := fn(childPath, nil, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+
+		if err := walkFollowingSymlinks(childPath, childInfo, visited, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
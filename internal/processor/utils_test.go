@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
 )
 
 func TestNormalizeServiceName(t *testing.T) {
@@ -390,3 +392,120 @@ func TestProcessingResult(t *testing.T) {
 			result.FailedSpecs[0].ServiceName, "service1")
 	}
 }
+
+func TestCheckServiceNameCollisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		wantErr bool
+	}{
+		{
+			name:  "distinct service names",
+			specs: []string{"/specs/funding-server-sdk/openapi.json", "/specs/holidays-server-sdk/openapi.json"},
+		},
+		{
+			name:    "case-only difference",
+			specs:   []string{"/specs/user-api-sdk/openapi.json", "/specs/user-API-sdk/openapi.json"},
+			wantErr: true,
+		},
+		{
+			name:    "suffix difference",
+			specs:   []string{"/specs/funding-server-sdk/openapi.json", "/specs/funding-sdk/openapi.json"},
+			wantErr: true,
+		},
+		{
+			name:    "same directory in different locations",
+			specs:   []string{"/specs/team-a/funding/openapi.json", "/specs/team-b/funding/openapi.yaml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkServiceNameCollisions(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkServiceNameCollisions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				for _, spec := range tt.specs {
+					if !contains(err.Error(), spec) {
+						t.Errorf("error %q should list conflicting spec %s", err.Error(), spec)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFindOpenAPISpecsServiceNameCollision(t *testing.T) {
+	specsDir := t.TempDir()
+	for _, service := range []string{"user-api-sdk", "user-API-sdk"} {
+		svcDir := filepath.Join(specsDir, service)
+		if err := os.MkdirAll(svcDir, 0755); err != nil {
+			t.Fatalf("Failed to create service directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	_, err := findOpenAPISpecs(specsDir, ".*", nil, false)
+	if err == nil {
+		t.Fatal("findOpenAPISpecs() expected service name collision error")
+	}
+	if !contains(err.Error(), "userAPI") || !contains(err.Error(), "user-API-sdk") || !contains(err.Error(), "user-api-sdk") {
+		t.Errorf("findOpenAPISpecs() error = %q, should name the service and both specs", err.Error())
+	}
+}
+
+func TestClientOutputPath(t *testing.T) {
+	specPath := filepath.Join("specs", "funding-server-sdk", "openapi.json")
+
+	tests := []struct {
+		name          string
+		outputMode    string
+		clientsSubdir string
+		want          string
+	}{
+		{"default is central", "", "clients", filepath.Join("generated", "clients", "fundingsdk")},
+		{"central", config.OutputModeCentral, "clients", filepath.Join("generated", "clients", "fundingsdk")},
+		{"custom subdir", config.OutputModeCentral, "sdk", filepath.Join("generated", "sdk", "fundingsdk")},
+		{"nested subdir", config.OutputModeModulePerService, "go/sdk", filepath.Join("generated", "go", "sdk", "fundingsdk")},
+		{"no subdir", config.OutputModeCentral, "", filepath.Join("generated", "fundingsdk")},
+		{"alongside spec ignores subdir", config.OutputModeAlongsideSpec, "sdk", filepath.Join("specs", "funding-server-sdk", "client")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientOutputPath("generated", tt.clientsSubdir, specPath, "fundingsdk", tt.outputMode); got != tt.want {
+				t.Errorf("clientOutputPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanDirectoryExcept(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"openapi.json", "oas_client_gen.go", filepath.Join("nested", "openapi.json")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	if err := cleanDirectoryExcept(dir, func(name string) bool { return name == "openapi.json" }); err != nil {
+		t.Fatalf("cleanDirectoryExcept() error = %v", err)
+	}
+
+	// Only top-level entries are kept
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "openapi.json" {
+		t.Errorf("cleanDirectoryExcept() left %v, want only openapi.json", entries)
+	}
+}
@@ -0,0 +1,284 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// validateSpec checks that a spec is an OpenAPI document and runs the configured validation rules.
+// Warnings are logged; issues with error severity fail the spec, and so do warnings when
+// failOnWarnings is set. Issues are also stored in the recorder, if provided.
+func validateSpec(validator *validation.Validator, specPath, serviceName string, failOnWarnings bool, recorder *validationRecorder) error {
+	return validateSpecDocument(validator, specPath, nil, serviceName, failOnWarnings, recorder)
+}
+
+// validateSpecDocument is validateSpec for a spec the caller may already have decoded;
+// a nil root is loaded from specPath
+func validateSpecDocument(validator *validation.Validator, specPath string, root map[string]interface{}, serviceName string, failOnWarnings bool, recorder *validationRecorder) error {
+	// Loading the document rejects files that are not OpenAPI at all, even without rules
+	var doc *validation.Document
+	var err error
+	if root != nil {
+		doc, err = validation.NewDocument(specPath, root)
+	} else {
+		doc, err = validation.LoadDocument(specPath)
+	}
+	if err != nil {
+		var docErr *validation.DocumentError
+		if errors.As(err, &docErr) {
+			return fmt.Errorf("invalid spec for %s: %w", serviceName, err)
+		}
+		return fmt.Errorf("failed to load spec for validation: %w", err)
+	}
+
+	if validator == nil {
+		return nil
+	}
+
+	issues := validator.Validate(doc)
+
+	recorder.Record(serviceName, issues)
+	for _, issue := range issues {
+		log.Printf("Validation %s for %s: %s", issue.Severity, serviceName, issue)
+	}
+
+	if validation.HasErrors(issues) {
+		return fmt.Errorf("spec validation failed for %s", serviceName)
+	}
+	if failOnWarnings && len(issues) > 0 {
+		return fmt.Errorf("spec validation failed for %s: %d warning(s) with fail_on_warnings enabled", serviceName, len(issues))
+	}
+
+	return nil
+}
+
+// newConfiguredValidator builds the validator for the optional rules enabled in configuration,
+// with their severities overridden by rule_severities, and the rules of the Spectral ruleset.
+// Returns nil if no rules are enabled.
+func newConfiguredValidator(cfg config.Config) (*validation.Validator, error) {
+	severities, err := validation.ParseRuleSeverities(cfg.RuleSeverities)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule severities: %w", err)
+	}
+	if len(cfg.ValidationRules) == 0 && cfg.SpectralRuleset == "" {
+		return nil, nil
+	}
+
+	validator, err := validation.NewValidatorFromNames(cfg.ValidationRules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation rules: %w", err)
+	}
+	validator.SetSeverities(severities)
+	if cfg.SpectralRuleset != "" {
+		rules, err := validation.LoadSpectralRuleset(cfg.SpectralRuleset)
+		if err != nil {
+			return nil, err
+		}
+		validator.AddRules(rules...)
+		log.Printf("Loaded %d rules from Spectral ruleset %s", len(rules), cfg.SpectralRuleset)
+	}
+	return validator, nil
+}
+
+// newValidationSkipMatcher returns a function reporting whether a spec is exempt from validation
+// by validator_skip_patterns, or nil if there are no patterns. Patterns (filepath.Match syntax)
+// match the spec path relative to specsDir, e.g. "stripe-server-sdk/openapi.json", or the name
+// of the spec's service directory, e.g. "stripe-*".
+func newValidationSkipMatcher(specsDir string, patterns []string) func(specPath string) bool {
+	if len(patterns) == 0 {
+		return nil
+	}
+	return func(specPath string) bool {
+		candidates := []string{filepath.Base(filepath.Dir(specPath))}
+		if rel, err := filepath.Rel(specsDir, specPath); err == nil && filepath.IsLocal(rel) {
+			candidates = append(candidates, filepath.ToSlash(rel))
+		}
+		for _, pattern := range patterns {
+			for _, candidate := range candidates {
+				if matched, _ := filepath.Match(pattern, candidate); matched {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// ValidationSummary is the result of validating every discovered spec
+type ValidationSummary struct {
+	// TotalSpecs is the number of validated specs
+	TotalSpecs int `json:"total_specs"`
+
+	// FailedSpecs is the number of specs that failed validation
+	FailedSpecs int `json:"failed_specs"`
+
+	// Specs holds the result of each spec, in discovery order
+	Specs []SpecValidation `json:"specs"`
+}
+
+// SpecValidation is the validation result of one spec
+type SpecValidation struct {
+	// Service is the service name derived from the spec's directory
+	Service string `json:"service"`
+
+	// SpecPath is the path of the spec file
+	SpecPath string `json:"spec_path"`
+
+	// Valid reports whether the spec passed validation
+	Valid bool `json:"valid"`
+
+	// Skipped reports that the spec matched validator_skip_patterns and was not validated
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Error explains why the spec failed (empty if it is valid)
+	Error string `json:"error,omitempty"`
+
+	// Issues are the issues reported by the validation rules, including warnings of valid specs
+	Issues []validation.Issue `json:"issues"`
+
+	// DurationMs is how long preparing and validating the spec took
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Err returns an error reporting how many specs failed, or nil if all are valid
+func (s *ValidationSummary) Err() error {
+	if s.FailedSpecs > 0 {
+		return fmt.Errorf("validation failed for %d/%d specs", s.FailedSpecs, s.TotalSpecs)
+	}
+	return nil
+}
+
+// Format renders one line per spec followed by the overall result
+func (s *ValidationSummary) Format() string {
+	var b strings.Builder
+	for _, result := range s.Specs {
+		if result.Skipped {
+			fmt.Fprintf(&b, "⏭️  %s validation skipped\n", result.Service)
+		} else if result.Valid {
+			fmt.Fprintf(&b, "✅ %s is valid\n", result.Service)
+		} else {
+			fmt.Fprintf(&b, "❌ %s\n", result.Error)
+		}
+	}
+	if err := s.Err(); err != nil {
+		fmt.Fprintf(&b, "%v\n", err)
+	} else {
+		fmt.Fprintf(&b, "All %d specs are valid\n", s.TotalSpecs)
+	}
+	return b.String()
+}
+
+// CheckOpenAPISpecs validates every discovered spec without generating clients and returns
+// the result of each. Specs are prepared as for generation (transcoding, preprocessing,
+// deprecated operation filtering) and checked against the configured validation rules.
+// All specs are validated even if some fail; the error only reports failures to discover
+// specs or build the validator.
+func CheckOpenAPISpecs(ctx context.Context, cfg config.Config) (*ValidationSummary, error) {
+	spec.SetParseCacheSize(cfg.ParseCacheSize)
+	spec.SetIOConcurrency(cfg.IOConcurrency)
+	spec.SetSpecEncoding(cfg.SpecEncoding)
+
+	// Remote and git specs are fetched and validated like local ones
+	network.SetOffline(cfg.Offline)
+	if err := network.SetProxy(cfg.SpecFetchProxy); err != nil {
+		return nil, err
+	}
+	sourceSpecs, err := fetchSpecSources(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	specs, err := findOpenAPISpecs(cfg.SpecsDir, cfg.TargetServices, cfg.SpecFilePatterns, cfg.FollowSymlinks, sourceSpecs...)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := newValidationRecorder()
+	opts := pipelineOptions{
+		preprocessCommand:  cfg.SpecPreprocessCommand,
+		specEncoding:       cfg.SpecEncoding,
+		excludeDeprecated:  cfg.ExcludeDeprecated,
+		downgradeOpenAPI31: cfg.DowngradeOpenAPI31,
+		convertSwagger2:    cfg.ConvertSwagger2,
+		failOnWarnings:     cfg.FailOnWarnings,
+		validationResults:  issues,
+		skipValidation:     newValidationSkipMatcher(cfg.SpecsDir, cfg.ValidatorSkipPatterns),
+	}
+	opts.validator, err = newConfiguredValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	summary := &ValidationSummary{TotalSpecs: len(specs), Specs: make([]SpecValidation, 0, len(specs))}
+	for _, specPath := range specs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		serviceName := normalizeServiceName(filepath.Base(filepath.Dir(specPath)))
+		result := SpecValidation{Service: serviceName, SpecPath: specPath, Valid: true}
+		specStart := time.Now()
+		if opts.skipValidation != nil && opts.skipValidation(specPath) {
+			log.Printf("Skipping validation for %s: spec matches validator_skip_patterns", serviceName)
+			result.Skipped = true
+		} else if err := validatePreparedSpec(ctx, specPath, serviceName, opts); err != nil {
+			result.Valid = false
+			result.Error = err.Error()
+			summary.FailedSpecs++
+		}
+		result.DurationMs = time.Since(specStart).Milliseconds()
+		summary.Specs = append(summary.Specs, result)
+	}
+
+	recorded := issues.Issues()
+	for i := range summary.Specs {
+		summary.Specs[i].Issues = recorded[summary.Specs[i].Service]
+		if summary.Specs[i].Issues == nil {
+			summary.Specs[i].Issues = []validation.Issue{}
+		}
+	}
+
+	if cfg.JUnitReport != "" {
+		if err := writeJUnitReport(cfg.JUnitReport, validationJUnitSuite(summary, startTime)); err != nil {
+			log.Printf("Warning: %v", err)
+		} else {
+			log.Printf("JUnit report written to: %s", cfg.JUnitReport)
+		}
+	}
+	return summary, nil
+}
+
+// ValidateOpenAPISpecs validates every discovered spec without generating clients, logging
+// the result of each (see CheckOpenAPISpecs). The returned error reports how many failed.
+func ValidateOpenAPISpecs(ctx context.Context, cfg config.Config) error {
+	summary, err := CheckOpenAPISpecs(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(summary.Format(), "\n"), "\n") {
+		log.Print(line)
+	}
+	return summary.Err()
+}
+
+// validatePreparedSpec prepares a spec and validates the result
+func validatePreparedSpec(ctx context.Context, specPath, serviceName string, opts pipelineOptions) error {
+	preparedPath, cleanup, err := prepareSpec(ctx, specPath, serviceName, nil, opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return validateSpec(opts.validator, preparedPath, serviceName, opts.failOnWarnings, opts.validationResults)
+}
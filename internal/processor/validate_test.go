@@ -0,0 +1,310 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// staticRule is a validation rule that always reports the configured issues
+type staticRule struct {
+	issues []validation.Issue
+}
+
+func (r *staticRule) Name() string { return "static" }
+
+func (r *staticRule) Check(doc *validation.Document) []validation.Issue { return r.issues }
+
+func TestValidateSpec(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"openapi":"3.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		validator      *validation.Validator
+		failOnWarnings bool
+		wantErr        bool
+	}{
+		{
+			name:      "no validator",
+			validator: nil,
+			wantErr:   false,
+		},
+		{
+			name: "warnings only",
+			validator: validation.NewValidator(&staticRule{issues: []validation.Issue{
+				{Rule: "static", Severity: validation.SeverityWarning, Path: "/info", Message: "warning"},
+			}}),
+			wantErr: false,
+		},
+		{
+			name: "warnings fail with failOnWarnings",
+			validator: validation.NewValidator(&staticRule{issues: []validation.Issue{
+				{Rule: "static", Severity: validation.SeverityWarning, Path: "/info", Message: "warning"},
+			}}),
+			failOnWarnings: true,
+			wantErr:        true,
+		},
+		{
+			name: "error severity fails",
+			validator: validation.NewValidator(&staticRule{issues: []validation.Issue{
+				{Rule: "static", Severity: validation.SeverityError, Path: "/paths", Message: "error"},
+			}}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newValidationRecorder()
+			err := validateSpec(tt.validator, specPath, "testservice", tt.failOnWarnings, recorder)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.validator != nil && len(recorder.Issues()["testservice"]) != 1 {
+				t.Errorf("recorded issues = %v, want 1 issue", recorder.Issues())
+			}
+		})
+	}
+}
+
+func TestValidateSpecNotOpenAPI(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(`{"name":"not-a-spec"}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	// Non-OpenAPI documents are rejected even when no validation rules are configured
+	err := validateSpec(nil, specPath, "testservice", false, nil)
+	if err == nil {
+		t.Fatal("validateSpec() expected error for non-OpenAPI document")
+	}
+	if !contains(err.Error(), "NOT_OPENAPI") {
+		t.Errorf("validateSpec() error = %q, want NOT_OPENAPI", err.Error())
+	}
+}
+
+func TestValidateOpenAPISpecsWarnings(t *testing.T) {
+	// The unused security scheme is a warning; there are no errors
+	specsDir := t.TempDir()
+	specPath := filepath.Join(specsDir, "funding-server-sdk", "openapi.json")
+	if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+	content := `{"openapi": "3.0.3", "paths": {}, "components": {"securitySchemes": {"apiKey": {"type": "apiKey", "in": "header", "name": "X-Key"}}}}`
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		failOnWarnings bool
+		severities     map[string]string
+		wantErr        bool
+	}{
+		{name: "warnings pass by default", failOnWarnings: false, wantErr: false},
+		{name: "warnings fail when strict", failOnWarnings: true, wantErr: true},
+		{
+			name:       "warning promoted to error by rule_severities",
+			severities: map[string]string{validation.UnusedSecuritySchemeRuleName: "error"},
+			wantErr:    true,
+		},
+		{
+			name:           "rule turned off by rule_severities passes when strict",
+			failOnWarnings: true,
+			severities:     map[string]string{validation.UnusedSecuritySchemeRuleName: "off"},
+			wantErr:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{
+				SpecsDir:        specsDir,
+				TargetServices:  ".*",
+				ValidationRules: []string{validation.UnusedSecuritySchemeRuleName},
+				FailOnWarnings:  tt.failOnWarnings,
+				RuleSeverities:  tt.severities,
+			}
+
+			err := ValidateOpenAPISpecs(context.Background(), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOpenAPISpecs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !contains(err.Error(), "1/1 specs") {
+				t.Errorf("ValidateOpenAPISpecs() error = %q, want failed spec count", err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateOpenAPISpecsContinuesAfterFailure(t *testing.T) {
+	specsDir := t.TempDir()
+	fixtures := map[string]string{
+		"broken-server-sdk/openapi.json": `{"name": "not-a-spec"}`,
+		"valid-server-sdk/openapi.json":  `{"openapi": "3.0.3", "paths": {}}`,
+	}
+	for name, content := range fixtures {
+		specPath := filepath.Join(specsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+			t.Fatalf("Failed to create spec directory: %v", err)
+		}
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	err := ValidateOpenAPISpecs(context.Background(), config.Config{SpecsDir: specsDir, TargetServices: ".*"})
+	if err == nil || !contains(err.Error(), "1/2 specs") {
+		t.Errorf("ValidateOpenAPISpecs() error = %v, want 1/2 specs failed", err)
+	}
+}
+
+func TestNewConfiguredValidatorInvalidSeverity(t *testing.T) {
+	cfg := config.Config{
+		ValidationRules: []string{validation.ExamplesRuleName},
+		RuleSeverities:  map[string]string{validation.ExamplesRuleName: "fatal"},
+	}
+	if _, err := newConfiguredValidator(cfg); err == nil || !contains(err.Error(), "invalid rule severities") {
+		t.Errorf("newConfiguredValidator() error = %v, want invalid rule severities", err)
+	}
+}
+
+func TestValidateOpenAPISpecsWithSpectralRuleset(t *testing.T) {
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	writeUnusedSchemeSpecs(t, specsDir, "funding-server-sdk")
+	rulesetPath := filepath.Join(tmpDir, ".spectral.yaml")
+	ruleset := "rules:\n  info-required:\n    given: $\n    severity: error\n    then: {field: info, function: truthy}\n"
+	if err := os.WriteFile(rulesetPath, []byte(ruleset), 0644); err != nil {
+		t.Fatalf("Failed to write ruleset: %v", err)
+	}
+
+	cfg := config.Config{SpecsDir: specsDir, TargetServices: ".*", SpectralRuleset: rulesetPath}
+	summary, err := CheckOpenAPISpecs(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("CheckOpenAPISpecs() error = %v", err)
+	}
+	if summary.FailedSpecs != 1 || len(summary.Specs[0].Issues) != 1 || summary.Specs[0].Issues[0].Rule != "info-required" {
+		t.Errorf("summary = %+v, want funding to fail the Spectral rule", summary)
+	}
+
+	cfg.SpectralRuleset = filepath.Join(tmpDir, "missing.yaml")
+	if _, err := newConfiguredValidator(cfg); err == nil || !contains(err.Error(), "Spectral ruleset") {
+		t.Errorf("newConfiguredValidator() error = %v, want a missing ruleset error", err)
+	}
+}
+
+func TestNewValidationSkipMatcher(t *testing.T) {
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	skip := newValidationSkipMatcher(specsDir, []string{"stripe-*", "vendor-*/openapi.yaml"})
+
+	tests := []struct {
+		specPath string
+		want     bool
+	}{
+		{specPath: "stripe-server-sdk/openapi.json", want: true},
+		{specPath: "vendor-maps-server-sdk/openapi.yaml", want: true},
+		{specPath: "vendor-maps-server-sdk/openapi.json", want: false},
+		{specPath: "funding-server-sdk/openapi.json", want: false},
+		{specPath: "team/stripe-server-sdk/openapi.json", want: true},
+	}
+	for _, tt := range tests {
+		if got := skip(filepath.Join(specsDir, filepath.FromSlash(tt.specPath))); got != tt.want {
+			t.Errorf("skip(%q) = %v, want %v", tt.specPath, got, tt.want)
+		}
+	}
+
+	if newValidationSkipMatcher(specsDir, nil) != nil {
+		t.Error("newValidationSkipMatcher() without patterns should return nil")
+	}
+}
+
+// writeUnusedSchemeSpecs writes specs with an unused security scheme for each service directory
+func writeUnusedSchemeSpecs(t *testing.T, specsDir string, serviceDirs ...string) {
+	t.Helper()
+	content := `{"openapi": "3.0.3", "paths": {}, "components": {"securitySchemes": {"apiKey": {"type": "apiKey", "in": "header", "name": "X-Key"}}}}`
+	for _, dir := range serviceDirs {
+		specPath := filepath.Join(specsDir, dir, "openapi.json")
+		if err := os.MkdirAll(filepath.Dir(specPath), 0755); err != nil {
+			t.Fatalf("Failed to create spec directory: %v", err)
+		}
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+}
+
+func TestValidatorSkipPatternsGeneratesSkippedSpecs(t *testing.T) {
+	gen := useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	writeUnusedSchemeSpecs(t, specsDir, "stripe-server-sdk", "funding-server-sdk")
+
+	cfg := config.Config{
+		SpecsDir:              specsDir,
+		OutputDir:             filepath.Join(tmpDir, "output"),
+		WorkerCount:           1,
+		ContinueOnError:       true,
+		ValidationRules:       []string{validation.UnusedSecuritySchemeRuleName},
+		RuleSeverities:        map[string]string{validation.UnusedSecuritySchemeRuleName: "error"},
+		ValidatorSkipPatterns: []string{"stripe-*"},
+	}
+	report, err := ProcessOpenAPISpecsWithResult(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ProcessOpenAPISpecsWithResult() error = %v", err)
+	}
+
+	// The exempt spec is generated without being validated; the other one fails validation
+	if report.Result.SuccessCount != 1 || len(report.Result.FailedSpecs) != 1 {
+		t.Fatalf("result = %d succeeded, %v failed, want 1 and funding", report.Result.SuccessCount, report.Result.FailedSpecs)
+	}
+	if failure := report.Result.FailedSpecs[0]; failure.ServiceName != "funding" || !contains(failure.Error.Error(), "spec validation failed") {
+		t.Errorf("failure = %s: %v, want funding to fail validation", failure.ServiceName, failure.Error)
+	}
+	if _, ok := report.ValidationIssues["stripe"]; ok {
+		t.Errorf("stripe should not be validated, got issues %v", report.ValidationIssues["stripe"])
+	}
+	if len(report.ValidationIssues["funding"]) != 1 {
+		t.Errorf("funding issues = %v, want 1", report.ValidationIssues["funding"])
+	}
+	if !contains(gen.specPath, "stripe-server-sdk") {
+		t.Errorf("generator ran for %q, want the stripe spec", gen.specPath)
+	}
+}
+
+func TestCheckOpenAPISpecsReportsSkippedSpecs(t *testing.T) {
+	specsDir := t.TempDir()
+	writeUnusedSchemeSpecs(t, specsDir, "stripe-server-sdk", "funding-server-sdk")
+
+	summary, err := CheckOpenAPISpecs(context.Background(), config.Config{
+		SpecsDir:              specsDir,
+		TargetServices:        ".*",
+		ValidationRules:       []string{validation.UnusedSecuritySchemeRuleName},
+		FailOnWarnings:        true,
+		ValidatorSkipPatterns: []string{"stripe-server-sdk/openapi.json"},
+	})
+	if err != nil {
+		t.Fatalf("CheckOpenAPISpecs() error = %v", err)
+	}
+	if summary.FailedSpecs != 1 {
+		t.Errorf("FailedSpecs = %d, want 1", summary.FailedSpecs)
+	}
+	for _, result := range summary.Specs {
+		wantSkipped := result.Service == "stripe"
+		if result.Skipped != wantSkipped || result.Valid != wantSkipped {
+			t.Errorf("%s: skipped = %v, valid = %v, want both %v", result.Service, result.Skipped, result.Valid, wantSkipped)
+		}
+	}
+	if !contains(summary.Format(), "stripe validation skipped") {
+		t.Errorf("Format() should report the skipped spec:\n%s", summary.Format())
+	}
+}
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+// ValidationReportFileName is the name of the validation report written to the output directory
+const ValidationReportFileName = "validation-report.json"
+
+// validationReport is the JSON structure of validation-report.json
+type validationReport struct {
+	GeneratedAt time.Time                     `json:"generated_at"`
+	Errors      int                           `json:"errors"`
+	Warnings    int                           `json:"warnings"`
+	Issues      map[string][]validation.Issue `json:"issues"`
+}
+
+// writeValidationReport writes the validation issues per service to validation-report.json
+// in the output directory and returns the report path. The report is written even when
+// there are no issues, so CI can rely on it existing.
+func writeValidationReport(outputDir string, issues map[string][]validation.Issue) (string, error) {
+	report := validationReport{
+		GeneratedAt: time.Now(),
+		Issues:      issues,
+	}
+	if report.Issues == nil {
+		report.Issues = make(map[string][]validation.Issue)
+	}
+	for _, serviceIssues := range issues {
+		for _, issue := range serviceIssues {
+			if issue.Severity == validation.SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode validation report: %w", err)
+	}
+
+	reportPath := filepath.Join(outputDir, ValidationReportFileName)
+	if err := writeFileWithRetry(reportPath, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write validation report: %w", err)
+	}
+	return reportPath, nil
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/validation"
+)
+
+func TestValidationReportWrittenWhenGenerationFails(t *testing.T) {
+	gen := useRecordingGenerator(t)
+	gen.err = errors.New("ogen exploded")
+
+	tmpDir := t.TempDir()
+	svcDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	spec := `{"openapi":"3.0.0","components":{"schemas":{"Age":{"type":"integer","example":"old"}}}}`
+	if err := os.WriteFile(filepath.Join(svcDir, "openapi.json"), []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	cfg := config.Config{
+		SpecsDir:                   filepath.Join(tmpDir, "specs"),
+		OutputDir:                  filepath.Join(tmpDir, "output"),
+		WorkerCount:                1,
+		ValidationRules:            []string{validation.ExamplesRuleName},
+		EmitValidationReportAlways: true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := ProcessOpenAPISpecsWithResult(ctx, cfg); err == nil {
+		t.Fatal("ProcessOpenAPISpecsWithResult() expected generation error")
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, ValidationReportFileName))
+	if err != nil {
+		t.Fatalf("validation report not written after failed generation: %v", err)
+	}
+	var report validationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to decode validation report: %v", err)
+	}
+	if report.Warnings != 1 || report.Errors != 0 {
+		t.Errorf("report counts = %d errors, %d warnings, want 0 errors, 1 warning", report.Errors, report.Warnings)
+	}
+	issues := report.Issues["funding"]
+	if len(issues) != 1 || issues[0].Path != "/components/schemas/Age/example" {
+		t.Errorf("report issues = %v, want the Age example warning", report.Issues)
+	}
+}
+
+func TestWriteValidationReportWithoutIssues(t *testing.T) {
+	outputDir := t.TempDir()
+
+	reportPath, err := writeValidationReport(outputDir, nil)
+	if err != nil {
+		t.Fatalf("writeValidationReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var report validationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Errors != 0 || report.Warnings != 0 || report.Issues == nil || len(report.Issues) != 0 {
+		t.Errorf("report = %+v, want empty issues object", report)
+	}
+}
@@ -0,0 +1,222 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+)
+
+// timer is the part of *time.Timer the debouncer needs, so tests can use a fake clock
+type timer interface {
+	Stop() bool
+}
+
+// debouncer delays a callback per key until no new trigger for that key arrived for the delay.
+// Editors often write a file several times per save; each write resets the key's timer,
+// so a burst of events results in a single callback.
+type debouncer struct {
+	delay     time.Duration
+	fire      func(key string)
+	afterFunc func(time.Duration, func()) timer
+
+	mu     sync.Mutex
+	timers map[string]timer
+}
+
+// newDebouncer creates a debouncer calling fire once a key has been quiet for delay
+func newDebouncer(delay time.Duration, fire func(key string)) *debouncer {
+	return &debouncer{
+		delay: delay,
+		fire:  fire,
+		afterFunc: func(d time.Duration, f func()) timer {
+			return time.AfterFunc(d, f)
+		},
+		timers: make(map[string]timer),
+	}
+}
+
+// Trigger (re)starts the timer for key
+func (d *debouncer) Trigger(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if pending, ok := d.timers[key]; ok {
+		pending.Stop()
+	}
+
+	var t timer
+	t = d.afterFunc(d.delay, func() {
+		d.mu.Lock()
+		// A timer that was stopped too late to prevent this call has been superseded
+		if d.timers[key] != t {
+			d.mu.Unlock()
+			return
+		}
+		delete(d.timers, key)
+		d.mu.Unlock()
+
+		d.fire(key)
+	})
+	d.timers[key] = t
+}
+
+// Stop cancels all pending callbacks
+func (d *debouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, t := range d.timers {
+		t.Stop()
+		delete(d.timers, key)
+	}
+}
+
+// WatchOpenAPISpecs generates clients once, then watches the specs directory and regenerates
+// whenever a spec changes, until ctx is cancelled. Changes to a spec are debounced by
+// cfg.WatchDebounce. Each regeneration is a full run, so unchanged specs are served from
+// the cache when caching is enabled. Failed runs are logged and watching continues.
+func WatchOpenAPISpecs(ctx context.Context, cfg config.Config, optionalLogger ...interface{}) error {
+	serviceRegex, err := compileServiceRegex(cfg.TargetServices)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, cfg.SpecsDir, cfg.FollowSymlinks); err != nil {
+		return err
+	}
+
+	// One collector for all runs, reset before each so metrics reflect the latest run
+	collector := metrics.NewCollector()
+	collector.SetLabels(cfg.MetricsLabels)
+
+	// Serve metrics for the lifetime of the watcher rather than per run, so scrapes between
+	// runs still see the last cycle's progress
+	runCfg := cfg
+	if cfg.MetricsAddr != "" {
+		runCfg.MetricsAddr = ""
+		serverCtx, stopServer := context.WithCancel(ctx)
+		serverDone := make(chan struct{})
+		go func() {
+			defer close(serverDone)
+			if err := metrics.NewServer(cfg.MetricsAddr, collector).Run(serverCtx); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}()
+		defer func() {
+			stopServer()
+			<-serverDone
+		}()
+	}
+
+	regenerate := func() {
+		if err := processWithCollector(ctx, runCfg, collector, optionalLogger); err != nil {
+			log.Printf("Warning: Generation failed: %v", err)
+		}
+	}
+
+	regenerate()
+	log.Printf("Watching %s for spec changes (debounce %s)", cfg.SpecsDir, cfg.WatchDebounce)
+
+	changed := make(chan string)
+	debounce := newDebouncer(cfg.WatchDebounce, func(specPath string) {
+		select {
+		case changed <- specPath:
+		case <-ctx.Done():
+		}
+	})
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				// Watch directories of newly added services
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchDirs(watcher, event.Name, cfg.FollowSymlinks); err != nil {
+						log.Printf("Warning: %v", err)
+					}
+					continue
+				}
+			}
+			if isWatchedSpec(event.Name, cfg.SpecFilePatterns, serviceRegex) {
+				debounce.Trigger(event.Name)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Warning: File watcher error: %v", err)
+
+		case specPath := <-changed:
+			log.Printf("Spec changed: %s, regenerating", specPath)
+			regenerate()
+		}
+	}
+}
+
+// processWithCollector resets the collector and runs generation recording into it, keeping
+// any other options passed by the caller
+func processWithCollector(ctx context.Context, cfg config.Config, collector *metrics.Collector, optionalLogger []interface{}) error {
+	collector.Reset()
+
+	opts := optionsFrom(optionalLogger)
+	opts.MetricsCollector = collector
+	// optionsFrom picks the first Options, so these take precedence over the caller's
+	args := append([]interface{}{opts}, optionalLogger...)
+	return ProcessOpenAPISpecs(ctx, cfg, args...)
+}
+
+// addWatchDirs adds root and every directory below it to the watcher
+func addWatchDirs(watcher *fsnotify.Watcher, root string, followSymlinks bool) error {
+	return walkSpecTree(root, followSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// isWatchedSpec reports whether a changed file is a spec that generation would pick up:
+// its name matches a spec file pattern and its service directory matches the service filter
+func isWatchedSpec(path string, specFilePatterns []string, serviceRegex *regexp.Regexp) bool {
+	if len(specFilePatterns) == 0 {
+		specFilePatterns = []string{"openapi.json", "openapi.yaml", "openapi.yml"}
+	}
+
+	filename := filepath.Base(path)
+	for _, pattern := range specFilePatterns {
+		if filename == pattern {
+			return serviceRegex.MatchString(filepath.Base(filepath.Dir(path)))
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/generator"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/metrics"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/postprocessor"
+)
+
+// fakeClock fires debouncer timers when advanced instead of after real time
+type fakeClock struct {
+	now    time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Duration
+	fn      func()
+	stopped bool
+	fired   bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	wasPending := !t.stopped && !t.fired
+	t.stopped = true
+	return wasPending
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, fn func()) timer {
+	t := &fakeTimer{at: c.now + d, fn: fn}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward, firing due timers in order
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now += d
+	for _, t := range c.timers {
+		if !t.stopped && !t.fired && t.at <= c.now {
+			t.fired = true
+			t.fn()
+		}
+	}
+}
+
+// fireRecorder records debouncer callbacks
+type fireRecorder struct {
+	mu    sync.Mutex
+	fired []string
+}
+
+func (r *fireRecorder) fire(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fired = append(r.fired, key)
+}
+
+func newFakeDebouncer(delay time.Duration) (*debouncer, *fakeClock, *fireRecorder) {
+	clock := &fakeClock{}
+	recorder := &fireRecorder{}
+	d := newDebouncer(delay, recorder.fire)
+	d.afterFunc = clock.AfterFunc
+	return d, clock, recorder
+}
+
+func TestDebouncerBurstYieldsSingleFire(t *testing.T) {
+	d, clock, recorder := newFakeDebouncer(500 * time.Millisecond)
+
+	// Five writes 100ms apart keep resetting the timer
+	for i := 0; i < 5; i++ {
+		d.Trigger("funding/openapi.json")
+		clock.Advance(100 * time.Millisecond)
+	}
+	if len(recorder.fired) != 0 {
+		t.Fatalf("fired during the burst: %v", recorder.fired)
+	}
+
+	clock.Advance(400 * time.Millisecond)
+	if want := []string{"funding/openapi.json"}; !reflect.DeepEqual(recorder.fired, want) {
+		t.Errorf("fired = %v, want %v", recorder.fired, want)
+	}
+
+	// Nothing left pending
+	clock.Advance(time.Hour)
+	if len(recorder.fired) != 1 {
+		t.Errorf("fired %d times, want 1", len(recorder.fired))
+	}
+}
+
+func TestDebouncerPerKey(t *testing.T) {
+	d, clock, recorder := newFakeDebouncer(500 * time.Millisecond)
+
+	d.Trigger("a/openapi.json")
+	clock.Advance(300 * time.Millisecond)
+	d.Trigger("b/openapi.json")
+	clock.Advance(300 * time.Millisecond)
+
+	// Events for b do not delay a
+	if want := []string{"a/openapi.json"}; !reflect.DeepEqual(recorder.fired, want) {
+		t.Fatalf("fired = %v, want %v", recorder.fired, want)
+	}
+
+	clock.Advance(300 * time.Millisecond)
+	fired := append([]string(nil), recorder.fired...)
+	sort.Strings(fired)
+	if want := []string{"a/openapi.json", "b/openapi.json"}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("fired = %v, want %v", fired, want)
+	}
+}
+
+func TestDebouncerSeparateBursts(t *testing.T) {
+	d, clock, recorder := newFakeDebouncer(500 * time.Millisecond)
+
+	d.Trigger("spec")
+	clock.Advance(time.Second)
+	d.Trigger("spec")
+	d.Trigger("spec")
+	clock.Advance(time.Second)
+
+	if len(recorder.fired) != 2 {
+		t.Errorf("fired %d times, want 2 (one per burst)", len(recorder.fired))
+	}
+}
+
+func TestDebouncerStop(t *testing.T) {
+	d, clock, recorder := newFakeDebouncer(500 * time.Millisecond)
+
+	d.Trigger("spec")
+	d.Stop()
+	clock.Advance(time.Second)
+
+	if len(recorder.fired) != 0 {
+		t.Errorf("fired after Stop: %v", recorder.fired)
+	}
+}
+
+func TestDebouncerRealTimers(t *testing.T) {
+	recorder := &fireRecorder{}
+	done := make(chan struct{})
+	d := newDebouncer(20*time.Millisecond, func(key string) {
+		recorder.fire(key)
+		close(done)
+	})
+
+	for i := 0; i < 10; i++ {
+		d.Trigger("spec")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("debounced callback never fired")
+	}
+	time.Sleep(50 * time.Millisecond)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.fired) != 1 {
+		t.Errorf("fired %d times, want 1", len(recorder.fired))
+	}
+}
+
+func TestIsWatchedSpec(t *testing.T) {
+	patterns := []string{"openapi.json", "openapi.yaml"}
+	funding := regexp.MustCompile("funding")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: filepath.Join("specs", "funding-server-sdk", "openapi.json"), want: true},
+		{path: filepath.Join("specs", "funding-server-sdk", "openapi.yaml"), want: true},
+		{path: filepath.Join("specs", "funding-server-sdk", "openapi.json.swp"), want: false},
+		{path: filepath.Join("specs", "funding-server-sdk", "README.md"), want: false},
+		{path: filepath.Join("specs", "holidays-server-sdk", "openapi.json"), want: false},
+	}
+	for _, tt := range tests {
+		if got := isWatchedSpec(tt.path, patterns, funding); got != tt.want {
+			t.Errorf("isWatchedSpec(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestProcessWithCollectorResetsBetweenRuns(t *testing.T) {
+	useRecordingGenerator(t)
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "funding-server-sdk")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "openapi.json"), []byte(`{"openapi":"3.0.0","paths":{}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	cfg := config.Config{
+		SpecsDir:    filepath.Join(tmpDir, "specs"),
+		OutputDir:   filepath.Join(tmpDir, "output"),
+		WorkerCount: 1,
+	}
+
+	var events int
+	callerOpts := Options{ProgressCallback: func(ProgressEvent) { events++ }}
+	collector := metrics.NewCollector()
+	for run := 1; run <= 2; run++ {
+		if err := processWithCollector(context.Background(), cfg, collector, []interface{}{callerOpts}); err != nil {
+			t.Fatalf("run %d: processWithCollector() error = %v", run, err)
+		}
+		if total := collector.GetMetrics().TotalSpecs; total != 1 {
+			t.Errorf("run %d: TotalSpecs = %d, want metrics of the latest run only", run, total)
+		}
+	}
+	if events == 0 {
+		t.Error("the caller's progress callback should still be used")
+	}
+}
+
+// gatedGenerator blocks each Generate call until released, announcing the call first
+type gatedGenerator struct {
+	noopGenerator
+	started chan string
+	release chan struct{}
+}
+
+func (g *gatedGenerator) Generate(ctx context.Context, spec generator.GenerateSpec) error {
+	g.started <- spec.PackageName
+	select {
+	case <-g.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestMetricsEndpointReportsCycleProgressMidRun(t *testing.T) {
+	gen := &gatedGenerator{started: make(chan string), release: make(chan struct{})}
+	previousGenerator := defaultGenerator
+	previousChain := defaultPostProcessorChain
+	SetGenerator(gen)
+	SetPostProcessorChain(postprocessor.NewChain())
+	t.Cleanup(func() {
+		defaultGenerator = previousGenerator
+		defaultPostProcessorChain = previousChain
+	})
+
+	cfg := config.Config{
+		SpecsDir:    writeProgressTestSpecs(t, "funding-server-sdk", "holidays-server-sdk"),
+		OutputDir:   filepath.Join(t.TempDir(), "output"),
+		WorkerCount: 1,
+	}
+	collector := metrics.NewCollector()
+	handler := metrics.NewServer(":0", collector).Handler()
+
+	scrape := func() metrics.CycleProgress {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		var got metrics.Metrics
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Failed to decode metrics: %v", err)
+		}
+		if got.Progress == nil {
+			t.Fatalf("metrics have no progress: %s", rec.Body.String())
+		}
+		return *got.Progress
+	}
+	waitStarted := func(want string) {
+		t.Helper()
+		select {
+		case got := <-gen.started:
+			if got != want {
+				t.Fatalf("generating %s, want %s", got, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for %s to start", want)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for cycle := 1; cycle <= 2; cycle++ {
+		done := make(chan error, 1)
+		go func() { done <- processWithCollector(ctx, cfg, collector, nil) }()
+
+		waitStarted("fundingsdk")
+		progress := scrape()
+		if progress.Cycle != cycle || !progress.InProgress || progress.SpecsPlanned != 2 || progress.SpecsProcessed != 0 {
+			t.Errorf("cycle %d: progress before the first spec = %+v", cycle, progress)
+		}
+		if cycle == 2 && (progress.CompletedCycles != 1 || progress.LastCycleSuccessRate != 100) {
+			t.Errorf("cycle 2: progress = %+v, want the first cycle's success rate", progress)
+		}
+
+		gen.release <- struct{}{}
+		waitStarted("holidayssdk")
+		if progress := scrape(); !progress.InProgress || progress.SpecsProcessed != 1 {
+			t.Errorf("cycle %d: progress after the first spec = %+v, want 1 processed", cycle, progress)
+		}
+
+		gen.release <- struct{}{}
+		if err := <-done; err != nil {
+			t.Fatalf("cycle %d: processWithCollector() error = %v", cycle, err)
+		}
+		progress = scrape()
+		if progress.InProgress || progress.SpecsProcessed != 2 || progress.CompletedCycles != cycle || progress.LastCycleSuccessRate != 100 {
+			t.Errorf("cycle %d: progress after the run = %+v", cycle, progress)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// maxBundleDepth bounds nested external $refs while bundling
+const maxBundleDepth = 32
+
+// Bundle loads a spec and returns it as a single self-contained document.
+// See BundleDocument.
+func Bundle(specPath string) (map[string]interface{}, error) {
+	root, err := LoadDocument(specPath)
+	if err != nil {
+		return nil, err
+	}
+	return BundleDocument(RefLocation{Path: specPath, Root: root})
+}
+
+// BundleDocument returns a copy of a document with every relative file $ref replaced by the
+// content it references, so the result no longer depends on other files. Local refs of the
+// root document ("#/components/schemas/Pet") are kept, since they resolve within the bundle;
+// local refs inside referenced files are inlined, and refs from referenced files back into
+// the root document become local refs. Remote (http/https) refs are kept as they are.
+// Circular refs between referenced files cannot be inlined and return an error.
+func BundleDocument(loc RefLocation) (map[string]interface{}, error) {
+	b := &bundler{rootPath: cleanPath(loc.Path)}
+	bundled, err := b.value(loc.Root, loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	doc, _ := bundled.(map[string]interface{})
+	return doc, nil
+}
+
+// EncodeDocument encodes a document tree as JSON (indented) or YAML, selected by the
+// extension (e.g. ".json", ".yaml") like DecodeDocument
+func EncodeDocument(doc map[string]interface{}, ext string) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec JSON: %w", err)
+	}
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		converted, err := yaml.JSONToYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode spec YAML: %w", err)
+		}
+		return converted, nil
+	}
+	return append(data, '\n'), nil
+}
+
+// bundler inlines external $refs into the document rooted at rootPath
+type bundler struct {
+	rootPath string
+}
+
+// value returns a copy of v with external refs inlined. loc is the document v belongs to;
+// stack holds the refs being inlined, to detect cycles.
+func (b *bundler) value(v interface{}, loc RefLocation, stack []string) (interface{}, error) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			return b.ref(ref, loc, stack)
+		}
+		copied := make(map[string]interface{}, len(node))
+		for key, child := range node {
+			bundled, err := b.value(child, loc, stack)
+			if err != nil {
+				return nil, err
+			}
+			copied[key] = bundled
+		}
+		return copied, nil
+
+	case []interface{}:
+		copied := make([]interface{}, len(node))
+		for i, child := range node {
+			bundled, err := b.value(child, loc, stack)
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = bundled
+		}
+		return copied, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// ref inlines the target of a $ref found in loc, or returns it as a local ref if it
+// points into the root document
+func (b *bundler) ref(ref string, loc RefLocation, stack []string) (interface{}, error) {
+	if strings.HasPrefix(ref, "#") && b.isRoot(loc) {
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+	if file, _, _ := strings.Cut(ref, "#"); strings.Contains(file, "://") {
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	target, targetLoc, err := loc.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	_, pointer, _ := strings.Cut(ref, "#")
+	if b.isRoot(targetLoc) {
+		return map[string]interface{}{"$ref": "#" + pointer}, nil
+	}
+
+	key := cleanPath(targetLoc.Path) + "#" + pointer
+	for _, seen := range stack {
+		if seen == key {
+			return nil, fmt.Errorf("circular $ref %q in %s cannot be inlined", ref, loc.Path)
+		}
+	}
+	if len(stack) >= maxBundleDepth {
+		return nil, fmt.Errorf("$ref %q in %s is nested more than %d levels deep", ref, loc.Path, maxBundleDepth)
+	}
+
+	return b.value(target, targetLoc, append(stack, key))
+}
+
+// isRoot reports whether loc is the document being bundled
+func (b *bundler) isRoot(loc RefLocation) bool {
+	return cleanPath(loc.Path) == b.rootPath
+}
+
+// cleanPath returns an absolute, cleaned form of path for comparing file locations
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,161 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeBundleFixture writes files into a temp dir and returns the dir
+func writeBundleFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestBundle(t *testing.T) {
+	dir := writeBundleFixture(t, map[string]string{
+		"openapi.json": `{
+			"openapi": "3.0.3",
+			"paths": {
+				"/pets": {"$ref": "paths/pets.yaml"},
+				"/health": {"get": {"responses": {"200": {"$ref": "#/components/responses/OK"}}}}
+			},
+			"components": {
+				"responses": {"OK": {"description": "OK"}},
+				"schemas": {"Error": {"type": "object"}}
+			}
+		}`,
+		"paths/pets.yaml": `get:
+  responses:
+    '200':
+      description: Pets
+      content:
+        application/json:
+          schema:
+            $ref: '../schemas/common.yaml#/Pets'
+    default:
+      $ref: '#/defaultError'
+defaultError:
+  description: Error
+  content:
+    application/json:
+      schema:
+        $ref: '../openapi.json#/components/schemas/Error'
+`,
+		"schemas/common.yaml": `Pets:
+  type: array
+  items:
+    $ref: '#/Pet'
+Pet:
+  type: object
+  properties:
+    name:
+      type: string
+`,
+	})
+
+	bundled, err := Bundle(filepath.Join(dir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("Bundle() error = %v", err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+	}{
+		// External path item, schema and nested local ref of the external file are inlined
+		{pointer: "/paths/~1pets/get/responses/200/description", want: "Pets"},
+		{pointer: "/paths/~1pets/get/responses/200/content/application~1json/schema/type", want: "array"},
+		{pointer: "/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/name/type", want: "string"},
+		{pointer: "/paths/~1pets/get/responses/default/description", want: "Error"},
+		// A ref from an external file back into the spec becomes a local ref
+		{pointer: "/paths/~1pets/get/responses/default/content/application~1json/schema", want: map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+		// Local refs of the spec are kept
+		{pointer: "/paths/~1health/get/responses/200", want: map[string]interface{}{"$ref": "#/components/responses/OK"}},
+	}
+	for _, tt := range tests {
+		got, err := LookupPointer(bundled, tt.pointer)
+		if err != nil {
+			t.Errorf("LookupPointer(%q) error = %v", tt.pointer, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.pointer, got, tt.want)
+		}
+	}
+
+	if _, err := LookupPointer(bundled, "/paths/~1pets/$ref"); err == nil {
+		t.Error("external path item $ref should be replaced by its content")
+	}
+}
+
+func TestBundleCircularRef(t *testing.T) {
+	dir := writeBundleFixture(t, map[string]string{
+		"openapi.yaml": "openapi: 3.0.3\ncomponents:\n  schemas:\n    Node:\n      $ref: 'node.yaml#/Node'\n",
+		"node.yaml":    "Node:\n  type: object\n  properties:\n    next:\n      $ref: '#/Node'\n",
+	})
+
+	_, err := Bundle(filepath.Join(dir, "openapi.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "circular") {
+		t.Errorf("Bundle() error = %v, want circular $ref error", err)
+	}
+}
+
+func TestEncodeDocument(t *testing.T) {
+	doc := map[string]interface{}{"openapi": "3.0.3", "info": map[string]interface{}{"title": "Pets"}}
+
+	for _, ext := range []string{".json", ".yaml", ".YML"} {
+		data, err := EncodeDocument(doc, ext)
+		if err != nil {
+			t.Fatalf("EncodeDocument(%s) error = %v", ext, err)
+		}
+		decoded, err := DecodeDocument(data, ext)
+		if err != nil {
+			t.Fatalf("DecodeDocument(%s) error = %v", ext, err)
+		}
+		if !reflect.DeepEqual(decoded, doc) {
+			t.Errorf("%s round trip = %v, want %v", ext, decoded, doc)
+		}
+	}
+}
+
+func TestBundleKeepsRemoteRefs(t *testing.T) {
+	dir := writeBundleFixture(t, map[string]string{
+		"openapi.yaml": "openapi: 3.0.3\ncomponents:\n  schemas:\n    Pet:\n      $ref: 'pet.yaml#/Pet'\n    Error:\n      $ref: 'https://specs.corp/errors.yaml#/Error'\n",
+		"pet.yaml":     "Pet:\n  type: object\n",
+	})
+
+	doc, err := LoadDocument(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if !HasExternalRefs(doc) {
+		t.Fatal("HasExternalRefs() = false for a spec with a file $ref")
+	}
+
+	bundled, err := BundleDocument(RefLocation{Path: filepath.Join(dir, "openapi.yaml"), Root: doc})
+	if err != nil {
+		t.Fatalf("BundleDocument() error = %v", err)
+	}
+	if HasExternalRefs(bundled) {
+		t.Error("bundled document should have no file $refs left")
+	}
+	if ref, _ := LookupPointer(bundled, "/components/schemas/Error/$ref"); ref != "https://specs.corp/errors.yaml#/Error" {
+		t.Errorf("remote $ref = %v, want it kept as is", ref)
+	}
+	if typ, _ := LookupPointer(bundled, "/components/schemas/Pet/type"); typ != "object" {
+		t.Errorf("Pet type = %v, want the inlined schema", typ)
+	}
+}
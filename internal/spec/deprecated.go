@@ -0,0 +1,58 @@
+package spec
+
+import (
+	"sort"
+	"strings"
+)
+
+// HTTPMethods are the path item keys that hold operations
+var HTTPMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// RemoveDeprecatedOperations removes operations marked `deprecated: true` from a decoded spec document.
+// Path items left without operations are removed as well.
+// Returns the removed operations as "METHOD /path", sorted.
+func RemoveDeprecatedOperations(doc map[string]interface{}) []string {
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var removed []string
+	for path, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		removedHere := 0
+		for _, method := range HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if deprecated, _ := operation["deprecated"].(bool); deprecated {
+				delete(item, method)
+				removed = append(removed, strings.ToUpper(method)+" "+path)
+				removedHere++
+			}
+		}
+
+		// A path item left with only shared fields (parameters, summary) would generate nothing
+		if removedHere > 0 && !hasOperations(item) {
+			delete(paths, path)
+		}
+	}
+
+	sort.Strings(removed)
+	return removed
+}
+
+// hasOperations reports whether a path item contains any operation
+func hasOperations(item map[string]interface{}) bool {
+	for _, method := range HTTPMethods {
+		if _, ok := item[method]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package spec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const deprecatedTestSpec = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/users": {
+			"get": {"operationId": "listUsers"},
+			"post": {"operationId": "createUserV1", "deprecated": true}
+		},
+		"/legacy": {
+			"parameters": [{"name": "id", "in": "query"}],
+			"get": {"operationId": "getLegacy", "deprecated": true}
+		},
+		"/health": {
+			"get": {"operationId": "health", "deprecated": false}
+		}
+	}
+}`
+
+func TestRemoveDeprecatedOperations(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(deprecatedTestSpec), &doc); err != nil {
+		t.Fatalf("Failed to decode spec: %v", err)
+	}
+
+	removed := RemoveDeprecatedOperations(doc)
+	want := []string{"GET /legacy", "POST /users"}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("RemoveDeprecatedOperations() = %v, want %v", removed, want)
+	}
+
+	paths := doc["paths"].(map[string]interface{})
+	if _, ok := paths["/legacy"]; ok {
+		t.Error("path left without operations should be removed")
+	}
+	users := paths["/users"].(map[string]interface{})
+	if _, ok := users["post"]; ok {
+		t.Error("deprecated POST /users should be removed")
+	}
+	if _, ok := users["get"]; !ok {
+		t.Error("GET /users should be kept")
+	}
+	if _, ok := paths["/health"]; !ok {
+		t.Error("operation with deprecated: false should be kept")
+	}
+
+	// Nothing left to remove
+	if removed := RemoveDeprecatedOperations(doc); len(removed) != 0 {
+		t.Errorf("second RemoveDeprecatedOperations() = %v, want none", removed)
+	}
+}
+
+func TestParseSpecFileDeprecatedOperations(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(deprecatedTestSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	parsed, err := ParseSpecFile(specPath)
+	if err != nil {
+		t.Fatalf("ParseSpecFile() error = %v", err)
+	}
+
+	want := []string{"GET /legacy", "POST /users"}
+	if got := parsed.DeprecatedOperations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedOperations() = %v, want %v", got, want)
+	}
+}
+
+func TestCountOperations(t *testing.T) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(deprecatedTestSpec), &doc); err != nil {
+		t.Fatalf("Failed to decode spec: %v", err)
+	}
+
+	// Path-level parameters are not operations
+	if got := CountOperations(doc); got != 4 {
+		t.Errorf("CountOperations() = %d, want 4", got)
+	}
+	if got := CountOperations(map[string]interface{}{"openapi": "3.0.0"}); got != 0 {
+		t.Errorf("CountOperations() without paths = %d, want 0", got)
+	}
+}
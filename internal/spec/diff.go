@@ -0,0 +1,214 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OperationChange describes how an operation present in both spec versions changed
+type OperationChange struct {
+	// Operation identifies the operation as "METHOD /path"
+	Operation string `json:"operation"`
+
+	// BreakingReasons explains why the change breaks existing clients (empty if it does not)
+	BreakingReasons []string `json:"breaking_reasons"`
+}
+
+// Breaking reports whether the change breaks existing clients
+func (c OperationChange) Breaking() bool {
+	return len(c.BreakingReasons) > 0
+}
+
+// Comparison lists the differences between two versions of a spec.
+// Operations are identified as "METHOD /path"; all lists are sorted.
+type Comparison struct {
+	// Added lists operations only present in the current version
+	Added []string `json:"added"`
+
+	// Deleted lists operations only present in the previous version (always breaking)
+	Deleted []string `json:"deleted"`
+
+	// Modified lists operations whose definition changed
+	Modified []OperationChange `json:"modified"`
+
+	// ChangedComponents lists reusable components (e.g. "schemas/Pet") that were added
+	// or changed. Operations referencing them via $ref are not reported as modified,
+	// so this is where such changes show up.
+	ChangedComponents []string `json:"changed_components"`
+
+	// DeletedComponents lists reusable components only present in the previous version
+	DeletedComponents []string `json:"deleted_components"`
+}
+
+// HasChanges reports whether any operation or component changed
+func (c *Comparison) HasChanges() bool {
+	return len(c.Added) > 0 || len(c.Deleted) > 0 || len(c.Modified) > 0 ||
+		len(c.ChangedComponents) > 0 || len(c.DeletedComponents) > 0
+}
+
+// Breaking returns a description of every breaking change, sorted
+func (c *Comparison) Breaking() []string {
+	var breaking []string
+	for _, operation := range c.Deleted {
+		breaking = append(breaking, "`"+operation+"`: operation removed")
+	}
+	for _, change := range c.Modified {
+		for _, reason := range change.BreakingReasons {
+			breaking = append(breaking, "`"+change.Operation+"`: "+reason)
+		}
+	}
+	for _, component := range c.DeletedComponents {
+		breaking = append(breaking, "`"+component+"`: component removed")
+	}
+	sort.Strings(breaking)
+	return breaking
+}
+
+// CompareDocuments compares two decoded versions of a spec at the operation level.
+// The fingerprint only tells whether a section changed; this reports which operations did.
+func CompareDocuments(previous, current map[string]interface{}) *Comparison {
+	return CompareSurfaces(SummarizeSurface(previous), SummarizeSurface(current))
+}
+
+// CompareFiles loads two spec files and compares them
+func CompareFiles(previousPath, currentPath string) (*Comparison, error) {
+	previous, err := LoadDocument(previousPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", previousPath, err)
+	}
+	current, err := LoadDocument(currentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", currentPath, err)
+	}
+	return CompareDocuments(previous, current), nil
+}
+
+// collectOperations returns the operations of a document keyed by "METHOD /path".
+// Path-level parameters are merged into each operation so moving a parameter between
+// the path item and its operations is not reported as a change.
+func collectOperations(doc map[string]interface{}) map[string]map[string]interface{} {
+	operations := make(map[string]map[string]interface{})
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		shared, _ := item["parameters"].([]interface{})
+		for _, method := range HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			merged := make(map[string]interface{}, len(operation))
+			for key, value := range operation {
+				merged[key] = value
+			}
+			merged["parameters"] = mergeParameters(shared, operation["parameters"])
+			operations[strings.ToUpper(method)+" "+path] = merged
+		}
+	}
+	return operations
+}
+
+// mergeParameters returns the operation parameters keyed by "in:name", with operation-level
+// parameters overriding path-level ones as in the OpenAPI spec
+func mergeParameters(shared []interface{}, own interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	ownList, _ := own.([]interface{})
+	for _, list := range [][]interface{}{shared, ownList} {
+		for _, rawParam := range list {
+			param, _ := rawParam.(map[string]interface{})
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+			key := in + ":" + name
+			if ref, ok := param["$ref"].(string); ok {
+				// $ref parameters have no name of their own
+				key = "ref:" + ref
+			}
+			merged[key] = rawParam
+		}
+	}
+	return merged
+}
+
+// collectComponents returns the reusable components of a document keyed by "section/name"
+func collectComponents(doc map[string]interface{}) map[string]interface{} {
+	components := make(map[string]interface{})
+	rawComponents, _ := doc["components"].(map[string]interface{})
+	for _, section := range []string{"schemas", "parameters", "requestBodies", "responses", "headers"} {
+		entries, _ := rawComponents[section].(map[string]interface{})
+		for name, component := range entries {
+			components[section+"/"+name] = component
+		}
+	}
+	return components
+}
+
+// breakingReasons lists the changes to an operation that break existing clients:
+// removed parameters, new or newly required parameters, a newly required request body
+// and removed responses
+func breakingReasons(previous, current map[string]interface{}) []string {
+	var reasons []string
+
+	previousParams, _ := previous["parameters"].(map[string]interface{})
+	currentParams, _ := current["parameters"].(map[string]interface{})
+	for _, key := range sortedMapKeys(previousParams) {
+		if _, ok := currentParams[key]; !ok {
+			reasons = append(reasons, "parameter "+describeParameter(key)+" removed")
+		}
+	}
+	for _, key := range sortedMapKeys(currentParams) {
+		if !isRequired(currentParams[key]) {
+			continue
+		}
+		old, ok := previousParams[key]
+		switch {
+		case !ok:
+			reasons = append(reasons, "new required parameter "+describeParameter(key))
+		case !isRequired(old):
+			reasons = append(reasons, "parameter "+describeParameter(key)+" is now required")
+		}
+	}
+
+	if isRequired(current["requestBody"]) && !isRequired(previous["requestBody"]) {
+		reasons = append(reasons, "request body is now required")
+	}
+
+	previousResponses, _ := previous["responses"].(map[string]interface{})
+	currentResponses, _ := current["responses"].(map[string]interface{})
+	for _, status := range sortedMapKeys(previousResponses) {
+		if _, ok := currentResponses[status]; !ok {
+			reasons = append(reasons, "response "+status+" removed")
+		}
+	}
+
+	return reasons
+}
+
+// describeParameter renders an "in:name" parameter key as "`name` (in)"
+func describeParameter(key string) string {
+	in, name, _ := strings.Cut(key, ":")
+	if in == "ref" {
+		return "`" + name + "`"
+	}
+	return "`" + name + "` (" + in + ")"
+}
+
+// isRequired reports whether a parameter or request body object is marked required
+func isRequired(value interface{}) bool {
+	object, _ := value.(map[string]interface{})
+	required, _ := object["required"].(bool)
+	return required
+}
+
+// sortedMapKeys returns the keys of a decoded JSON object in sorted order
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,189 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+const previousPetstore = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "parameters": [{"name": "limit", "in": "query"}], "responses": {"200": {}}},
+			"post": {"operationId": "createPet", "responses": {"201": {}}}
+		},
+		"/pets/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true}],
+			"get": {"operationId": "getPet", "responses": {"200": {}, "404": {}}},
+			"delete": {"operationId": "deletePet", "responses": {"204": {}}}
+		}
+	},
+	"components": {"schemas": {"Pet": {"type": "object"}, "Legacy": {"type": "object"}}}
+}`
+
+const currentPetstore = `{
+	"openapi": "3.0.3",
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "parameters": [{"name": "limit", "in": "query", "required": true}], "responses": {"200": {}}},
+			"post": {"operationId": "createPet", "description": "Adds a pet", "responses": {"201": {}}}
+		},
+		"/pets/{id}": {
+			"get": {"operationId": "getPet", "parameters": [{"name": "id", "in": "path", "required": true}], "responses": {"200": {}}}
+		},
+		"/owners": {
+			"get": {"operationId": "listOwners", "responses": {"200": {}}}
+		}
+	},
+	"components": {"schemas": {"Pet": {"type": "object", "required": ["name"]}, "Owner": {"type": "object"}}}
+}`
+
+func TestCompareDocuments(t *testing.T) {
+	previous, err := DecodeDocument([]byte(previousPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	current, err := DecodeDocument([]byte(currentPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+
+	comparison := CompareDocuments(previous, current)
+
+	if want := []string{"GET /owners"}; !reflect.DeepEqual(comparison.Added, want) {
+		t.Errorf("Added = %v, want %v", comparison.Added, want)
+	}
+	if want := []string{"DELETE /pets/{id}"}; !reflect.DeepEqual(comparison.Deleted, want) {
+		t.Errorf("Deleted = %v, want %v", comparison.Deleted, want)
+	}
+
+	// Moving the id parameter from the path item to the operation is not a change
+	wantModified := []OperationChange{
+		{Operation: "GET /pets", BreakingReasons: []string{"parameter `limit` (query) is now required"}},
+		{Operation: "GET /pets/{id}", BreakingReasons: []string{"response 404 removed"}},
+		{Operation: "POST /pets"},
+	}
+	if !reflect.DeepEqual(comparison.Modified, wantModified) {
+		t.Errorf("Modified = %+v, want %+v", comparison.Modified, wantModified)
+	}
+
+	if want := []string{"schemas/Owner", "schemas/Pet"}; !reflect.DeepEqual(comparison.ChangedComponents, want) {
+		t.Errorf("ChangedComponents = %v, want %v", comparison.ChangedComponents, want)
+	}
+	if want := []string{"schemas/Legacy"}; !reflect.DeepEqual(comparison.DeletedComponents, want) {
+		t.Errorf("DeletedComponents = %v, want %v", comparison.DeletedComponents, want)
+	}
+
+	wantBreaking := []string{
+		"`DELETE /pets/{id}`: operation removed",
+		"`GET /pets/{id}`: response 404 removed",
+		"`GET /pets`: parameter `limit` (query) is now required",
+		"`schemas/Legacy`: component removed",
+	}
+	if got := comparison.Breaking(); !reflect.DeepEqual(got, wantBreaking) {
+		t.Errorf("Breaking() = %v, want %v", got, wantBreaking)
+	}
+}
+
+func TestCompareDocumentsBreakingReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		current  string
+		want     []string
+	}{
+		{
+			name:     "optional parameter added",
+			previous: `{"paths": {"/a": {"get": {}}}}`,
+			current:  `{"paths": {"/a": {"get": {"parameters": [{"name": "q", "in": "query"}]}}}}`,
+			want:     nil,
+		},
+		{
+			name:     "required parameter added",
+			previous: `{"paths": {"/a": {"get": {}}}}`,
+			current:  `{"paths": {"/a": {"get": {"parameters": [{"name": "X-Tenant", "in": "header", "required": true}]}}}}`,
+			want:     []string{"new required parameter `X-Tenant` (header)"},
+		},
+		{
+			name:     "parameter removed",
+			previous: `{"paths": {"/a": {"get": {"parameters": [{"name": "q", "in": "query"}]}}}}`,
+			current:  `{"paths": {"/a": {"get": {}}}}`,
+			want:     []string{"parameter `q` (query) removed"},
+		},
+		{
+			name:     "request body becomes required",
+			previous: `{"paths": {"/a": {"post": {"requestBody": {"content": {}}}}}}`,
+			current:  `{"paths": {"/a": {"post": {"requestBody": {"required": true, "content": {}}}}}}`,
+			want:     []string{"request body is now required"},
+		},
+		{
+			name:     "response added",
+			previous: `{"paths": {"/a": {"get": {"responses": {"200": {}}}}}}`,
+			current:  `{"paths": {"/a": {"get": {"responses": {"200": {}, "400": {}}}}}}`,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, err := DecodeDocument([]byte(tt.previous), ".json")
+			if err != nil {
+				t.Fatalf("DecodeDocument() error = %v", err)
+			}
+			current, err := DecodeDocument([]byte(tt.current), ".json")
+			if err != nil {
+				t.Fatalf("DecodeDocument() error = %v", err)
+			}
+
+			comparison := CompareDocuments(previous, current)
+			if len(comparison.Modified) != 1 {
+				t.Fatalf("Modified = %+v, want one change", comparison.Modified)
+			}
+			if got := comparison.Modified[0].BreakingReasons; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BreakingReasons = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDocumentsNoChanges(t *testing.T) {
+	doc, err := DecodeDocument([]byte(previousPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	if comparison := CompareDocuments(doc, doc); comparison.HasChanges() {
+		t.Errorf("HasChanges() = true for identical documents: %+v", comparison)
+	}
+}
+
+func TestCompareDocumentsResponseHeaderChange(t *testing.T) {
+	previous := `{"openapi": "3.0.3", "paths": {"/pets": {"get": {"responses": {"200": {
+		"description": "OK",
+		"headers": {"X-Total-Count": {"schema": {"type": "integer"}}, "X-Request-Id": {"$ref": "#/components/headers/RequestId"}}
+	}}}}}, "components": {"headers": {"RequestId": {"schema": {"type": "string"}}}}}`
+	current := `{"openapi": "3.0.3", "paths": {"/pets": {"get": {"responses": {"200": {
+		"description": "OK",
+		"headers": {"X-Total-Count": {"schema": {"type": "string"}}, "X-Request-Id": {"$ref": "#/components/headers/RequestId"}}
+	}}}}}, "components": {"headers": {"RequestId": {"schema": {"type": "string", "format": "uuid"}}}}}`
+
+	previousDoc, err := DecodeDocument([]byte(previous), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	currentDoc, err := DecodeDocument([]byte(current), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+
+	comparison := CompareDocuments(previousDoc, currentDoc)
+	wantModified := []OperationChange{{Operation: "GET /pets"}}
+	if !reflect.DeepEqual(comparison.Modified, wantModified) {
+		t.Errorf("Modified = %+v, want %+v", comparison.Modified, wantModified)
+	}
+	if !reflect.DeepEqual(comparison.ChangedComponents, []string{"headers/RequestId"}) {
+		t.Errorf("ChangedComponents = %v, want [headers/RequestId]", comparison.ChangedComponents)
+	}
+	if len(comparison.Added) != 0 || len(comparison.Deleted) != 0 {
+		t.Errorf("unexpected added/deleted operations: %+v", comparison)
+	}
+}
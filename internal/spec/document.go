@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// LoadDocument reads a JSON or YAML spec file into a generic document tree.
+// YAML files (.yaml, .yml) are converted to JSON first so both formats decode
+// to the same representation. Parsed documents are cached by content, so reading
+// the same content again (from any path) skips decoding; callers get their own copy.
+func LoadDocument(specPath string) (map[string]interface{}, error) {
+	data, err := readSpecFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(specPath)
+	key := parseCacheKey(data, ext)
+	if doc, ok := documentCache.get(key); ok {
+		return doc, nil
+	}
+
+	doc, err := DecodeDocument(data, ext)
+	if err != nil {
+		return nil, err
+	}
+	documentCache.add(key, doc)
+	return doc, nil
+}
+
+// DecodeDocument decodes spec content into a generic document tree.
+// The extension (e.g. ".json", ".yaml") selects the format.
+func DecodeDocument(data []byte, ext string) (map[string]interface{}, error) {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse spec YAML: %w", err)
+		}
+		data = converted
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec JSON: %w", err)
+	}
+
+	return doc, nil
+}
+
+// CountOperations returns the number of operations across all path items of a decoded spec
+// document, including the webhooks of OpenAPI 3.1 specs
+func CountOperations(doc map[string]interface{}) int {
+	count := 0
+	for _, section := range []string{"paths", "webhooks"} {
+		items, _ := doc[section].(map[string]interface{})
+		for _, rawItem := range items {
+			item, ok := rawItem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, method := range HTTPMethods {
+				if _, ok := item[method].(map[string]interface{}); ok {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// CopyDocument returns a deep copy of a decoded spec document, for callers that modify a
+// document shared with other stages
+func CopyDocument(doc map[string]interface{}) map[string]interface{} {
+	if doc == nil {
+		return nil
+	}
+	return copyValue(doc).(map[string]interface{})
+}
+
+// DocumentHasSecurity reports whether a decoded spec document declares global security
+// requirements or security schemes, like OpenAPISpec.HasSecurity
+func DocumentHasSecurity(doc map[string]interface{}) bool {
+	if security, _ := doc["security"].([]interface{}); len(security) > 0 {
+		return true
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	return len(schemes) > 0
+}
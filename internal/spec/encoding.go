@@ -0,0 +1,111 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// specEncoding is the spec_encoding applied when reading spec files (nil detects a BOM)
+var specEncoding atomic.Pointer[string]
+
+// utf8Files holds spec files written by the pipeline itself (e.g. transcoded or bundled
+// copies), which are UTF-8 whatever spec_encoding says
+var utf8Files sync.Map
+
+// SetSpecEncoding sets the encoding of spec files read by LoadDocument, ParseSpecFile and
+// ComputeFingerprint. An empty name detects a byte order mark and otherwise assumes UTF-8.
+func SetSpecEncoding(name string) {
+	specEncoding.Store(&name)
+}
+
+// MarkUTF8 records that the file at path was written as UTF-8 by the pipeline, so it is read
+// without applying spec_encoding. The returned function removes the mark.
+func MarkUTF8(path string) (unmark func()) {
+	utf8Files.Store(path, struct{}{})
+	return func() { utf8Files.Delete(path) }
+}
+
+// LookupEncoding returns the encoding for a spec_encoding name (e.g., "utf-16le", "latin1").
+// An empty name or UTF-8 returns nil, meaning no transcoding beyond BOM detection.
+func LookupEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "utf-8", "utf8":
+		return nil, nil
+	case "utf-16", "utf16":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case "utf-16le", "utf16le":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case "utf-16be", "utf16be":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case "latin1", "latin-1", "iso-8859-1":
+		return charmap.ISO8859_1, nil
+	case "windows-1252", "cp1252":
+		return charmap.Windows1252, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return nil, fmt.Errorf("unsupported spec encoding %q", name)
+	}
+	return enc, nil
+}
+
+// ToUTF8 converts spec content to UTF-8.
+// If encodingName is empty, a UTF-8 or UTF-16 byte order mark is detected and
+// content without a BOM is assumed to already be UTF-8.
+func ToUTF8(data []byte, encodingName string) ([]byte, error) {
+	enc, err := LookupEncoding(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc == nil {
+		switch {
+		case bytes.HasPrefix(data, utf8BOM):
+			return data[len(utf8BOM):], nil
+		case bytes.HasPrefix(data, utf16LEBOM):
+			enc = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+		case bytes.HasPrefix(data, utf16BEBOM):
+			enc = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+		default:
+			return data, nil
+		}
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode spec content: %w", err)
+	}
+	return bytes.TrimPrefix(decoded, utf8BOM), nil
+}
+
+// readSpecFile reads a spec file and converts it to UTF-8 using the configured spec_encoding
+// (see SetSpecEncoding), or its byte order mark if none is configured
+func readSpecFile(specPath string) ([]byte, error) {
+	data, err := ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	encodingName := ""
+	if _, written := utf8Files.Load(specPath); !written {
+		if name := specEncoding.Load(); name != nil {
+			encodingName = *name
+		}
+	}
+	return ToUTF8(data, encodingName)
+}
@@ -0,0 +1,145 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const encodingTestSpec = `{"openapi":"3.0.0","info":{"title":"Zahlungsdienst für Überweisungen","version":"1.0"},"components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer"}}}}`
+
+// encodeUTF16 encodes s as UTF-16 with a byte order mark
+func encodeUTF16(t *testing.T, s string, endianness unicode.Endianness) []byte {
+	t.Helper()
+
+	data, err := unicode.UTF16(endianness, unicode.UseBOM).NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to encode UTF-16: %v", err)
+	}
+	return data
+}
+
+func TestParseSpecFileUTF16(t *testing.T) {
+	tests := []struct {
+		name string
+		data func(t *testing.T) []byte
+	}{
+		{name: "UTF-16 LE with BOM", data: func(t *testing.T) []byte { return encodeUTF16(t, encodingTestSpec, unicode.LittleEndian) }},
+		{name: "UTF-16 BE with BOM", data: func(t *testing.T) []byte { return encodeUTF16(t, encodingTestSpec, unicode.BigEndian) }},
+		{name: "UTF-8 with BOM", data: func(t *testing.T) []byte { return append([]byte{0xEF, 0xBB, 0xBF}, encodingTestSpec...) }},
+		{name: "UTF-8", data: func(t *testing.T) []byte { return []byte(encodingTestSpec) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := filepath.Join(t.TempDir(), "openapi.json")
+			if err := os.WriteFile(specPath, tt.data(t), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			parsed, err := ParseSpecFile(specPath)
+			if err != nil {
+				t.Fatalf("ParseSpecFile() error = %v", err)
+			}
+			if parsed.OpenAPI != "3.0.0" {
+				t.Errorf("OpenAPI = %q, want 3.0.0", parsed.OpenAPI)
+			}
+			if title := parsed.Info["title"]; title != "Zahlungsdienst für Überweisungen" {
+				t.Errorf("Info.title = %q, want non-ASCII title preserved", title)
+			}
+			if !parsed.HasSecurity() {
+				t.Error("HasSecurity() = false, want true")
+			}
+
+			if _, err := LoadDocument(specPath); err != nil {
+				t.Errorf("LoadDocument() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestToUTF8WithEncodingOverride(t *testing.T) {
+	latin1, err := charmap.ISO8859_1.NewEncoder().Bytes([]byte(encodingTestSpec))
+	if err != nil {
+		t.Fatalf("Failed to encode latin1: %v", err)
+	}
+
+	converted, err := ToUTF8(latin1, "latin1")
+	if err != nil {
+		t.Fatalf("ToUTF8() error = %v", err)
+	}
+	if string(converted) != encodingTestSpec {
+		t.Errorf("ToUTF8() = %q, want %q", converted, encodingTestSpec)
+	}
+
+	// Without the override, latin1 content is passed through unchanged
+	unchanged, err := ToUTF8(latin1, "")
+	if err != nil {
+		t.Fatalf("ToUTF8() error = %v", err)
+	}
+	if string(unchanged) != string(latin1) {
+		t.Error("ToUTF8() without BOM or override should not modify content")
+	}
+
+	// An explicit UTF-16 override also handles a leading BOM
+	utf16, err := ToUTF8(encodeUTF16(t, encodingTestSpec, unicode.LittleEndian), "utf-16le")
+	if err != nil {
+		t.Fatalf("ToUTF8() error = %v", err)
+	}
+	if string(utf16) != encodingTestSpec {
+		t.Errorf("ToUTF8() = %q, want %q", utf16, encodingTestSpec)
+	}
+}
+
+func TestLookupEncoding(t *testing.T) {
+	for _, name := range []string{"", "UTF-8", "utf-16", "utf-16le", "UTF-16BE", "latin1", "ISO-8859-1", "windows-1252", "Shift_JIS"} {
+		if _, err := LookupEncoding(name); err != nil {
+			t.Errorf("LookupEncoding(%q) error = %v", name, err)
+		}
+	}
+
+	if _, err := LookupEncoding("klingon"); err == nil {
+		t.Error("LookupEncoding() expected error for unknown encoding")
+	}
+}
+
+func TestLoadDocumentWithSpecEncoding(t *testing.T) {
+	SetSpecEncoding("latin1")
+	t.Cleanup(func() { SetSpecEncoding("") })
+
+	yamlSpec := "openapi: 3.0.0\ninfo:\n  title: Zahlungsdienst für Überweisungen\n  version: \"1.0\"\npaths: {}\n"
+	latin1, err := charmap.ISO8859_1.NewEncoder().Bytes([]byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("Failed to encode latin1: %v", err)
+	}
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, latin1, 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	doc, err := LoadDocument(specPath)
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if title := doc["info"].(map[string]interface{})["title"]; title != "Zahlungsdienst für Überweisungen" {
+		t.Errorf("title = %q, want it decoded from latin1", title)
+	}
+
+	// Files the pipeline wrote as UTF-8 are not decoded again
+	utf8Path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(utf8Path, []byte(encodingTestSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	unmark := MarkUTF8(utf8Path)
+	defer unmark()
+	doc, err = LoadDocument(utf8Path)
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if title := doc["info"].(map[string]interface{})["title"]; title != "Zahlungsdienst für Überweisungen" {
+		t.Errorf("title = %q, want the UTF-8 content unchanged", title)
+	}
+}
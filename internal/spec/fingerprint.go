@@ -0,0 +1,134 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// Fingerprint summarizes the code-affecting sections of an OpenAPI spec.
+// Metadata such as info, tags and externalDocs is excluded, and each
+// section is hashed in canonical form so formatting changes do not affect it.
+type Fingerprint struct {
+	// Version is the hash of the openapi version field
+	Version string `json:"version"`
+
+	// Operations is the hash of the paths section
+	Operations string `json:"operations"`
+
+	// Schemas is the hash of components.schemas
+	Schemas string `json:"schemas"`
+
+	// Parameters is the hash of components.parameters
+	Parameters string `json:"parameters"`
+
+	// RequestBodies is the hash of components.requestBodies
+	RequestBodies string `json:"request_bodies"`
+
+	// Responses is the hash of components.responses
+	Responses string `json:"responses"`
+
+	// Headers is the hash of components.headers (empty if the spec defines none). Inline
+	// response headers are part of Operations and Responses; this covers headers that
+	// responses reference via $ref.
+	Headers string `json:"headers,omitempty"`
+
+	// Webhooks is the hash of the OpenAPI 3.1 webhooks section (empty if the spec defines none)
+	Webhooks string `json:"webhooks,omitempty"`
+
+	// Servers is the hash of the top-level servers section (empty if the spec defines none).
+	// The internal client emits base URL constants and server selection from it.
+	Servers string `json:"servers,omitempty"`
+
+	// ComponentsHash combines the hashes of all reusable component sections above.
+	// Operations referencing shared components via $ref are byte-identical when only the
+	// component changes, so this is what detects such changes.
+	ComponentsHash string `json:"components_hash"`
+
+	// Security is the hash of the global security requirements and components.securitySchemes
+	Security string `json:"security"`
+}
+
+// Equal reports whether two fingerprints describe the same generated code
+func (f *Fingerprint) Equal(other *Fingerprint) bool {
+	if f == nil || other == nil {
+		return f == other
+	}
+	return *f == *other
+}
+
+// HasChanges reports whether the spec changed in a code-affecting way since the previous fingerprint
+func (f *Fingerprint) HasChanges(previous *Fingerprint) bool {
+	return !f.Equal(previous)
+}
+
+// ComputeFingerprint reads a JSON or YAML spec file and computes its fingerprint
+func ComputeFingerprint(specPath string) (*Fingerprint, error) {
+	data, err := readSpecFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := DecodeDocument(data, filepath.Ext(specPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return FingerprintDocument(doc), nil
+}
+
+// FingerprintDocument computes the fingerprint of an already decoded spec document
+func FingerprintDocument(doc map[string]interface{}) *Fingerprint {
+	components, _ := doc["components"].(map[string]interface{})
+
+	fingerprint := &Fingerprint{
+		Version:       hashSection(doc["openapi"]),
+		Operations:    hashSection(doc["paths"]),
+		Schemas:       hashSection(components["schemas"]),
+		Parameters:    hashSection(components["parameters"]),
+		RequestBodies: hashSection(components["requestBodies"]),
+		Responses:     hashSection(components["responses"]),
+		Security: hashSection(map[string]interface{}{
+			"security":        doc["security"],
+			"securitySchemes": components["securitySchemes"],
+		}),
+	}
+	componentHashes := []string{
+		fingerprint.Schemas,
+		fingerprint.Parameters,
+		fingerprint.RequestBodies,
+		fingerprint.Responses,
+	}
+	// Only specs with shared headers include them, so fingerprints of other specs keep
+	// matching cache entries written before headers were tracked
+	if headers, ok := components["headers"]; ok {
+		fingerprint.Headers = hashSection(headers)
+		componentHashes = append(componentHashes, fingerprint.Headers)
+	}
+	fingerprint.ComponentsHash = hashSection(componentHashes)
+
+	// Webhooks generate operations like paths; specs without them keep their fingerprint
+	if webhooks, ok := doc["webhooks"]; ok {
+		fingerprint.Webhooks = hashSection(webhooks)
+	}
+
+	// Servers become base URL constants of the internal client; specs without them keep
+	// their fingerprint
+	if servers, ok := doc["servers"]; ok {
+		fingerprint.Servers = hashSection(servers)
+	}
+
+	return fingerprint
+}
+
+// hashSection hashes the canonical JSON encoding of a spec section.
+// encoding/json sorts map keys, which makes the encoding independent of source formatting.
+func hashSection(section interface{}) string {
+	data, err := json.Marshal(section)
+	if err != nil {
+		// Decoded JSON values are always marshalable
+		data = []byte(fmt.Sprintf("%v", section))
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
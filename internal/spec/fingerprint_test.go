@@ -0,0 +1,306 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeFingerprint(t *testing.T) {
+	base := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Test", "version": "1.0", "description": "Original"},
+		"servers": [{"url": "https://example.com"}],
+		"paths": {"/items": {"get": {"operationId": "listItems"}}},
+		"components": {"schemas": {"Item": {"type": "object"}}}
+	}`
+
+	tests := []struct {
+		name      string
+		updated   string
+		wantEqual bool
+	}{
+		{
+			name: "metadata only change",
+			updated: `{
+				"openapi": "3.0.0",
+				"info": {"title": "Renamed", "version": "2.0", "description": "Updated"},
+				"servers": [{"url": "https://example.com"}],
+				"tags": [{"name": "items"}],
+				"paths": {"/items": {"get": {"operationId": "listItems"}}},
+				"components": {"schemas": {"Item": {"type": "object"}}}
+			}`,
+			wantEqual: true,
+		},
+		{
+			name:      "reformatted",
+			updated:   `{"components":{"schemas":{"Item":{"type":"object"}}},"paths":{"/items":{"get":{"operationId":"listItems"}}},"servers":[{"url":"https://example.com"}],"openapi":"3.0.0"}`,
+			wantEqual: true,
+		},
+		{
+			name: "servers change",
+			updated: `{
+				"openapi": "3.0.0",
+				"info": {"title": "Test", "version": "1.0", "description": "Original"},
+				"servers": [{"url": "https://other.example.com"}],
+				"paths": {"/items": {"get": {"operationId": "listItems"}}},
+				"components": {"schemas": {"Item": {"type": "object"}}}
+			}`,
+			wantEqual: false,
+		},
+		{
+			name: "operation change",
+			updated: `{
+				"openapi": "3.0.0",
+				"paths": {"/items": {"get": {"operationId": "getItems"}}},
+				"components": {"schemas": {"Item": {"type": "object"}}}
+			}`,
+			wantEqual: false,
+		},
+		{
+			name: "schema change",
+			updated: `{
+				"openapi": "3.0.0",
+				"paths": {"/items": {"get": {"operationId": "listItems"}}},
+				"components": {"schemas": {"Item": {"type": "string"}}}
+			}`,
+			wantEqual: false,
+		},
+		{
+			name: "security change",
+			updated: `{
+				"openapi": "3.0.0",
+				"security": [{"bearer": []}],
+				"paths": {"/items": {"get": {"operationId": "listItems"}}},
+				"components": {"schemas": {"Item": {"type": "object"}}}
+			}`,
+			wantEqual: false,
+		},
+	}
+
+	tmpDir := t.TempDir()
+	basePath := filepath.Join(tmpDir, "base.json")
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	baseFingerprint, err := ComputeFingerprint(basePath)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updatedPath := filepath.Join(t.TempDir(), "updated.json")
+			if err := os.WriteFile(updatedPath, []byte(tt.updated), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			updatedFingerprint, err := ComputeFingerprint(updatedPath)
+			if err != nil {
+				t.Fatalf("ComputeFingerprint() error = %v", err)
+			}
+
+			if got := baseFingerprint.Equal(updatedFingerprint); got != tt.wantEqual {
+				t.Errorf("Equal() = %v, want %v (base=%+v updated=%+v)", got, tt.wantEqual, baseFingerprint, updatedFingerprint)
+			}
+		})
+	}
+}
+
+func TestComputeFingerprintYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonPath := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"openapi":"3.0.0","paths":{"/items":{"get":{"operationId":"listItems"}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	yamlPath := filepath.Join(tmpDir, "openapi.yaml")
+	if err := os.WriteFile(yamlPath, []byte("openapi: 3.0.0\npaths:\n  /items:\n    get:\n      operationId: listItems\n"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	jsonFingerprint, err := ComputeFingerprint(jsonPath)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint(json) error = %v", err)
+	}
+	yamlFingerprint, err := ComputeFingerprint(yamlPath)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint(yaml) error = %v", err)
+	}
+	if !jsonFingerprint.Equal(yamlFingerprint) {
+		t.Errorf("YAML fingerprint %+v differs from the same spec in JSON %+v", yamlFingerprint, jsonFingerprint)
+	}
+}
+
+func TestComputeFingerprintInvalidSpec(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	if _, err := ComputeFingerprint(specPath); err == nil {
+		t.Error("ComputeFingerprint() expected error for invalid JSON")
+	}
+}
+
+func TestFingerprintSharedComponentChanges(t *testing.T) {
+	// Operations reference shared components by $ref, so they stay byte-identical
+	paths := `"paths": {"/items": {"post": {
+		"parameters": [{"$ref": "#/components/parameters/Limit"}],
+		"requestBody": {"$ref": "#/components/requestBodies/Item"},
+		"responses": {"200": {"$ref": "#/components/responses/Ok"}}
+	}}}`
+
+	base := `{"openapi": "3.0.0", ` + paths + `, "components": {
+		"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}},
+		"requestBodies": {"Item": {"content": {"application/json": {"schema": {"type": "object"}}}}},
+		"responses": {"Ok": {"description": "ok"}}
+	}}`
+
+	tests := []struct {
+		name        string
+		updated     string
+		wantChanges bool
+	}{
+		{
+			name: "shared parameter changed",
+			updated: `{"openapi": "3.0.0", ` + paths + `, "components": {
+				"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "string"}}},
+				"requestBodies": {"Item": {"content": {"application/json": {"schema": {"type": "object"}}}}},
+				"responses": {"Ok": {"description": "ok"}}
+			}}`,
+			wantChanges: true,
+		},
+		{
+			name: "shared request body changed",
+			updated: `{"openapi": "3.0.0", ` + paths + `, "components": {
+				"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}},
+				"requestBodies": {"Item": {"required": true, "content": {"application/json": {"schema": {"type": "object"}}}}},
+				"responses": {"Ok": {"description": "ok"}}
+			}}`,
+			wantChanges: true,
+		},
+		{
+			name: "shared response changed",
+			updated: `{"openapi": "3.0.0", ` + paths + `, "components": {
+				"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}},
+				"requestBodies": {"Item": {"content": {"application/json": {"schema": {"type": "object"}}}}},
+				"responses": {"Ok": {"description": "ok", "content": {"text/plain": {"schema": {"type": "string"}}}}}
+			}}`,
+			wantChanges: true,
+		},
+		{
+			name:        "unchanged",
+			updated:     base,
+			wantChanges: false,
+		},
+	}
+
+	baseDoc, err := DecodeDocument([]byte(base), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	baseFingerprint := FingerprintDocument(baseDoc)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updatedDoc, err := DecodeDocument([]byte(tt.updated), ".json")
+			if err != nil {
+				t.Fatalf("DecodeDocument() error = %v", err)
+			}
+			updatedFingerprint := FingerprintDocument(updatedDoc)
+
+			if updatedFingerprint.Operations != baseFingerprint.Operations {
+				t.Fatal("operation hashes should be identical when only shared components change")
+			}
+			if got := updatedFingerprint.HasChanges(baseFingerprint); got != tt.wantChanges {
+				t.Errorf("HasChanges() = %v, want %v", got, tt.wantChanges)
+			}
+			if got := updatedFingerprint.ComponentsHash != baseFingerprint.ComponentsHash; got != tt.wantChanges {
+				t.Errorf("ComponentsHash changed = %v, want %v", got, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestFingerprintResponseHeaderChanges(t *testing.T) {
+	base := `{"openapi": "3.0.0", "paths": {"/items": {"get": {"responses": {"200": {
+		"description": "ok",
+		"headers": {
+			"X-Rate-Limit": {"schema": {"type": "integer"}},
+			"X-Request-Id": {"$ref": "#/components/headers/RequestId"}
+		}
+	}}}}}, "components": {"headers": {"RequestId": {"schema": {"type": "string"}}}}}`
+
+	tests := []struct {
+		name    string
+		updated string
+	}{
+		{
+			name: "inline response header changed",
+			updated: `{"openapi": "3.0.0", "paths": {"/items": {"get": {"responses": {"200": {
+				"description": "ok",
+				"headers": {
+					"X-Rate-Limit": {"schema": {"type": "string"}},
+					"X-Request-Id": {"$ref": "#/components/headers/RequestId"}
+				}
+			}}}}}, "components": {"headers": {"RequestId": {"schema": {"type": "string"}}}}}`,
+		},
+		{
+			name: "shared response header changed",
+			updated: `{"openapi": "3.0.0", "paths": {"/items": {"get": {"responses": {"200": {
+				"description": "ok",
+				"headers": {
+					"X-Rate-Limit": {"schema": {"type": "integer"}},
+					"X-Request-Id": {"$ref": "#/components/headers/RequestId"}
+				}
+			}}}}}, "components": {"headers": {"RequestId": {"schema": {"type": "string", "format": "uuid"}}}}}`,
+		},
+	}
+
+	fingerprint := func(content string, stripHeaders bool) *Fingerprint {
+		t.Helper()
+		doc, err := DecodeDocument([]byte(content), ".json")
+		if err != nil {
+			t.Fatalf("DecodeDocument() error = %v", err)
+		}
+		if stripHeaders {
+			StripResponseHeaders(doc)
+		}
+		return FingerprintDocument(doc)
+	}
+
+	if fingerprint(base, false).Headers == "" {
+		t.Error("Headers should be set for a spec with components.headers")
+	}
+	if fingerprint(base, true).Headers != "" {
+		t.Error("Headers should be empty once response headers are stripped")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !fingerprint(tt.updated, false).HasChanges(fingerprint(base, false)) {
+				t.Error("HasChanges() = false, want true for a response header change")
+			}
+			if fingerprint(tt.updated, true).HasChanges(fingerprint(base, true)) {
+				t.Error("HasChanges() = true with response headers stripped, want false")
+			}
+		})
+	}
+}
+
+func TestFingerprintWithoutSharedHeaders(t *testing.T) {
+	doc, err := DecodeDocument([]byte(`{"openapi": "3.0.0", "components": {"schemas": {"Item": {"type": "object"}}}}`), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	fingerprint := FingerprintDocument(doc)
+	if fingerprint.Headers != "" {
+		t.Errorf("Headers = %q, want empty without components.headers", fingerprint.Headers)
+	}
+
+	// Without shared headers the components hash covers the same sections as before headers were tracked
+	want := hashSection([]string{fingerprint.Schemas, fingerprint.Parameters, fingerprint.RequestBodies, fingerprint.Responses})
+	if fingerprint.ComponentsHash != want {
+		t.Error("ComponentsHash should not change for specs without components.headers")
+	}
+}
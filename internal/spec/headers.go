@@ -0,0 +1,36 @@
+package spec
+
+// StripResponseHeaders removes response header definitions from a decoded spec document:
+// the headers of every operation response and of components.responses, and components.headers.
+// Request headers are parameters and are kept.
+func StripResponseHeaders(doc map[string]interface{}) {
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			responses, _ := operation["responses"].(map[string]interface{})
+			stripHeaders(responses)
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	responses, _ := components["responses"].(map[string]interface{})
+	stripHeaders(responses)
+	delete(components, "headers")
+}
+
+// stripHeaders removes the headers of each response in a responses object
+func stripHeaders(responses map[string]interface{}) {
+	for _, rawResponse := range responses {
+		if response, ok := rawResponse.(map[string]interface{}); ok {
+			delete(response, "headers")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package spec
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// ioLimiter bounds concurrent spec I/O
+type ioLimiter struct {
+	slots chan struct{}
+}
+
+// specIO is the limiter for spec reads; nil means no limit
+var specIO atomic.Pointer[ioLimiter]
+
+// readFile reads a file; tests replace it to instrument reads
+var readFile = os.ReadFile
+
+// SetIOConcurrency limits how many spec reads (and hashes, see AcquireIO) run at once,
+// independently of the number of generation workers. A limit of 0 removes the cap.
+// Reads already waiting keep the limit they started with.
+func SetIOConcurrency(limit int) {
+	if limit <= 0 {
+		specIO.Store(nil)
+		return
+	}
+	specIO.Store(&ioLimiter{slots: make(chan struct{}, limit)})
+}
+
+// AcquireIO waits for a spec I/O slot and returns the function releasing it.
+// Callers reading spec files outside this package use it to share the cap.
+func AcquireIO() (release func()) {
+	limiter := specIO.Load()
+	if limiter == nil {
+		return func() {}
+	}
+	limiter.slots <- struct{}{}
+	return func() { <-limiter.slots }
+}
+
+// ReadFile reads a spec file within the I/O concurrency limit
+func ReadFile(path string) ([]byte, error) {
+	release := AcquireIO()
+	defer release()
+	return readFile(path)
+}
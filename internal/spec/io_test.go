@@ -0,0 +1,116 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// useIOConcurrency sets the spec I/O limit for the duration of a test
+func useIOConcurrency(t *testing.T, limit int) {
+	t.Helper()
+	previous := specIO.Load()
+	SetIOConcurrency(limit)
+	t.Cleanup(func() { specIO.Store(previous) })
+}
+
+// instrumentReads replaces the file reader with one that records the peak number of
+// concurrent reads, holding each read briefly so overlapping reads are observed
+func instrumentReads(t *testing.T) *int32 {
+	t.Helper()
+	var active, peak int32
+	previous := readFile
+	readFile = func(path string) ([]byte, error) {
+		current := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return os.ReadFile(path)
+	}
+	t.Cleanup(func() { readFile = previous })
+	return &peak
+}
+
+func TestIOConcurrency(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 8; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("spec%d.json", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(`{"openapi": "3.0.3", "info": {"title": "Spec %d"}}`, i)), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		wantPeak int32 // maximum allowed peak; 0 means more than one read must overlap
+	}{
+		{name: "single reader", limit: 1, wantPeak: 1},
+		{name: "two readers", limit: 2, wantPeak: 2},
+		{name: "no limit", limit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useParseCache(t, 0)
+			useIOConcurrency(t, tt.limit)
+			peak := instrumentReads(t)
+
+			var wg sync.WaitGroup
+			for _, path := range paths {
+				wg.Add(1)
+				go func(path string) {
+					defer wg.Done()
+					if _, err := LoadDocument(path); err != nil {
+						t.Errorf("LoadDocument(%s) error = %v", path, err)
+					}
+				}(path)
+			}
+			wg.Wait()
+
+			got := atomic.LoadInt32(peak)
+			if tt.wantPeak > 0 && got > tt.wantPeak {
+				t.Errorf("peak concurrent reads = %d, want at most %d", got, tt.wantPeak)
+			}
+			if tt.wantPeak == 0 && got < 2 {
+				t.Errorf("peak concurrent reads = %d, want overlapping reads without a limit", got)
+			}
+		})
+	}
+}
+
+func TestAcquireIOSharesLimit(t *testing.T) {
+	useIOConcurrency(t, 1)
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	release := AcquireIO()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ReadFile(missing)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("ReadFile should wait while another caller holds the only I/O slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ReadFile should proceed once the slot is released")
+	}
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+// headTraceSpec defines HEAD and TRACE next to GET on the same path
+const headTraceSpec = `{"openapi": "3.0.3", "paths": {"/pets": {
+	"get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}},
+	"head": {"operationId": "checkPets", "responses": {"200": {"description": "OK"}}},
+	"trace": {"operationId": "tracePets", "deprecated": true, "responses": {"200": {"description": "OK"}}}
+}}}`
+
+func decodeHeadTraceSpec(t *testing.T, content string) map[string]interface{} {
+	t.Helper()
+	doc, err := DecodeDocument([]byte(content), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	return doc
+}
+
+func TestHeadAndTraceOperations(t *testing.T) {
+	doc := decodeHeadTraceSpec(t, headTraceSpec)
+	if got := CountOperations(doc); got != 3 {
+		t.Errorf("CountOperations() = %d, want 3", got)
+	}
+
+	// Changing only the HEAD or TRACE operation changes the fingerprint and is reported as modified
+	for _, method := range []string{"head", "trace"} {
+		t.Run(method, func(t *testing.T) {
+			updated := decodeHeadTraceSpec(t, headTraceSpec)
+			operation := updated["paths"].(map[string]interface{})["/pets"].(map[string]interface{})[method].(map[string]interface{})
+			operation["responses"] = map[string]interface{}{"204": map[string]interface{}{"description": "No Content"}}
+
+			if !FingerprintDocument(updated).HasChanges(FingerprintDocument(doc)) {
+				t.Errorf("fingerprint unchanged after editing the %s operation", method)
+			}
+
+			comparison := CompareDocuments(doc, updated)
+			want := "HEAD /pets"
+			if method == "trace" {
+				want = "TRACE /pets"
+			}
+			if len(comparison.Modified) != 1 || comparison.Modified[0].Operation != want {
+				t.Errorf("Modified = %+v, want %s", comparison.Modified, want)
+			}
+		})
+	}
+}
+
+func TestRemoveDeprecatedTraceOperation(t *testing.T) {
+	doc := decodeHeadTraceSpec(t, headTraceSpec)
+	removed := RemoveDeprecatedOperations(doc)
+	if !reflect.DeepEqual(removed, []string{"TRACE /pets"}) {
+		t.Errorf("RemoveDeprecatedOperations() = %v, want [TRACE /pets]", removed)
+	}
+	if got := CountOperations(doc); got != 2 {
+		t.Errorf("CountOperations() after filtering = %d, want 2", got)
+	}
+}
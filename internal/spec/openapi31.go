@@ -0,0 +1,235 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpenAPI30Version is the version specs converted to OpenAPI 3.0 declare
+const OpenAPI30Version = "3.0.3"
+
+// nameContainerKeys hold maps keyed by names (property names, status codes, media types)
+// rather than keywords, so their keys are never converted
+var nameContainerKeys = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true,
+	"schemas": true, "parameters": true, "headers": true, "responses": true, "requestBodies": true,
+	"securitySchemes": true, "links": true, "callbacks": true, "content": true, "encoding": true,
+	"paths": true, "variables": true, "scopes": true, "mapping": true,
+}
+
+// dataKeywords hold instance data rather than schemas, so they are not walked
+var dataKeywords = map[string]bool{"example": true, "examples": true, "default": true, "enum": true, "const": true}
+
+// unsupportedKeywords are JSON Schema keywords of OpenAPI 3.1 with no OpenAPI 3.0 equivalent
+var unsupportedKeywords = []string{
+	"$dynamicAnchor", "$dynamicRef", "contains", "dependentRequired", "dependentSchemas", "else", "if",
+	"maxContains", "minContains", "prefixItems", "propertyNames", "then", "unevaluatedItems",
+	"unevaluatedProperties",
+}
+
+// IsOpenAPI31 reports whether a decoded document declares OpenAPI 3.1
+func IsOpenAPI31(doc map[string]interface{}) bool {
+	version, _ := doc["openapi"].(string)
+	return strings.HasPrefix(version, "3.1")
+}
+
+// SchemaTypes returns the types a schema allows, from a `type` string (OpenAPI 3.0) or
+// array (OpenAPI 3.1). "null" is included for 3.1 null types and 3.0 `nullable: true`.
+func SchemaTypes(schema map[string]interface{}) []string {
+	var types []string
+	switch value := schema["type"].(type) {
+	case string:
+		types = append(types, value)
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+	if nullable, _ := schema["nullable"].(bool); nullable && !containsString(types, "null") {
+		types = append(types, "null")
+	}
+	return types
+}
+
+// DowngradeOpenAPI31 rewrites a decoded OpenAPI 3.1 document in place as OpenAPI 3.0, for
+// generators that only read 3.0:
+//   - type arrays become a single type with `nullable: true`, or `anyOf` for several types
+//   - `const` becomes a single-value `enum`, and schema `examples` the first `example`
+//   - numeric `exclusiveMinimum`/`exclusiveMaximum` become `minimum`/`maximum` with a flag
+//   - `contentEncoding: base64` and binary `contentMediaType` become `format: byte`/`binary`
+//   - path items referenced from components.pathItems are inlined
+//
+// Webhooks, jsonSchemaDialect and JSON Schema keywords without a 3.0 equivalent are dropped.
+// Returns a note for each dropped construct, as "<pointer>: <note>", sorted.
+func DowngradeOpenAPI31(doc map[string]interface{}) []string {
+	var notes []string
+	doc["openapi"] = OpenAPI30Version
+	delete(doc, "jsonSchemaDialect")
+
+	if webhooks, ok := doc["webhooks"].(map[string]interface{}); ok {
+		if len(webhooks) > 0 {
+			notes = append(notes, fmt.Sprintf("/webhooks: dropped %d webhook(s)", len(webhooks)))
+		}
+		delete(doc, "webhooks")
+	}
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]interface{}); ok {
+			delete(license, "identifier")
+		}
+	}
+
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		// Paths are optional in 3.1 but required in 3.0
+		paths = map[string]interface{}{}
+		doc["paths"] = paths
+	}
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		inlinePathItems(doc, paths, &notes)
+		delete(components, "pathItems")
+	}
+
+	downgradeNode(doc, "", false, &notes)
+	sort.Strings(notes)
+	return notes
+}
+
+// inlinePathItems replaces refs to components.pathItems with the path items themselves
+func inlinePathItems(doc, paths map[string]interface{}, notes *[]string) {
+	for path, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok := item["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/pathItems/") {
+			continue
+		}
+		target, err := LookupPointer(doc, strings.TrimPrefix(ref, "#"))
+		if resolved, ok := target.(map[string]interface{}); err == nil && ok {
+			paths[path] = copyValue(resolved)
+		} else {
+			*notes = append(*notes, fmt.Sprintf("/paths/%s: dropped unresolvable path item %s", escapePointerToken(path), ref))
+			delete(paths, path)
+		}
+	}
+}
+
+// downgradeNode converts the schema keywords of every object in a document tree. Keys of
+// name containers are names, so their objects are only walked, not converted.
+func downgradeNode(node interface{}, pointer string, names bool, notes *[]string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if !names {
+			downgradeSchemaKeywords(value, pointer, notes)
+		}
+		for key, child := range value {
+			if !names && dataKeywords[key] {
+				continue
+			}
+			downgradeNode(child, pointer+"/"+escapePointerToken(key), !names && nameContainerKeys[key], notes)
+		}
+	case []interface{}:
+		for i, child := range value {
+			downgradeNode(child, fmt.Sprintf("%s/%d", pointer, i), false, notes)
+		}
+	}
+}
+
+// downgradeSchemaKeywords converts the OpenAPI 3.1 keywords of a single object
+func downgradeSchemaKeywords(obj map[string]interface{}, pointer string, notes *[]string) {
+	if _, ok := obj["type"].([]interface{}); ok {
+		downgradeTypeArray(obj, pointer, notes)
+	} else if obj["type"] == "null" {
+		delete(obj, "type")
+		obj["nullable"] = true
+	}
+
+	if value, ok := obj["const"]; ok {
+		if _, hasEnum := obj["enum"]; !hasEnum {
+			obj["enum"] = []interface{}{value}
+		}
+		delete(obj, "const")
+	}
+	if examples, ok := obj["examples"].([]interface{}); ok {
+		if _, hasExample := obj["example"]; !hasExample && len(examples) > 0 {
+			obj["example"] = examples[0]
+		}
+		delete(obj, "examples")
+	}
+
+	for keyword, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if limit, ok := obj[keyword].(float64); ok {
+			obj[bound] = limit
+			obj[keyword] = true
+		}
+	}
+
+	if encoding, ok := obj["contentEncoding"].(string); ok {
+		if strings.EqualFold(encoding, "base64") {
+			obj["format"] = "byte"
+		}
+		delete(obj, "contentEncoding")
+	}
+	if mediaType, ok := obj["contentMediaType"].(string); ok {
+		if _, hasFormat := obj["format"]; !hasFormat && mediaType == "application/octet-stream" {
+			obj["format"] = "binary"
+		}
+		delete(obj, "contentMediaType")
+	}
+
+	for _, keyword := range []string{"$schema", "$id", "$anchor", "$comment"} {
+		delete(obj, keyword)
+	}
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := obj[keyword]; ok {
+			delete(obj, keyword)
+			*notes = append(*notes, fmt.Sprintf("%s: dropped unsupported keyword %s", pointer, keyword))
+		}
+	}
+}
+
+// downgradeTypeArray replaces a 3.1 type array: "null" becomes `nullable: true`, a single
+// remaining type stays `type`, and several become an `anyOf` of one schema per type
+func downgradeTypeArray(obj map[string]interface{}, pointer string, notes *[]string) {
+	types := SchemaTypes(obj)
+	delete(obj, "type")
+
+	var nonNull []string
+	for _, name := range types {
+		if name == "null" {
+			obj["nullable"] = true
+		} else {
+			nonNull = append(nonNull, name)
+		}
+	}
+
+	switch {
+	case len(nonNull) == 1:
+		obj["type"] = nonNull[0]
+	case len(nonNull) > 1:
+		if _, hasAnyOf := obj["anyOf"]; hasAnyOf {
+			*notes = append(*notes, fmt.Sprintf("%s: dropped type %v, which cannot be combined with its anyOf", pointer, nonNull))
+			return
+		}
+		anyOf := make([]interface{}, 0, len(nonNull))
+		for _, name := range nonNull {
+			anyOf = append(anyOf, map[string]interface{}{"type": name})
+		}
+		obj["anyOf"] = anyOf
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaTypes(t *testing.T) {
+	tests := []struct {
+		schema map[string]interface{}
+		want   []string
+	}{
+		{map[string]interface{}{"type": "string"}, []string{"string"}},
+		{map[string]interface{}{"type": "string", "nullable": true}, []string{"string", "null"}},
+		{map[string]interface{}{"type": []interface{}{"integer", "null"}}, []string{"integer", "null"}},
+		{map[string]interface{}{"$ref": "#/components/schemas/Pet"}, nil},
+	}
+	for _, tt := range tests {
+		if got := SchemaTypes(tt.schema); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SchemaTypes(%v) = %v, want %v", tt.schema, got, tt.want)
+		}
+	}
+}
+
+func TestDowngradeOpenAPI31(t *testing.T) {
+	doc, err := DecodeDocument([]byte(`
+openapi: 3.1.0
+jsonSchemaDialect: https://spec.openapis.org/oas/3.1/dialect/base
+info: {title: Pets, version: 1.0.0, summary: Pet store, license: {name: MIT, identifier: MIT}}
+webhooks:
+  newPet:
+    post: {operationId: newPet}
+paths:
+  /pets:
+    $ref: '#/components/pathItems/Pets'
+components:
+  pathItems:
+    Pets:
+      get: {operationId: listPets}
+  schemas:
+    Pet:
+      $schema: https://json-schema.org/draft/2020-12/schema
+      type: object
+      properties:
+        name: {type: [string, "null"], examples: [Rex]}
+        kind: {const: dog}
+        age: {type: [integer, string]}
+        weight: {type: number, exclusiveMinimum: 0}
+        photo: {type: string, contentMediaType: application/octet-stream}
+        tags: {type: array, prefixItems: [{type: string}]}
+        const: {type: string}
+`), ".yaml")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+
+	notes := DowngradeOpenAPI31(doc)
+	want := []string{
+		"/components/schemas/Pet/properties/tags: dropped unsupported keyword prefixItems",
+		"/webhooks: dropped 1 webhook(s)",
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("notes = %v, want %v", notes, want)
+	}
+
+	checks := map[string]interface{}{
+		"/openapi":                      OpenAPI30Version,
+		"/paths/~1pets/get/operationId": "listPets",
+		"/components/schemas/Pet/properties/name/type":     "string",
+		"/components/schemas/Pet/properties/name/nullable": true,
+		"/components/schemas/Pet/properties/name/example":  "Rex",
+		"/components/schemas/Pet/properties/kind/enum":     []interface{}{"dog"},
+		"/components/schemas/Pet/properties/age/anyOf": []interface{}{
+			map[string]interface{}{"type": "integer"}, map[string]interface{}{"type": "string"},
+		},
+		"/components/schemas/Pet/properties/weight/minimum":          float64(0),
+		"/components/schemas/Pet/properties/weight/exclusiveMinimum": true,
+		"/components/schemas/Pet/properties/photo/format":            "binary",
+		// A property named like a keyword is a name, not a keyword
+		"/components/schemas/Pet/properties/const/type": "string",
+	}
+	for pointer, want := range checks {
+		if got, err := LookupPointer(doc, pointer); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, %v; want %v", pointer, got, err, want)
+		}
+	}
+	for _, pointer := range []string{
+		"/jsonSchemaDialect", "/webhooks", "/info/summary", "/info/license/identifier",
+		"/components/pathItems", "/components/schemas/Pet/$schema",
+		"/components/schemas/Pet/properties/name/examples", "/components/schemas/Pet/properties/kind/const",
+	} {
+		if _, err := LookupPointer(doc, pointer); err == nil {
+			t.Errorf("%s should be removed", pointer)
+		}
+	}
+	if IsOpenAPI31(doc) {
+		t.Error("downgraded document should not be OpenAPI 3.1")
+	}
+}
+
+func TestCountOperationsWithWebhooks(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi":  "3.1.0",
+		"paths":    map[string]interface{}{"/pets": map[string]interface{}{"get": map[string]interface{}{}}},
+		"webhooks": map[string]interface{}{"newPet": map[string]interface{}{"post": map[string]interface{}{}}},
+	}
+	if got := CountOperations(doc); got != 2 {
+		t.Errorf("CountOperations() = %d, want 2", got)
+	}
+	if FingerprintDocument(doc).Webhooks == "" {
+		t.Error("fingerprint should cover webhooks")
+	}
+}
@@ -0,0 +1,158 @@
+package spec
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// DefaultParseCacheSize is the default number of parsed documents kept in memory
+const DefaultParseCacheSize = 64
+
+// documentCache holds parsed documents for LoadDocument, shared by every stage of a run
+// (fingerprinting, validation, generation) and by successive runs in watch mode
+var documentCache = newParseCache(DefaultParseCacheSize)
+
+// SetParseCacheSize bounds the number of parsed documents kept in memory, evicting the least
+// recently used ones if the cache is over the new size. A size of 0 disables the cache.
+func SetParseCacheSize(size int) {
+	documentCache.resize(size)
+}
+
+// ParseStats counts LoadDocument calls by whether the parsed-spec cache served them
+type ParseStats struct {
+	// Reused is the number of documents served from the cache
+	Reused int
+
+	// Parsed is the number of documents decoded from their content
+	Parsed int
+}
+
+// Sub returns the calls counted in s but not in earlier, e.g. the calls made during a run
+func (s ParseStats) Sub(earlier ParseStats) ParseStats {
+	return ParseStats{Reused: s.Reused - earlier.Reused, Parsed: s.Parsed - earlier.Parsed}
+}
+
+// ParseCacheStats returns the number of documents loaded from the cache and parsed since
+// the process started
+func ParseCacheStats() ParseStats {
+	return documentCache.stats()
+}
+
+// parseCache is an LRU cache of decoded documents keyed by a hash of their content and format,
+// so identical content is parsed once even if it is read from different paths
+type parseCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used; values are *parseCacheEntry
+	entries  map[string]*list.Element
+	hits     int
+	misses   int
+}
+
+// parseCacheEntry is a cached document
+type parseCacheEntry struct {
+	key string
+	doc map[string]interface{}
+}
+
+// newParseCache creates a cache holding at most capacity documents
+func newParseCache(capacity int) *parseCache {
+	return &parseCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// parseCacheKey returns the cache key for spec content decoded with the format of ext
+func parseCacheKey(data []byte, ext string) string {
+	format := "json"
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	sum := sha256.Sum256(data)
+	return format + ":" + hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the cached document for key, so callers may modify it
+func (c *parseCache) get(key string) (map[string]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return copyValue(element.Value.(*parseCacheEntry).doc).(map[string]interface{}), true
+}
+
+// stats returns the hit and miss counts; misses include every lookup while the cache is disabled
+func (c *parseCache) stats() ParseStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ParseStats{Reused: c.hits, Parsed: c.misses}
+}
+
+// add stores a copy of doc under key, evicting the least recently used document if full
+func (c *parseCache) add(key string, doc map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	entry := &parseCacheEntry{key: key, doc: copyValue(doc).(map[string]interface{})}
+	c.entries[key] = c.order.PushFront(entry)
+	c.evict()
+}
+
+// resize changes the capacity and evicts documents over it
+func (c *parseCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	c.evict()
+}
+
+// evict removes least recently used documents until the cache is within capacity.
+// The caller must hold c.mu.
+func (c *parseCache) evict() {
+	for c.order.Len() > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parseCacheEntry).key)
+	}
+}
+
+// copyValue deep-copies a decoded document value
+func copyValue(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(node))
+		for key, child := range node {
+			copied[key] = copyValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(node))
+		for i, child := range node {
+			copied[i] = copyValue(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,137 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useParseCache replaces the shared parse cache for the duration of a test
+func useParseCache(t *testing.T, capacity int) *parseCache {
+	t.Helper()
+	previous := documentCache
+	documentCache = newParseCache(capacity)
+	t.Cleanup(func() { documentCache = previous })
+	return documentCache
+}
+
+func TestLoadDocumentParseCache(t *testing.T) {
+	cache := useParseCache(t, DefaultParseCacheSize)
+
+	dir := t.TempDir()
+	content := `{"openapi": "3.0.3", "info": {"title": "Pets"}}`
+	firstPath := filepath.Join(dir, "funding", "openapi.json")
+	secondPath := filepath.Join(dir, "holidays", "openapi.json")
+	for _, path := range []string{firstPath, secondPath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+	}
+
+	first, err := LoadDocument(firstPath)
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if cache.hits != 0 || cache.misses != 1 {
+		t.Errorf("after first load: hits = %d, misses = %d, want 0, 1", cache.hits, cache.misses)
+	}
+
+	// Identical content under another path is served from the cache
+	second, err := LoadDocument(secondPath)
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if cache.hits != 1 {
+		t.Errorf("identical content: hits = %d, want 1", cache.hits)
+	}
+
+	// Callers get independent copies
+	first["info"].(map[string]interface{})["title"] = "changed"
+	if second["info"].(map[string]interface{})["title"] != "Pets" {
+		t.Error("documents returned for the same content should not share state")
+	}
+	third, _ := LoadDocument(firstPath)
+	if third["info"].(map[string]interface{})["title"] != "Pets" {
+		t.Error("modifying a returned document should not modify the cached one")
+	}
+
+	// Modified content misses
+	if err := os.WriteFile(secondPath, []byte(`{"openapi": "3.0.3", "info": {"title": "Holidays"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	misses := cache.misses
+	modified, err := LoadDocument(secondPath)
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	if cache.misses != misses+1 {
+		t.Errorf("modified content: misses = %d, want %d", cache.misses, misses+1)
+	}
+	if modified["info"].(map[string]interface{})["title"] != "Holidays" {
+		t.Errorf("modified content returned stale document: %v", modified)
+	}
+}
+
+func TestParseCacheKeyIncludesFormat(t *testing.T) {
+	data := []byte(`{"openapi": "3.0.3"}`)
+	if parseCacheKey(data, ".json") == parseCacheKey(data, ".yaml") {
+		t.Error("the same bytes decoded as JSON and YAML should have different keys")
+	}
+	if parseCacheKey(data, ".yml") != parseCacheKey(data, ".YAML") {
+		t.Error("YAML extensions should share keys")
+	}
+}
+
+func TestParseCacheEviction(t *testing.T) {
+	cache := newParseCache(2)
+	cache.add("a", map[string]interface{}{"n": "a"})
+	cache.add("b", map[string]interface{}{"n": "b"})
+
+	// Using "a" makes "b" the least recently used entry
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("get(a) missed")
+	}
+	cache.add("c", map[string]interface{}{"n": "c"})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("get(%s) missed", key)
+		}
+	}
+
+	cache.resize(1)
+	if cache.order.Len() != 1 {
+		t.Errorf("after resize: %d entries, want 1", cache.order.Len())
+	}
+
+	cache.resize(0)
+	cache.add("d", map[string]interface{}{})
+	if cache.order.Len() != 0 {
+		t.Errorf("disabled cache holds %d entries", cache.order.Len())
+	}
+}
+
+func TestParseCacheStats(t *testing.T) {
+	useParseCache(t, DefaultParseCacheSize)
+	before := ParseCacheStats()
+
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := LoadDocument(specPath); err != nil {
+			t.Fatalf("LoadDocument() error = %v", err)
+		}
+	}
+
+	if got := ParseCacheStats().Sub(before); got != (ParseStats{Reused: 2, Parsed: 1}) {
+		t.Errorf("ParseCacheStats() delta = %+v, want 2 reused, 1 parsed", got)
+	}
+}
@@ -3,21 +3,33 @@ package spec
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
+	"strings"
 )
 
 // OpenAPISpec represents a minimal OpenAPI specification structure
-// We only parse the parts we need for security detection
+// We only parse the parts we need for security detection and deprecation checks
 type OpenAPISpec struct {
-	OpenAPI    string                    `json:"openapi"`
-	Info       map[string]interface{}    `json:"info"`
-	Security   []map[string][]string     `json:"security,omitempty"`
-	Components *Components               `json:"components,omitempty"`
+	OpenAPI    string                                `json:"openapi"`
+	Info       map[string]interface{}                `json:"info"`
+	Security   []map[string][]string                 `json:"security,omitempty"`
+	Components *Components                           `json:"components,omitempty"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths,omitempty"`
+}
+
+// Operation represents the parts of an operation we inspect
+type Operation struct {
+	OperationID string `json:"operationId,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
 }
 
 // Components represents the components section of OpenAPI spec
 type Components struct {
-	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	SecuritySchemes map[string]SecurityScheme  `json:"securitySchemes,omitempty"`
+	Schemas         map[string]json.RawMessage `json:"schemas,omitempty"`
+	Parameters      map[string]json.RawMessage `json:"parameters,omitempty"`
+	RequestBodies   map[string]json.RawMessage `json:"requestBodies,omitempty"`
+	Responses       map[string]json.RawMessage `json:"responses,omitempty"`
 }
 
 // SecurityScheme represents a security scheme definition
@@ -31,9 +43,9 @@ type SecurityScheme struct {
 
 // ParseSpecFile parses an OpenAPI specification file
 func ParseSpecFile(specPath string) (*OpenAPISpec, error) {
-	data, err := os.ReadFile(specPath)
+	data, err := readSpecFile(specPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read spec file: %w", err)
+		return nil, err
 	}
 
 	var spec OpenAPISpec
@@ -59,6 +71,28 @@ func (s *OpenAPISpec) HasSecurity() bool {
 	return false
 }
 
+// DeprecatedOperations returns the operations marked deprecated as "METHOD /path", sorted
+func (s *OpenAPISpec) DeprecatedOperations() []string {
+	var deprecated []string
+	for path, item := range s.Paths {
+		for _, method := range HTTPMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var operation Operation
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				continue
+			}
+			if operation.Deprecated {
+				deprecated = append(deprecated, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(deprecated)
+	return deprecated
+}
+
 // GetSecuritySchemes returns all defined security schemes
 func (s *OpenAPISpec) GetSecuritySchemes() map[string]SecurityScheme {
 	if s.Components == nil {
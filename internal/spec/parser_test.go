@@ -197,6 +197,15 @@ func TestHasSecurity(t *testing.T) {
 			if result != tt.expected {
 				t.Errorf("HasSecurity() = %v, want %v", result, tt.expected)
 			}
+
+			// The decoded-document check must agree
+			doc, err := LoadDocument(tmpFile)
+			if err != nil {
+				t.Fatalf("LoadDocument() error = %v", err)
+			}
+			if got := DocumentHasSecurity(doc); got != tt.expected {
+				t.Errorf("DocumentHasSecurity() = %v, want %v", got, tt.expected)
+			}
 		})
 	}
 }
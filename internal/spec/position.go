@@ -0,0 +1,66 @@
+package spec
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a 1-based line and column in a spec file
+type Position struct {
+	Line   int
+	Column int
+}
+
+// PointerPosition returns where the value a JSON pointer (e.g. "/paths/~1pets/get") refers to
+// appears in JSON or YAML spec content. Pointers into an object report the position of the
+// member's key. ok is false if the content does not parse or the pointer does not resolve.
+func PointerPosition(data []byte, pointer string) (pos Position, ok bool) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return Position{}, false
+	}
+
+	current := root.Content[0]
+	pos = Position{Line: current.Line, Column: current.Column}
+	if pointer == "" {
+		return pos, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return Position{}, false
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		for current.Kind == yaml.AliasNode {
+			current = current.Alias
+		}
+
+		switch current.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(current.Content); i += 2 {
+				if key := current.Content[i]; key.Value == token {
+					pos = Position{Line: key.Line, Column: key.Column}
+					current = current.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return Position{}, false
+			}
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(current.Content) {
+				return Position{}, false
+			}
+			current = current.Content[index]
+			pos = Position{Line: current.Line, Column: current.Column}
+		default:
+			return Position{}, false
+		}
+	}
+	return pos, true
+}
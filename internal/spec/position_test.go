@@ -0,0 +1,40 @@
+package spec
+
+import "testing"
+
+func TestPointerPosition(t *testing.T) {
+	yamlSpec := `openapi: 3.0.0
+paths:
+  /pets/{id}:
+    get:
+      tags:
+        - a
+        - b
+`
+	jsonSpec := "{\n\t\"openapi\": \"3.0.0\",\n\t\"paths\": {\n\t\t\"/pets\": {\"get\": {\"tags\": [\"a\", \"b\"]}}\n\t}\n}\n"
+
+	tests := []struct {
+		name    string
+		data    string
+		pointer string
+		want    Position
+		wantOK  bool
+	}{
+		{name: "yaml key", data: yamlSpec, pointer: "/paths/~1pets~1{id}/get", want: Position{Line: 4, Column: 5}, wantOK: true},
+		{name: "yaml array item", data: yamlSpec, pointer: "/paths/~1pets~1{id}/get/tags/1", want: Position{Line: 7, Column: 11}, wantOK: true},
+		{name: "yaml root", data: yamlSpec, pointer: "", want: Position{Line: 1, Column: 1}, wantOK: true},
+		{name: "json key", data: jsonSpec, pointer: "/paths/~1pets/get", want: Position{Line: 4, Column: 13}, wantOK: true},
+		{name: "missing member", data: yamlSpec, pointer: "/paths/~1missing"},
+		{name: "index out of range", data: yamlSpec, pointer: "/paths/~1pets~1{id}/get/tags/2"},
+		{name: "not a pointer", data: yamlSpec, pointer: "paths"},
+		{name: "invalid content", data: "{", pointer: "/paths"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PointerPosition([]byte(tt.data), tt.pointer)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("PointerPosition(%q) = %+v, %v, want %+v, %v", tt.pointer, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
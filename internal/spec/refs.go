@@ -0,0 +1,150 @@
+package spec
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RefLocation is a document that $refs are resolved against
+type RefLocation struct {
+	// Path is the spec file path; relative file refs are resolved against its directory
+	Path string
+
+	// Root is the decoded document tree
+	Root map[string]interface{}
+}
+
+// Resolve looks up a $ref. Local refs ("#/components/...") are resolved in the location's
+// document; relative file refs ("common.yaml#/paths/~1pets") load the referenced file.
+// Returns the referenced value and the location that further refs inside it resolve against.
+// Remote (http/https) refs are not supported.
+func (l RefLocation) Resolve(ref string) (interface{}, RefLocation, error) {
+	file, pointer, _ := strings.Cut(ref, "#")
+
+	target := l
+	if file != "" {
+		if strings.Contains(file, "://") {
+			return nil, l, fmt.Errorf("remote $ref %q is not supported", ref)
+		}
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(l.Path), filepath.FromSlash(file))
+		}
+		root, err := LoadDocument(path)
+		if err != nil {
+			return nil, l, fmt.Errorf("failed to load $ref %q: %w", ref, err)
+		}
+		target = RefLocation{Path: path, Root: root}
+	}
+
+	value, err := LookupPointer(target.Root, pointer)
+	if err != nil {
+		return nil, l, fmt.Errorf("failed to resolve $ref %q: %w", ref, err)
+	}
+	return value, target, nil
+}
+
+// LookupPointer returns the value a JSON pointer (RFC 6901) refers to in a decoded document
+func LookupPointer(root interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return root, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	current := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", pointer)
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("%q not found", pointer)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%q not found", pointer)
+		}
+	}
+	return current, nil
+}
+
+// ExternalRefFiles returns the files a spec references through external $refs, directly or
+// through other referenced files, as cleaned absolute paths in sorted order. doc is the
+// spec's decoded document (nil loads it). Remote refs are skipped. Referenced files that
+// cannot be loaded are still listed, so their appearance counts as a change.
+func ExternalRefFiles(specPath string, doc map[string]interface{}) []string {
+	specPath, _ = filepath.Abs(specPath)
+	if doc == nil {
+		loaded, err := LoadDocument(specPath)
+		if err != nil {
+			return nil
+		}
+		doc = loaded
+	}
+
+	seen := map[string]bool{specPath: true}
+	var files []string
+	queue := []RefLocation{{Path: specPath, Root: doc}}
+	for len(queue) > 0 {
+		location := queue[0]
+		queue = queue[1:]
+
+		for _, file := range externalRefs(location.Root) {
+			path := file
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(location.Path), filepath.FromSlash(file))
+			}
+			path = filepath.Clean(path)
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			files = append(files, path)
+
+			if root, err := LoadDocument(path); err == nil {
+				queue = append(queue, RefLocation{Path: path, Root: root})
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// HasExternalRefs reports whether a document has relative or absolute file $refs, which
+// BundleDocument inlines. Remote refs are not counted.
+func HasExternalRefs(doc map[string]interface{}) bool {
+	return len(externalRefs(doc)) > 0
+}
+
+// externalRefs returns the file part of every external, non-remote $ref in a document tree
+func externalRefs(node interface{}) []string {
+	var files []string
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			if file, _, _ := strings.Cut(ref, "#"); file != "" && !strings.Contains(file, "://") {
+				files = append(files, file)
+			}
+		}
+		for _, child := range value {
+			files = append(files, externalRefs(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			files = append(files, externalRefs(child)...)
+		}
+	}
+	return files
+}
@@ -0,0 +1,110 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLookupPointer(t *testing.T) {
+	doc, err := DecodeDocument([]byte(`{"paths": {"/pets/{id}": {"get": {"tags": ["a", "b"]}}}, "x~y": 1}`), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+		wantErr bool
+	}{
+		{pointer: "/paths/~1pets~1{id}/get/tags/1", want: "b"},
+		{pointer: "/x~0y", want: float64(1)},
+		{pointer: "/paths/~1missing", wantErr: true},
+		{pointer: "/paths/~1pets~1{id}/get/tags/2", wantErr: true},
+		{pointer: "paths", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := LookupPointer(doc, tt.pointer)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LookupPointer(%q) error = %v, wantErr %v", tt.pointer, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LookupPointer(%q) = %v, want %v", tt.pointer, got, tt.want)
+		}
+	}
+}
+
+func TestRefLocationResolve(t *testing.T) {
+	dir := t.TempDir()
+	commonPath := filepath.Join(dir, "shared", "common.yaml")
+	if err := os.MkdirAll(filepath.Dir(commonPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(commonPath, []byte("schemas:\n  Pet:\n    type: object\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	location := RefLocation{
+		Path: filepath.Join(dir, "openapi.json"),
+		Root: map[string]interface{}{"components": map[string]interface{}{"x": "local"}},
+	}
+
+	value, target, err := location.Resolve("#/components/x")
+	if err != nil || value != "local" || target.Path != location.Path {
+		t.Errorf("local Resolve() = %v, %v, %v", value, target.Path, err)
+	}
+
+	value, target, err = location.Resolve("shared/common.yaml#/schemas/Pet")
+	if err != nil {
+		t.Fatalf("file Resolve() error = %v", err)
+	}
+	if want := map[string]interface{}{"type": "object"}; !reflect.DeepEqual(value, want) {
+		t.Errorf("file Resolve() = %v, want %v", value, want)
+	}
+	if target.Path != commonPath {
+		t.Errorf("file Resolve() location = %q, want %q", target.Path, commonPath)
+	}
+
+	if _, _, err := location.Resolve("https://example.com/common.yaml#/x"); err == nil {
+		t.Error("remote refs should not be supported")
+	}
+}
+
+func TestExternalRefFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		return path
+	}
+
+	errorsPath := write("shared/errors.yaml", "Error:\n  type: object\n")
+	commonPath := write("shared/common.yaml", "Pet:\n  properties:\n    error:\n      $ref: 'errors.yaml#/Error'\n")
+	specPath := write("funding/openapi.json", `{"openapi":"3.0.0","paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"../shared/common.yaml#/Pet"}}}},
+		"400":{"$ref":"#/components/responses/BadRequest"},
+		"404":{"$ref":"../shared/missing.yaml#/NotFound"},
+		"500":{"$ref":"https://example.com/errors.yaml#/Error"}}}}},
+		"components":{"schemas":{"Self":{"$ref":"openapi.json#/components/schemas/Other"}}}}`)
+
+	// Transitive refs are followed, missing files listed, and local, remote and self refs skipped
+	want := []string{commonPath, errorsPath, filepath.Join(dir, "shared", "missing.yaml")}
+	if got := ExternalRefFiles(specPath, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExternalRefFiles() = %v, want %v", got, want)
+	}
+
+	standalone := write("holidays/openapi.json", `{"openapi":"3.0.0","paths":{}}`)
+	if got := ExternalRefFiles(standalone, nil); len(got) != 0 {
+		t.Errorf("ExternalRefFiles() without external refs = %v", got)
+	}
+	if got := ExternalRefFiles(filepath.Join(dir, "unknown.json"), nil); got != nil {
+		t.Errorf("ExternalRefFiles() for a missing spec = %v", got)
+	}
+}
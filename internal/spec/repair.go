@@ -0,0 +1,346 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PlaceholderInfoVersion is the info.version Repair adds to specs without one
+const PlaceholderInfoVersion = "0.0.0"
+
+// shortOpenAPIVersion matches an openapi version missing its patch number, e.g. "3.0"
+var shortOpenAPIVersion = regexp.MustCompile(`^3\.\d+$`)
+
+// RepairChange is one fix applied by Repair
+type RepairChange struct {
+	// Pointer is the JSON pointer of the changed value, e.g. "/info/version"
+	Pointer string `json:"pointer"`
+
+	// Old is the previous value, empty if the value was added
+	Old string `json:"old,omitempty"`
+
+	// New is the value after the fix
+	New string `json:"new"`
+
+	// Reason describes the fix
+	Reason string `json:"reason"`
+}
+
+// Repair applies conservative fixes to spec content: an openapi version missing its patch
+// number ("3.0") gets one, a missing info.version gets PlaceholderInfoVersion, and operations
+// without an operationId get one derived from their method and path. Fixes only add or
+// complete values, never remove anything. Key order is kept, and so are comments in YAML.
+// The extension (e.g. ".json", ".yaml") selects the output format. Returns the repaired
+// content and the changes; without changes, the content is returned as is.
+func Repair(data []byte, ext string) ([]byte, []RepairChange, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("spec is not an object")
+	}
+	doc := root.Content[0]
+
+	var changes []RepairChange
+	changes = append(changes, repairOpenAPIVersion(doc)...)
+	changes = append(changes, repairInfoVersion(doc)...)
+	changes = append(changes, repairOperationIDs(doc)...)
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	var repaired []byte
+	var err error
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		repaired, err = encodeYAMLNode(&root)
+	default:
+		repaired, err = encodeJSONNode(doc, jsonIndent(data))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode repaired spec: %w", err)
+	}
+	return repaired, changes, nil
+}
+
+// repairOpenAPIVersion completes an openapi version missing its patch number
+func repairOpenAPIVersion(doc *yaml.Node) []RepairChange {
+	version := mappingValue(doc, "openapi")
+	if version == nil || version.Kind != yaml.ScalarNode || !shortOpenAPIVersion.MatchString(version.Value) {
+		return nil
+	}
+
+	old := version.Value
+	version.Value = old + ".0"
+	version.Tag = "!!str"
+	if version.Style == 0 {
+		// A plain 3.0 is a YAML number; the fixed version must stay a string
+		version.Style = yaml.DoubleQuotedStyle
+	}
+	return []RepairChange{{
+		Pointer: "/openapi",
+		Old:     old,
+		New:     version.Value,
+		Reason:  "openapi version normalized to major.minor.patch",
+	}}
+}
+
+// repairInfoVersion adds a placeholder info.version, and the info object if it is missing
+func repairInfoVersion(doc *yaml.Node) []RepairChange {
+	info := mappingValue(doc, "info")
+	if info == nil {
+		info = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		insertMappingPair(doc, afterKey(doc, "openapi"), "info", info)
+	}
+	if info.Kind != yaml.MappingNode || mappingValue(info, "version") != nil {
+		return nil
+	}
+
+	insertMappingPair(info, len(info.Content)/2, "version", stringNode(PlaceholderInfoVersion))
+	return []RepairChange{{
+		Pointer: "/info/version",
+		New:     PlaceholderInfoVersion,
+		Reason:  "missing info.version set to a placeholder",
+	}}
+}
+
+// repairOperationIDs adds operationIds derived from method and path to operations without
+// one, numbering them if the derived operationId is already used
+func repairOperationIDs(doc *yaml.Node) []RepairChange {
+	paths := mappingValue(doc, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	type missing struct {
+		path, method string
+		operation    *yaml.Node
+	}
+	used := make(map[string]bool)
+	var operations []missing
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, paths.Content[i+1]
+		if item.Kind != yaml.MappingNode || mappingValue(item, "$ref") != nil {
+			continue
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			method, operation := item.Content[j].Value, item.Content[j+1]
+			if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+			if id := mappingValue(operation, "operationId"); id != nil {
+				used[id.Value] = true
+				continue
+			}
+			operations = append(operations, missing{path: path, method: method, operation: operation})
+		}
+	}
+
+	var changes []RepairChange
+	for _, op := range operations {
+		base := DeriveOperationID(op.method, op.path)
+		id := base
+		for n := 2; used[id]; n++ {
+			id = base + strconv.Itoa(n)
+		}
+		used[id] = true
+
+		insertMappingPair(op.operation, 0, "operationId", stringNode(id))
+		changes = append(changes, RepairChange{
+			Pointer: "/paths/" + escapePointerToken(op.path) + "/" + op.method + "/operationId",
+			New:     id,
+			Reason:  fmt.Sprintf("missing operationId derived from %s %s", strings.ToUpper(op.method), op.path),
+		})
+	}
+	return changes
+}
+
+// DeriveOperationID derives an operationId from an operation's method and path, e.g.
+// "getUsersUserIdOrders" for GET /users/{userId}/orders, or "getRoot" for GET /
+func DeriveOperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		words = []string{"root"}
+	}
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// isHTTPMethod reports whether a path item key holds an operation
+func isHTTPMethod(key string) bool {
+	for _, method := range HTTPMethods {
+		if key == method {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// afterKey returns the pair index following a key in a mapping node, or 0 if it is missing
+func afterKey(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i/2 + 1
+		}
+	}
+	return 0
+}
+
+// insertMappingPair inserts a key and value as the pair at index in a mapping node
+func insertMappingPair(mapping *yaml.Node, index int, key string, value *yaml.Node) {
+	pair := []*yaml.Node{stringNode(key), value}
+	content := make([]*yaml.Node, 0, len(mapping.Content)+2)
+	content = append(content, mapping.Content[:2*index]...)
+	content = append(content, pair...)
+	mapping.Content = append(content, mapping.Content[2*index:]...)
+}
+
+// stringNode returns a string scalar node
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// escapePointerToken escapes a JSON pointer token (RFC 6901)
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// encodeYAMLNode encodes a YAML document with two-space indentation
+func encodeYAMLNode(root *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonIndent returns the indentation of JSON content, taken from its first indented line,
+// or "" for single-line content
+func jsonIndent(data []byte) string {
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines[1:] {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return ""
+}
+
+// encodeJSONNode encodes a node decoded from JSON back to JSON, keeping key order and
+// number formatting, indented with indent ("" for compact output)
+func encodeJSONNode(node *yaml.Node, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSONNode(&buf, node); err != nil {
+		return nil, err
+	}
+	if indent == "" {
+		return append(buf.Bytes(), '\n'), nil
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", indent); err != nil {
+		return nil, err
+	}
+	return append(indented.Bytes(), '\n'), nil
+}
+
+// writeJSONNode writes a node as compact JSON
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.AliasNode:
+		target := node.Alias
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			target = node.Content[0]
+		}
+		if target == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, target)
+
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONString(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float", "!!bool":
+			buf.WriteString(node.Value)
+		case "!!null":
+			buf.WriteString("null")
+		default:
+			return writeJSONString(buf, node.Value)
+		}
+
+	default:
+		return fmt.Errorf("unsupported node at line %d", node.Line)
+	}
+	return nil
+}
+
+// writeJSONString writes a JSON string without escaping HTML characters
+func writeJSONString(buf *bytes.Buffer, value string) error {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
@@ -0,0 +1,176 @@
+package spec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepairYAML(t *testing.T) {
+	input := `# Funding API
+openapi: 3.0
+paths:
+  /users/{userId}/orders:
+    get:
+      responses:
+        "200":
+          description: OK # listed
+    post:
+      operationId: createOrder
+  /:
+    get: {}
+  /shared:
+    $ref: common.yaml#/paths/shared
+`
+	repaired, changes, err := Repair([]byte(input), ".yaml")
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	want := []RepairChange{
+		{Pointer: "/openapi", Old: "3.0", New: "3.0.0", Reason: "openapi version normalized to major.minor.patch"},
+		{Pointer: "/info/version", New: "0.0.0", Reason: "missing info.version set to a placeholder"},
+		{Pointer: "/paths/~1users~1{userId}~1orders/get/operationId", New: "getUsersUserIdOrders", Reason: "missing operationId derived from GET /users/{userId}/orders"},
+		{Pointer: "/paths/~1/get/operationId", New: "getRoot", Reason: "missing operationId derived from GET /"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v\nwant %+v", changes, want)
+	}
+
+	// Comments and key order survive; the info object is added after openapi
+	wantOutput := `# Funding API
+openapi: "3.0.0"
+info:
+  version: 0.0.0
+paths:
+  /users/{userId}/orders:
+    get:
+      operationId: getUsersUserIdOrders
+      responses:
+        "200":
+          description: OK # listed
+    post:
+      operationId: createOrder
+  /:
+    get: {operationId: getRoot}
+  /shared:
+    $ref: common.yaml#/paths/shared
+`
+	if string(repaired) != wantOutput {
+		t.Errorf("repaired spec =\n%s\nwant\n%s", repaired, wantOutput)
+	}
+
+	// The repaired spec decodes, and repairing it again changes nothing
+	doc, err := DecodeDocument(repaired, ".yaml")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	if doc["openapi"] != "3.0.0" || InfoVersion(doc) != "0.0.0" {
+		t.Errorf("decoded openapi = %v, info.version = %q", doc["openapi"], InfoVersion(doc))
+	}
+	again, changes, err := Repair(repaired, ".yaml")
+	if err != nil || len(changes) != 0 || string(again) != string(repaired) {
+		t.Errorf("second Repair() = %d changes, %v", len(changes), err)
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	input := `{
+    "openapi": "3.1",
+    "info": {"title": "Funding <API>", "x-rate": 1.50},
+    "paths": {
+        "/withdrawals": {
+            "get": {"operationId": "getWithdrawals"},
+            "post": {"deprecated": false, "tags": null}
+        },
+        "/withdrawals/": {"get": {}}
+    }
+}
+`
+	repaired, changes, err := Repair([]byte(input), ".json")
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	var pointers []string
+	for _, change := range changes {
+		pointers = append(pointers, change.Pointer+"="+change.New)
+	}
+	wantPointers := []string{
+		"/openapi=3.1.0",
+		"/info/version=0.0.0",
+		"/paths/~1withdrawals/post/operationId=postWithdrawals",
+		// The derived operationId is taken, so it is numbered
+		"/paths/~1withdrawals~1/get/operationId=getWithdrawals2",
+	}
+	if !reflect.DeepEqual(pointers, wantPointers) {
+		t.Errorf("changes = %v, want %v", pointers, wantPointers)
+	}
+
+	// Key order, number formatting, HTML characters and the original indentation are kept
+	wantOutput := `{
+    "openapi": "3.1.0",
+    "info": {
+        "title": "Funding <API>",
+        "x-rate": 1.50,
+        "version": "0.0.0"
+    },
+    "paths": {
+        "/withdrawals": {
+            "get": {
+                "operationId": "getWithdrawals"
+            },
+            "post": {
+                "operationId": "postWithdrawals",
+                "deprecated": false,
+                "tags": null
+            }
+        },
+        "/withdrawals/": {
+            "get": {
+                "operationId": "getWithdrawals2"
+            }
+        }
+    }
+}
+`
+	if string(repaired) != wantOutput {
+		t.Errorf("repaired spec =\n%s\nwant\n%s", repaired, wantOutput)
+	}
+}
+
+func TestRepairUnchanged(t *testing.T) {
+	input := `{"openapi":"3.0.3","info":{"version":"1.2.0"},"paths":{"/pets":{"get":{"operationId":"listPets"}}}}`
+	repaired, changes, err := Repair([]byte(input), ".json")
+	if err != nil || len(changes) != 0 || string(repaired) != input {
+		t.Errorf("Repair() of a valid spec = %q, %v, %v; want it unchanged", repaired, changes, err)
+	}
+
+	// Compact specs stay compact
+	repaired, _, err = Repair([]byte(`{"openapi":"3.0.3","info":{}}`), ".json")
+	if err != nil || strings.TrimSpace(string(repaired)) != `{"openapi":"3.0.3","info":{"version":"0.0.0"}}` {
+		t.Errorf("Repair() of a compact spec = %q, %v", repaired, err)
+	}
+
+	for _, invalid := range []string{"[1, 2]", "openapi: [", ""} {
+		if _, _, err := Repair([]byte(invalid), ".yaml"); err == nil {
+			t.Errorf("Repair(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestDeriveOperationID(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/users/{userId}/orders", "getUsersUserIdOrders"},
+		{"post", "/payment-methods/{method_id}", "postPaymentMethodsMethodId"},
+		{"delete", "/v1.2/items", "deleteV12Items"},
+		{"get", "/", "getRoot"},
+	}
+	for _, tt := range tests {
+		if got := DeriveOperationID(tt.method, tt.path); got != tt.want {
+			t.Errorf("DeriveOperationID(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
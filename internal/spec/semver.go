@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version parsed from a spec's info.version
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// InfoVersion returns the info.version of a decoded spec document, or "" if it has none
+func InfoVersion(doc map[string]interface{}) string {
+	info, _ := doc["info"].(map[string]interface{})
+	switch version := info["version"].(type) {
+	case string:
+		return strings.TrimSpace(version)
+	case float64:
+		// Unquoted YAML versions such as `version: 1.2` decode as numbers
+		return strconv.FormatFloat(version, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// ParseVersion parses a semantic version such as "1.2.3", "v1.2.3-beta.1" or "1.2".
+// Missing minor and patch numbers are 0, and build metadata ("+build.5") is ignored.
+// Other formats, such as dates, return an error.
+func ParseVersion(s string) (Version, error) {
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	core, _, _ = strings.Cut(core, "+")
+	core, prerelease, hasPrerelease := strings.Cut(core, "-")
+	if hasPrerelease && prerelease == "" {
+		return Version{}, fmt.Errorf("%q is not a semantic version: empty pre-release", s)
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("%q is not a semantic version: too many components", s)
+	}
+	// Only complete versions take a pre-release, so dates such as "2024-01-15" are rejected
+	if hasPrerelease && len(parts) != 3 {
+		return Version{}, fmt.Errorf("%q is not a semantic version", s)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part == "" || (len(part) > 1 && part[0] == '0') {
+			return Version{}, fmt.Errorf("%q is not a semantic version", s)
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Prerelease: prerelease}, nil
+}
+
+// String returns the version as "MAJOR.MINOR.PATCH[-PRERELEASE]"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than other,
+// following semver precedence (a pre-release is lower than its release)
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c := compareInts(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers: numeric identifiers
+// compare numerically and sort before alphanumeric ones, and a shorter prefix sorts first
+func comparePrerelease(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		leftNum, leftErr := strconv.Atoi(left[i])
+		rightNum, rightErr := strconv.Atoi(right[i])
+		switch {
+		case leftErr == nil && rightErr == nil:
+			if c := compareInts(leftNum, rightNum); c != 0 {
+				return c
+			}
+		case leftErr == nil:
+			return -1
+		case rightErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(left[i], right[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(left), len(right))
+}
+
+// compareInts returns -1, 0 or 1 as a is lower than, equal to or higher than b
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,90 @@
+package spec
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{input: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v2.0.1", want: Version{Major: 2, Minor: 0, Patch: 1}},
+		{input: "1.2", want: Version{Major: 1, Minor: 2}},
+		{input: "3", want: Version{Major: 3}},
+		{input: "1.0.0-beta.2+build.7", want: Version{Major: 1, Prerelease: "beta.2"}},
+		{input: "2024-01-15", wantErr: true},
+		{input: "latest", wantErr: true},
+		{input: "1.2.3.4", wantErr: true},
+		{input: "01.2.3", wantErr: true},
+		{input: "1.0.0-", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2", b: "v1.2.0", want: 0},
+		{a: "1.2.4", b: "1.2.3", want: 1},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "1.9.9", b: "2.0.0", want: -1},
+		{a: "1.0.0-alpha", b: "1.0.0", want: -1},
+		{a: "1.0.0-alpha.1", b: "1.0.0-alpha", want: 1},
+		{a: "1.0.0-alpha.2", b: "1.0.0-alpha.10", want: -1},
+		{a: "1.0.0-beta", b: "1.0.0-alpha.1", want: 1},
+		{a: "1.0.0-1", b: "1.0.0-alpha", want: -1},
+		{a: "1.0.0+build.1", b: "1.0.0+build.2", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, errA := ParseVersion(tt.a)
+			b, errB := ParseVersion(tt.b)
+			if errA != nil || errB != nil {
+				t.Fatalf("ParseVersion() errors = %v, %v", errA, errB)
+			}
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare() = %d, want %d", got, tt.want)
+			}
+			if got := b.Compare(a); got != -tt.want {
+				t.Errorf("reverse Compare() = %d, want %d", got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  map[string]interface{}
+		want string
+	}{
+		{name: "string", doc: map[string]interface{}{"info": map[string]interface{}{"version": " 1.2.3 "}}, want: "1.2.3"},
+		{name: "unquoted YAML number", doc: map[string]interface{}{"info": map[string]interface{}{"version": 1.2}}, want: "1.2"},
+		{name: "missing info", doc: map[string]interface{}{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InfoVersion(tt.doc); got != tt.want {
+				t.Errorf("InfoVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
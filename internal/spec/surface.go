@@ -0,0 +1,118 @@
+package spec
+
+import "sort"
+
+// OperationSummary is what comparisons need to know about an operation, so that a spec can
+// be compared against a stored summary instead of the full previous version
+type OperationSummary struct {
+	// Hash is the hash of the operation, with path-level parameters merged in
+	Hash string `json:"hash"`
+
+	// Parameters maps each parameter key ("in:name", or "ref:<$ref>" for referenced
+	// parameters) to whether the parameter is required
+	Parameters map[string]bool `json:"parameters,omitempty"`
+
+	// RequestBodyRequired reports whether the request body is marked required
+	RequestBodyRequired bool `json:"request_body_required,omitempty"`
+
+	// Responses lists the documented response status codes, sorted
+	Responses []string `json:"responses,omitempty"`
+}
+
+// Surface summarizes the operations and reusable components of a spec.
+// Operations are keyed by "METHOD /path" and components by "section/name".
+type Surface struct {
+	Operations map[string]OperationSummary `json:"operations"`
+	Components map[string]string           `json:"components"`
+}
+
+// SummarizeSurface summarizes a decoded spec for CompareSurfaces
+func SummarizeSurface(doc map[string]interface{}) *Surface {
+	surface := &Surface{
+		Operations: make(map[string]OperationSummary),
+		Components: make(map[string]string),
+	}
+
+	for key, operation := range collectOperations(doc) {
+		summary := OperationSummary{
+			Hash:                hashSection(operation),
+			RequestBodyRequired: isRequired(operation["requestBody"]),
+		}
+		parameters, _ := operation["parameters"].(map[string]interface{})
+		for paramKey, param := range parameters {
+			if summary.Parameters == nil {
+				summary.Parameters = make(map[string]bool, len(parameters))
+			}
+			summary.Parameters[paramKey] = isRequired(param)
+		}
+		responses, _ := operation["responses"].(map[string]interface{})
+		if len(responses) > 0 {
+			summary.Responses = sortedMapKeys(responses)
+		}
+		surface.Operations[key] = summary
+	}
+
+	for name, component := range collectComponents(doc) {
+		surface.Components[name] = hashSection(component)
+	}
+	return surface
+}
+
+// breakingView rebuilds the parts of the operation that breakingReasons inspects
+func (s OperationSummary) breakingView() map[string]interface{} {
+	parameters := make(map[string]interface{}, len(s.Parameters))
+	for key, required := range s.Parameters {
+		parameters[key] = map[string]interface{}{"required": required}
+	}
+	responses := make(map[string]interface{}, len(s.Responses))
+	for _, status := range s.Responses {
+		responses[status] = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"parameters":  parameters,
+		"requestBody": map[string]interface{}{"required": s.RequestBodyRequired},
+		"responses":   responses,
+	}
+}
+
+// CompareSurfaces compares two summarized versions of a spec at the operation level
+func CompareSurfaces(previous, current *Surface) *Comparison {
+	comparison := &Comparison{}
+	for key, operation := range current.Operations {
+		old, ok := previous.Operations[key]
+		switch {
+		case !ok:
+			comparison.Added = append(comparison.Added, key)
+		case old.Hash != operation.Hash:
+			comparison.Modified = append(comparison.Modified, OperationChange{
+				Operation:       key,
+				BreakingReasons: breakingReasons(old.breakingView(), operation.breakingView()),
+			})
+		}
+	}
+	for key := range previous.Operations {
+		if _, ok := current.Operations[key]; !ok {
+			comparison.Deleted = append(comparison.Deleted, key)
+		}
+	}
+
+	for name, hash := range current.Components {
+		if old, ok := previous.Components[name]; !ok || old != hash {
+			comparison.ChangedComponents = append(comparison.ChangedComponents, name)
+		}
+	}
+	for name := range previous.Components {
+		if _, ok := current.Components[name]; !ok {
+			comparison.DeletedComponents = append(comparison.DeletedComponents, name)
+		}
+	}
+
+	sort.Strings(comparison.Added)
+	sort.Strings(comparison.Deleted)
+	sort.Slice(comparison.Modified, func(i, j int) bool {
+		return comparison.Modified[i].Operation < comparison.Modified[j].Operation
+	})
+	sort.Strings(comparison.ChangedComponents)
+	sort.Strings(comparison.DeletedComponents)
+	return comparison
+}
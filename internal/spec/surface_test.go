@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompareSurfacesMatchesDocuments(t *testing.T) {
+	previous, err := DecodeDocument([]byte(previousPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	current, err := DecodeDocument([]byte(currentPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+
+	// A stored summary compares like the document it was taken from
+	data, err := json.Marshal(SummarizeSurface(previous))
+	if err != nil {
+		t.Fatalf("Failed to encode surface: %v", err)
+	}
+	var stored Surface
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Failed to decode surface: %v", err)
+	}
+
+	got := CompareSurfaces(&stored, SummarizeSurface(current))
+	if want := CompareDocuments(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareSurfaces() = %+v, want %+v", got, want)
+	}
+	if len(got.Breaking()) != 4 {
+		t.Errorf("Breaking() = %v, want 4 breaking changes", got.Breaking())
+	}
+}
+
+func TestSummarizeSurface(t *testing.T) {
+	doc, err := DecodeDocument([]byte(previousPetstore), ".json")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	surface := SummarizeSurface(doc)
+
+	getPet := surface.Operations["GET /pets/{id}"]
+	if !reflect.DeepEqual(getPet.Parameters, map[string]bool{"path:id": true}) {
+		t.Errorf("GET /pets/{id} parameters = %v, want the path-level id parameter", getPet.Parameters)
+	}
+	if !reflect.DeepEqual(getPet.Responses, []string{"200", "404"}) {
+		t.Errorf("GET /pets/{id} responses = %v", getPet.Responses)
+	}
+	if len(surface.Operations) != 4 || len(surface.Components) != 2 || surface.Components["schemas/Pet"] == "" {
+		t.Errorf("surface = %+v, want 4 operations and 2 components", surface)
+	}
+}
@@ -0,0 +1,506 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// swaggerRefPrefixes maps Swagger 2.0 ref prefixes to their OpenAPI 3.0 components
+var swaggerRefPrefixes = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// swaggerSchemaFields are the parameter and header fields that move into their schema
+var swaggerSchemaFields = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// IsSwagger2 reports whether a decoded document is a Swagger 2.0 spec
+func IsSwagger2(doc map[string]interface{}) bool {
+	version, _ := doc["swagger"].(string)
+	return version == "2.0"
+}
+
+// ConvertSwagger2 converts a decoded Swagger 2.0 document to OpenAPI 3.0.3 and returns the
+// new document; doc is not modified. host, basePath and schemes become servers, definitions,
+// global parameters and responses, and securityDefinitions move under components, body and
+// formData parameters become request bodies, and response schemas become content for each
+// produced media type. Returns a note for each construct that could not be converted, as
+// "<pointer>: <note>", sorted.
+func ConvertSwagger2(doc map[string]interface{}) (map[string]interface{}, []string) {
+	src := rewriteSwaggerRefs(copyValue(doc)).(map[string]interface{})
+	c := &swaggerConverter{
+		src:      src,
+		consumes: mediaTypes(src["consumes"], "application/json"),
+		produces: mediaTypes(src["produces"], "application/json"),
+	}
+
+	out := map[string]interface{}{"openapi": OpenAPI30Version}
+	for key, value := range src {
+		switch key {
+		case "swagger", "host", "basePath", "schemes", "consumes", "produces", "paths",
+			"definitions", "parameters", "responses", "securityDefinitions":
+		default:
+			// info, tags, security, externalDocs and extensions are unchanged
+			out[key] = value
+		}
+	}
+	if servers := c.servers(); len(servers) > 0 {
+		out["servers"] = servers
+	}
+
+	components := map[string]interface{}{}
+	if definitions, ok := src["definitions"].(map[string]interface{}); ok {
+		for _, schema := range definitions {
+			convertSwaggerSchema(schema)
+		}
+		components["schemas"] = definitions
+	}
+	if parameters, ok := src["parameters"].(map[string]interface{}); ok {
+		c.convertGlobalParameters(parameters, components)
+	}
+	if responses, ok := src["responses"].(map[string]interface{}); ok {
+		converted := map[string]interface{}{}
+		for name, response := range responses {
+			converted[name] = c.convertResponse(response, c.produces)
+		}
+		components["responses"] = converted
+	}
+	if definitions, ok := src["securityDefinitions"].(map[string]interface{}); ok {
+		schemes := map[string]interface{}{}
+		for name, definition := range definitions {
+			schemes[name] = c.convertSecurityScheme(definition, "/securityDefinitions/"+escapePointerToken(name))
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	paths := map[string]interface{}{}
+	if srcPaths, ok := src["paths"].(map[string]interface{}); ok {
+		for path, item := range srcPaths {
+			if item, ok := item.(map[string]interface{}); ok {
+				paths[path] = c.convertPathItem(item, "/paths/"+escapePointerToken(path))
+			} else {
+				paths[path] = item
+			}
+		}
+	}
+	out["paths"] = paths
+
+	sort.Strings(c.notes)
+	return out, c.notes
+}
+
+// swaggerConverter holds the document-wide defaults and notes of a conversion
+type swaggerConverter struct {
+	src      map[string]interface{}
+	consumes []string
+	produces []string
+	notes    []string
+}
+
+// note records a construct that could not be converted
+func (c *swaggerConverter) note(pointer, format string, args ...interface{}) {
+	c.notes = append(c.notes, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+// rewriteSwaggerRefs points the $refs of a document tree at their OpenAPI 3.0 components
+func rewriteSwaggerRefs(node interface{}) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				for prefix, replacement := range swaggerRefPrefixes {
+					if strings.HasPrefix(ref, prefix) {
+						value[key] = replacement + strings.TrimPrefix(ref, prefix)
+					}
+				}
+				continue
+			}
+			rewriteSwaggerRefs(child)
+		}
+	case []interface{}:
+		for _, child := range value {
+			rewriteSwaggerRefs(child)
+		}
+	}
+	return node
+}
+
+// mediaTypes returns a consumes or produces list, or fallback when it is empty
+func mediaTypes(value interface{}, fallback ...string) []string {
+	var types []string
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		if mediaType, ok := item.(string); ok {
+			types = append(types, mediaType)
+		}
+	}
+	if len(types) == 0 {
+		return fallback
+	}
+	return types
+}
+
+// servers builds the server list from host, basePath and schemes
+func (c *swaggerConverter) servers() []interface{} {
+	host, _ := c.src["host"].(string)
+	basePath, _ := c.src["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	var servers []interface{}
+	for _, scheme := range mediaTypes(c.src["schemes"], "https") {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+// convertGlobalParameters adds the global parameters to components: body parameters become
+// request bodies, and formData parameters are inlined where they are used
+func (c *swaggerConverter) convertGlobalParameters(parameters, components map[string]interface{}) {
+	converted := map[string]interface{}{}
+	requestBodies := map[string]interface{}{}
+	for name, rawParam := range parameters {
+		param, ok := rawParam.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch param["in"] {
+		case "body":
+			requestBodies[name] = c.bodyRequestBody(param, c.consumes)
+		case "formData":
+		default:
+			converted[name] = c.convertParameter(param, "/parameters/"+escapePointerToken(name))
+		}
+	}
+	if len(converted) > 0 {
+		components["parameters"] = converted
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+}
+
+// convertPathItem converts the parameters and operations of a path item
+func (c *swaggerConverter) convertPathItem(item map[string]interface{}, pointer string) map[string]interface{} {
+	// Body and formData parameters of the path item belong to each operation's request body
+	var shared []interface{}
+	if parameters, ok := item["parameters"].([]interface{}); ok {
+		var kept []interface{}
+		for _, param := range parameters {
+			if in := c.parameterLocation(param); in == "body" || in == "formData" {
+				shared = append(shared, param)
+			} else {
+				kept = append(kept, c.convertParameter(param, pointer+"/parameters"))
+			}
+		}
+		if len(kept) > 0 {
+			item["parameters"] = kept
+		} else {
+			delete(item, "parameters")
+		}
+	}
+
+	for _, method := range HTTPMethods {
+		if operation, ok := item[method].(map[string]interface{}); ok {
+			c.convertOperation(operation, shared, pointer+"/"+method)
+		}
+	}
+	return item
+}
+
+// convertOperation converts an operation in place
+func (c *swaggerConverter) convertOperation(operation map[string]interface{}, shared []interface{}, pointer string) {
+	consumes := mediaTypes(operation["consumes"], c.consumes...)
+	produces := mediaTypes(operation["produces"], c.produces...)
+	delete(operation, "consumes")
+	delete(operation, "produces")
+	delete(operation, "schemes")
+
+	var parameters, formData []interface{}
+	rawParameters, _ := operation["parameters"].([]interface{})
+	for _, param := range append(append([]interface{}{}, shared...), rawParameters...) {
+		switch c.parameterLocation(param) {
+		case "body":
+			if ref, ok := param.(map[string]interface{})["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/parameters/")
+				operation["requestBody"] = map[string]interface{}{"$ref": "#/components/requestBodies/" + name}
+			} else {
+				operation["requestBody"] = c.bodyRequestBody(param.(map[string]interface{}), consumes)
+			}
+		case "formData":
+			formData = append(formData, c.resolveParameter(param))
+		default:
+			parameters = append(parameters, c.convertParameter(param, pointer+"/parameters"))
+		}
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	} else {
+		delete(operation, "parameters")
+	}
+	if len(formData) > 0 {
+		operation["requestBody"] = c.formRequestBody(formData, consumes)
+	}
+
+	if responses, ok := operation["responses"].(map[string]interface{}); ok {
+		for code, response := range responses {
+			responses[code] = c.convertResponse(response, produces)
+		}
+	}
+}
+
+// parameterLocation returns the `in` of a parameter, following a $ref to a global parameter
+func (c *swaggerConverter) parameterLocation(param interface{}) string {
+	in, _ := c.resolveParameter(param)["in"].(string)
+	return in
+}
+
+// resolveParameter returns a parameter, or the global parameter it references
+func (c *swaggerConverter) resolveParameter(param interface{}) map[string]interface{} {
+	obj, _ := param.(map[string]interface{})
+	ref, ok := obj["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/components/parameters/") {
+		return obj
+	}
+	globals, _ := c.src["parameters"].(map[string]interface{})
+	resolved, _ := globals[strings.TrimPrefix(ref, "#/components/parameters/")].(map[string]interface{})
+	if resolved == nil {
+		return obj
+	}
+	return copyValue(resolved).(map[string]interface{})
+}
+
+// convertParameter moves the type fields of a non-body parameter into its schema and its
+// collectionFormat into style and explode. Refs are kept.
+func (c *swaggerConverter) convertParameter(param interface{}, pointer string) interface{} {
+	obj, ok := param.(map[string]interface{})
+	if !ok {
+		return param
+	}
+	if _, ok := obj["$ref"]; ok {
+		return obj
+	}
+	in, _ := obj["in"].(string)
+	name, _ := obj["name"].(string)
+
+	// csv, the Swagger 2.0 default, is the 3.0 default for path and header parameters, but
+	// query arrays default to exploded in 3.0
+	switch format, _ := obj["collectionFormat"].(string); format {
+	case "", "csv":
+		if in == "query" && obj["type"] == "array" {
+			obj["style"], obj["explode"] = "form", false
+		}
+	case "multi":
+		obj["style"], obj["explode"] = "form", true
+	case "ssv":
+		obj["style"] = "spaceDelimited"
+	case "pipes":
+		obj["style"] = "pipeDelimited"
+	default:
+		c.note(pointer, "parameter %s: collectionFormat %s has no OpenAPI 3.0 equivalent", name, format)
+	}
+	delete(obj, "collectionFormat")
+
+	obj["schema"] = moveSchemaFields(obj)
+	return obj
+}
+
+// moveSchemaFields moves the schema fields of a parameter, header or formData field into a
+// new schema and returns it
+func moveSchemaFields(obj map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+	for _, field := range swaggerSchemaFields {
+		if value, ok := obj[field]; ok {
+			schema[field] = value
+			delete(obj, field)
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		delete(items, "collectionFormat")
+	}
+	convertSwaggerSchema(schema)
+	return schema
+}
+
+// bodyRequestBody converts a body parameter into a request body with its schema for each
+// consumed media type
+func (c *swaggerConverter) bodyRequestBody(param map[string]interface{}, consumes []string) map[string]interface{} {
+	schema, _ := param["schema"].(map[string]interface{})
+	convertSwaggerSchema(schema)
+
+	content := map[string]interface{}{}
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	body := map[string]interface{}{"content": content}
+	if description, ok := param["description"]; ok {
+		body["description"] = description
+	}
+	if required, ok := param["required"]; ok {
+		body["required"] = required
+	}
+	return body
+}
+
+// formRequestBody converts formData parameters into an object schema request body, as
+// multipart/form-data when a field is a file or it is consumed, and url-encoded otherwise
+func (c *swaggerConverter) formRequestBody(fields []interface{}, consumes []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+	multipart := false
+	for _, rawField := range fields {
+		field, ok := rawField.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		if field["type"] == "file" {
+			multipart = true
+		}
+		schema := moveSchemaFields(field)
+		if description, ok := field["description"]; ok {
+			schema["description"] = description
+		}
+		properties[name] = schema
+		if isRequired, _ := field["required"].(bool); isRequired {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart || containsString(consumes, "multipart/form-data") {
+		mediaType = "multipart/form-data"
+	}
+	return map[string]interface{}{
+		"content": map[string]interface{}{mediaType: map[string]interface{}{"schema": schema}},
+	}
+}
+
+// convertResponse moves a response's schema and examples into content for each produced
+// media type, and the type fields of its headers into schemas
+func (c *swaggerConverter) convertResponse(rawResponse interface{}, produces []string) interface{} {
+	response, ok := rawResponse.(map[string]interface{})
+	if !ok {
+		return rawResponse
+	}
+	if _, ok := response["$ref"]; ok {
+		return response
+	}
+
+	examples, _ := response["examples"].(map[string]interface{})
+	if schema, ok := response["schema"].(map[string]interface{}); ok {
+		convertSwaggerSchema(schema)
+		content := map[string]interface{}{}
+		for _, mediaType := range produces {
+			media := map[string]interface{}{"schema": schema}
+			if example, ok := examples[mediaType]; ok {
+				media["example"] = example
+			}
+			content[mediaType] = media
+		}
+		response["content"] = content
+	}
+	delete(response, "schema")
+	delete(response, "examples")
+
+	if headers, ok := response["headers"].(map[string]interface{}); ok {
+		for _, rawHeader := range headers {
+			if header, ok := rawHeader.(map[string]interface{}); ok {
+				header["schema"] = moveSchemaFields(header)
+			}
+		}
+	}
+	if _, ok := response["description"]; !ok {
+		response["description"] = ""
+	}
+	return response
+}
+
+// convertSecurityScheme converts a security definition; oauth2 flows move under `flows`
+func (c *swaggerConverter) convertSecurityScheme(rawDefinition interface{}, pointer string) interface{} {
+	definition, ok := rawDefinition.(map[string]interface{})
+	if !ok {
+		return rawDefinition
+	}
+
+	switch definition["type"] {
+	case "basic":
+		definition["type"] = "http"
+		definition["scheme"] = "basic"
+	case "oauth2":
+		flow := map[string]interface{}{}
+		for _, field := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if value, ok := definition[field]; ok {
+				flow[field] = value
+				delete(definition, field)
+			}
+		}
+		if _, ok := flow["scopes"]; !ok {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		flowName := map[interface{}]string{
+			"implicit": "implicit", "password": "password",
+			"application": "clientCredentials", "accessCode": "authorizationCode",
+		}[definition["flow"]]
+		if flowName == "" {
+			c.note(pointer, "unknown oauth2 flow %v", definition["flow"])
+			flowName = "implicit"
+		}
+		delete(definition, "flow")
+		definition["flows"] = map[string]interface{}{flowName: flow}
+	}
+	return definition
+}
+
+// convertSwaggerSchema converts the Swagger 2.0 keywords of a schema and its subschemas:
+// `type: file` becomes a binary string, `x-nullable` becomes `nullable`, and a discriminator
+// property name becomes a discriminator object
+func convertSwaggerSchema(rawSchema interface{}) {
+	schema, ok := rawSchema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if schema["type"] == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+	if nullable, ok := schema["x-nullable"].(bool); ok {
+		schema["nullable"] = nullable
+		delete(schema, "x-nullable")
+	}
+	if property, ok := schema["discriminator"].(string); ok {
+		schema["discriminator"] = map[string]interface{}{"propertyName": property}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			convertSwaggerSchema(property)
+		}
+	}
+	convertSwaggerSchema(schema["items"])
+	convertSwaggerSchema(schema["additionalProperties"])
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if subschemas, ok := schema[keyword].([]interface{}); ok {
+			for _, subschema := range subschemas {
+				convertSwaggerSchema(subschema)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+const petstoreSwagger = `
+swagger: "2.0"
+info: {title: Petstore, version: 1.0.0}
+host: petstore.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  basic: {type: basic}
+  oauth: {type: oauth2, flow: accessCode, authorizationUrl: https://auth/authorize, tokenUrl: https://auth/token, scopes: {read: Read pets}}
+parameters:
+  limit: {name: limit, in: query, type: integer, format: int32}
+  pet: {name: pet, in: body, required: true, schema: {$ref: '#/definitions/Pet'}}
+responses:
+  NotFound: {description: Not found, schema: {$ref: '#/definitions/Error'}}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: '#/parameters/limit'
+        - {name: tags, in: query, type: array, items: {type: string}}
+        - {name: ids, in: query, type: array, items: {type: integer}, collectionFormat: multi}
+      responses:
+        "200":
+          description: Pets
+          schema: {type: array, items: {$ref: '#/definitions/Pet'}}
+          headers:
+            X-Next: {type: string}
+        "404": {$ref: '#/responses/NotFound'}
+    post:
+      operationId: createPet
+      parameters:
+        - $ref: '#/parameters/pet'
+      responses:
+        "201": {description: Created}
+  /pets/{id}/photo:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+    put:
+      operationId: uploadPhoto
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: file, in: formData, type: file, required: true}
+        - {name: caption, in: formData, type: string}
+      responses:
+        "204": {description: Uploaded}
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind: {type: string}
+      nickname: {type: string, x-nullable: true}
+  Error:
+    type: object
+`
+
+func TestConvertSwagger2(t *testing.T) {
+	doc, err := DecodeDocument([]byte(petstoreSwagger), ".yaml")
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	if !IsSwagger2(doc) {
+		t.Fatal("IsSwagger2() = false")
+	}
+
+	converted, notes := ConvertSwagger2(doc)
+	if len(notes) != 0 {
+		t.Errorf("notes = %v, want none", notes)
+	}
+	if _, ok := doc["definitions"]; !ok {
+		t.Error("the source document should not be modified")
+	}
+
+	checks := map[string]interface{}{
+		"/openapi":       OpenAPI30Version,
+		"/servers/0/url": "https://petstore.example.com/v1",
+		"/servers/1/url": "http://petstore.example.com/v1",
+		"/info/title":    "Petstore",
+		"/components/schemas/Pet/discriminator/propertyName":                   "kind",
+		"/components/schemas/Pet/properties/nickname/nullable":                 true,
+		"/components/parameters/limit/schema/format":                           "int32",
+		"/components/requestBodies/pet/required":                               true,
+		"/components/requestBodies/pet/content/application~1json/schema/$ref":  "#/components/schemas/Pet",
+		"/components/responses/NotFound/content/application~1json/schema/$ref": "#/components/schemas/Error",
+		"/components/securitySchemes/basic/scheme":                             "basic",
+		"/components/securitySchemes/oauth/flows/authorizationCode/tokenUrl":   "https://auth/token",
+
+		"/paths/~1pets/get/parameters/0/$ref":                                         "#/components/parameters/limit",
+		"/paths/~1pets/get/parameters/1/schema/type":                                  "array",
+		"/paths/~1pets/get/parameters/1/explode":                                      false,
+		"/paths/~1pets/get/parameters/2/explode":                                      true,
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/items/$ref": "#/components/schemas/Pet",
+		"/paths/~1pets/get/responses/200/headers/X-Next/schema/type":                  "string",
+		"/paths/~1pets/get/responses/404/$ref":                                        "#/components/responses/NotFound",
+		"/paths/~1pets/post/requestBody/$ref":                                         "#/components/requestBodies/pet",
+
+		"/paths/~1pets~1{id}~1photo/parameters/0/schema/type":                                                   "string",
+		"/paths/~1pets~1{id}~1photo/put/requestBody/content/multipart~1form-data/schema/properties/file/format": "binary",
+		"/paths/~1pets~1{id}~1photo/put/requestBody/content/multipart~1form-data/schema/required":               []interface{}{"file"},
+	}
+	for pointer, want := range checks {
+		if got, err := LookupPointer(converted, pointer); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, %v; want %v", pointer, got, err, want)
+		}
+	}
+	for _, pointer := range []string{
+		"/swagger", "/host", "/definitions", "/securityDefinitions", "/consumes",
+		"/paths/~1pets/post/parameters", "/paths/~1pets/get/responses/200/schema",
+		"/paths/~1pets~1{id}~1photo/put/consumes",
+	} {
+		if _, err := LookupPointer(converted, pointer); err == nil {
+			t.Errorf("%s should be removed", pointer)
+		}
+	}
+	if got := CountOperations(converted); got != 3 {
+		t.Errorf("CountOperations() = %d, want 3", got)
+	}
+}
+
+func TestConvertSwagger2Notes(t *testing.T) {
+	doc := map[string]interface{}{
+		"swagger": "2.0",
+		"paths": map[string]interface{}{
+			"/items": map[string]interface{}{
+				"get": map[string]interface{}{
+					"parameters": []interface{}{
+						map[string]interface{}{"name": "ids", "in": "query", "type": "array", "collectionFormat": "tsv"},
+					},
+					"responses": map[string]interface{}{"200": map[string]interface{}{"description": "ok"}},
+				},
+			},
+		},
+	}
+
+	converted, notes := ConvertSwagger2(doc)
+	want := []string{"/paths/~1items/get/parameters: parameter ids: collectionFormat tsv has no OpenAPI 3.0 equivalent"}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("notes = %v, want %v", notes, want)
+	}
+	if _, err := LookupPointer(converted, "/servers"); err == nil {
+		t.Error("a spec without host or basePath should have no servers")
+	}
+}
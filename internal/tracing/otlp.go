@@ -0,0 +1,169 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/network"
+)
+
+// ServiceName is the OTLP service.name resource attribute of exported traces
+const ServiceName = "openapi-go"
+
+// otlpTracesPath is the OTLP/HTTP path for traces, appended to the configured endpoint
+const otlpTracesPath = "/v1/traces"
+
+// otlpExportTimeout bounds how long an export may take
+const otlpExportTimeout = 10 * time.Second
+
+// otlpSpanKindInternal is the OTLP SPAN_KIND_INTERNAL span kind
+const otlpSpanKindInternal = 1
+
+// OTLPExporter sends spans to an OpenTelemetry collector with OTLP/HTTP in its JSON encoding
+type OTLPExporter struct {
+	url string
+}
+
+// NewOTLPExporter creates an exporter for an OTLP/HTTP endpoint (e.g. http://localhost:4318).
+// Spans are posted to <endpoint>/v1/traces unless the endpoint already ends with that path.
+func NewOTLPExporter(endpoint string) *OTLPExporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, otlpTracesPath) {
+		url += otlpTracesPath
+	}
+	return &OTLPExporter{url: url}
+}
+
+// URL returns the URL spans are posted to
+func (e *OTLPExporter) URL() string {
+	return e.url
+}
+
+// Export posts the spans to the collector
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, otlpExportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create trace export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := network.NewHTTPClient(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export trace: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("trace export failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// OTLP JSON request types (ExportTraceServiceRequest), trimmed to the fields used here
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlpRequest converts spans into an OTLP export request. IDs are hex-encoded and
+// timestamps are decimal strings, as the OTLP JSON encoding requires.
+func otlpRequest(spans []SpanData) otlpExportRequest {
+	converted := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		converted[i] = otlpSpan{
+			TraceID:           hex.EncodeToString(span.TraceID[:]),
+			SpanID:            hex.EncodeToString(span.SpanID[:]),
+			Name:              span.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: span.StatusCode, Message: span.StatusMessage},
+		}
+		if span.ParentID != (SpanID{}) {
+			converted[i].ParentSpanID = hex.EncodeToString(span.ParentID[:])
+		}
+	}
+
+	return otlpExportRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: otlpAttributes(map[string]string{"service.name": ServiceName})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: ServiceName}, Spans: converted}},
+	}}}
+}
+
+// otlpAttributes converts string attributes, sorted by key
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	converted := make([]otlpAttribute, len(keys))
+	for i, key := range keys {
+		converted[i] = otlpAttribute{Key: key, Value: otlpAnyValue{StringValue: attributes[key]}}
+	}
+	return converted
+}
@@ -0,0 +1,186 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// Span status codes, as in OTLP
+const (
+	StatusUnset = 0
+	StatusOK    = 1
+	StatusError = 2
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// SpanData is a finished span
+type SpanData struct {
+	Name     string
+	TraceID  TraceID
+	SpanID   SpanID
+	ParentID SpanID // zero for the root span
+	Start    time.Time
+	End      time.Time
+
+	// Attributes are string attributes of the span, e.g. "openapi.service"
+	Attributes map[string]string
+
+	// StatusCode is StatusUnset, StatusOK or StatusError, with StatusMessage describing errors
+	StatusCode    int
+	StatusMessage string
+}
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Tracer records the spans of a single trace, e.g. a root span for a generation run and child
+// spans per spec and stage, and exports them on Flush. A nil *Tracer (tracing disabled) and
+// the nil spans it returns are no-ops, so callers never check whether tracing is enabled.
+type Tracer struct {
+	exporter Exporter
+	traceID  TraceID
+
+	mu       sync.Mutex
+	finished []SpanData
+}
+
+// NewTracer creates a tracer for a new trace exported through exporter
+func NewTracer(exporter Exporter) *Tracer {
+	t := &Tracer{exporter: exporter}
+	rand.Read(t.traceID[:])
+	return t
+}
+
+// Span is a span in progress. All methods are safe on a nil span.
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// spanContextKey is the context key of the current span
+type spanContextKey struct{}
+
+// Start starts a span as a child of the span in ctx (or as the root span) and returns a
+// context carrying it. On a nil tracer it returns ctx and a nil span.
+func (t *Tracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			TraceID:    t.traceID,
+			Start:      time.Now(),
+			Attributes: make(map[string]string, len(attributes)),
+		},
+	}
+	rand.Read(span.data.SpanID[:])
+	if parent, ok := ctx.Value(spanContextKey{}).(*Span); ok && parent != nil && parent.tracer == t {
+		span.data.ParentID = parent.data.SpanID
+	}
+	for key, value := range attributes {
+		span.data.Attributes[key] = value
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// ContextWithSpan returns a copy of ctx carrying span, so spans started from it become its
+// children. Used where work runs under a context not derived from the span's, e.g. worker tasks.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SetAttribute sets a string attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes[key] = value
+}
+
+// End finishes the span, with an error status if err is not nil. Only the first call counts.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.StatusCode = StatusOK
+	if err != nil {
+		s.data.StatusCode = StatusError
+		s.data.StatusMessage = err.Error()
+	}
+	data := s.data
+	data.Attributes = make(map[string]string, len(s.data.Attributes))
+	for key, value := range s.data.Attributes {
+		data.Attributes[key] = value
+	}
+	s.mu.Unlock()
+
+	s.tracer.mu.Lock()
+	s.tracer.finished = append(s.tracer.finished, data)
+	s.tracer.mu.Unlock()
+}
+
+// Flush exports the spans finished since the last flush
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.finished
+	t.finished = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	return t.exporter.Export(ctx, spans)
+}
+
+// InMemoryExporter keeps exported spans in memory, for tests
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// Export records the spans
+func (e *InMemoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Spans returns the exported spans in the order they finished
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
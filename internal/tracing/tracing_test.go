@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracerSpanHierarchy(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	tracer := NewTracer(exporter)
+
+	ctx, root := tracer.Start(context.Background(), "run", map[string]string{"specs": "1"})
+	childCtx, child := tracer.Start(ctx, "spec", nil)
+	child.SetAttribute("openapi.service", "funding")
+	_, stage := tracer.Start(ContextWithSpan(context.Background(), child), "generate", nil)
+	if childCtx == ctx {
+		t.Error("Start() should return a context carrying the span")
+	}
+	stage.End(errors.New("generator failed"))
+	child.End(nil)
+	child.End(errors.New("ignored"))
+	root.End(nil)
+
+	if got := exporter.Spans(); len(got) != 0 {
+		t.Fatalf("spans exported before Flush: %d", len(got))
+	}
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	stageData, childData, rootData := spans[0], spans[1], spans[2]
+
+	if rootData.ParentID != (SpanID{}) {
+		t.Errorf("root span has parent %x", rootData.ParentID)
+	}
+	if childData.ParentID != rootData.SpanID || stageData.ParentID != childData.SpanID {
+		t.Errorf("parents: spec -> %x (root %x), generate -> %x (spec %x)",
+			childData.ParentID, rootData.SpanID, stageData.ParentID, childData.SpanID)
+	}
+	for _, span := range spans {
+		if span.TraceID != rootData.TraceID {
+			t.Errorf("span %s has trace %x, want %x", span.Name, span.TraceID, rootData.TraceID)
+		}
+		if span.End.Before(span.Start) {
+			t.Errorf("span %s ends before it starts", span.Name)
+		}
+	}
+	if childData.Attributes["openapi.service"] != "funding" || rootData.Attributes["specs"] != "1" {
+		t.Errorf("attributes: spec %v, run %v", childData.Attributes, rootData.Attributes)
+	}
+	if childData.StatusCode != StatusOK {
+		t.Errorf("spec status = %d, want OK (only the first End counts)", childData.StatusCode)
+	}
+	if stageData.StatusCode != StatusError || stageData.StatusMessage != "generator failed" {
+		t.Errorf("generate status = %d %q, want error", stageData.StatusCode, stageData.StatusMessage)
+	}
+
+	// Flushed spans are not exported again
+	if err := tracer.Flush(context.Background()); err != nil || len(exporter.Spans()) != 3 {
+		t.Errorf("second Flush() exported %d spans, err %v", len(exporter.Spans()), err)
+	}
+}
+
+func TestNilTracerIsNoop(t *testing.T) {
+	var tracer *Tracer
+	ctx := context.Background()
+
+	spanCtx, span := tracer.Start(ctx, "run", map[string]string{"a": "b"})
+	if spanCtx != ctx || span != nil {
+		t.Errorf("Start() on a nil tracer = %v, %v; want the same context and a nil span", spanCtx, span)
+	}
+	span.SetAttribute("a", "b")
+	span.End(errors.New("ignored"))
+	if err := tracer.Flush(ctx); err != nil {
+		t.Errorf("Flush() on a nil tracer error = %v", err)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body []byte
+	var path, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL + "/")
+	if exporter.URL() != server.URL+"/v1/traces" {
+		t.Errorf("URL() = %q", exporter.URL())
+	}
+	if got := NewOTLPExporter(server.URL + "/v1/traces").URL(); got != server.URL+"/v1/traces" {
+		t.Errorf("URL() with the traces path = %q", got)
+	}
+
+	tracer := NewTracer(exporter)
+	ctx, root := tracer.Start(context.Background(), "run", nil)
+	_, child := tracer.Start(ctx, "spec", map[string]string{"openapi.service": "funding"})
+	child.End(errors.New("boom"))
+	root.End(nil)
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if path != "/v1/traces" || contentType != "application/json" {
+		t.Errorf("request to %s with content type %q", path, contentType)
+	}
+
+	var request otlpExportRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("failed to decode request %s: %v", body, err)
+	}
+	if len(request.ResourceSpans) != 1 || len(request.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected request structure: %s", body)
+	}
+	resource := request.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || resource[0].Value.StringValue != ServiceName {
+		t.Errorf("resource attributes = %+v", resource)
+	}
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	child0, root0 := spans[0], spans[1]
+	if len(child0.TraceID) != 32 || len(child0.SpanID) != 16 || child0.TraceID != root0.TraceID {
+		t.Errorf("IDs: child %s/%s, root trace %s", child0.TraceID, child0.SpanID, root0.TraceID)
+	}
+	if child0.ParentSpanID != root0.SpanID || root0.ParentSpanID != "" {
+		t.Errorf("parents: child %q (root %q), root %q", child0.ParentSpanID, root0.SpanID, root0.ParentSpanID)
+	}
+	if child0.Status.Code != StatusError || child0.Status.Message != "boom" || root0.Status.Code != StatusOK {
+		t.Errorf("statuses: child %+v, root %+v", child0.Status, root0.Status)
+	}
+	if len(child0.Attributes) != 1 || child0.Attributes[0].Value.StringValue != "funding" {
+		t.Errorf("child attributes = %+v", child0.Attributes)
+	}
+	if child0.StartTimeUnixNano == "" || strings.Contains(string(body), `"startTimeUnixNano":0`) {
+		t.Errorf("timestamps should be decimal strings: %s", body)
+	}
+}
+
+func TestOTLPExporterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "collector unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewOTLPExporter(server.URL).Export(context.Background(), []SpanData{{Name: "run"}})
+	if err == nil || !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "collector unavailable") {
+		t.Errorf("Export() error = %v, want the status and body", err)
+	}
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Location is where an issue was found; zero fields are unknown
+type Location struct {
+	// File is the spec path, relative to the repository root for annotations to attach to it
+	File string
+
+	// Line and Column are 1-based
+	Line   int
+	Column int
+}
+
+// GitHubAnnotation formats an issue as a GitHub Actions workflow command, e.g.
+// "::warning file=specs/pets/openapi.yaml,line=12,col=5,title=examples::...", which the
+// Actions runner shows as an inline annotation. The file and position are included when known.
+func (i Issue) GitHubAnnotation(loc Location) string {
+	command := "warning"
+	if i.Severity == SeverityError {
+		command = "error"
+	}
+
+	message := i.Message
+	if i.Path != "" {
+		message = fmt.Sprintf("%s (at %s)", i.Message, i.Path)
+	}
+	return FormatGitHubAnnotation(command, loc, i.Rule, message)
+}
+
+// FormatGitHubAnnotation formats a GitHub Actions annotation command ("error", "warning" or
+// "notice") with its location and title properties, escaping them and the message
+func FormatGitHubAnnotation(command string, loc Location, title, message string) string {
+	var properties []string
+	if loc.File != "" {
+		properties = append(properties, "file="+escapeAnnotationProperty(loc.File))
+		if loc.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", loc.Line))
+			if loc.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", loc.Column))
+			}
+		}
+	}
+	if title != "" {
+		properties = append(properties, "title="+escapeAnnotationProperty(title))
+	}
+
+	var b strings.Builder
+	b.WriteString("::" + command)
+	if len(properties) > 0 {
+		b.WriteString(" " + strings.Join(properties, ","))
+	}
+	b.WriteString("::" + escapeAnnotationData(message))
+	return b.String()
+}
+
+// escapeAnnotationData escapes a workflow command message
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,43 @@
+package validation
+
+import "testing"
+
+func TestIssueGitHubAnnotation(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue Issue
+		loc   Location
+		want  string
+	}{
+		{
+			name:  "error with location",
+			issue: Issue{Rule: "examples", Severity: SeverityError, Path: "/paths/~1pets/get", Message: "example does not match schema"},
+			loc:   Location{File: "specs/pets/openapi.yaml", Line: 12, Column: 5},
+			want:  "::error file=specs/pets/openapi.yaml,line=12,col=5,title=examples::example does not match schema (at /paths/~1pets/get)",
+		},
+		{
+			name:  "warning without location",
+			issue: Issue{Rule: "require-request-body", Severity: SeverityWarning, Path: "/paths/~1pets/post", Message: "POST has no request body"},
+			want:  "::warning title=require-request-body::POST has no request body (at /paths/~1pets/post)",
+		},
+		{
+			name:  "file without line",
+			issue: Issue{Rule: "security", Severity: SeverityWarning, Message: "unused scheme"},
+			loc:   Location{File: "openapi.json"},
+			want:  "::warning file=openapi.json,title=security::unused scheme",
+		},
+		{
+			name:  "escaped message and properties",
+			issue: Issue{Rule: "a,b:c", Severity: SeverityError, Message: "100% broken\nsecond line"},
+			loc:   Location{File: "specs/a,b.json", Line: 1},
+			want:  "::error file=specs/a%2Cb.json,line=1,title=a%2Cb%3Ac::100%25 broken%0Asecond line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.issue.GitHubAnnotation(tt.loc); got != tt.want {
+				t.Errorf("GitHubAnnotation() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
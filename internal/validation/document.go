@@ -0,0 +1,45 @@
+package validation
+
+import "fmt"
+
+// ErrorCode classifies documents that cannot be validated at all
+type ErrorCode string
+
+const (
+	// ErrCodeNotOpenAPI indicates the document has neither an "openapi" nor a "swagger" top-level key
+	ErrCodeNotOpenAPI ErrorCode = "NOT_OPENAPI"
+)
+
+// DocumentError describes a spec file that is not a valid OpenAPI document
+type DocumentError struct {
+	// Code classifies the error
+	Code ErrorCode
+
+	// Path is the spec file path
+	Path string
+
+	// Message is a human-readable description of the error
+	Message string
+}
+
+// Error returns the error message including the error code
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("%s [%s]: %s", e.Path, e.Code, e.Message)
+}
+
+// checkOpenAPIDocument verifies that a decoded document looks like an OpenAPI (or Swagger) spec.
+// Files matching a spec filename pattern may be arbitrary JSON/YAML, which would otherwise
+// fail much later with a confusing generator error.
+func checkOpenAPIDocument(doc *Document) error {
+	_, hasOpenAPI := doc.Root["openapi"]
+	_, hasSwagger := doc.Root["swagger"]
+	if hasOpenAPI || hasSwagger {
+		return nil
+	}
+
+	return &DocumentError{
+		Code:    ErrCodeNotOpenAPI,
+		Path:    doc.Path,
+		Message: `not an OpenAPI document: missing top-level "openapi" or "swagger" key (check spec_file_patterns if this file should not be processed)`,
+	}
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDocumentNotOpenAPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "arbitrary JSON object",
+			file:    "openapi.json",
+			content: `{"name":"my-package","version":"1.0.0","dependencies":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "arbitrary YAML object",
+			file:    "openapi.yaml",
+			content: "apiVersion: v1\nkind: ConfigMap\n",
+			wantErr: true,
+		},
+		{
+			name:    "OpenAPI 3 document",
+			file:    "openapi.json",
+			content: `{"openapi":"3.0.0","info":{"title":"Test","version":"1.0"}}`,
+		},
+		{
+			name:    "Swagger 2 document",
+			file:    "swagger.yaml",
+			content: "swagger: \"2.0\"\ninfo:\n  title: Test\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(specPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write spec: %v", err)
+			}
+
+			_, err := LoadDocument(specPath)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("LoadDocument() error = %v", err)
+				}
+				return
+			}
+
+			var docErr *DocumentError
+			if !errors.As(err, &docErr) {
+				t.Fatalf("LoadDocument() error = %v, want *DocumentError", err)
+			}
+			if docErr.Code != ErrCodeNotOpenAPI {
+				t.Errorf("Code = %s, want %s", docErr.Code, ErrCodeNotOpenAPI)
+			}
+			if !strings.Contains(err.Error(), "NOT_OPENAPI") || !strings.Contains(err.Error(), "not an OpenAPI document") {
+				t.Errorf("Error() = %q, want a clear NOT_OPENAPI message", err.Error())
+			}
+		})
+	}
+}
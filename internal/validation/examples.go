@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// ExamplesRuleName is the configuration name of the example type-checking rule
+const ExamplesRuleName = "validate-examples"
+
+// ExamplesRule warns when scalar example values don't match their schema type.
+// It checks `example` on schemas, `examples` arrays and `const` on schemas (OpenAPI 3.1), and
+// `example`/`examples` on parameters and media types against their `schema`.
+type ExamplesRule struct{}
+
+// NewExamplesRule creates a new example validation rule
+func NewExamplesRule() *ExamplesRule {
+	return &ExamplesRule{}
+}
+
+// Name returns the rule name
+func (r *ExamplesRule) Name() string {
+	return ExamplesRuleName
+}
+
+// Check walks the document and reports mismatched examples
+func (r *ExamplesRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	r.walk(doc.Root, "", &issues)
+	return issues
+}
+
+// walk recursively visits every object in the document tree
+func (r *ExamplesRule) walk(node interface{}, pointer string, issues *[]Issue) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		r.checkObject(n, pointer, issues)
+
+		for _, key := range sortedKeys(n) {
+			r.walk(n[key], childPointer(pointer, key), issues)
+		}
+	case []interface{}:
+		for i, item := range n {
+			r.walk(item, childPointer(pointer, strconv.Itoa(i)), issues)
+		}
+	}
+}
+
+// checkObject checks examples declared directly on an object
+func (r *ExamplesRule) checkObject(obj map[string]interface{}, pointer string, issues *[]Issue) {
+	// Schema object with its own example(s)
+	if schemaType, ok := scalarType(obj); ok {
+		if example, ok := obj["example"]; ok {
+			r.check(schemaType, example, childPointer(pointer, "example"), issues)
+		}
+		if value, ok := obj["const"]; ok {
+			r.check(schemaType, value, childPointer(pointer, "const"), issues)
+		}
+		if examples, ok := obj["examples"].([]interface{}); ok {
+			for i, example := range examples {
+				r.check(schemaType, example, childPointer(childPointer(pointer, "examples"), strconv.Itoa(i)), issues)
+			}
+		}
+		return
+	}
+
+	// Parameter or media type object with a sibling schema
+	schema, ok := obj["schema"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	schemaType, ok := scalarType(schema)
+	if !ok {
+		return
+	}
+	if example, ok := obj["example"]; ok {
+		r.check(schemaType, example, childPointer(pointer, "example"), issues)
+	}
+	if examples, ok := obj["examples"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(examples) {
+			exampleObj, ok := examples[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if value, ok := exampleObj["value"]; ok {
+				r.check(schemaType, value, childPointer(childPointer(childPointer(pointer, "examples"), name), "value"), issues)
+			}
+		}
+	}
+}
+
+// check appends an issue if the example does not match the schema type
+func (r *ExamplesRule) check(schemaType string, example interface{}, pointer string, issues *[]Issue) {
+	if example == nil || matchesType(schemaType, example) {
+		return
+	}
+	*issues = append(*issues, Issue{
+		Rule:     ExamplesRuleName,
+		Severity: SeverityWarning,
+		Path:     pointer,
+		Message:  fmt.Sprintf("example %v (%s) does not match schema type %q", example, describeValue(example), schemaType),
+	})
+}
+
+// scalarType returns the schema's type if it is a scalar type we can check. OpenAPI 3.1
+// type arrays of a single type and "null" count as that type; null examples always match.
+func scalarType(schema map[string]interface{}) (string, bool) {
+	var schemaType string
+	for _, name := range spec.SchemaTypes(schema) {
+		if name == "null" {
+			continue
+		}
+		if schemaType != "" {
+			return "", false
+		}
+		schemaType = name
+	}
+	switch schemaType {
+	case "string", "integer", "number", "boolean":
+		return schemaType, true
+	default:
+		return "", false
+	}
+}
+
+// matchesType reports whether a decoded JSON value matches a scalar schema type
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	default:
+		return true
+	}
+}
+
+// describeValue returns the JSON type name of a decoded value
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestExamplesRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		root          map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "matching schema examples",
+			root: map[string]interface{}{
+				"components": map[string]interface{}{
+					"schemas": map[string]interface{}{
+						"Name":    map[string]interface{}{"type": "string", "example": "alice"},
+						"Age":     map[string]interface{}{"type": "integer", "example": float64(42)},
+						"Score":   map[string]interface{}{"type": "number", "example": 4.5},
+						"Active":  map[string]interface{}{"type": "boolean", "example": true},
+						"Tags":    map[string]interface{}{"type": "string", "examples": []interface{}{"a", "b"}},
+						"Unknown": map[string]interface{}{"$ref": "#/components/schemas/Name", "example": float64(1)},
+					},
+				},
+			},
+			expectedPaths: nil,
+		},
+		{
+			name: "mismatched schema examples",
+			root: map[string]interface{}{
+				"components": map[string]interface{}{
+					"schemas": map[string]interface{}{
+						"Age":    map[string]interface{}{"type": "integer", "example": "forty-two"},
+						"Count":  map[string]interface{}{"type": "integer", "example": 1.5},
+						"Active": map[string]interface{}{"type": "boolean", "examples": []interface{}{true, "yes"}},
+					},
+				},
+			},
+			expectedPaths: []string{
+				"/components/schemas/Active/examples/1",
+				"/components/schemas/Age/example",
+				"/components/schemas/Count/example",
+			},
+		},
+		{
+			name: "OpenAPI 3.1 type arrays and const",
+			root: map[string]interface{}{
+				"components": map[string]interface{}{
+					"schemas": map[string]interface{}{
+						"Nickname": map[string]interface{}{"type": []interface{}{"string", "null"}, "examples": []interface{}{"al", nil, float64(3)}},
+						"Kind":     map[string]interface{}{"type": "string", "const": float64(1)},
+						"Mixed":    map[string]interface{}{"type": []interface{}{"string", "integer"}, "example": true},
+					},
+				},
+			},
+			expectedPaths: []string{
+				"/components/schemas/Kind/const",
+				"/components/schemas/Nickname/examples/2",
+			},
+		},
+		{
+			name: "parameter and media type examples",
+			root: map[string]interface{}{
+				"paths": map[string]interface{}{
+					"/items/{id}": map[string]interface{}{
+						"get": map[string]interface{}{
+							"parameters": []interface{}{
+								map[string]interface{}{
+									"name":    "id",
+									"in":      "path",
+									"schema":  map[string]interface{}{"type": "integer"},
+									"example": "abc",
+								},
+							},
+							"requestBody": map[string]interface{}{
+								"content": map[string]interface{}{
+									"text/plain": map[string]interface{}{
+										"schema": map[string]interface{}{"type": "string"},
+										"examples": map[string]interface{}{
+											"ok":  map[string]interface{}{"value": "hello"},
+											"bad": map[string]interface{}{"value": float64(7)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPaths: []string{
+				"/paths/~1items~1{id}/get/parameters/0/example",
+				"/paths/~1items~1{id}/get/requestBody/content/text~1plain/examples/bad/value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewExamplesRule()
+			issues := rule.Check(&Document{Path: "openapi.json", Root: tt.root})
+
+			if len(issues) != len(tt.expectedPaths) {
+				t.Fatalf("Check() returned %d issues, want %d: %v", len(issues), len(tt.expectedPaths), issues)
+			}
+			for i, issue := range issues {
+				if issue.Path != tt.expectedPaths[i] {
+					t.Errorf("issue[%d].Path = %q, want %q", i, issue.Path, tt.expectedPaths[i])
+				}
+				if issue.Severity != SeverityWarning {
+					t.Errorf("issue[%d].Severity = %q, want %q", i, issue.Severity, SeverityWarning)
+				}
+				if issue.Rule != ExamplesRuleName {
+					t.Errorf("issue[%d].Rule = %q, want %q", i, issue.Rule, ExamplesRuleName)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath expression of the subset used by lint rulesets: the root $,
+// child (.name, ['name'], [0]) and wildcard (.*, [*]) selectors, unions (['get','put'] or
+// [get,put]) and recursive descent (..name, ..*). Filter expressions are not supported.
+type jsonPath []jsonPathSegment
+
+// jsonPathSegment selects children of the current nodes, or of the current nodes and all their
+// descendants for a recursive segment
+type jsonPathSegment struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+}
+
+// jsonPathNode is a value selected by a JSONPath, with its JSON pointer
+type jsonPathNode struct {
+	pointer string
+	value   interface{}
+}
+
+// parseJSONPath parses a JSONPath expression starting with $
+func parseJSONPath(expr string) (jsonPath, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(expr), "$")
+	if !ok {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+
+	var path jsonPath
+	for rest != "" {
+		var segment jsonPathSegment
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			segment.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty name", expr)
+			}
+			if name == "*" {
+				segment.wildcard = true
+			} else {
+				segment.names = []string{name}
+			}
+			path = append(path, segment)
+			continue
+		case !strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("JSONPath %q is invalid at %q", expr, rest)
+		}
+
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("JSONPath %q has an unclosed [", expr)
+		}
+		selector := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+		switch {
+		case strings.HasPrefix(selector, "?") || strings.HasPrefix(selector, "("):
+			return nil, fmt.Errorf("JSONPath %q uses an expression, which is not supported", expr)
+		case selector == "*":
+			segment.wildcard = true
+		default:
+			for _, name := range strings.Split(selector, ",") {
+				name = strings.TrimSpace(name)
+				if unquoted, err := strconv.Unquote(name); err == nil {
+					name = unquoted
+				} else if len(name) >= 2 && name[0] == '\'' && name[len(name)-1] == '\'' {
+					name = name[1 : len(name)-1]
+				}
+				if name == "" {
+					return nil, fmt.Errorf("JSONPath %q has an empty name", expr)
+				}
+				segment.names = append(segment.names, name)
+			}
+		}
+		path = append(path, segment)
+	}
+	return path, nil
+}
+
+// evaluate returns the nodes of root selected by the path, in document order
+func (p jsonPath) evaluate(root interface{}) []jsonPathNode {
+	nodes := []jsonPathNode{{pointer: "", value: root}}
+	for _, segment := range p {
+		if segment.recursive {
+			var expanded []jsonPathNode
+			for _, node := range nodes {
+				expanded = appendDescendants(expanded, node)
+			}
+			nodes = expanded
+		}
+
+		var selected []jsonPathNode
+		for _, node := range nodes {
+			selected = append(selected, segment.children(node)...)
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+// children returns the children of a node selected by the segment
+func (s jsonPathSegment) children(node jsonPathNode) []jsonPathNode {
+	var children []jsonPathNode
+	switch value := node.value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			for _, key := range sortedKeys(value) {
+				children = append(children, jsonPathNode{childPointer(node.pointer, key), value[key]})
+			}
+			return children
+		}
+		for _, name := range s.names {
+			if child, ok := value[name]; ok {
+				children = append(children, jsonPathNode{childPointer(node.pointer, name), child})
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			if s.wildcard || containsString(s.names, strconv.Itoa(i)) {
+				children = append(children, jsonPathNode{childPointer(node.pointer, strconv.Itoa(i)), item})
+			}
+		}
+	}
+	return children
+}
+
+// appendDescendants appends a node and all nodes below it, parents first
+func appendDescendants(nodes []jsonPathNode, node jsonPathNode) []jsonPathNode {
+	nodes = append(nodes, node)
+	for _, child := range (jsonPathSegment{wildcard: true}).children(node) {
+		nodes = appendDescendants(nodes, child)
+	}
+	return nodes
+}
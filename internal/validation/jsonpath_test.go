@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	root := map[string]interface{}{
+		"info": map[string]interface{}{"title": "Users"},
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get":  map[string]interface{}{"parameters": []interface{}{map[string]interface{}{"name": "limit"}}},
+				"post": map[string]interface{}{"tags": []interface{}{"users", "admin"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		expr     string
+		expected []string
+		wantErr  bool
+	}{
+		{expr: "$", expected: []string{""}},
+		{expr: "$.info.title", expected: []string{"/info/title"}},
+		{expr: "$.paths[*][get,post]", expected: []string{"/paths/~1users/get", "/paths/~1users/post"}},
+		{expr: "$.paths['/users'].*", expected: []string{"/paths/~1users/get", "/paths/~1users/post"}},
+		{expr: `$.paths["/users"].post.tags[1]`, expected: []string{"/paths/~1users/post/tags/1"}},
+		{expr: "$..parameters[*].name", expected: []string{"/paths/~1users/get/parameters/0/name"}},
+		{expr: "$..['tags'][0]", expected: []string{"/paths/~1users/post/tags/0"}},
+		{expr: "$.missing.*", expected: nil},
+		{expr: "$.paths[?(@.get)]", wantErr: true},
+		{expr: "paths", wantErr: true},
+		{expr: "$.paths[*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			path, err := parseJSONPath(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJSONPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var pointers []string
+			for _, node := range path.evaluate(root) {
+				pointers = append(pointers, node.pointer)
+			}
+			if !reflect.DeepEqual(pointers, tt.expected) {
+				t.Errorf("evaluate() = %v, want %v", pointers, tt.expected)
+			}
+		})
+	}
+}
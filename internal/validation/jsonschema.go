@@ -0,0 +1,386 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jsonSchema evaluates documents against a JSON Schema. It supports the keywords used by the
+// embedded OpenAPI schemas: $ref to the schema's own definitions, type, enum, const, required,
+// properties, patternProperties, additionalProperties, propertyNames, minProperties,
+// maxProperties, items, minItems, uniqueItems, pattern, minimum, maximum, exclusiveMinimum,
+// allOf, anyOf, oneOf, not, and if/then/else. Formats are annotations and are not checked.
+type jsonSchema struct {
+	root map[string]interface{}
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// schemaViolation is an instance location that does not satisfy a schema
+type schemaViolation struct {
+	// pointer is the JSON pointer of the offending value in the document
+	pointer string
+
+	// message describes the violation
+	message string
+}
+
+// newJSONSchema wraps a decoded schema document
+func newJSONSchema(root map[string]interface{}) *jsonSchema {
+	return &jsonSchema{root: root, patterns: make(map[string]*regexp.Regexp)}
+}
+
+// validate returns the violations of the document, at most one per location
+func (s *jsonSchema) validate(doc interface{}) []schemaViolation {
+	violations := s.evaluate(s.root, doc, "")
+	seen := make(map[string]bool, len(violations))
+	unique := violations[:0]
+	for _, violation := range violations {
+		if !seen[violation.pointer] {
+			seen[violation.pointer] = true
+			unique = append(unique, violation)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool { return unique[i].pointer < unique[j].pointer })
+	return unique
+}
+
+// evaluate applies a schema to an instance at pointer
+func (s *jsonSchema) evaluate(schemaNode, instance interface{}, pointer string) []schemaViolation {
+	schema, ok := schemaNode.(map[string]interface{})
+	if !ok {
+		if allowed, isBool := schemaNode.(bool); isBool && !allowed {
+			return []schemaViolation{{pointer, "no value is allowed here"}}
+		}
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			return []schemaViolation{{pointer, err.Error()}}
+		}
+		if violations := s.evaluate(target, instance, pointer); len(violations) > 0 {
+			return violations
+		}
+	}
+
+	if violation, ok := s.checkType(schema, instance, pointer); !ok {
+		// The other keywords would only restate the type mismatch
+		return []schemaViolation{violation}
+	}
+
+	var violations []schemaViolation
+	add := func(message string, args ...interface{}) {
+		violations = append(violations, schemaViolation{pointer, fmt.Sprintf(message, args...)})
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, instance) {
+		add("must be one of %s, got %s", describeValues(enum), describeInstance(instance))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, instance) {
+		add("must be %s, got %s", describeInstance(constant), describeInstance(instance))
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		violations = append(violations, s.evaluateObject(schema, value, pointer)...)
+	case []interface{}:
+		violations = append(violations, s.evaluateArray(schema, value, pointer)...)
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !s.matches(pattern, value) {
+			add("%q does not match the pattern %s", value, pattern)
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok {
+			if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && value <= minimum {
+				add("must be greater than %v", minimum)
+			} else if value < minimum {
+				add("must be at least %v", minimum)
+			}
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+			add("must be at most %v", maximum)
+		}
+		if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+			add("must be greater than %v", minimum)
+		}
+	}
+
+	violations = append(violations, s.evaluateCombinators(schema, instance, pointer)...)
+	return violations
+}
+
+// evaluateObject applies the object keywords of a schema
+func (s *jsonSchema) evaluateObject(schema, obj map[string]interface{}, pointer string) []schemaViolation {
+	var violations []schemaViolation
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, _ := name.(string); key != "" {
+				if _, exists := obj[key]; !exists {
+					violations = append(violations, schemaViolation{pointer, fmt.Sprintf("missing required property %q", key)})
+				}
+			}
+		}
+	}
+	if minimum, ok := schema["minProperties"].(float64); ok && float64(len(obj)) < minimum {
+		violations = append(violations, schemaViolation{pointer, minimumMessage(minimum, "properties")})
+	}
+	if maximum, ok := schema["maxProperties"].(float64); ok && float64(len(obj)) > maximum {
+		violations = append(violations, schemaViolation{pointer, fmt.Sprintf("must have at most %v properties", maximum)})
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"]
+	names, _ := schema["propertyNames"].(map[string]interface{})
+	for _, key := range sortedKeys(obj) {
+		child := childPointer(pointer, key)
+		if names != nil {
+			if pattern, ok := names["pattern"].(string); ok && !s.matches(pattern, key) {
+				violations = append(violations, schemaViolation{child, fmt.Sprintf("name %q does not match the pattern %s", key, pattern)})
+			}
+		}
+
+		matched := false
+		if propertySchema, ok := properties[key]; ok {
+			matched = true
+			violations = append(violations, s.evaluate(propertySchema, obj[key], child)...)
+		}
+		for _, pattern := range sortedKeys(patternProperties) {
+			if s.matches(pattern, key) {
+				matched = true
+				violations = append(violations, s.evaluate(patternProperties[pattern], obj[key], child)...)
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			violations = append(violations, schemaViolation{child, fmt.Sprintf("property %q is not allowed", key)})
+			continue
+		}
+		violations = append(violations, s.evaluate(additional, obj[key], child)...)
+	}
+	return violations
+}
+
+// evaluateArray applies the array keywords of a schema
+func (s *jsonSchema) evaluateArray(schema map[string]interface{}, items []interface{}, pointer string) []schemaViolation {
+	var violations []schemaViolation
+	if minimum, ok := schema["minItems"].(float64); ok && float64(len(items)) < minimum {
+		violations = append(violations, schemaViolation{pointer, minimumMessage(minimum, "items")})
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := 1; i < len(items); i++ {
+			if containsValue(items[:i], items[i]) {
+				violations = append(violations, schemaViolation{fmt.Sprintf("%s/%d", pointer, i), "duplicates an earlier item"})
+			}
+		}
+	}
+	if itemSchema, ok := schema["items"]; ok {
+		for i, item := range items {
+			violations = append(violations, s.evaluate(itemSchema, item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	}
+	return violations
+}
+
+// evaluateCombinators applies allOf, anyOf, oneOf, not and if/then/else
+func (s *jsonSchema) evaluateCombinators(schema map[string]interface{}, instance interface{}, pointer string) []schemaViolation {
+	var violations []schemaViolation
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			violations = append(violations, s.evaluate(sub, instance, pointer)...)
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		if matched, best := s.matchAlternatives(anyOf, instance, pointer); matched == 0 {
+			violations = append(violations, best...)
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matched, best := s.matchAlternatives(oneOf, instance, pointer)
+		switch {
+		case matched == 0:
+			violations = append(violations, best...)
+		case matched > 1:
+			violations = append(violations, schemaViolation{pointer, "matches more than one of the allowed forms"})
+		}
+	}
+
+	if not, ok := schema["not"]; ok && len(s.evaluate(not, instance, pointer)) == 0 {
+		message := "matches a form that is not allowed"
+		if description := schemaDescription(not); description != "" {
+			message = description
+		}
+		violations = append(violations, schemaViolation{pointer, message})
+	}
+
+	if condition, ok := schema["if"]; ok {
+		branch := "else"
+		if len(s.evaluate(condition, instance, pointer)) == 0 {
+			branch = "then"
+		}
+		if sub, ok := schema[branch]; ok {
+			violations = append(violations, s.evaluate(sub, instance, pointer)...)
+		}
+	}
+	return violations
+}
+
+// matchAlternatives returns how many alternatives the instance matches, and if it matches
+// none, the violations of the closest one: the alternative that failed deepest in the
+// instance, which is the one the author most likely meant, with the fewest violations
+func (s *jsonSchema) matchAlternatives(alternatives []interface{}, instance interface{}, pointer string) (int, []schemaViolation) {
+	matched := 0
+	var best []schemaViolation
+	bestDepth := -1
+	for _, alternative := range alternatives {
+		violations := s.evaluate(alternative, instance, pointer)
+		if len(violations) == 0 {
+			matched++
+			continue
+		}
+		depth := 0
+		for _, violation := range violations {
+			depth = max(depth, strings.Count(violation.pointer, "/"))
+		}
+		if depth > bestDepth || (depth == bestDepth && len(violations) < len(best)) {
+			best, bestDepth = violations, depth
+		}
+	}
+	return matched, best
+}
+
+// checkType reports whether the instance has one of the schema's types
+func (s *jsonSchema) checkType(schema map[string]interface{}, instance interface{}, pointer string) (schemaViolation, bool) {
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, name := range t {
+			if name, ok := name.(string); ok {
+				types = append(types, name)
+			}
+		}
+	default:
+		return schemaViolation{}, true
+	}
+
+	for _, name := range types {
+		switch name {
+		case "object", "array":
+			if describeValue(instance) == name {
+				return schemaViolation{}, true
+			}
+		case "null":
+			if instance == nil {
+				return schemaViolation{}, true
+			}
+		default:
+			if instance != nil && matchesType(name, instance) {
+				return schemaViolation{}, true
+			}
+		}
+	}
+	return schemaViolation{pointer, fmt.Sprintf("must be %s, got %s", strings.Join(types, " or "), jsonTypeName(instance))}, false
+}
+
+// resolve returns the schema a local $ref such as "#/definitions/Info" points to
+func (s *jsonSchema) resolve(ref string) (interface{}, error) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	var node interface{} = s.root
+	for _, token := range strings.Split(path, "/") {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolved schema reference %q", ref)
+		}
+		if node, ok = obj[strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")]; !ok {
+			return nil, fmt.Errorf("unresolved schema reference %q", ref)
+		}
+	}
+	return node, nil
+}
+
+// matches reports whether value matches a schema pattern, compiling each pattern once
+func (s *jsonSchema) matches(pattern, value string) bool {
+	s.mu.Lock()
+	re, ok := s.patterns[pattern]
+	if !ok {
+		re, _ = regexp.Compile(pattern)
+		s.patterns[pattern] = re
+	}
+	s.mu.Unlock()
+	return re == nil || re.MatchString(value)
+}
+
+// minimumMessage describes a minProperties or minItems violation
+func minimumMessage(minimum float64, noun string) string {
+	if minimum == 1 {
+		return "must not be empty"
+	}
+	return fmt.Sprintf("must have at least %v %s", minimum, noun)
+}
+
+// schemaDescription returns the description of a schema, used to explain a violation
+func schemaDescription(schemaNode interface{}) string {
+	schema, _ := schemaNode.(map[string]interface{})
+	description, _ := schema["description"].(string)
+	return description
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeValues renders allowed values for a message, e.g. `"path", "query"`
+func describeValues(values []interface{}) string {
+	described := make([]string, len(values))
+	for i, value := range values {
+		described[i] = describeInstance(value)
+	}
+	return strings.Join(described, ", ")
+}
+
+// describeInstance renders a scalar for a message, or the type of other values
+func describeInstance(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case float64:
+		if v == math.Trunc(v) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%v", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		return jsonTypeName(value)
+	}
+}
+
+// jsonTypeName returns the JSON type name of a decoded value, including null
+func jsonTypeName(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return describeValue(value)
+}
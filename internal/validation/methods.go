@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MethodSupportRuleName is the configuration name of the method support rule
+const MethodSupportRuleName = "method-support"
+
+// limitedMethods are HTTP methods that are fingerprinted and generated like any other, but
+// that ogen may not support or may generate without response bodies
+var limitedMethods = []string{"head", "trace"}
+
+// anyMethodExtension defines an API Gateway catch-all operation on a path item. It is not an
+// OpenAPI operation, so generators ignore it.
+const anyMethodExtension = "x-amazon-apigateway-any-method"
+
+// MethodSupportRule notes operations the generated client may not cover: HEAD and TRACE
+// operations, and catch-all x-amazon-apigateway-any-method operations, which overload a
+// path without an HTTP method.
+type MethodSupportRule struct{}
+
+// NewMethodSupportRule creates a new method support rule
+func NewMethodSupportRule() *MethodSupportRule {
+	return &MethodSupportRule{}
+}
+
+// Name returns the rule name
+func (r *MethodSupportRule) Name() string {
+	return MethodSupportRuleName
+}
+
+// Check reports a warning for each HEAD, TRACE and catch-all operation
+func (r *MethodSupportRule) Check(doc *Document) []Issue {
+	paths, _ := doc.Root["paths"].(map[string]interface{})
+
+	var issues []Issue
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		itemPointer := childPointer("/paths", path)
+
+		for _, method := range limitedMethods {
+			if _, ok := item[method].(map[string]interface{}); !ok {
+				continue
+			}
+			issues = append(issues, Issue{
+				Rule:     MethodSupportRuleName,
+				Severity: SeverityWarning,
+				Path:     childPointer(itemPointer, method),
+				Message:  fmt.Sprintf("%s operations may not be supported by ogen; check the generated client for %s %s", strings.ToUpper(method), strings.ToUpper(method), path),
+			})
+		}
+
+		if _, ok := item[anyMethodExtension]; ok {
+			issues = append(issues, Issue{
+				Rule:     MethodSupportRuleName,
+				Severity: SeverityWarning,
+				Path:     childPointer(itemPointer, anyMethodExtension),
+				Message:  fmt.Sprintf("%s: %s is not an OpenAPI operation and is left out of the generated client; define each method explicitly", path, anyMethodExtension),
+			})
+		}
+	}
+	return issues
+}
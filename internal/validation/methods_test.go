@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMethodSupportRule(t *testing.T) {
+	ok := map[string]interface{}{"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}}}
+
+	tests := []struct {
+		name          string
+		paths         map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "HEAD and TRACE noted",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{"get": ok, "head": ok, "trace": ok},
+			},
+			expectedPaths: []string{"/paths/~1pets/head", "/paths/~1pets/trace"},
+		},
+		{
+			name: "API Gateway catch-all noted",
+			paths: map[string]interface{}{
+				"/proxy": map[string]interface{}{"x-amazon-apigateway-any-method": map[string]interface{}{}},
+			},
+			expectedPaths: []string{"/paths/~1proxy/x-amazon-apigateway-any-method"},
+		},
+		{
+			name: "other methods pass",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{"get": ok, "put": ok, "post": ok, "delete": ok, "options": ok, "patch": ok},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Root: map[string]interface{}{"openapi": "3.0.3", "paths": tt.paths}}
+			issues := NewMethodSupportRule().Check(doc)
+
+			var paths []string
+			for _, issue := range issues {
+				paths = append(paths, issue.Path)
+				if issue.Rule != MethodSupportRuleName || issue.Severity != SeverityWarning {
+					t.Errorf("issue = %+v, want a %s warning", issue, MethodSupportRuleName)
+				}
+			}
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("issue paths = %v, want %v", paths, tt.expectedPaths)
+			}
+		})
+	}
+}
+
+func TestMethodSupportRuleMessage(t *testing.T) {
+	doc := &Document{Root: map[string]interface{}{
+		"paths": map[string]interface{}{"/pets": map[string]interface{}{"head": map[string]interface{}{}}},
+	}}
+	issues := NewMethodSupportRule().Check(doc)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "HEAD /pets") {
+		t.Errorf("issues = %+v, want one naming HEAD /pets", issues)
+	}
+}
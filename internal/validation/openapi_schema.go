@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// OpenAPISchemaRuleName is the configuration name of the OpenAPI JSON Schema rule
+const OpenAPISchemaRuleName = "openapi-schema"
+
+// openAPISchemaFiles are the JSON Schemas of OpenAPI 3.0 and 3.1 documents, transcribed from the
+// official schemas with the keywords jsonSchema supports
+//
+//go:embed schemas/*.json
+var openAPISchemaFiles embed.FS
+
+// openAPISchemas holds the decoded schemas by file name, parsed on first use
+var openAPISchemas = struct {
+	sync.Mutex
+	parsed map[string]*jsonSchema
+}{parsed: make(map[string]*jsonSchema)}
+
+// loadOpenAPISchema returns the embedded schema with the given file name
+func loadOpenAPISchema(name string) (*jsonSchema, error) {
+	openAPISchemas.Lock()
+	defer openAPISchemas.Unlock()
+	if schema, ok := openAPISchemas.parsed[name]; ok {
+		return schema, nil
+	}
+
+	data, err := openAPISchemaFiles.ReadFile("schemas/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI schema %s: %w", name, err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI schema %s: %w", name, err)
+	}
+	schema := newJSONSchema(root)
+	openAPISchemas.parsed[name] = schema
+	return schema, nil
+}
+
+// OpenAPISchemaRule validates the whole document against the JSON Schema of its OpenAPI
+// version (3.0 or 3.1), reporting an error for each location that violates it. Swagger 2.0
+// documents and other versions are not checked.
+type OpenAPISchemaRule struct{}
+
+// NewOpenAPISchemaRule creates a new OpenAPI JSON Schema rule
+func NewOpenAPISchemaRule() *OpenAPISchemaRule {
+	return &OpenAPISchemaRule{}
+}
+
+// Name returns the rule name
+func (r *OpenAPISchemaRule) Name() string {
+	return OpenAPISchemaRuleName
+}
+
+// Check reports an error for each location of the document that violates the OpenAPI schema
+func (r *OpenAPISchemaRule) Check(doc *Document) []Issue {
+	version, _ := doc.Root["openapi"].(string)
+	var name string
+	switch {
+	case strings.HasPrefix(version, "3.0"):
+		name = "openapi-3.0.json"
+	case strings.HasPrefix(version, "3.1"):
+		name = "openapi-3.1.json"
+	default:
+		return nil
+	}
+
+	schema, err := loadOpenAPISchema(name)
+	if err != nil {
+		return []Issue{{Rule: OpenAPISchemaRuleName, Severity: SeverityError, Path: "", Message: err.Error()}}
+	}
+
+	var issues []Issue
+	for _, violation := range schema.validate(doc.Root) {
+		issues = append(issues, Issue{
+			Rule:     OpenAPISchemaRuleName,
+			Severity: SeverityError,
+			Path:     violation.pointer,
+			Message:  violation.message,
+		})
+	}
+	return issues
+}
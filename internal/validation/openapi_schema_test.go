@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+func TestOpenAPISchemaRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected map[string]string
+	}{
+		{
+			name: "valid 3.0 document",
+			spec: `
+openapi: 3.0.3
+info: {title: Users, version: "1.0"}
+servers: [{url: "https://{env}.example.com", variables: {env: {default: api}}}]
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        default: {$ref: "#/components/responses/Error"}
+      security: [{bearer: []}]
+components:
+  schemas:
+    User:
+      type: object
+      nullable: true
+      required: [id]
+      properties:
+        id: {type: string, format: uuid, example: abc}
+        age: {type: integer, minimum: 0, exclusiveMinimum: true}
+  responses:
+    Error: {description: Error}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+x-owner: team
+`,
+		},
+		{
+			name: "valid 3.1 document",
+			spec: `
+openapi: 3.1.0
+info: {title: Users, version: "1.0", license: {name: MIT, identifier: MIT}}
+webhooks:
+  userCreated:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {type: [object, "null"], const: {id: 1}}
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    Any: true
+    User: {$ref: "#/components/schemas/Any", description: A user}
+`,
+		},
+		{
+			name: "3.0 violations",
+			spec: `
+openapi: 3.0.3
+info: {title: Users}
+paths:
+  /users:
+    get:
+      parameters:
+        - {name: id, in: body, schema: {type: string}}
+      responses: {}
+    post:
+      requestBody:
+        content:
+          application/json:
+            example: {}
+            examples: {one: {value: {}}}
+      responses:
+        "200": {description: OK}
+      summaries: oops
+`,
+			expected: map[string]string{
+				"/info":                              `missing required property "version"`,
+				"/paths/~1users/get/parameters/0/in": `must be one of "path", "query", "header", "cookie", got "body"`,
+				"/paths/~1users/get/responses":       "must not be empty",
+				"/paths/~1users/post/requestBody/content/application~1json": "example and examples are mutually exclusive",
+				"/paths/~1users/post/summaries":                             `property "summaries" is not allowed`,
+			},
+		},
+		{
+			name: "3.1 violations",
+			spec: `
+openapi: 3.1.0
+info: {title: Users, version: 1}
+components:
+  schemas:
+    User: {type: object}
+    "User Name": {type: string}
+`,
+			expected: map[string]string{
+				"/info/version":                 "must be string, got integer",
+				"/components/schemas/User Name": `name "User Name" does not match the pattern ^[a-zA-Z0-9._-]+$`,
+			},
+		},
+		{
+			name:     "swagger 2.0 is not checked",
+			spec:     "swagger: '2.0'\ninfo: {title: Users}\npaths: {}\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := spec.DecodeDocument([]byte(tt.spec), ".yaml")
+			if err != nil {
+				t.Fatalf("failed to decode spec: %v", err)
+			}
+			issues := NewOpenAPISchemaRule().Check(&Document{Root: root})
+
+			if len(issues) != len(tt.expected) {
+				t.Fatalf("expected %d issues, got %d: %v", len(tt.expected), len(issues), issues)
+			}
+			for _, issue := range issues {
+				if issue.Rule != OpenAPISchemaRuleName || issue.Severity != SeverityError {
+					t.Errorf("unexpected rule or severity: %v", issue)
+				}
+				if expected, ok := tt.expected[issue.Path]; !ok || issue.Message != expected {
+					t.Errorf("unexpected issue at %s: %q (expected %q)", issue.Path, issue.Message, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// UniqueOperationIDsRuleName is the configuration name of the operationId uniqueness rule
+const UniqueOperationIDsRuleName = "unique-operation-ids"
+
+// maxRefDepth bounds how many chained $refs are followed for a single path item
+const maxRefDepth = 16
+
+// UniqueOperationIDsRule reports operationIds used by more than one operation, which ogen
+// rejects. Path items defined via $ref (e.g. shared path items in a common file) are resolved
+// first, so collisions that only appear after expansion are caught as well.
+type UniqueOperationIDsRule struct{}
+
+// NewUniqueOperationIDsRule creates a new operationId uniqueness rule
+func NewUniqueOperationIDsRule() *UniqueOperationIDsRule {
+	return &UniqueOperationIDsRule{}
+}
+
+// Name returns the rule name
+func (r *UniqueOperationIDsRule) Name() string {
+	return UniqueOperationIDsRuleName
+}
+
+// operationUse is one operation declaring an operationId
+type operationUse struct {
+	operation string // "METHOD /path"
+	pointer   string // JSON pointer to the operationId in the spec
+	ref       string // $ref the path item was resolved through ("" if defined inline)
+}
+
+// Check reports every operation whose operationId is already used by an earlier operation
+func (r *UniqueOperationIDsRule) Check(doc *Document) []Issue {
+	paths, _ := doc.Root["paths"].(map[string]interface{})
+	location := spec.RefLocation{Path: doc.Path, Root: doc.Root}
+
+	var issues []Issue
+	seen := make(map[string]operationUse)
+	for _, path := range sortedKeys(paths) {
+		itemPointer := childPointer("/paths", path)
+		item, ref, err := resolvePathItem(location, paths[path])
+		if err != nil {
+			issues = append(issues, Issue{
+				Rule:     UniqueOperationIDsRuleName,
+				Severity: SeverityWarning,
+				Path:     itemPointer,
+				Message:  fmt.Sprintf("operationIds not checked: %v", err),
+			})
+			continue
+		}
+
+		for _, method := range spec.HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operationID, _ := operation["operationId"].(string)
+			if operationID == "" {
+				continue
+			}
+
+			use := operationUse{
+				operation: strings.ToUpper(method) + " " + path,
+				pointer:   childPointer(childPointer(itemPointer, method), "operationId"),
+				ref:       ref,
+			}
+			first, duplicate := seen[operationID]
+			if !duplicate {
+				seen[operationID] = use
+				continue
+			}
+			issues = append(issues, Issue{
+				Rule:     UniqueOperationIDsRuleName,
+				Severity: SeverityError,
+				Path:     use.pointer,
+				Message:  duplicateOperationIDMessage(operationID, first, use),
+			})
+		}
+	}
+	return issues
+}
+
+// resolvePathItem follows a chain of path item $refs. Returns the resolved path item and
+// the first $ref followed ("" if the item is defined inline).
+func resolvePathItem(location spec.RefLocation, raw interface{}) (map[string]interface{}, string, error) {
+	firstRef := ""
+	for depth := 0; ; depth++ {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, firstRef, nil
+		}
+		ref, ok := item["$ref"].(string)
+		if !ok {
+			return item, firstRef, nil
+		}
+		if depth == maxRefDepth {
+			return nil, firstRef, fmt.Errorf("$ref %q nests more than %d levels (circular reference?)", firstRef, maxRefDepth)
+		}
+		if firstRef == "" {
+			firstRef = ref
+		}
+
+		var err error
+		raw, location, err = location.Resolve(ref)
+		if err != nil {
+			return nil, firstRef, err
+		}
+	}
+}
+
+// duplicateOperationIDMessage describes a duplicate, naming the $refs that introduced it
+func duplicateOperationIDMessage(operationID string, first, duplicate operationUse) string {
+	msg := fmt.Sprintf("operationId %q of %s is already used by %s", operationID, duplicate.operation, first.operation)
+
+	var refs []string
+	for _, use := range []operationUse{first, duplicate} {
+		if use.ref != "" {
+			refs = append(refs, fmt.Sprintf("%s via $ref %q", use.operation, use.ref))
+		}
+	}
+	if len(refs) > 0 {
+		msg += " (after resolving " + strings.Join(refs, ", ") + ")"
+	}
+	return msg
+}
@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUniqueOperationIDsRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		files         map[string]string
+		expectedPaths []string
+		expectedInMsg string
+	}{
+		{
+			name: "unique operationIds",
+			spec: `{"openapi": "3.0.3", "paths": {
+				"/pets": {"get": {"operationId": "listPets"}, "post": {"operationId": "createPet"}},
+				"/pets/{id}": {"get": {"operationId": "getPet"}}
+			}}`,
+		},
+		{
+			name: "inline duplicate",
+			spec: `{"openapi": "3.0.3", "paths": {
+				"/cats": {"get": {"operationId": "list"}},
+				"/dogs": {"get": {"operationId": "list"}}
+			}}`,
+			expectedPaths: []string{"/paths/~1dogs/get/operationId"},
+			expectedInMsg: `operationId "list" of GET /dogs is already used by GET /cats`,
+		},
+		{
+			name: "duplicate introduced by a shared file path item",
+			spec: `{"openapi": "3.0.3", "paths": {
+				"/health": {"get": {"operationId": "getStatus"}},
+				"/status": {"$ref": "common.json#/paths/~1status"}
+			}}`,
+			files: map[string]string{
+				"common.json": `{"paths": {"/status": {"get": {"operationId": "getStatus"}}}}`,
+			},
+			expectedPaths: []string{"/paths/~1status/get/operationId"},
+			expectedInMsg: `after resolving GET /status via $ref "common.json#/paths/~1status"`,
+		},
+		{
+			name: "two paths referencing the same path item",
+			spec: `{"openapi": "3.0.3",
+				"paths": {
+					"/v1/ping": {"$ref": "#/x-shared/ping"},
+					"/v2/ping": {"$ref": "#/x-shared/ping"}
+				},
+				"x-shared": {"ping": {"get": {"operationId": "ping"}}}
+			}`,
+			expectedPaths: []string{"/paths/~1v2~1ping/get/operationId"},
+			expectedInMsg: `GET /v1/ping via $ref "#/x-shared/ping", GET /v2/ping via $ref "#/x-shared/ping"`,
+		},
+		{
+			name: "chained refs across files",
+			spec: `{"openapi": "3.0.3", "paths": {
+				"/a": {"get": {"operationId": "fetch"}},
+				"/b": {"$ref": "shared/paths.yaml#/b"}
+			}}`,
+			files: map[string]string{
+				"shared/paths.yaml": "b:\n  $ref: '#/c'\nc:\n  get:\n    operationId: fetch\n",
+			},
+			expectedPaths: []string{"/paths/~1b/get/operationId"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := writeRefFixture(t, tt.spec, tt.files)
+
+			issues := NewUniqueOperationIDsRule().Check(doc)
+
+			if len(issues) != len(tt.expectedPaths) {
+				t.Fatalf("got %d issues, want %d: %v", len(issues), len(tt.expectedPaths), issues)
+			}
+			for i, issue := range issues {
+				if issue.Path != tt.expectedPaths[i] {
+					t.Errorf("issue %d path = %q, want %q", i, issue.Path, tt.expectedPaths[i])
+				}
+				if issue.Severity != SeverityError {
+					t.Errorf("issue %d severity = %q, want error", i, issue.Severity)
+				}
+				if tt.expectedInMsg != "" && !strings.Contains(issue.Message, tt.expectedInMsg) {
+					t.Errorf("issue %d message = %q, want it to contain %q", i, issue.Message, tt.expectedInMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueOperationIDsRuleUnresolvableRef(t *testing.T) {
+	doc := writeRefFixture(t, `{"openapi": "3.0.3", "paths": {
+		"/missing": {"$ref": "missing.json#/paths/~1x"},
+		"/loop": {"$ref": "#/paths/~1loop"}
+	}}`, nil)
+
+	issues := NewUniqueOperationIDsRule().Check(doc)
+
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2: %v", len(issues), issues)
+	}
+	for _, issue := range issues {
+		if issue.Severity != SeverityWarning {
+			t.Errorf("unresolvable $ref should be a warning, got %v", issue)
+		}
+	}
+	if !strings.Contains(issues[0].Message, "circular") {
+		t.Errorf("self-referencing path item should be reported as circular, got %q", issues[0].Message)
+	}
+}
+
+// writeRefFixture writes a spec and the files it references into a temp dir and loads the spec
+func writeRefFixture(t *testing.T, specContent string, files map[string]string) *Document {
+	t.Helper()
+	dir := t.TempDir()
+	all := map[string]string{"openapi.json": specContent}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	doc, err := LoadDocument(filepath.Join(dir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	return doc
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// OperationIDVerbRuleName is the configuration name of the operationId verb consistency rule
+const OperationIDVerbRuleName = "operation-id-verb-consistency"
+
+// operationIDVerbMethods maps the leading verb of an operationId to the HTTP methods it is
+// consistent with
+var operationIDVerbMethods = map[string][]string{
+	"get":    {"get", "head"},
+	"list":   {"get"},
+	"create": {"post", "put"},
+	"update": {"put", "patch"},
+	"delete": {"delete"},
+}
+
+// OperationIDVerbRule warns when the leading verb of an operationId conflicts with the
+// operation's HTTP method, e.g. deleteUser on GET /users, which is usually a spec bug.
+// operationIds starting with another word are not checked.
+type OperationIDVerbRule struct{}
+
+// NewOperationIDVerbRule creates a new operationId verb consistency rule
+func NewOperationIDVerbRule() *OperationIDVerbRule {
+	return &OperationIDVerbRule{}
+}
+
+// Name returns the rule name
+func (r *OperationIDVerbRule) Name() string {
+	return OperationIDVerbRuleName
+}
+
+// Check reports a warning for each operation whose operationId verb conflicts with its method
+func (r *OperationIDVerbRule) Check(doc *Document) []Issue {
+	paths, _ := doc.Root["paths"].(map[string]interface{})
+
+	var issues []Issue
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, method := range spec.HTTPMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operationID, _ := operation["operationId"].(string)
+			verb := leadingVerb(operationID)
+			methods, known := operationIDVerbMethods[verb]
+			if !known || containsString(methods, method) {
+				continue
+			}
+
+			expected := make([]string, len(methods))
+			for i, m := range methods {
+				expected[i] = strings.ToUpper(m)
+			}
+			issues = append(issues, Issue{
+				Rule:     OperationIDVerbRuleName,
+				Severity: SeverityWarning,
+				Path:     childPointer(childPointer(childPointer("/paths", path), method), "operationId"),
+				Message: fmt.Sprintf("operationId %q of %s %s starts with %q, which suggests %s",
+					operationID, strings.ToUpper(method), path, verb, strings.Join(expected, " or ")),
+			})
+		}
+	}
+	return issues
+}
+
+// leadingVerb returns the lowercase first word of a camelCase operationId, e.g. "delete"
+// for "deleteUser"
+func leadingVerb(operationID string) string {
+	end := strings.IndexFunc(operationID, func(r rune) bool { return !unicode.IsLower(r) })
+	if end == -1 {
+		return operationID
+	}
+	return operationID[:end]
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
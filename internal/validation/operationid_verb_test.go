@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOperationIDVerbRule(t *testing.T) {
+	op := func(operationID string) map[string]interface{} {
+		return map[string]interface{}{"operationId": operationID}
+	}
+
+	tests := []struct {
+		name          string
+		paths         map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "consistent verbs pass",
+			paths: map[string]interface{}{
+				"/users": map[string]interface{}{"get": op("listUsers"), "post": op("createUser")},
+				"/users/{id}": map[string]interface{}{
+					"get":    op("getUser"),
+					"put":    op("updateUser"),
+					"patch":  op("updateUserEmail"),
+					"delete": op("deleteUser"),
+				},
+			},
+		},
+		{
+			name: "conflicting verb flagged",
+			paths: map[string]interface{}{
+				"/users": map[string]interface{}{"get": op("deleteUser")},
+			},
+			expectedPaths: []string{"/paths/~1users/get/operationId"},
+		},
+		{
+			name: "conflicts flagged per operation",
+			paths: map[string]interface{}{
+				"/users/{id}": map[string]interface{}{"post": op("getUser"), "delete": op("updateUser")},
+			},
+			expectedPaths: []string{"/paths/~1users~1{id}/post/operationId", "/paths/~1users~1{id}/delete/operationId"},
+		},
+		{
+			name: "other verbs and missing operationIds are not checked",
+			paths: map[string]interface{}{
+				"/jobs/{id}/cancel": map[string]interface{}{"post": op("cancelJob")},
+				"/getters":          map[string]interface{}{"delete": op("getters"), "get": map[string]interface{}{}},
+				"/Users":            map[string]interface{}{"delete": op("GetUser")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Root: map[string]interface{}{"openapi": "3.0.3", "paths": tt.paths}}
+			issues := NewOperationIDVerbRule().Check(doc)
+
+			var paths []string
+			for _, issue := range issues {
+				paths = append(paths, issue.Path)
+				if issue.Rule != OperationIDVerbRuleName || issue.Severity != SeverityWarning {
+					t.Errorf("issue = %+v, want a %s warning", issue, OperationIDVerbRuleName)
+				}
+			}
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("issue paths = %v, want %v", paths, tt.expectedPaths)
+			}
+		})
+	}
+}
+
+func TestOperationIDVerbRuleMessage(t *testing.T) {
+	doc := &Document{Root: map[string]interface{}{
+		"paths": map[string]interface{}{"/users": map[string]interface{}{"get": map[string]interface{}{"operationId": "deleteUser"}}},
+	}}
+	issues := NewOperationIDVerbRule().Check(doc)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(issues))
+	}
+	for _, want := range []string{`"deleteUser"`, "GET /users", `"delete"`, "DELETE"} {
+		if !strings.Contains(issues[0].Message, want) {
+			t.Errorf("message %q should contain %q", issues[0].Message, want)
+		}
+	}
+}
+
+func TestOperationIDVerbRuleIsOptIn(t *testing.T) {
+	validator, err := NewValidatorFromNames([]string{OperationIDVerbRuleName})
+	if err != nil {
+		t.Fatalf("NewValidatorFromNames() error = %v", err)
+	}
+	if rules := validator.Rules(); !reflect.DeepEqual(rules, []string{OperationIDVerbRuleName}) {
+		t.Errorf("Rules() = %v", rules)
+	}
+	if issues := NewValidator().Validate(&Document{Root: map[string]interface{}{
+		"paths": map[string]interface{}{"/users": map[string]interface{}{"get": map[string]interface{}{"operationId": "deleteUser"}}},
+	}}); len(issues) != 0 {
+		t.Errorf("a validator without the rule reported %v", issues)
+	}
+}
+
+func TestLeadingVerb(t *testing.T) {
+	tests := map[string]string{
+		"deleteUser":  "delete",
+		"list":        "list",
+		"get_user":    "get",
+		"GetUser":     "",
+		"":            "",
+		"update2FA":   "update",
+		"createOrder": "create",
+	}
+	for operationID, want := range tests {
+		if got := leadingVerb(operationID); got != want {
+			t.Errorf("leadingVerb(%q) = %q, want %q", operationID, got, want)
+		}
+	}
+}
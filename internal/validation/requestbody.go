@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequireRequestBodyRuleName is the configuration name of the request body rule
+const RequireRequestBodyRuleName = "require-request-body"
+
+// noRequestBodyExtension marks a write operation that intentionally has no request body
+// (e.g. POST /jobs/{id}/cancel), exempting it from the rule
+const noRequestBodyExtension = "x-no-request-body"
+
+// writeMethods are the HTTP methods that usually carry a request body
+var writeMethods = []string{"post", "put", "patch"}
+
+// RequireRequestBodyRule flags POST, PUT and PATCH operations without a requestBody, which
+// often means the body was forgotten. Operations without a body by design can be exempted
+// with `x-no-request-body: true`.
+type RequireRequestBodyRule struct{}
+
+// NewRequireRequestBodyRule creates a new request body rule
+func NewRequireRequestBodyRule() *RequireRequestBodyRule {
+	return &RequireRequestBodyRule{}
+}
+
+// Name returns the rule name
+func (r *RequireRequestBodyRule) Name() string {
+	return RequireRequestBodyRuleName
+}
+
+// Check reports a warning for each write operation without a request body
+func (r *RequireRequestBodyRule) Check(doc *Document) []Issue {
+	paths, _ := doc.Root["paths"].(map[string]interface{})
+
+	var issues []Issue
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, method := range writeMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := operation["requestBody"]; ok {
+				continue
+			}
+			if exempt, _ := operation[noRequestBodyExtension].(bool); exempt {
+				continue
+			}
+
+			name := strings.ToUpper(method) + " " + path
+			if operationID, _ := operation["operationId"].(string); operationID != "" {
+				name = fmt.Sprintf("%s (%s)", name, operationID)
+			}
+			issues = append(issues, Issue{
+				Rule:     RequireRequestBodyRuleName,
+				Severity: SeverityWarning,
+				Path:     childPointer(childPointer("/paths", path), method),
+				Message:  fmt.Sprintf("%s has no requestBody; add one or mark the operation with %s: true", name, noRequestBodyExtension),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequireRequestBodyRule(t *testing.T) {
+	responses := map[string]interface{}{"200": map[string]interface{}{"description": "OK"}}
+	body := map[string]interface{}{"content": map[string]interface{}{"application/json": map[string]interface{}{}}}
+
+	tests := []struct {
+		name          string
+		paths         map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "POST with a body passes",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{"post": map[string]interface{}{"requestBody": body, "responses": responses}},
+			},
+		},
+		{
+			name: "referenced body passes",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{"put": map[string]interface{}{
+					"requestBody": map[string]interface{}{"$ref": "#/components/requestBodies/Pet"},
+					"responses":   responses,
+				}},
+			},
+		},
+		{
+			name: "write methods without a body warn",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{
+					"post":  map[string]interface{}{"responses": responses},
+					"patch": map[string]interface{}{"responses": responses},
+				},
+				"/pets/{id}": map[string]interface{}{"put": map[string]interface{}{"responses": responses}},
+			},
+			expectedPaths: []string{"/paths/~1pets/post", "/paths/~1pets/patch", "/paths/~1pets~1{id}/put"},
+		},
+		{
+			name: "read methods are not checked",
+			paths: map[string]interface{}{
+				"/pets": map[string]interface{}{
+					"get":    map[string]interface{}{"responses": responses},
+					"delete": map[string]interface{}{"responses": responses},
+				},
+			},
+		},
+		{
+			name: "exempted operation passes",
+			paths: map[string]interface{}{
+				"/jobs/{id}/cancel": map[string]interface{}{"post": map[string]interface{}{"x-no-request-body": true, "responses": responses}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Root: map[string]interface{}{"openapi": "3.0.3", "paths": tt.paths}}
+			issues := NewRequireRequestBodyRule().Check(doc)
+
+			var paths []string
+			for _, issue := range issues {
+				paths = append(paths, issue.Path)
+				if issue.Rule != RequireRequestBodyRuleName || issue.Severity != SeverityWarning {
+					t.Errorf("issue = %+v, want a %s warning", issue, RequireRequestBodyRuleName)
+				}
+			}
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("issue paths = %v, want %v", paths, tt.expectedPaths)
+			}
+		})
+	}
+}
+
+func TestRequireRequestBodyRuleMessage(t *testing.T) {
+	doc := &Document{Root: map[string]interface{}{
+		"paths": map[string]interface{}{"/pets": map[string]interface{}{
+			"post": map[string]interface{}{"operationId": "createPet"},
+		}},
+	}}
+
+	issues := NewRequireRequestBodyRule().Check(doc)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if !strings.Contains(issues[0].Message, "POST /pets (createPet)") || !strings.Contains(issues[0].Message, "x-no-request-body") {
+		t.Errorf("message should name the operation and the exemption: %s", issues[0].Message)
+	}
+}
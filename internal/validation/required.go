@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RequiredPropertiesRuleName is the configuration name of the required properties rule
+const RequiredPropertiesRuleName = "required-properties-exist"
+
+// compositionKeywords are schema keywords whose subschemas may contribute properties
+var compositionKeywords = []string{"allOf", "anyOf", "oneOf"}
+
+// RequiredPropertiesRule warns when a schema in `components.schemas` lists a `required`
+// property that is not defined in its `properties`. Nested schemas (properties, items,
+// additionalProperties and composition subschemas) are checked too.
+// Schemas that combine subschemas or allow additional properties are skipped, since
+// the required property may legitimately be defined elsewhere.
+type RequiredPropertiesRule struct{}
+
+// NewRequiredPropertiesRule creates a new required properties rule
+func NewRequiredPropertiesRule() *RequiredPropertiesRule {
+	return &RequiredPropertiesRule{}
+}
+
+// Name returns the rule name
+func (r *RequiredPropertiesRule) Name() string {
+	return RequiredPropertiesRuleName
+}
+
+// Check scans the component schemas and reports required names without a property
+func (r *RequiredPropertiesRule) Check(doc *Document) []Issue {
+	components, _ := doc.Root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+
+	var issues []Issue
+	for _, name := range sortedKeys(schemas) {
+		r.checkSchema(schemas[name], childPointer("/components/schemas", name), &issues)
+	}
+	return issues
+}
+
+// checkSchema checks a schema object and its nested schemas
+func (r *RequiredPropertiesRule) checkSchema(node interface{}, pointer string, issues *[]Issue) {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if required, ok := schema["required"].([]interface{}); ok && !r.mayDefineElsewhere(schema) {
+		for i, rawName := range required {
+			name, ok := rawName.(string)
+			if !ok {
+				continue
+			}
+			if _, defined := properties[name]; defined {
+				continue
+			}
+			*issues = append(*issues, Issue{
+				Rule:     RequiredPropertiesRuleName,
+				Severity: SeverityWarning,
+				Path:     childPointer(childPointer(pointer, "required"), strconv.Itoa(i)),
+				Message:  fmt.Sprintf("required property %q is not defined in properties", name),
+			})
+		}
+	}
+
+	// Nested schemas
+	for _, name := range sortedKeys(properties) {
+		r.checkSchema(properties[name], childPointer(childPointer(pointer, "properties"), name), issues)
+	}
+	r.checkSchema(schema["items"], childPointer(pointer, "items"), issues)
+	r.checkSchema(schema["additionalProperties"], childPointer(pointer, "additionalProperties"), issues)
+	for _, keyword := range compositionKeywords {
+		subschemas, _ := schema[keyword].([]interface{})
+		for i, subschema := range subschemas {
+			r.checkSchema(subschema, childPointer(childPointer(pointer, keyword), strconv.Itoa(i)), issues)
+		}
+	}
+}
+
+// mayDefineElsewhere reports whether required properties may come from outside `properties`:
+// a $ref, composition subschemas, or additional properties
+func (r *RequiredPropertiesRule) mayDefineElsewhere(schema map[string]interface{}) bool {
+	if _, ok := schema["$ref"]; ok {
+		return true
+	}
+	for _, keyword := range compositionKeywords {
+		if _, ok := schema[keyword]; ok {
+			return true
+		}
+	}
+	switch additional := schema["additionalProperties"].(type) {
+	case bool:
+		return additional
+	case map[string]interface{}:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestRequiredPropertiesRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		schemas       map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "all required properties defined",
+			schemas: map[string]interface{}{
+				"User": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"id", "name"},
+					"properties": map[string]interface{}{
+						"id":   map[string]interface{}{"type": "string"},
+						"name": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+			expectedPaths: nil,
+		},
+		{
+			name: "schema requiring a missing property",
+			schemas: map[string]interface{}{
+				"User": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"id", "emial"},
+					"properties": map[string]interface{}{
+						"id":    map[string]interface{}{"type": "string"},
+						"email": map[string]interface{}{"type": "string"},
+					},
+				},
+				"Empty": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"value"},
+				},
+			},
+			expectedPaths: []string{
+				"/components/schemas/Empty/required/0",
+				"/components/schemas/User/required/1",
+			},
+		},
+		{
+			name: "nested schemas",
+			schemas: map[string]interface{}{
+				"Order": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"lines": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type":       "object",
+								"required":   []interface{}{"sku"},
+								"properties": map[string]interface{}{"qty": map[string]interface{}{"type": "integer"}},
+							},
+						},
+					},
+				},
+			},
+			expectedPaths: []string{"/components/schemas/Order/properties/lines/items/required/0"},
+		},
+		{
+			name: "properties defined elsewhere are skipped",
+			schemas: map[string]interface{}{
+				"Admin": map[string]interface{}{
+					"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}},
+					"required": []interface{}{"id"},
+				},
+				"Labels": map[string]interface{}{
+					"type":                 "object",
+					"required":             []interface{}{"team"},
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+				"Open": map[string]interface{}{
+					"type":                 "object",
+					"required":             []interface{}{"anything"},
+					"additionalProperties": true,
+				},
+			},
+			expectedPaths: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := map[string]interface{}{
+				"components": map[string]interface{}{"schemas": tt.schemas},
+			}
+			issues := NewRequiredPropertiesRule().Check(&Document{Path: "openapi.json", Root: root})
+
+			if len(issues) != len(tt.expectedPaths) {
+				t.Fatalf("Check() returned %d issues, want %d: %v", len(issues), len(tt.expectedPaths), issues)
+			}
+			for i, issue := range issues {
+				if issue.Path != tt.expectedPaths[i] {
+					t.Errorf("issue[%d].Path = %q, want %q", i, issue.Path, tt.expectedPaths[i])
+				}
+				if issue.Severity != SeverityWarning {
+					t.Errorf("issue[%d].Severity = %q, want %q", i, issue.Severity, SeverityWarning)
+				}
+				if issue.Rule != RequiredPropertiesRuleName {
+					t.Errorf("issue[%d].Rule = %q, want %q", i, issue.Rule, RequiredPropertiesRuleName)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"fmt"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// UnusedSecuritySchemeRuleName is the configuration name of the unused security scheme rule
+const UnusedSecuritySchemeRuleName = "unused-security-scheme"
+
+// UnusedSecuritySchemeRule warns about schemes in `components.securitySchemes` that are not
+// referenced by the global `security` requirements or by any operation's `security`.
+type UnusedSecuritySchemeRule struct{}
+
+// NewUnusedSecuritySchemeRule creates a new unused security scheme rule
+func NewUnusedSecuritySchemeRule() *UnusedSecuritySchemeRule {
+	return &UnusedSecuritySchemeRule{}
+}
+
+// Name returns the rule name
+func (r *UnusedSecuritySchemeRule) Name() string {
+	return UnusedSecuritySchemeRuleName
+}
+
+// Check reports every defined security scheme that no security requirement references
+func (r *UnusedSecuritySchemeRule) Check(doc *Document) []Issue {
+	components, _ := doc.Root["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	used := make(map[string]bool)
+	collectSecuritySchemes(doc.Root["security"], used)
+	paths, _ := doc.Root["paths"].(map[string]interface{})
+	for _, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range spec.HTTPMethods {
+			if operation, ok := item[method].(map[string]interface{}); ok {
+				collectSecuritySchemes(operation["security"], used)
+			}
+		}
+	}
+
+	var issues []Issue
+	for _, name := range sortedKeys(schemes) {
+		if used[name] {
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:     UnusedSecuritySchemeRuleName,
+			Severity: SeverityWarning,
+			Path:     childPointer("/components/securitySchemes", name),
+			Message:  fmt.Sprintf("security scheme %q is not referenced by the global or any operation security requirement", name),
+		})
+	}
+	return issues
+}
+
+// collectSecuritySchemes records the scheme names referenced by a security requirement list
+func collectSecuritySchemes(security interface{}, used map[string]bool) {
+	requirements, ok := security.([]interface{})
+	if !ok {
+		return
+	}
+	for _, rawRequirement := range requirements {
+		requirement, ok := rawRequirement.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name := range requirement {
+			used[name] = true
+		}
+	}
+}
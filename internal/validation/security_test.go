@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestUnusedSecuritySchemeRule(t *testing.T) {
+	schemes := map[string]interface{}{
+		"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+		"apiKey":     map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+	}
+
+	tests := []struct {
+		name          string
+		root          map[string]interface{}
+		expectedPaths []string
+	}{
+		{
+			name: "scheme referenced by global security",
+			root: map[string]interface{}{
+				"security":   []interface{}{map[string]interface{}{"bearerAuth": []interface{}{}}},
+				"components": map[string]interface{}{"securitySchemes": schemes},
+			},
+			expectedPaths: []string{"/components/securitySchemes/apiKey"},
+		},
+		{
+			name: "scheme referenced by operation security",
+			root: map[string]interface{}{
+				"paths": map[string]interface{}{
+					"/users": map[string]interface{}{
+						"get": map[string]interface{}{
+							"security": []interface{}{map[string]interface{}{"apiKey": []interface{}{}}},
+						},
+					},
+				},
+				"components": map[string]interface{}{"securitySchemes": schemes},
+			},
+			expectedPaths: []string{"/components/securitySchemes/bearerAuth"},
+		},
+		{
+			name: "all schemes referenced",
+			root: map[string]interface{}{
+				"security": []interface{}{map[string]interface{}{"bearerAuth": []interface{}{}}},
+				"paths": map[string]interface{}{
+					"/admin": map[string]interface{}{
+						"post": map[string]interface{}{
+							"security": []interface{}{
+								map[string]interface{}{"bearerAuth": []interface{}{}, "apiKey": []interface{}{}},
+							},
+						},
+					},
+				},
+				"components": map[string]interface{}{"securitySchemes": schemes},
+			},
+			expectedPaths: nil,
+		},
+		{
+			name: "no schemes referenced",
+			root: map[string]interface{}{
+				"components": map[string]interface{}{"securitySchemes": schemes},
+			},
+			expectedPaths: []string{
+				"/components/securitySchemes/apiKey",
+				"/components/securitySchemes/bearerAuth",
+			},
+		},
+		{
+			name:          "no security schemes defined",
+			root:          map[string]interface{}{"paths": map[string]interface{}{}},
+			expectedPaths: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewUnusedSecuritySchemeRule()
+			issues := rule.Check(&Document{Path: "openapi.json", Root: tt.root})
+
+			if len(issues) != len(tt.expectedPaths) {
+				t.Fatalf("Check() returned %d issues, want %d: %v", len(issues), len(tt.expectedPaths), issues)
+			}
+			for i, issue := range issues {
+				if issue.Path != tt.expectedPaths[i] {
+					t.Errorf("issue[%d].Path = %q, want %q", i, issue.Path, tt.expectedPaths[i])
+				}
+				if issue.Severity != SeverityWarning {
+					t.Errorf("issue[%d].Severity = %q, want %q", i, issue.Severity, SeverityWarning)
+				}
+				if issue.Rule != UnusedSecuritySchemeRuleName {
+					t.Errorf("issue[%d].Rule = %q, want %q", i, issue.Rule, UnusedSecuritySchemeRuleName)
+				}
+			}
+		})
+	}
+}
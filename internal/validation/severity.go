@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SeverityOff turns a rule's issues off in a severity mapping; issues never carry it
+const SeverityOff Severity = "off"
+
+// ParseRuleSeverities checks a mapping of rule names to "error", "warning" or "off"
+// from configuration. Returns an error for unknown rules or severities.
+func ParseRuleSeverities(raw map[string]string) (map[string]Severity, error) {
+	rules := make([]string, 0, len(raw))
+	for rule := range raw {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	severities := make(map[string]Severity, len(raw))
+	for _, rule := range rules {
+		value := raw[rule]
+		if _, ok := lookupRule(rule); !ok {
+			return nil, fmt.Errorf("unknown validation rule %q in rule_severities (available: %s)",
+				rule, strings.Join(AvailableRules(), ", "))
+		}
+		severity := Severity(strings.ToLower(strings.TrimSpace(value)))
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityOff:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for rule %q in rule_severities (must be error, warning or off)", value, rule)
+		}
+		severities[rule] = severity
+	}
+	return severities, nil
+}
+
+// ApplySeverities returns the issues with their severity replaced by the mapping for their
+// rule. Issues of rules mapped to "off" are removed; rules without a mapping are unchanged.
+func ApplySeverities(issues []Issue, severities map[string]Severity) []Issue {
+	if len(severities) == 0 {
+		return issues
+	}
+
+	var result []Issue
+	for _, issue := range issues {
+		severity, ok := severities[issue.Rule]
+		switch {
+		case !ok:
+		case severity == SeverityOff:
+			continue
+		default:
+			issue.Severity = severity
+		}
+		result = append(result, issue)
+	}
+	return result
+}
+
+// SetSeverities overrides the severity of the issues reported by each mapped rule
+func (v *Validator) SetSeverities(severities map[string]Severity) {
+	v.severities = severities
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplySeverities(t *testing.T) {
+	issues := []Issue{
+		{Rule: ExamplesRuleName, Severity: SeverityWarning, Path: "/components/schemas/Age/example"},
+		{Rule: UniqueOperationIDsRuleName, Severity: SeverityError, Path: "/paths/~1dogs/get/operationId"},
+		{Rule: UnusedSecuritySchemeRuleName, Severity: SeverityWarning, Path: "/components/securitySchemes/apiKey"},
+		{Rule: RequiredPropertiesRuleName, Severity: SeverityWarning, Path: "/components/schemas/Pet/required/0"},
+	}
+	severities := map[string]Severity{
+		ExamplesRuleName:             SeverityError,
+		UniqueOperationIDsRuleName:   SeverityWarning,
+		UnusedSecuritySchemeRuleName: SeverityOff,
+	}
+
+	got := ApplySeverities(issues, severities)
+
+	want := []Issue{
+		{Rule: ExamplesRuleName, Severity: SeverityError, Path: "/components/schemas/Age/example"},
+		{Rule: UniqueOperationIDsRuleName, Severity: SeverityWarning, Path: "/paths/~1dogs/get/operationId"},
+		{Rule: RequiredPropertiesRuleName, Severity: SeverityWarning, Path: "/components/schemas/Pet/required/0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplySeverities() = %v, want %v", got, want)
+	}
+	if issues[0].Severity != SeverityWarning {
+		t.Error("ApplySeverities() should not modify the input issues")
+	}
+}
+
+func TestValidatorAppliesSeverities(t *testing.T) {
+	doc := &Document{Root: map[string]interface{}{
+		"openapi": "3.0.3",
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{"apiKey": map[string]interface{}{"type": "apiKey"}},
+		},
+	}}
+
+	validator := NewValidator(NewUnusedSecuritySchemeRule())
+	if issues := validator.Validate(doc); len(issues) != 1 || issues[0].Severity != SeverityWarning {
+		t.Fatalf("Validate() without overrides = %v, want one warning", issues)
+	}
+
+	validator.SetSeverities(map[string]Severity{UnusedSecuritySchemeRuleName: SeverityError})
+	issues := validator.Validate(doc)
+	if !HasErrors(issues) {
+		t.Errorf("Validate() = %v, want the warning promoted to an error", issues)
+	}
+}
+
+func TestParseRuleSeverities(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    map[string]string
+		want   map[string]Severity
+		errMsg string
+	}{
+		{
+			name: "valid severities",
+			raw:  map[string]string{ExamplesRuleName: "Error", UniqueOperationIDsRuleName: " warning ", UnusedSecuritySchemeRuleName: "off"},
+			want: map[string]Severity{ExamplesRuleName: SeverityError, UniqueOperationIDsRuleName: SeverityWarning, UnusedSecuritySchemeRuleName: SeverityOff},
+		},
+		{
+			name:   "unknown rule",
+			raw:    map[string]string{"no-such-rule": "error"},
+			errMsg: `unknown validation rule "no-such-rule"`,
+		},
+		{
+			name:   "unknown severity",
+			raw:    map[string]string{ExamplesRuleName: "fatal"},
+			errMsg: `invalid severity "fatal"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRuleSeverities(tt.raw)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("ParseRuleSeverities() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRuleSeverities() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRuleSeverities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// spectralRuleset is the subset of a Spectral ruleset file (.spectral.yaml) that is supported
+type spectralRuleset struct {
+	Extends json.RawMessage            `json:"extends"`
+	Rules   map[string]json.RawMessage `json:"rules"`
+}
+
+// spectralRuleDefinition is a rule of a Spectral ruleset
+type spectralRuleDefinition struct {
+	Description string          `json:"description"`
+	Message     string          `json:"message"`
+	Severity    json.RawMessage `json:"severity"`
+	Given       json.RawMessage `json:"given"`
+	Then        json.RawMessage `json:"then"`
+}
+
+// spectralThen is a function applied to the nodes selected by a rule
+type spectralThen struct {
+	Field           string `json:"field"`
+	Function        string `json:"function"`
+	FunctionOptions struct {
+		Match    string        `json:"match"`
+		NotMatch string        `json:"notMatch"`
+		Values   []interface{} `json:"values"`
+	} `json:"functionOptions"`
+}
+
+// spectralCheck is a parsed then clause
+type spectralCheck struct {
+	field    string
+	function string
+	match    *regexp.Regexp
+	notMatch *regexp.Regexp
+	values   []interface{}
+}
+
+// SpectralRule is a rule loaded from a Spectral ruleset. It applies the truthy, pattern or
+// enumeration function to the nodes selected by JSONPath expressions.
+type SpectralRule struct {
+	name        string
+	description string
+	message     string
+	severity    Severity
+	given       []jsonPath
+	then        []spectralCheck
+}
+
+// LoadSpectralRuleset reads the rules of a Spectral ruleset file (YAML or JSON). Rules turned
+// off are left out. Returns an error for rules using functions, JSONPath expressions or options
+// that are not supported, so they are not silently skipped.
+func LoadSpectralRuleset(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Spectral ruleset: %w", err)
+	}
+	var ruleset spectralRuleset
+	if err := yaml.Unmarshal(data, &ruleset); err != nil {
+		return nil, fmt.Errorf("failed to parse Spectral ruleset %s: %w", path, err)
+	}
+	if len(ruleset.Extends) > 0 {
+		log.Printf("Warning: Spectral ruleset %s extends other rulesets, which are not loaded", path)
+	}
+
+	var rules []Rule
+	for _, name := range sortedRawKeys(ruleset.Rules) {
+		rule, err := parseSpectralRule(name, ruleset.Rules[name])
+		if err != nil {
+			return nil, fmt.Errorf("Spectral ruleset %s: rule %q: %w", path, name, err)
+		}
+		if rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// parseSpectralRule parses a rule definition; returns nil for rules turned off, including
+// toggles of rules from extended rulesets
+func parseSpectralRule(name string, raw json.RawMessage) (*SpectralRule, error) {
+	var toggle interface{}
+	json.Unmarshal(raw, &toggle)
+	if _, isDefinition := toggle.(map[string]interface{}); !isDefinition {
+		log.Printf("Warning: Spectral rule %q only toggles a rule of an extended ruleset, ignored", name)
+		return nil, nil
+	}
+
+	var definition spectralRuleDefinition
+	if err := json.Unmarshal(raw, &definition); err != nil {
+		return nil, err
+	}
+	if _, exists := lookupRule(name); exists {
+		return nil, fmt.Errorf("has the name of a built-in validation rule")
+	}
+	severity, err := parseSpectralSeverity(definition.Severity)
+	if err != nil || severity == SeverityOff {
+		return nil, err
+	}
+
+	rule := &SpectralRule{
+		name:        name,
+		description: definition.Description,
+		message:     definition.Message,
+		severity:    severity,
+	}
+
+	var given []string
+	if err := unmarshalOneOrMany(definition.Given, &given); err != nil || len(given) == 0 {
+		return nil, fmt.Errorf("given must be a JSONPath or a list of JSONPaths")
+	}
+	for _, expr := range given {
+		if strings.HasPrefix(expr, "#") {
+			return nil, fmt.Errorf("aliases such as %q are not supported", expr)
+		}
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		rule.given = append(rule.given, path)
+	}
+
+	var then []spectralThen
+	if err := unmarshalOneOrMany(definition.Then, &then); err != nil || len(then) == 0 {
+		return nil, fmt.Errorf("then must be an object or a list of objects")
+	}
+	for _, clause := range then {
+		check, err := parseSpectralCheck(clause)
+		if err != nil {
+			return nil, err
+		}
+		rule.then = append(rule.then, check)
+	}
+	return rule, nil
+}
+
+// parseSpectralCheck parses a then clause using the truthy, pattern or enumeration function
+func parseSpectralCheck(clause spectralThen) (spectralCheck, error) {
+	check := spectralCheck{field: clause.Field, function: clause.Function}
+	if strings.HasPrefix(clause.Field, "$") {
+		return check, fmt.Errorf("JSONPath fields such as %q are not supported", clause.Field)
+	}
+
+	var err error
+	switch clause.Function {
+	case "truthy":
+	case "pattern":
+		options := clause.FunctionOptions
+		if options.Match == "" && options.NotMatch == "" {
+			return check, fmt.Errorf("pattern needs a match or notMatch option")
+		}
+		if check.match, err = compileSpectralPattern(options.Match); err != nil {
+			return check, err
+		}
+		if check.notMatch, err = compileSpectralPattern(options.NotMatch); err != nil {
+			return check, err
+		}
+	case "enumeration":
+		if len(clause.FunctionOptions.Values) == 0 {
+			return check, fmt.Errorf("enumeration needs a values option")
+		}
+		check.values = clause.FunctionOptions.Values
+	default:
+		return check, fmt.Errorf("function %q is not supported (supported: truthy, pattern, enumeration)", clause.Function)
+	}
+	return check, nil
+}
+
+// compileSpectralPattern compiles a pattern option, written as a regular expression or as
+// /expression/flags; nil for an empty pattern
+func compileSpectralPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	expr := pattern
+	if end := strings.LastIndex(pattern, "/"); strings.HasPrefix(pattern, "/") && end > 0 {
+		expr = pattern[1:end]
+		var flags string
+		for _, flag := range pattern[end+1:] {
+			if strings.ContainsRune("ims", flag) {
+				flags += string(flag)
+			}
+		}
+		if flags != "" {
+			expr = "(?" + flags + ")" + expr
+		}
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// parseSpectralSeverity maps a Spectral severity to a validation severity: error (or 0) is an
+// error, warn (1), info (2) and hint (3) are warnings, and off (or -1) turns the rule off.
+// Spectral rules warn by default.
+func parseSpectralSeverity(raw json.RawMessage) (Severity, error) {
+	if len(raw) == 0 {
+		return SeverityWarning, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+	switch fmt.Sprint(value) {
+	case "error", "0":
+		return SeverityError, nil
+	case "warn", "info", "hint", "1", "2", "3":
+		return SeverityWarning, nil
+	case "off", "-1", "false":
+		return SeverityOff, nil
+	default:
+		return "", fmt.Errorf("invalid severity %v (must be error, warn, info, hint or off)", value)
+	}
+}
+
+// unmarshalOneOrMany decodes a value or a list of values into a slice
+func unmarshalOneOrMany[T any](raw json.RawMessage, target *[]T) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err == nil {
+		return nil
+	}
+	var single T
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return err
+	}
+	*target = []T{single}
+	return nil
+}
+
+// sortedRawKeys returns the keys of the ruleset rules in sorted order
+func sortedRawKeys(rules map[string]json.RawMessage) []string {
+	obj := make(map[string]interface{}, len(rules))
+	for name := range rules {
+		obj[name] = nil
+	}
+	return sortedKeys(obj)
+}
+
+// Name returns the rule name from the ruleset
+func (r *SpectralRule) Name() string {
+	return r.name
+}
+
+// Check applies the rule's functions to the nodes selected by its given paths
+func (r *SpectralRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	for _, path := range r.given {
+		for _, node := range path.evaluate(doc.Root) {
+			for _, check := range r.then {
+				for _, target := range check.targets(node) {
+					if message := check.apply(target); message != "" {
+						issues = append(issues, r.issue(target, message))
+					}
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// spectralTarget is the value a function is applied to; present is false for a missing field
+type spectralTarget struct {
+	jsonPathNode
+	property string
+	present  bool
+}
+
+// targets returns the values of a selected node the function is applied to: the node itself,
+// its field (a property name or a dotted path) or, for the field @key, each of its keys
+func (c spectralCheck) targets(node jsonPathNode) []spectralTarget {
+	property := lastPointerToken(node.pointer)
+	if c.field == "" {
+		return []spectralTarget{{node, property, true}}
+	}
+
+	if c.field == "@key" {
+		obj, _ := node.value.(map[string]interface{})
+		var targets []spectralTarget
+		for _, key := range sortedKeys(obj) {
+			targets = append(targets, spectralTarget{jsonPathNode{childPointer(node.pointer, key), key}, key, true})
+		}
+		return targets
+	}
+
+	// A missing field keeps the pointer to where it would be
+	target := spectralTarget{jsonPathNode: node, present: true}
+	for _, name := range strings.Split(c.field, ".") {
+		obj, _ := target.value.(map[string]interface{})
+		target.pointer = childPointer(target.pointer, name)
+		target.property = name
+		target.value, target.present = obj[name]
+	}
+	return []spectralTarget{target}
+}
+
+// apply returns the function's message if the target fails it, or "" if it passes
+func (c spectralCheck) apply(target spectralTarget) string {
+	switch c.function {
+	case "truthy":
+		if !target.present || !isTruthy(target.value) {
+			return fmt.Sprintf("%q property must be truthy", target.property)
+		}
+	case "pattern":
+		value, ok := target.value.(string)
+		if !ok {
+			return ""
+		}
+		if c.match != nil && !c.match.MatchString(value) {
+			return fmt.Sprintf("%q must match the pattern %q", value, c.match.String())
+		}
+		if c.notMatch != nil && c.notMatch.MatchString(value) {
+			return fmt.Sprintf("%q must not match the pattern %q", value, c.notMatch.String())
+		}
+	case "enumeration":
+		if !target.present || target.value == nil {
+			return ""
+		}
+		for _, allowed := range c.values {
+			if reflect.DeepEqual(allowed, target.value) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s must be equal to one of the allowed values: %s", describeInstance(target.value), describeValues(c.values))
+	}
+	return ""
+}
+
+// issue builds the issue for a failed function, with the rule's message template if it has one
+func (r *SpectralRule) issue(target spectralTarget, message string) Issue {
+	template := r.message
+	if template == "" {
+		template = r.description
+	}
+	if template != "" {
+		value := ""
+		if target.present {
+			value = describeTemplateValue(target.value)
+		}
+		message = strings.NewReplacer(
+			"{{error}}", message,
+			"{{description}}", r.description,
+			"{{property}}", target.property,
+			"{{path}}", target.pointer,
+			"{{value}}", value,
+		).Replace(template)
+	}
+	return Issue{Rule: r.name, Severity: r.severity, Path: target.pointer, Message: message}
+}
+
+// isTruthy reports whether a value is truthy in JavaScript terms, as Spectral checks it
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// describeTemplateValue renders a value for the {{value}} placeholder, strings without quotes
+func describeTemplateValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return describeInstance(value)
+}
+
+// lastPointerToken returns the unescaped last token of a JSON pointer
+func lastPointerToken(pointer string) string {
+	token := pointer[strings.LastIndex(pointer, "/")+1:]
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
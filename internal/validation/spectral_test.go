@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+func writeRuleset(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".spectral.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write ruleset: %v", err)
+	}
+	return path
+}
+
+func TestSpectralRuleset(t *testing.T) {
+	rulesetPath := writeRuleset(t, `
+extends: ["spectral:oas"]
+rules:
+  operation-tags: off
+  info-contact:
+    description: Info must have a contact
+    given: $.info
+    severity: error
+    then:
+      field: contact.email
+      function: truthy
+  paths-kebab-case:
+    message: "{{property}} is not kebab-case: {{error}}"
+    given: $.paths
+    then:
+      field: "@key"
+      function: pattern
+      functionOptions:
+        match: "^(/[a-z0-9-{}]+)+$"
+  no-x-internal-servers:
+    given: ["$.servers[*].url"]
+    severity: hint
+    then:
+      function: pattern
+      functionOptions:
+        notMatch: /INTERNAL/i
+  parameter-in:
+    given: $..parameters[*]
+    then:
+      - field: in
+        function: enumeration
+        functionOptions:
+          values: [query, path]
+      - field: description
+        function: truthy
+  disabled:
+    severity: off
+    given: $
+    then:
+      function: truthy
+`)
+	rules, err := LoadSpectralRuleset(rulesetPath)
+	if err != nil {
+		t.Fatalf("LoadSpectralRuleset() error = %v", err)
+	}
+	validator := NewValidator()
+	validator.AddRules(rules...)
+	expectedRules := []string{"info-contact", "no-x-internal-servers", "parameter-in", "paths-kebab-case"}
+	if !reflect.DeepEqual(validator.Rules(), expectedRules) {
+		t.Fatalf("Rules() = %v, want %v", validator.Rules(), expectedRules)
+	}
+
+	root, err := spec.DecodeDocument([]byte(`
+openapi: 3.0.3
+info: {title: Users, version: "1.0", contact: {name: Team}}
+servers: [{url: "https://api.example.com"}, {url: "https://internal.example.com"}]
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, description: The user ID}
+        - {name: X-Trace, in: header}
+  /userGroups: {}
+`), ".yaml")
+	if err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	issues := validator.Validate(&Document{Root: root})
+
+	expected := []Issue{
+		{Rule: "info-contact", Severity: SeverityError, Path: "/info/contact/email", Message: "Info must have a contact"},
+		{Rule: "no-x-internal-servers", Severity: SeverityWarning, Path: "/servers/1/url", Message: `"https://internal.example.com" must not match the pattern "(?i)INTERNAL"`},
+		{Rule: "parameter-in", Severity: SeverityWarning, Path: "/paths/~1users~1{id}/get/parameters/1/in", Message: `"header" must be equal to one of the allowed values: "query", "path"`},
+		{Rule: "parameter-in", Severity: SeverityWarning, Path: "/paths/~1users~1{id}/get/parameters/1/description", Message: `"description" property must be truthy`},
+		{Rule: "paths-kebab-case", Severity: SeverityWarning, Path: "/paths/~1userGroups", Message: `/userGroups is not kebab-case: "/userGroups" must match the pattern "^(/[a-z0-9-{}]+)+$"`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Validate() =\n%v\nwant\n%v", issues, expected)
+	}
+}
+
+func TestLoadSpectralRulesetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{name: "unsupported function", rule: "{given: $, then: {function: schema}}", wantErr: `function "schema" is not supported`},
+		{name: "filter expression", rule: "{given: \"$.paths[?(@.get)]\", then: {function: truthy}}", wantErr: "not supported"},
+		{name: "alias", rule: "{given: \"#Operations\", then: {function: truthy}}", wantErr: "aliases"},
+		{name: "missing given", rule: "{then: {function: truthy}}", wantErr: "given must be"},
+		{name: "pattern without options", rule: "{given: $, then: {function: pattern}}", wantErr: "match or notMatch"},
+		{name: "invalid pattern", rule: "{given: $, then: {function: pattern, functionOptions: {match: \"(?=x)\"}}}", wantErr: "invalid pattern"},
+		{name: "invalid severity", rule: "{given: $, severity: fatal, then: {function: truthy}}", wantErr: "invalid severity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpectralRuleset(writeRuleset(t, "rules:\n  custom: "+tt.rule+"\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadSpectralRuleset() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadSpectralRuleset(writeRuleset(t, "rules:\n  "+ExamplesRuleName+": {given: $, then: {function: truthy}}\n")); err == nil {
+		t.Error("LoadSpectralRuleset() expected error for a rule named like a built-in rule")
+	}
+	if _, err := LoadSpectralRuleset(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadSpectralRuleset() expected error for a missing file")
+	}
+}
@@ -0,0 +1,224 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/spec"
+)
+
+// Severity indicates how serious a validation issue is
+type Severity string
+
+const (
+	// SeverityError marks issues that should fail generation for the spec
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks issues that are reported but do not fail generation
+	SeverityWarning Severity = "warning"
+)
+
+// Issue describes a single problem found in a spec
+type Issue struct {
+	// Rule is the name of the rule that reported the issue
+	Rule string `json:"rule"`
+
+	// Severity is the severity of the issue
+	Severity Severity `json:"severity"`
+
+	// Path is the JSON pointer to the offending location in the spec
+	Path string `json:"path"`
+
+	// Message is a human-readable description of the issue
+	Message string `json:"message"`
+}
+
+// String returns a single-line representation of the issue
+func (i Issue) String() string {
+	return fmt.Sprintf("[%s] %s: %s (at %s)", i.Severity, i.Rule, i.Message, i.Path)
+}
+
+// Document is a decoded OpenAPI spec passed to validation rules
+type Document struct {
+	// Path is the spec file path
+	Path string
+
+	// Root is the decoded spec document tree
+	Root map[string]interface{}
+}
+
+// LoadDocument reads and decodes a spec file for validation.
+// Returns a *DocumentError with code NOT_OPENAPI if the file is not an OpenAPI document.
+func LoadDocument(specPath string) (*Document, error) {
+	root, err := spec.LoadDocument(specPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewDocument(specPath, root)
+}
+
+// NewDocument wraps a spec the caller already decoded for validation, with the same
+// NOT_OPENAPI check as LoadDocument. Rules do not modify the document.
+func NewDocument(specPath string, root map[string]interface{}) (*Document, error) {
+	doc := &Document{Path: specPath, Root: root}
+	if err := checkOpenAPIDocument(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Rule defines a single validation check over a spec document
+type Rule interface {
+	// Name returns the rule name used in configuration (e.g., "validate-examples")
+	Name() string
+
+	// Check inspects the document and returns any issues found
+	Check(doc *Document) []Issue
+}
+
+// optionalRulesMu guards optionalRules against rules registered concurrently with lookups
+var optionalRulesMu sync.RWMutex
+
+// optionalRules are rules that can be enabled by name via configuration, including the rules
+// added with RegisterRule
+var optionalRules = map[string]func() Rule{
+	ExamplesRuleName:             func() Rule { return NewExamplesRule() },
+	UnusedSecuritySchemeRuleName: func() Rule { return NewUnusedSecuritySchemeRule() },
+	RequiredPropertiesRuleName:   func() Rule { return NewRequiredPropertiesRule() },
+	UniqueOperationIDsRuleName:   func() Rule { return NewUniqueOperationIDsRule() },
+	MethodSupportRuleName:        func() Rule { return NewMethodSupportRule() },
+	RequireRequestBodyRuleName:   func() Rule { return NewRequireRequestBodyRule() },
+	OperationIDVerbRuleName:      func() Rule { return NewOperationIDVerbRule() },
+	OpenAPISchemaRuleName:        func() Rule { return NewOpenAPISchemaRule() },
+}
+
+// RegisterRule adds an organization-specific rule that can be enabled by name in
+// validation_rules and rule_severities like the built-in rules. The factory is called once per
+// validator. Register rules before loading the configuration, e.g. from an init function.
+func RegisterRule(name string, factory func() Rule) error {
+	if name == "" {
+		return fmt.Errorf("validation rule name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("cannot register nil factory for validation rule %q", name)
+	}
+
+	optionalRulesMu.Lock()
+	defer optionalRulesMu.Unlock()
+	if _, exists := optionalRules[name]; exists {
+		return fmt.Errorf("validation rule %q is already registered", name)
+	}
+	optionalRules[name] = factory
+	return nil
+}
+
+// lookupRule returns the factory of an optional rule by name
+func lookupRule(name string) (func() Rule, bool) {
+	optionalRulesMu.RLock()
+	defer optionalRulesMu.RUnlock()
+	factory, ok := optionalRules[name]
+	return factory, ok
+}
+
+// AvailableRules returns the names of all optional rules, sorted
+func AvailableRules() []string {
+	optionalRulesMu.RLock()
+	defer optionalRulesMu.RUnlock()
+	names := make([]string, 0, len(optionalRules))
+	for name := range optionalRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validator runs an ordered set of rules against spec documents
+type Validator struct {
+	rules      []Rule
+	severities map[string]Severity
+}
+
+// NewValidator creates a validator with the given rules
+func NewValidator(rules ...Rule) *Validator {
+	return &Validator{rules: rules}
+}
+
+// NewValidatorFromNames creates a validator with the optional rules enabled by name.
+// Returns an error if any rule name is unknown.
+func NewValidatorFromNames(names []string) (*Validator, error) {
+	rules := make([]Rule, 0, len(names))
+	for _, name := range names {
+		factory, ok := lookupRule(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown validation rule %q (available: %s)",
+				name, strings.Join(AvailableRules(), ", "))
+		}
+		rules = append(rules, factory())
+	}
+	return NewValidator(rules...), nil
+}
+
+// AddRules appends rules to those run by the validator, such as the rules of a Spectral ruleset
+func (v *Validator) AddRules(rules ...Rule) {
+	v.rules = append(v.rules, rules...)
+}
+
+// Rules returns the names of the rules run by the validator
+func (v *Validator) Rules() []string {
+	names := make([]string, len(v.rules))
+	for i, rule := range v.rules {
+		names[i] = rule.Name()
+	}
+	return names
+}
+
+// Validate runs all rules against the document and returns the issues found,
+// with the configured severity overrides applied
+func (v *Validator) Validate(doc *Document) []Issue {
+	var issues []Issue
+	for _, rule := range v.rules {
+		issues = append(issues, rule.Check(doc)...)
+	}
+	return ApplySeverities(issues, v.severities)
+}
+
+// ValidateFile loads a spec file and runs all rules against it
+func (v *Validator) ValidateFile(specPath string) ([]Issue, error) {
+	doc, err := LoadDocument(specPath)
+	if err != nil {
+		return nil, err
+	}
+	return v.Validate(doc), nil
+}
+
+// HasErrors reports whether any issue has error severity
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointerToken escapes a key for use in a JSON pointer (RFC 6901)
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// childPointer appends a token to a JSON pointer
+func childPointer(parent, token string) string {
+	return parent + "/" + escapePointerToken(token)
+}
+
+// sortedKeys returns the keys of a document object in sorted order for deterministic output
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
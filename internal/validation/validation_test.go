@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewValidatorFromNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []string
+		wantErr bool
+	}{
+		{name: "no rules", rules: nil},
+		{name: "known rule", rules: []string{ExamplesRuleName}},
+		{name: "unknown rule", rules: []string{"no-such-rule"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := NewValidatorFromNames(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewValidatorFromNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), ExamplesRuleName) {
+					t.Errorf("error should list available rules, got %q", err.Error())
+				}
+				return
+			}
+			if len(validator.Rules()) != len(tt.rules) {
+				t.Errorf("Rules() = %v, want %v", validator.Rules(), tt.rules)
+			}
+		})
+	}
+}
+
+func TestValidatorValidateFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	jsonSpec := filepath.Join(tmpDir, "openapi.json")
+	if err := os.WriteFile(jsonSpec, []byte(`{"openapi":"3.0.0","components":{"schemas":{"Age":{"type":"integer","example":"old"}}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	yamlSpec := filepath.Join(tmpDir, "openapi.yaml")
+	yamlContent := "openapi: 3.0.0\ncomponents:\n  schemas:\n    Age:\n      type: integer\n      example: old\n"
+	if err := os.WriteFile(yamlSpec, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	validator := NewValidator(NewExamplesRule())
+
+	for _, specPath := range []string{jsonSpec, yamlSpec} {
+		t.Run(filepath.Base(specPath), func(t *testing.T) {
+			issues, err := validator.ValidateFile(specPath)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+			if len(issues) != 1 {
+				t.Fatalf("ValidateFile() returned %d issues, want 1: %v", len(issues), issues)
+			}
+			if HasErrors(issues) {
+				t.Error("HasErrors() = true, want false for warnings only")
+			}
+		})
+	}
+
+	if _, err := validator.ValidateFile(filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("ValidateFile() expected error for missing file")
+	}
+}
+
+// titleRule is an organization-specific rule requiring info.title, as registered by RegisterRule
+type titleRule struct{}
+
+func (titleRule) Name() string { return "require-title" }
+
+func (titleRule) Check(doc *Document) []Issue {
+	info, _ := doc.Root["info"].(map[string]interface{})
+	if title, _ := info["title"].(string); title != "" {
+		return nil
+	}
+	return []Issue{{Rule: "require-title", Severity: SeverityWarning, Path: "/info", Message: "info.title is required"}}
+}
+
+func TestRegisterRule(t *testing.T) {
+	if err := RegisterRule("require-title", func() Rule { return titleRule{} }); err != nil {
+		t.Fatalf("RegisterRule() error = %v", err)
+	}
+	t.Cleanup(func() {
+		optionalRulesMu.Lock()
+		delete(optionalRules, "require-title")
+		optionalRulesMu.Unlock()
+	})
+
+	for _, name := range []string{"require-title", ExamplesRuleName, ""} {
+		if err := RegisterRule(name, func() Rule { return titleRule{} }); err == nil {
+			t.Errorf("RegisterRule(%q) expected error for a duplicate or empty name", name)
+		}
+	}
+	if err := RegisterRule("no-factory", nil); err == nil {
+		t.Error("RegisterRule() expected error for a nil factory")
+	}
+
+	if !strings.Contains(strings.Join(AvailableRules(), ","), "require-title") {
+		t.Errorf("AvailableRules() = %v, want the registered rule", AvailableRules())
+	}
+	validator, err := NewValidatorFromNames([]string{"require-title"})
+	if err != nil {
+		t.Fatalf("NewValidatorFromNames() error = %v", err)
+	}
+	severities, err := ParseRuleSeverities(map[string]string{"require-title": "error"})
+	if err != nil {
+		t.Fatalf("ParseRuleSeverities() error = %v", err)
+	}
+	validator.SetSeverities(severities)
+
+	issues := validator.Validate(&Document{Root: map[string]interface{}{"openapi": "3.0.3", "info": map[string]interface{}{}}})
+	if len(issues) != 1 || issues[0].Severity != SeverityError {
+		t.Errorf("Validate() = %v, want one error from the registered rule", issues)
+	}
+}
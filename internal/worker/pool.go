@@ -2,15 +2,30 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"time"
 )
 
+// ErrTaskTimeout is reported for a task that ran past its Timeout
+var ErrTaskTimeout = errors.New("task timed out")
+
 // Task represents a unit of work to be processed by the worker pool
 type Task struct {
 	ID      string
 	Execute func(ctx context.Context) error
+
+	// Timeout bounds the execution of this task alone; its context is cancelled once it
+	// expires and the task's result is an ErrTaskTimeout error. Zero means no task deadline.
+	Timeout time.Duration
+}
+
+// queuedTask is a task waiting for a worker, with the context it was submitted with
+type queuedTask struct {
+	task Task
+	ctx  context.Context // nil for tasks submitted without a context
 }
 
 // Result represents the result of processing a task
@@ -22,13 +37,39 @@ type Result struct {
 // Pool manages a pool of workers for concurrent task execution
 type Pool struct {
 	workerCount int
-	tasks       chan Task
+	tasks       chan queuedTask
 	results     chan Result
 	wg          sync.WaitGroup
 	ctx         context.Context
 	cancel      context.CancelFunc
 	mu          sync.Mutex
 	started     bool
+	stopOnError bool
+	stopped     bool // set when the pool was stopped by a task error
+
+	// Utilization statistics, guarded by statsMu
+	statsMu        sync.Mutex
+	peakQueueDepth int
+	tasksSubmitted int
+	tasksPerWorker []int
+}
+
+// Metrics describes worker pool utilization, to help right-size the worker count
+type Metrics struct {
+	// WorkerCount is the number of workers in the pool
+	WorkerCount int
+
+	// PeakQueueDepth is the largest number of tasks waiting in the queue at once
+	PeakQueueDepth int
+
+	// TasksSubmitted is the number of tasks accepted by the pool
+	TasksSubmitted int
+
+	// TasksProcessed is the number of tasks executed by workers
+	TasksProcessed int
+
+	// TasksPerWorker is the number of tasks executed by each worker (index 0 is worker 1)
+	TasksPerWorker []int
 }
 
 // Config contains configuration for the worker pool
@@ -37,6 +78,8 @@ type Config struct {
 	WorkerCount int
 	// Buffer size for task queue (defaults to 100)
 	TaskQueueSize int
+	// StopOnError cancels remaining tasks as soon as any task fails (defaults to false)
+	StopOnError bool
 }
 
 // NewPool creates a new worker pool with the given configuration
@@ -51,11 +94,13 @@ func NewPool(cfg Config) *Pool {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Pool{
-		workerCount: cfg.WorkerCount,
-		tasks:       make(chan Task, cfg.TaskQueueSize),
-		results:     make(chan Result, cfg.TaskQueueSize),
-		ctx:         ctx,
-		cancel:      cancel,
+		workerCount:    cfg.WorkerCount,
+		tasks:          make(chan queuedTask, cfg.TaskQueueSize),
+		results:        make(chan Result, cfg.TaskQueueSize),
+		ctx:            ctx,
+		cancel:         cancel,
+		stopOnError:    cfg.StopOnError,
+		tasksPerWorker: make([]int, cfg.WorkerCount),
 	}
 }
 
@@ -89,35 +134,168 @@ func (p *Pool) worker(id int) {
 			log.Printf("Worker %d stopping due to context cancellation", id)
 			return
 
-		case task, ok := <-p.tasks:
+		case queued, ok := <-p.tasks:
+			task := queued.task
 			if !ok {
 				log.Printf("Worker %d stopping: task channel closed", id)
 				return
 			}
 
+			// Don't start queued tasks once the pool has been cancelled
+			if p.ctx.Err() != nil {
+				log.Printf("Worker %d skipping task %s: context cancelled", id, task.ID)
+				return
+			}
+
 			log.Printf("Worker %d processing task: %s", id, task.ID)
 
 			// Execute the task
-			err := task.Execute(p.ctx)
+			err := p.execute(queued)
+			p.recordProcessed(id)
 
 			// Send result
-			select {
-			case p.results <- Result{TaskID: task.ID, Error: err}:
-				if err != nil {
-					log.Printf("Worker %d completed task %s with error: %v", id, task.ID, err)
-				} else {
-					log.Printf("Worker %d completed task %s successfully", id, task.ID)
-				}
-			case <-p.ctx.Done():
+			if !p.sendResult(Result{TaskID: task.ID, Error: err}) {
 				log.Printf("Worker %d unable to send result: context cancelled", id)
 				return
 			}
+			if err != nil {
+				log.Printf("Worker %d completed task %s with error: %v", id, task.ID, err)
+				if p.stopOnError {
+					p.stop(task.ID)
+				}
+			} else {
+				log.Printf("Worker %d completed task %s successfully", id, task.ID)
+			}
 		}
 	}
 }
 
+// execute runs a task under the pool context, also cancelled by the context the task was
+// submitted with and by the task's own timeout
+func (p *Pool) execute(queued queuedTask) error {
+	ctx := p.ctx
+	if queued.ctx != nil {
+		if queued.ctx.Err() != nil {
+			return fmt.Errorf("task cancelled before it started: %w", context.Cause(queued.ctx))
+		}
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		stop := context.AfterFunc(queued.ctx, func() { cancel(context.Cause(queued.ctx)) })
+		defer stop()
+	}
+
+	task := queued.task
+	if task.Timeout <= 0 {
+		return task.Execute(ctx)
+	}
+
+	taskCtx, cancel := context.WithTimeoutCause(ctx, task.Timeout, ErrTaskTimeout)
+	defer cancel()
+	err := task.Execute(taskCtx)
+	if context.Cause(taskCtx) != ErrTaskTimeout {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w after %v: %w", ErrTaskTimeout, task.Timeout, err)
+	}
+	return fmt.Errorf("%w after %v", ErrTaskTimeout, task.Timeout)
+}
+
+// sendResult delivers a result, preferring buffered delivery even if the pool was cancelled
+// so that results of in-flight tasks are kept. Returns false if the result was dropped.
+func (p *Pool) sendResult(result Result) bool {
+	select {
+	case p.results <- result:
+		return true
+	default:
+	}
+
+	select {
+	case p.results <- result:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// stop cancels the remaining tasks after a task failure (StopOnError)
+func (p *Pool) stop(taskID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return
+	}
+	log.Printf("Stopping worker pool: task %s failed", taskID)
+	p.stopped = true
+	p.cancel()
+}
+
+// Stopped reports whether the pool was stopped early because a task failed (StopOnError)
+func (p *Pool) Stopped() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopped
+}
+
+// recordSubmitted updates the submitted count and peak queue depth after a task was queued
+func (p *Pool) recordSubmitted() {
+	depth := len(p.tasks)
+
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	p.tasksSubmitted++
+	if depth > p.peakQueueDepth {
+		p.peakQueueDepth = depth
+	}
+}
+
+// recordProcessed counts a task executed by the given worker
+func (p *Pool) recordProcessed(workerID int) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	p.tasksPerWorker[workerID-1]++
+}
+
+// Metrics returns a snapshot of the pool utilization statistics
+func (p *Pool) Metrics() Metrics {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	metrics := Metrics{
+		WorkerCount:    p.workerCount,
+		PeakQueueDepth: p.peakQueueDepth,
+		TasksSubmitted: p.tasksSubmitted,
+		TasksPerWorker: append([]int(nil), p.tasksPerWorker...),
+	}
+	for _, count := range p.tasksPerWorker {
+		metrics.TasksProcessed += count
+	}
+	return metrics
+}
+
 // Submit adds a task to the pool's queue
 func (p *Pool) Submit(task Task) error {
+	return p.submit(nil, task)
+}
+
+// SubmitWithContext adds a task to the pool's queue, giving up if ctx is done before there is
+// room. The task's execution context is also cancelled when ctx is, so a caller can scope a
+// task (or a group of tasks) tighter than the pool, e.g. with a deadline.
+func (p *Pool) SubmitWithContext(ctx context.Context, task Task) error {
+	return p.submit(ctx, task)
+}
+
+// submit queues a task with the context it was submitted with (nil for none)
+func (p *Pool) submit(ctx context.Context, task Task) error {
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
 	p.mu.Lock()
 	if !p.started {
 		p.mu.Unlock()
@@ -126,10 +304,13 @@ func (p *Pool) Submit(task Task) error {
 	p.mu.Unlock()
 
 	select {
-	case p.tasks <- task:
+	case p.tasks <- queuedTask{task: task, ctx: ctx}:
+		p.recordSubmitted()
 		return nil
 	case <-p.ctx.Done():
 		return fmt.Errorf("pool context cancelled")
+	case <-done:
+		return fmt.Errorf("submit cancelled: %w", context.Cause(ctx))
 	}
 }
 
@@ -169,7 +350,8 @@ func (p *Pool) Shutdown() {
 }
 
 // ProcessBatch submits multiple tasks and waits for all to complete
-// Returns results for all tasks in the order they complete
+// Returns results for all tasks in the order they complete.
+// With StopOnError, returns the partial results collected before the pool stopped.
 func (p *Pool) ProcessBatch(ctx context.Context, tasks []Task) ([]Result, error) {
 	// Start the pool if not already started
 	p.mu.Lock()
@@ -185,6 +367,10 @@ func (p *Pool) ProcessBatch(ctx context.Context, tasks []Task) ([]Result, error)
 	// Submit all tasks
 	for _, task := range tasks {
 		if err := p.Submit(task); err != nil {
+			if p.Stopped() {
+				// A task failed with StopOnError; skip the rest and collect partial results
+				break
+			}
 			return nil, fmt.Errorf("failed to submit task %s: %w", task.ID, err)
 		}
 	}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -384,6 +385,89 @@ func TestPoolErrorHandling(t *testing.T) {
 	}
 }
 
+func TestPoolStopOnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		stopOnError bool
+		wantAll     bool
+	}{
+		{
+			name:        "stop on error skips remaining tasks",
+			stopOnError: true,
+			wantAll:     false,
+		},
+		{
+			name:        "continue on error runs all tasks",
+			stopOnError: false,
+			wantAll:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewPool(Config{WorkerCount: 1, StopOnError: tt.stopOnError})
+
+			var executed int32
+			tasks := []Task{
+				{
+					ID: "error-first",
+					Execute: func(ctx context.Context) error {
+						atomic.AddInt32(&executed, 1)
+						return fmt.Errorf("intentional error")
+					},
+				},
+			}
+			for i := 0; i < 9; i++ {
+				tasks = append(tasks, Task{
+					ID: fmt.Sprintf("task-%d", i),
+					Execute: func(ctx context.Context) error {
+						atomic.AddInt32(&executed, 1)
+						return nil
+					},
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			results, err := pool.ProcessBatch(ctx, tasks)
+			if err != nil {
+				t.Fatalf("ProcessBatch() failed: %v", err)
+			}
+
+			if tt.wantAll {
+				if len(results) != len(tasks) {
+					t.Errorf("ProcessBatch() returned %d results, want %d", len(results), len(tasks))
+				}
+				if pool.Stopped() {
+					t.Error("Stopped() = true, want false without StopOnError")
+				}
+				return
+			}
+
+			if int(atomic.LoadInt32(&executed)) >= len(tasks) {
+				t.Errorf("executed %d tasks, want fewer than %d after early failure", executed, len(tasks))
+			}
+			if len(results) >= len(tasks) {
+				t.Errorf("ProcessBatch() returned %d results, want fewer than %d", len(results), len(tasks))
+			}
+			if !pool.Stopped() {
+				t.Error("Stopped() = false, want true after failure with StopOnError")
+			}
+
+			foundFailure := false
+			for _, result := range results {
+				if result.TaskID == "error-first" && result.Error != nil {
+					foundFailure = true
+				}
+			}
+			if !foundFailure {
+				t.Error("partial results should include the failed task")
+			}
+		})
+	}
+}
+
 func TestPoolShutdown(t *testing.T) {
 	pool := NewPool(Config{WorkerCount: 2})
 
@@ -499,3 +583,195 @@ func TestPoolRaceConditions(t *testing.T) {
 		t.Errorf("Concurrent ProcessBatch() error: %v", err)
 	}
 }
+
+func TestPoolMetrics(t *testing.T) {
+	const taskCount = 20
+
+	pool := NewPool(Config{WorkerCount: 3, TaskQueueSize: taskCount})
+
+	tasks := make([]Task, taskCount)
+	for i := 0; i < taskCount; i++ {
+		tasks[i] = Task{
+			ID: fmt.Sprintf("task-%d", i),
+			Execute: func(ctx context.Context) error {
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			},
+		}
+	}
+
+	results, err := pool.ProcessBatch(context.Background(), tasks)
+	if err != nil {
+		t.Fatalf("ProcessBatch() error = %v", err)
+	}
+	if len(results) != taskCount {
+		t.Fatalf("ProcessBatch() returned %d results, want %d", len(results), taskCount)
+	}
+
+	metrics := pool.Metrics()
+	if metrics.WorkerCount != 3 {
+		t.Errorf("WorkerCount = %d, want 3", metrics.WorkerCount)
+	}
+	if metrics.TasksSubmitted != taskCount {
+		t.Errorf("TasksSubmitted = %d, want %d", metrics.TasksSubmitted, taskCount)
+	}
+	if metrics.TasksProcessed != taskCount {
+		t.Errorf("TasksProcessed = %d, want %d", metrics.TasksProcessed, taskCount)
+	}
+	if len(metrics.TasksPerWorker) != 3 {
+		t.Fatalf("TasksPerWorker has %d entries, want 3", len(metrics.TasksPerWorker))
+	}
+
+	sum := 0
+	for _, count := range metrics.TasksPerWorker {
+		sum += count
+	}
+	if sum != taskCount {
+		t.Errorf("sum of TasksPerWorker = %d, want %d", sum, taskCount)
+	}
+
+	// Tasks are submitted faster than workers can run them, so the queue builds up
+	if metrics.PeakQueueDepth < 1 || metrics.PeakQueueDepth > taskCount {
+		t.Errorf("PeakQueueDepth = %d, want between 1 and %d", metrics.PeakQueueDepth, taskCount)
+	}
+
+	// The snapshot must not alias internal state
+	metrics.TasksPerWorker[0] = -1
+	if pool.Metrics().TasksPerWorker[0] == -1 {
+		t.Error("Metrics() should return a copy of TasksPerWorker")
+	}
+}
+
+func TestPoolTaskTimeout(t *testing.T) {
+	pool := NewPool(Config{WorkerCount: 4})
+
+	fast := func(ctx context.Context) error { return nil }
+	tasks := []Task{
+		{ID: "fast-1", Execute: fast},
+		{
+			ID:      "slow",
+			Timeout: 50 * time.Millisecond,
+			Execute: func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(5 * time.Second):
+					return nil
+				}
+			},
+		},
+		{ID: "fast-2", Execute: fast, Timeout: 5 * time.Second},
+		{
+			// Ignores its context, but still overruns its deadline
+			ID:      "stubborn",
+			Timeout: 10 * time.Millisecond,
+			Execute: func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	results, err := pool.ProcessBatch(ctx, tasks)
+	if err != nil {
+		t.Fatalf("ProcessBatch() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ProcessBatch() took %v, the slow task should have been cut off by its timeout", elapsed)
+	}
+
+	resultMap := make(map[string]error)
+	for _, result := range results {
+		resultMap[result.TaskID] = result.Error
+	}
+	if len(resultMap) != len(tasks) {
+		t.Fatalf("got %d results, want %d", len(resultMap), len(tasks))
+	}
+	for _, id := range []string{"fast-1", "fast-2"} {
+		if resultMap[id] != nil {
+			t.Errorf("task %s error = %v, want success", id, resultMap[id])
+		}
+	}
+	for _, id := range []string{"slow", "stubborn"} {
+		if !errors.Is(resultMap[id], ErrTaskTimeout) {
+			t.Errorf("task %s error = %v, want ErrTaskTimeout", id, resultMap[id])
+		}
+	}
+	if !errors.Is(resultMap["slow"], context.DeadlineExceeded) {
+		t.Errorf("slow task error = %v, should wrap the error returned by the task", resultMap["slow"])
+	}
+}
+
+func TestPoolSubmitWithContext(t *testing.T) {
+	pool := NewPool(Config{WorkerCount: 2})
+	if err := pool.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Cancelling the submit context cancels its task, not the pool
+	taskCtx, cancelTask := context.WithCancel(context.Background())
+	running := make(chan struct{})
+	err := pool.SubmitWithContext(taskCtx, Task{
+		ID: "scoped",
+		Execute: func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitWithContext() error = %v", err)
+	}
+	if err := pool.Submit(Task{ID: "unscoped", Execute: func(ctx context.Context) error { return nil }}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-running
+	cancelTask()
+
+	resultMap := make(map[string]error)
+	for _, result := range pool.Wait() {
+		resultMap[result.TaskID] = result.Error
+	}
+	if !errors.Is(resultMap["scoped"], context.Canceled) {
+		t.Errorf("scoped task error = %v, want context.Canceled", resultMap["scoped"])
+	}
+	if err, ok := resultMap["unscoped"]; !ok || err != nil {
+		t.Errorf("unscoped task result = %v (present %v), want success", err, ok)
+	}
+}
+
+func TestPoolSubmitWithContextQueueFull(t *testing.T) {
+	pool := NewPool(Config{WorkerCount: 1, TaskQueueSize: 1})
+	if err := pool.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pool.Shutdown()
+
+	// Keep the only worker busy and fill the queue
+	release := make(chan struct{})
+	defer close(release)
+	running := make(chan struct{})
+	blocking := func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	}
+	if err := pool.Submit(Task{ID: "busy", Execute: blocking}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-running
+	if err := pool.Submit(Task{ID: "queued", Execute: func(ctx context.Context) error { return nil }}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pool.SubmitWithContext(ctx, Task{ID: "rejected", Execute: func(ctx context.Context) error { return nil }})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SubmitWithContext() error = %v, want context.DeadlineExceeded once the queue stays full", err)
+	}
+}
@@ -1,54 +1,170 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/config"
 	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/logger"
-	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/processor"
+	"gitlab.stashaway.com/vladimir.semashko/openapi-go/internal/paths"
 )
 
 func main() {
-	// Step 1: Load configuration (before logger so we can configure it)
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches the command line to its subcommand and returns the exit code. Without a
+// subcommand, the flags of earlier releases are accepted (see runLegacy).
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runLegacy(args)
+	}
+
+	name := args[0]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+	if name == "help" {
+		usage(os.Stdout)
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return 2
+}
+
+// usage prints the subcommands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: openapi-go <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "openapi-go <command> -h" for the flags of a command. Without a command, clients are`)
+	fmt.Fprintln(w, `generated as with generate, and the flags of earlier releases ("openapi-go -h") are accepted.`)
+}
+
+// runLegacy runs the single-shot command line of earlier releases, where flags such as
+// --validate and --stats select what to do and the rest is read from application.yml
+func runLegacy(args []string) int {
+	fs := flag.NewFlagSet("openapi-go", flag.ContinueOnError)
+	fs.Usage = func() {
+		usage(fs.Output())
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	jsonLogs := fs.Bool("json-logs", false, "Force JSON log output (overrides log_format)")
+	textLogs := fs.Bool("text-logs", false, "Force text log output (overrides log_format)")
+	includeDeprecated := fs.Bool("include-deprecated", true, "Generate operations marked deprecated (set to false to exclude them)")
+	updateLock := fs.Bool("update-lock", false, "Record current spec checksums in the lockfile instead of verifying them")
+	updateBaseline := fs.Bool("update-baseline", false, "Rewrite baseline_fingerprints with the current specs instead of comparing against it")
+	printConfigFlag := fs.Bool("print-config", false, "Print the resolved configuration and exit")
+	printConfigFormat := fs.String("print-config-format", "yaml", "Format for --print-config (yaml or json)")
+	stats := fs.Bool("stats", false, "Print a summary of the discovered specs without generating clients and exit")
+	changelog := fs.Bool("changelog", false, "Print a Markdown changelog between two spec files (args: <previous-spec> <current-spec>) and exit")
+	changelogService := fs.String("changelog-service", "", "Service name for the --changelog heading (default: derived from the current spec's directory)")
+	watch := fs.Bool("watch", false, "Keep running and regenerate clients when specs change (debounced by watch_debounce)")
+	validate := fs.Bool("validate", false, "Validate the discovered specs without generating clients and exit (non-zero if any spec is invalid)")
+	strict := fs.Bool("strict", false, "With --validate, treat validation warnings as failures for this run")
+	repair := fs.Bool("repair", false, "Fix common spec issues (missing operationIds and info.version, short openapi versions), writing <name>.repaired.<ext> copies, and exit")
+	write := fs.Bool("write", false, "With --repair, fix the specs in place instead of writing copies")
+	dryRun := fs.Bool("dry-run", false, "Discover, fingerprint and check the cache of the specs, print which clients would be regenerated and why, and exit without generating")
+	jsonOutput := fs.Bool("json", false, "Print the result of --stats, --validate, --repair, --dry-run or --changelog as JSON")
+	ogenConfig := fs.String("ogen-config", "", "Path to an ogen configuration file for this run (overrides ogen_config_path)")
+	githubAnnotations := fs.Bool("github-annotations", false, "Print validation issues and failed specs as GitHub Actions annotations (automatic when GITHUB_ACTIONS=true)")
+	traceCache := fs.Bool("trace-cache", false, "Log the inputs and outcome of each spec's cache decision as greppable cache-trace lines (overrides trace_cache)")
+	maxParallelIO := fs.Int("max-parallel-io", 0, "Limit concurrent spec file reads, independently of worker_count (overrides io_concurrency; 0 keeps the configured value)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	// Compare two spec versions; needs no configuration
+	if *changelog {
+		if fs.NArg() != 2 {
+			return fail(2, "--changelog expects two arguments: <previous-spec> <current-spec>")
+		}
+		return diffSpecs(*changelogService, fs.Arg(0), fs.Arg(1), *jsonOutput)
+	}
+
+	// Load configuration (before logger so we can configure it)
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		// Use default logger for config load errors
-		defaultLog := logger.NewDefault()
-		defaultLog.Error("Failed to load configuration", "error", err)
-		os.Exit(1)
-	}
-
-	// Step 2: Initialize structured logger with config
-	structuredLog := logger.New(logger.Config{
-		Level:  cfg.LogLevel,
-		Format: cfg.LogFormat,
-		Output: os.Stdout,
-	})
-
-	structuredLog.Info("Starting OpenAPI client generator")
-	config.LogConfiguration(cfg, structuredLog)
-
-	// Step 3: Set up context with cancellation on interrupt
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle graceful shutdown on SIGINT/SIGTERM
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		structuredLog.Warn("Received interrupt signal, cancelling operations...")
-		cancel()
-	}()
-
-	// Step 4: Process OpenAPI specs to generate clients
-	if err := processor.ProcessOpenAPISpecs(ctx, cfg, structuredLog); err != nil {
-		structuredLog.Error("Error processing OpenAPI specs", "error", err)
-		os.Exit(1)
-	}
-
-	structuredLog.Info("Client generation completed successfully")
+		return fail(1, "Failed to load configuration", "error", err)
+	}
+
+	// CLI flags override the configured log format
+	cfg.LogFormat, err = config.ResolveLogFormat(cfg.LogFormat, *jsonLogs, *textLogs)
+	if err != nil {
+		return fail(2, "Invalid command line flags", "error", err)
+	}
+
+	if !*includeDeprecated {
+		cfg.ExcludeDeprecated = true
+	}
+	if *updateLock {
+		cfg.UpdateLock = true
+	}
+	if *updateBaseline {
+		cfg.UpdateBaseline = true
+	}
+	if *maxParallelIO < 0 {
+		return fail(2, "Invalid command line flags", "error", "--max-parallel-io must not be negative")
+	}
+	if *traceCache {
+		cfg.TraceCache = true
+	}
+	if *maxParallelIO > 0 {
+		cfg.IOConcurrency = *maxParallelIO
+	}
+	if *ogenConfig != "" {
+		// Resolved like ogen_config_path, since ogen runs from the repository root
+		ogenConfigPath := paths.MakeAbsolutePath(*ogenConfig)
+		if err := config.ValidateOgenConfigPath(ogenConfigPath); err != nil {
+			return fail(2, "Invalid command line flags", "error", "--ogen-config: "+err.Error())
+		}
+		cfg.OgenConfigPath = ogenConfigPath
+	}
+	if *strict {
+		if !*validate {
+			return fail(2, "Invalid command line flags", "error", "--strict requires --validate")
+		}
+		// Scoped to this validation run; generation keeps the configured fail_on_warnings
+		cfg.FailOnWarnings = true
+	}
+	if *write && !*repair {
+		return fail(2, "Invalid command line flags", "error", "--write requires --repair")
+	}
+
+	switch {
+	case *printConfigFlag:
+		return printConfig(cfg, *printConfigFormat)
+	case *stats:
+		return printStats(cfg, *jsonOutput)
+	case *repair:
+		return repairSpecs(cfg, *write, *jsonOutput)
+	case *validate:
+		return validateSpecs(cfg, *jsonOutput, *githubAnnotations)
+	case *dryRun:
+		return planSpecs(cfg, *jsonOutput)
+	default:
+		return generate(cfg, *watch, *githubAnnotations, *jsonOutput)
+	}
+}
+
+// fail logs a failure with the default logger and returns the exit code
+func fail(code int, msg string, args ...any) int {
+	defaultLog := logger.NewDefault()
+	defaultLog.Error(msg, args...)
+	return code
 }